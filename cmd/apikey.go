@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	apikey_service "github.com/tesarwijaya/ouroboros/internal/domain/apikey/service"
+	"github.com/urfave/cli/v2"
+)
+
+func newAPIKeyCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "apikey",
+		Usage: "manage api keys of machine clients",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "create",
+				Usage: "create an api key, the key is only shown once",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "name",
+						Usage:    "who or what the key is for",
+						Required: true,
+					},
+					&cli.StringSliceFlag{
+						Name:  "scope",
+						Usage: "scope granted to the key, matched against the authorization policies",
+					},
+					&cli.DurationFlag{
+						Name:  "expires-in",
+						Usage: "lifetime of the key, it never expires when omitted",
+					},
+				},
+				Action: func(c *cli.Context) error {
+					return runCommand(c.Context, func(svc apikey_service.APIKeyService) error {
+						apiKey, key, err := svc.Create(c.Context, apikey_service.CreatePayload{
+							Name:      c.String("name"),
+							Scopes:    c.StringSlice("scope"),
+							ExpiresIn: c.Duration("expires-in"),
+						})
+						if err != nil {
+							return err
+						}
+
+						fmt.Printf("created api key %d for %s, store it now as it can't be shown again:\n%s\n", apiKey.ID, apiKey.Name, key)
+
+						return nil
+					})
+				},
+			},
+			{
+				Name:  "list",
+				Usage: "list api keys",
+				Action: func(c *cli.Context) error {
+					return runCommand(c.Context, func(svc apikey_service.APIKeyService) error {
+						apiKeys, err := svc.FindAll(c.Context)
+						if err != nil {
+							return err
+						}
+
+						now := time.Now()
+						w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+						fmt.Fprintln(w, "ID\tNAME\tSCOPES\tSTATUS\tEXPIRES AT\tLAST USED AT")
+						for _, apiKey := range apiKeys {
+							status := "active"
+							switch {
+							case apiKey.RevokedAt != nil:
+								status = "revoked"
+							case !apiKey.Active(now):
+								status = "expired"
+							}
+
+							fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n",
+								apiKey.ID,
+								apiKey.Name,
+								strings.Join(apiKey.Scopes, ","),
+								status,
+								formatTime(apiKey.ExpiresAt),
+								formatTime(apiKey.LastUsedAt),
+							)
+						}
+
+						return w.Flush()
+					})
+				},
+			},
+			{
+				Name:  "revoke",
+				Usage: "revoke an api key, it is rejected from then on",
+				Flags: []cli.Flag{
+					&cli.Int64Flag{
+						Name:     "id",
+						Usage:    "id of the key to revoke",
+						Required: true,
+					},
+				},
+				Action: func(c *cli.Context) error {
+					return runCommand(c.Context, func(svc apikey_service.APIKeyService) error {
+						if err := svc.Revoke(c.Context, c.Int64("id")); err != nil {
+							return err
+						}
+
+						fmt.Printf("revoked api key %d\n", c.Int64("id"))
+
+						return nil
+					})
+				},
+			},
+		},
+	}
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+
+	return t.Format(time.RFC3339)
+}
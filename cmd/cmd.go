@@ -1,68 +1,58 @@
 package cmd
 
 import (
-	"context"
-	"database/sql"
-	"fmt"
-	"time"
-
 	"github.com/tesarwijaya/ouroboros/internal/config"
+	apikey_repository "github.com/tesarwijaya/ouroboros/internal/domain/apikey/repository"
+	apikey_service "github.com/tesarwijaya/ouroboros/internal/domain/apikey/service"
+	auth_service "github.com/tesarwijaya/ouroboros/internal/domain/auth/service"
+	command_service "github.com/tesarwijaya/ouroboros/internal/domain/command/service"
 	event_repository "github.com/tesarwijaya/ouroboros/internal/domain/event/repository"
+	event_service "github.com/tesarwijaya/ouroboros/internal/domain/event/service"
+	exporter_service "github.com/tesarwijaya/ouroboros/internal/domain/exporter/service"
 	healthz_service "github.com/tesarwijaya/ouroboros/internal/domain/healthz/service"
+	idempotency_repository "github.com/tesarwijaya/ouroboros/internal/domain/idempotency/repository"
+	idempotency_service "github.com/tesarwijaya/ouroboros/internal/domain/idempotency/service"
+	importer_service "github.com/tesarwijaya/ouroboros/internal/domain/importer/service"
+	offer_process "github.com/tesarwijaya/ouroboros/internal/domain/offer/process"
+	offer_repository "github.com/tesarwijaya/ouroboros/internal/domain/offer/repository"
+	offer_service "github.com/tesarwijaya/ouroboros/internal/domain/offer/service"
 	player_repository "github.com/tesarwijaya/ouroboros/internal/domain/player/repository"
 	player_service "github.com/tesarwijaya/ouroboros/internal/domain/player/service"
+	ratelimit_repository "github.com/tesarwijaya/ouroboros/internal/domain/ratelimit/repository"
+	ratelimit_service "github.com/tesarwijaya/ouroboros/internal/domain/ratelimit/service"
+	snapshot_repository "github.com/tesarwijaya/ouroboros/internal/domain/snapshot/repository"
+	snapshot_service "github.com/tesarwijaya/ouroboros/internal/domain/snapshot/service"
 	team_repository "github.com/tesarwijaya/ouroboros/internal/domain/team/repository"
 	team_service "github.com/tesarwijaya/ouroboros/internal/domain/team/service"
+	"github.com/tesarwijaya/ouroboros/internal/entry-point/graphql"
+	"github.com/tesarwijaya/ouroboros/internal/entry-point/grpc"
+	player_handler "github.com/tesarwijaya/ouroboros/internal/entry-point/grpc/handler/player"
+	team_handler "github.com/tesarwijaya/ouroboros/internal/entry-point/grpc/handler/team"
 	"github.com/tesarwijaya/ouroboros/internal/entry-point/rest"
+	exporter_controller "github.com/tesarwijaya/ouroboros/internal/entry-point/rest/controller/exporter"
 	healthz_controller "github.com/tesarwijaya/ouroboros/internal/entry-point/rest/controller/healthz"
+	importer_controller "github.com/tesarwijaya/ouroboros/internal/entry-point/rest/controller/importer"
+	offer_controller "github.com/tesarwijaya/ouroboros/internal/entry-point/rest/controller/offer"
 	player_controller "github.com/tesarwijaya/ouroboros/internal/entry-point/rest/controller/player"
 	team_controller "github.com/tesarwijaya/ouroboros/internal/entry-point/rest/controller/team"
 	"github.com/tesarwijaya/ouroboros/internal/resource"
+	"github.com/tesarwijaya/ouroboros/internal/worker"
 	"github.com/urfave/cli/v2"
 	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/zap"
 )
 
 func NewCmd() *cli.App {
 	return &cli.App{
 		Commands: []*cli.Command{
-			{
-				Name:  "server-start",
-				Usage: "start the fcking server!",
-				Action: func(*cli.Context) error {
-					server := newApp(func(lc fx.Lifecycle, server rest.RestServer, db *sql.DB) {
-						lc.Append(fx.Hook{
-							OnStart: func(ctx context.Context) error {
-								go server.Start()
-
-								return nil
-							},
-							OnStop: func(ctx context.Context) error {
-								fmt.Println("closing db...")
-
-								return db.Close()
-							},
-						})
-					})
-
-					ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-					defer cancel()
-
-					if err := server.Start(ctx); err != nil {
-						panic(err)
-					}
-
-					<-server.Done()
-
-					ctxStop, cancelStop := context.WithTimeout(context.Background(), 15*time.Second)
-					defer cancelStop()
-
-					if err := server.Stop(ctxStop); err != nil {
-						panic(err)
-					}
-
-					return nil
-				},
-			},
+			newServerStartCmd(),
+			newSnapshotCmd(),
+			newAPIKeyCmd(),
+			newTeamCmd(),
+			newPlayerCmd(),
+			newImportCmd(),
+			newExportCmd(),
 		},
 	}
 }
@@ -71,24 +61,110 @@ func newApp(invoker ...interface{}) *fx.App {
 	return fx.New(
 		fx.Provide(
 			rest.NewRestServer,
+			grpc.NewGrpcServer,
+			graphql.NewGraphqlHandler,
 			config.NewConfig,
 
+			resource.NewLogger,
+			resource.NewTracerProvider,
 			resource.NewSQLConnection,
 			resource.NewEventStoreConnection,
 
+			auth_service.NewAuthService,
+			auth_service.NewAuthorizer,
+			apikey_service.NewAPIKeyService,
+			apikey_repository.NewAPIKeyRepository,
+
 			healthz_controller.NewHealthzController,
 			healthz_service.NewHealthzService,
+			fx.Annotated{
+				Group:  "healthz_checks",
+				Target: healthz_service.NewSQLCheck,
+			},
+			fx.Annotated{
+				Group:  "healthz_checks",
+				Target: healthz_service.NewMigrationCheck,
+			},
 
 			player_controller.NewPlayerController,
+			player_handler.NewPlayerHandler,
 			player_service.NewPlayerService,
+			player_service.NewCommandHandlers,
 			player_repository.NewPlayerReposity,
 
 			team_controller.NewTeamController,
+			team_handler.NewTeamHandler,
 			team_service.NewTeamService,
+			team_service.NewCommandHandlers,
 			team_repository.NewTeamReposity,
 
+			offer_controller.NewOfferController,
+			offer_service.NewOfferService,
+			offer_service.NewCommandHandlers,
+			offer_repository.NewOfferRepository,
+			fx.Annotated{
+				Group:  "event_handlers",
+				Target: offer_process.NewWithdrawalProcess,
+			},
+			fx.Annotated{
+				Group:  "workers",
+				Target: offer_service.NewExpiryWorker,
+			},
+
+			importer_controller.NewImportController,
+			importer_service.NewImportService,
+
+			exporter_controller.NewExportController,
+			exporter_service.NewExportService,
+
 			event_repository.NewTeamReposity,
+			event_repository.NewOutboxRepository,
+			event_service.NewEventBus,
+			fx.Annotated{
+				Group:  "workers",
+				Target: event_service.NewRelayWorker,
+			},
+			fx.Annotated{
+				Group:  "healthz_checks",
+				Target: event_service.NewEventStoreCheck,
+			},
+			fx.Annotated{
+				Group:  "healthz_checks",
+				Target: event_service.NewProjectionLagCheck,
+			},
+
+			command_service.NewCommandBus,
+			fx.Annotated{
+				Group:  "command_middlewares",
+				Target: command_service.NewLoggingMiddleware,
+			},
+			fx.Annotated{
+				Group:  "command_middlewares",
+				Target: command_service.NewValidationMiddleware,
+			},
+
+			snapshot_service.NewSnapshotService,
+			snapshot_repository.NewSnapshotRepository,
+
+			idempotency_service.NewIdempotencyService,
+			idempotency_repository.NewIdempotencyRepository,
+			fx.Annotated{
+				Group:  "workers",
+				Target: idempotency_service.NewPurgeWorker,
+			},
+
+			ratelimit_service.NewRateLimitService,
+			ratelimit_repository.NewRateLimitRepository,
+			fx.Annotated{
+				Group:  "workers",
+				Target: ratelimit_service.NewPurgeWorker,
+			},
+
+			worker.NewRunner,
 		),
+		fx.WithLogger(func(l *zap.Logger) fxevent.Logger {
+			return &fxevent.ZapLogger{Logger: l}
+		}),
 		fx.Invoke(invoker...),
 	)
 }
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"bufio"
+	"os"
+	"strings"
+
+	"github.com/tesarwijaya/ouroboros/internal/domain/exporter/model"
+	exporter_service "github.com/tesarwijaya/ouroboros/internal/domain/exporter/service"
+	"github.com/urfave/cli/v2"
+)
+
+func newExportCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "export",
+		Usage: "stream teams, players or transfers as CSV or NDJSON",
+		Subcommands: []*cli.Command{
+			newExportKindCmd(model.KIND_TEAMS, "export every team"),
+			newExportKindCmd(model.KIND_PLAYERS, "export every player, or the players of a team",
+				&cli.Int64Flag{
+					Name:  "team-id",
+					Usage: "only export the players of the team",
+				},
+			),
+			newExportKindCmd(model.KIND_TRANSFERS, "export every transfer, or the transfers of a team or a player",
+				&cli.Int64Flag{
+					Name:  "team-id",
+					Usage: "only export the transfers from or to the team",
+				},
+				&cli.Int64Flag{
+					Name:  "player-id",
+					Usage: "only export the transfers of the player",
+				},
+			),
+		},
+	}
+}
+
+func newExportKindCmd(kind string, usage string, filters ...cli.Flag) *cli.Command {
+	return &cli.Command{
+		Name:  kind,
+		Usage: usage,
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "format of the export, csv or ndjson",
+				Value: model.FORMAT_CSV,
+			},
+			&cli.PathFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "file to write the export to, stdout by default",
+			},
+		}, filters...),
+		Action: func(c *cli.Context) error {
+			format := strings.ToLower(c.String("format"))
+			if format != model.FORMAT_CSV && format != model.FORMAT_NDJSON {
+				return model.ErrUnknownFormat
+			}
+
+			out := c.App.Writer
+			if c.Path("file") != "" {
+				f, err := os.Create(c.Path("file"))
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+
+				out = f
+			}
+
+			return runCommand(c.Context, func(svc exporter_service.ExportService) error {
+				w := bufio.NewWriter(out)
+				if err := svc.Export(c.Context, kind, format, model.Filter{
+					TeamID:   c.Int64("team-id"),
+					PlayerID: c.Int64("player-id"),
+				}, w); err != nil {
+					return err
+				}
+
+				return w.Flush()
+			})
+		},
+	}
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tesarwijaya/ouroboros/internal/domain/importer/model"
+	importer_service "github.com/tesarwijaya/ouroboros/internal/domain/importer/service"
+	"github.com/urfave/cli/v2"
+)
+
+func newImportCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "import",
+		Usage: "import teams and players from a CSV, JSON or NDJSON file",
+		Flags: []cli.Flag{
+			&cli.PathFlag{
+				Name:     "file",
+				Aliases:  []string{"f"},
+				Usage:    "file to import",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "format of the file, csv, json or ndjson, guessed from the file extension by default",
+			},
+			&cli.IntFlag{
+				Name:  "chunk-size",
+				Usage: "rows imported per transaction, the whole file is imported in one transaction when 0",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "validate the file and print what would be done without applying it",
+			},
+			outputFlag(),
+		},
+		Action: func(c *cli.Context) error {
+			format, err := outputFormat(c)
+			if err != nil {
+				return err
+			}
+
+			if c.Int("chunk-size") < 0 {
+				return fmt.Errorf("chunk-size must be a positive number")
+			}
+
+			fileFormat := strings.ToLower(c.String("format"))
+			if fileFormat == "" {
+				fileFormat = model.FormatFromFilename(c.Path("file"))
+			}
+
+			f, err := os.Open(c.Path("file"))
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			rows, err := model.Parse(f, fileFormat)
+			if err != nil {
+				return err
+			}
+
+			return runCommand(c.Context, func(svc importer_service.ImportService) error {
+				report, err := svc.Import(c.Context, rows, model.Options{
+					DryRun:    c.Bool("dry-run"),
+					ChunkSize: c.Int("chunk-size"),
+				})
+				if err != nil {
+					return err
+				}
+
+				if err := writeOutput(c.App.Writer, format, reportOutput(report)); err != nil {
+					return err
+				}
+
+				return reportSummary(c, report)
+			})
+		},
+	}
+}
+
+func reportOutput(report model.ImportReport) output {
+	rows := make([][]string, 0, len(report.Rows))
+	for _, row := range report.Rows {
+		rows = append(rows, []string{
+			fmt.Sprint(row.Row),
+			row.Team,
+			row.Player,
+			formatID(row.PlayerID),
+			row.Action,
+			fmt.Sprint(row.Applied),
+			row.Error,
+		})
+	}
+
+	return output{
+		Header: []string{"row", "team", "player", "player_id", "action", "applied", "error"},
+		Rows:   rows,
+		Value:  report,
+	}
+}
+
+// reportSummary prints the summary on stderr, so that stdout can still be
+// parsed, and fails the command when a row failed.
+func reportSummary(c *cli.Context, report model.ImportReport) error {
+	if report.DryRun {
+		fmt.Fprintln(c.App.ErrWriter, "dry run, nothing was changed")
+	}
+
+	fmt.Fprintf(c.App.ErrWriter, "%d created, %d updated, %d skipped, %d failed\n",
+		report.Summary.Created, report.Summary.Updated, report.Summary.Skipped, report.Summary.Failed)
+
+	if report.Summary.Failed == 0 {
+		return nil
+	}
+
+	if !report.Applied {
+		return fmt.Errorf("%d rows failed, nothing was imported", report.Summary.Failed)
+	}
+
+	applied := 0
+	for _, row := range report.Rows {
+		if row.Applied {
+			applied++
+		}
+	}
+
+	return fmt.Errorf("%d rows failed, %d rows were imported before", report.Summary.Failed, applied)
+}
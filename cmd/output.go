@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	command_model "github.com/tesarwijaya/ouroboros/internal/domain/command/model"
+	player_model "github.com/tesarwijaya/ouroboros/internal/domain/player/model"
+	team_model "github.com/tesarwijaya/ouroboros/internal/domain/team/model"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	OUTPUT_TABLE = "table"
+	OUTPUT_JSON  = "json"
+	OUTPUT_CSV   = "csv"
+)
+
+// output is what a command prints, Rows are rendered as a table or CSV while
+// Value is encoded as JSON.
+type output struct {
+	Header []string
+	Rows   [][]string
+	Value  interface{}
+}
+
+func outputFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:    "output",
+		Aliases: []string{"o"},
+		Usage:   "output format, table, json or csv",
+		Value:   OUTPUT_TABLE,
+	}
+}
+
+func dryRunFlag() cli.Flag {
+	return &cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "run every check of the change without applying it",
+	}
+}
+
+// commandContext marks the commands dispatched from it as a dry run when the
+// --dry-run flag is set.
+func commandContext(c *cli.Context) context.Context {
+	if c.Bool("dry-run") {
+		return command_model.WithDryRun(c.Context)
+	}
+
+	return c.Context
+}
+
+// outputFormat is checked before connecting to anything, so that a typo
+// doesn't surface after a change was applied.
+func outputFormat(c *cli.Context) (string, error) {
+	format := strings.ToLower(c.String("output"))
+	switch format {
+	case OUTPUT_TABLE, OUTPUT_JSON, OUTPUT_CSV:
+		return format, nil
+	}
+
+	return "", fmt.Errorf("unknown output format %q, use table, json or csv", c.String("output"))
+}
+
+func writeOutput(w io.Writer, format string, out output) error {
+	switch format {
+	case OUTPUT_JSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(out.Value)
+	case OUTPUT_CSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(out.Header); err != nil {
+			return err
+		}
+
+		return cw.WriteAll(out.Rows)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.ToUpper(strings.Join(out.Header, "\t")))
+	for _, row := range out.Rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+// writeResult prints the result of a change, a dry run is reported on stderr
+// so that stdout can still be parsed.
+func writeResult(c *cli.Context, format string, out output) error {
+	if c.Bool("dry-run") {
+		fmt.Fprintln(c.App.ErrWriter, "dry run, nothing was changed")
+	}
+
+	return writeOutput(c.App.Writer, format, out)
+}
+
+func teamOutput(teams []team_model.TeamModel) output {
+	if teams == nil {
+		teams = []team_model.TeamModel{}
+	}
+
+	rows := make([][]string, 0, len(teams))
+	for _, team := range teams {
+		rows = append(rows, []string{formatID(team.ID), team.Name})
+	}
+
+	return output{
+		Header: []string{"id", "name"},
+		Rows:   rows,
+		Value:  teams,
+	}
+}
+
+func playerOutput(players []player_model.PlayerModel) output {
+	if players == nil {
+		players = []player_model.PlayerModel{}
+	}
+
+	rows := make([][]string, 0, len(players))
+	for _, player := range players {
+		rows = append(rows, []string{formatID(player.ID), player.Name, formatID(player.TeamID)})
+	}
+
+	return output{
+		Header: []string{"id", "name", "team_id"},
+		Rows:   rows,
+		Value:  players,
+	}
+}
+
+// teamItemOutput prints a single team as a JSON object rather than an array.
+func teamItemOutput(team team_model.TeamModel) output {
+	out := teamOutput([]team_model.TeamModel{team})
+	out.Value = team
+
+	return out
+}
+
+func playerItemOutput(player player_model.PlayerModel) output {
+	out := playerOutput([]player_model.PlayerModel{player})
+	out.Value = player
+
+	return out
+}
+
+// formatID leaves ids that aren't assigned yet, e.g. on a dry run, empty.
+func formatID(id int64) string {
+	if id == 0 {
+		return ""
+	}
+
+	return strconv.FormatInt(id, 10)
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	command_service "github.com/tesarwijaya/ouroboros/internal/domain/command/service"
+	"github.com/tesarwijaya/ouroboros/internal/domain/player/model"
+	player_service "github.com/tesarwijaya/ouroboros/internal/domain/player/service"
+	"github.com/urfave/cli/v2"
+)
+
+func newPlayerCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "player",
+		Usage: "manage players",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "list",
+				Usage: "list players",
+				Flags: []cli.Flag{
+					&cli.Int64SliceFlag{
+						Name:  "team-id",
+						Usage: "only list the players of the team, every player when omitted",
+					},
+					outputFlag(),
+				},
+				Action: func(c *cli.Context) error {
+					format, err := outputFormat(c)
+					if err != nil {
+						return err
+					}
+
+					return runCommand(c.Context, func(svc player_service.PlayerService) error {
+						var players []model.PlayerModel
+						if teamIDs := c.Int64Slice("team-id"); len(teamIDs) > 0 {
+							players, err = svc.FindByTeamIDs(c.Context, teamIDs)
+						} else {
+							players, err = svc.FindAll(c.Context)
+						}
+						if err != nil {
+							return err
+						}
+
+						return writeOutput(c.App.Writer, format, playerOutput(players))
+					})
+				},
+			},
+			{
+				Name:  "get",
+				Usage: "show a player",
+				Flags: []cli.Flag{
+					&cli.Int64Flag{
+						Name:     "id",
+						Usage:    "id of the player",
+						Required: true,
+					},
+					outputFlag(),
+				},
+				Action: func(c *cli.Context) error {
+					format, err := outputFormat(c)
+					if err != nil {
+						return err
+					}
+
+					return runCommand(c.Context, func(svc player_service.PlayerService) error {
+						player, err := svc.FindByID(c.Context, c.Int64("id"))
+						if err != nil {
+							return playerError(c.Int64("id"), err)
+						}
+
+						return writeOutput(c.App.Writer, format, playerItemOutput(player))
+					})
+				},
+			},
+			{
+				Name:  "create",
+				Usage: "create a player in a team",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "name",
+						Usage:    "name of the player",
+						Required: true,
+					},
+					&cli.Int64Flag{
+						Name:     "team-id",
+						Usage:    "team the player plays for",
+						Required: true,
+					},
+					outputFlag(),
+					dryRunFlag(),
+				},
+				Action: func(c *cli.Context) error {
+					format, err := outputFormat(c)
+					if err != nil {
+						return err
+					}
+
+					return runCommand(c.Context, func(bus command_service.CommandBus) error {
+						player, err := command_service.Dispatch[model.PlayerModel](commandContext(c), bus, player_service.InsertPlayerCommand{
+							Payload: model.PlayerModel{
+								Name:   c.String("name"),
+								TeamID: c.Int64("team-id"),
+							},
+						})
+						if err != nil {
+							return teamError(c.Int64("team-id"), err)
+						}
+
+						return writeResult(c, format, playerItemOutput(player))
+					})
+				},
+			},
+			{
+				Name:  "transfer",
+				Usage: "transfer a player to another team",
+				Flags: []cli.Flag{
+					&cli.Int64Flag{
+						Name:     "id",
+						Usage:    "id of the player",
+						Required: true,
+					},
+					&cli.Int64Flag{
+						Name:     "team-id",
+						Usage:    "team the player is transferred to",
+						Required: true,
+					},
+					outputFlag(),
+					dryRunFlag(),
+				},
+				Action: func(c *cli.Context) error {
+					format, err := outputFormat(c)
+					if err != nil {
+						return err
+					}
+
+					return runCommand(c.Context, func(svc player_service.PlayerService, bus command_service.CommandBus) error {
+						if _, err := bus.Dispatch(commandContext(c), player_service.TransferPlayerCommand{
+							Payload: player_service.TransferPayload{
+								PlayerID: c.Int64("id"),
+								TeamID:   c.Int64("team-id"),
+							},
+						}); err != nil {
+							return playerError(c.Int64("id"), err)
+						}
+
+						player, err := svc.FindByID(c.Context, c.Int64("id"))
+						if err != nil {
+							return playerError(c.Int64("id"), err)
+						}
+						// a dry run leaves the player where it is
+						player.TeamID = c.Int64("team-id")
+
+						return writeResult(c, format, playerItemOutput(player))
+					})
+				},
+			},
+		},
+	}
+}
+
+func playerError(id int64, err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("player %d not found", id)
+	}
+
+	return err
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+
+	"github.com/EventStore/EventStore-Client-Go/esdb"
+	"github.com/tesarwijaya/ouroboros/internal/config"
+	event_service "github.com/tesarwijaya/ouroboros/internal/domain/event/service"
+	"go.uber.org/fx"
+	"go.uber.org/multierr"
+)
+
+var (
+	lifecycleType = reflect.TypeOf((*fx.Lifecycle)(nil)).Elem()
+	errorType     = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+type commandResource struct {
+	fx.In
+	Lifecycle  fx.Lifecycle
+	Config     *config.Config
+	Bus        event_service.EventBus
+	EventStore *esdb.Client
+	Db         *sql.DB
+}
+
+// runCommand runs command, a func(deps...) error, with its dependencies
+// injected once the app started, and stops the app afterwards. The resources
+// the command relied on are closed whether it failed or not.
+func runCommand(ctx context.Context, command interface{}) error {
+	var cfg *config.Config
+
+	app := newApp(func(r commandResource) {
+		cfg = r.Config
+
+		// appended first so that it stops last, after the command returned
+		r.Lifecycle.Append(fx.Hook{
+			OnStop: func(ctx context.Context) error {
+				var err error
+
+				err = multierr.Append(err, r.Bus.Close(ctx))
+				err = multierr.Append(err, r.EventStore.Close())
+				err = multierr.Append(err, r.Db.Close())
+
+				return err
+			},
+		})
+	}, onStart(command))
+	if err := app.Err(); err != nil {
+		return err
+	}
+
+	// a failed start stops what was already started
+	if err := app.Start(ctx); err != nil {
+		return err
+	}
+
+	ctxStop, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	return app.Stop(ctxStop)
+}
+
+// onStart wraps command into an invoker taking the same dependencies plus the
+// lifecycle, which runs command when the app starts.
+func onStart(command interface{}) interface{} {
+	fn := reflect.ValueOf(command)
+	if fn.Kind() != reflect.Func || fn.Type().NumOut() != 1 || fn.Type().Out(0) != errorType {
+		panic("command must be a func returning an error")
+	}
+
+	in := []reflect.Type{lifecycleType}
+	for i := 0; i < fn.Type().NumIn(); i++ {
+		in = append(in, fn.Type().In(i))
+	}
+
+	return reflect.MakeFunc(reflect.FuncOf(in, nil, false), func(args []reflect.Value) []reflect.Value {
+		lc := args[0].Interface().(fx.Lifecycle)
+		lc.Append(fx.Hook{
+			OnStart: func(context.Context) error {
+				err, _ := fn.Call(args[1:])[0].Interface().(error)
+
+				return err
+			},
+		})
+
+		return nil
+	}).Interface()
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/EventStore/EventStore-Client-Go/esdb"
+	"github.com/tesarwijaya/ouroboros/internal/config"
+	event_service "github.com/tesarwijaya/ouroboros/internal/domain/event/service"
+	"github.com/tesarwijaya/ouroboros/internal/entry-point/grpc"
+	"github.com/tesarwijaya/ouroboros/internal/entry-point/rest"
+	"github.com/tesarwijaya/ouroboros/internal/worker"
+	"github.com/urfave/cli/v2"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/fx"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+)
+
+type serverResource struct {
+	fx.In
+	Lifecycle  fx.Lifecycle
+	Shutdowner fx.Shutdowner
+	Config     *config.Config
+	Logger     *zap.Logger
+	Tracer     *sdktrace.TracerProvider
+	Server     rest.RestServer
+	Grpc       grpc.GrpcServer
+	Workers    worker.Runner
+	Bus        event_service.EventBus
+	EventStore *esdb.Client
+	Db         *sql.DB
+}
+
+func newServerStartCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "server-start",
+		Usage: "start the fcking server!",
+		Action: func(*cli.Context) error {
+			var (
+				cfg *config.Config
+				log *zap.Logger
+			)
+
+			app := newApp(func(r serverResource) {
+				cfg = r.Config
+				log = r.Logger
+
+				r.Lifecycle.Append(fx.Hook{
+					OnStart: func(ctx context.Context) error {
+						ln, err := r.Server.Listen()
+						if err != nil {
+							return fmt.Errorf("listen on port %s: %w", r.Config.Port, err)
+						}
+
+						go func() {
+							if err := r.Server.Serve(ln); err != nil {
+								r.Logger.Error("http server stopped unexpectedly", zap.Error(err))
+
+								_ = r.Shutdowner.Shutdown()
+							}
+						}()
+						r.Logger.Info("http server listening", zap.Stringer("addr", ln.Addr()))
+
+						return r.Workers.Start(ctx)
+					},
+					OnStop: func(ctx context.Context) error {
+						var err error
+
+						r.Logger.Info("draining http requests")
+						err = multierr.Append(err, r.Server.Shutdown(ctx))
+
+						r.Logger.Info("stopping background workers")
+						err = multierr.Append(err, r.Workers.Stop(ctx))
+
+						r.Logger.Info("draining event handlers")
+						err = multierr.Append(err, r.Bus.Close(ctx))
+
+						r.Logger.Info("closing event store")
+						err = multierr.Append(err, r.EventStore.Close())
+
+						r.Logger.Info("closing db")
+						err = multierr.Append(err, r.Db.Close())
+
+						r.Logger.Info("flushing traces")
+						err = multierr.Append(err, r.Tracer.Shutdown(ctx))
+
+						if err != nil {
+							return err
+						}
+
+						r.Logger.Info("shutdown complete")
+						// stderr can't be synced on every platform, so its error is ignored
+						_ = r.Logger.Sync()
+
+						return nil
+					},
+				})
+
+				if !r.Config.GrpcEnabled {
+					return
+				}
+
+				// appended last so that it stops first, before the resources
+				// the calls rely on are closed
+				r.Lifecycle.Append(fx.Hook{
+					OnStart: func(ctx context.Context) error {
+						ln, err := r.Grpc.Listen()
+						if err != nil {
+							return fmt.Errorf("listen on grpc port %s: %w", r.Config.GrpcPort, err)
+						}
+
+						go func() {
+							if err := r.Grpc.Serve(ln); err != nil {
+								r.Logger.Error("grpc server stopped unexpectedly", zap.Error(err))
+
+								_ = r.Shutdowner.Shutdown()
+							}
+						}()
+						r.Logger.Info("grpc server listening", zap.Stringer("addr", ln.Addr()))
+
+						return nil
+					},
+					OnStop: func(ctx context.Context) error {
+						r.Logger.Info("draining grpc calls")
+
+						return r.Grpc.Shutdown(ctx)
+					},
+				})
+			})
+			if err := app.Err(); err != nil {
+				return err
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), cfg.StartTimeout)
+			defer cancel()
+
+			if err := app.Start(ctx); err != nil {
+				return err
+			}
+
+			signal := <-app.Done()
+			log.Info("shutting down", zap.Stringer("signal", signal))
+
+			ctxStop, cancelStop := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+			defer cancelStop()
+
+			return app.Stop(ctxStop)
+		},
+	}
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+
+	player_model "github.com/tesarwijaya/ouroboros/internal/domain/player/model"
+	player_repository "github.com/tesarwijaya/ouroboros/internal/domain/player/repository"
+	snapshot_service "github.com/tesarwijaya/ouroboros/internal/domain/snapshot/service"
+	"github.com/urfave/cli/v2"
+)
+
+func newSnapshotCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "snapshot",
+		Usage: "manage aggregate snapshots",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "rebuild",
+				Usage: "replay player streams from the start and store fresh snapshots",
+				Flags: []cli.Flag{
+					&cli.Int64SliceFlag{
+						Name:  "player-id",
+						Usage: "player to rebuild, every player when omitted",
+					},
+				},
+				Action: func(c *cli.Context) error {
+					return runCommand(c.Context, func(svc snapshot_service.SnapshotService, repo player_repository.PlayerRepository) error {
+						ids := c.Int64Slice("player-id")
+						if len(ids) == 0 {
+							players, err := repo.FindAll(c.Context)
+							if err != nil {
+								return err
+							}
+
+							for _, player := range players {
+								ids = append(ids, player.ID)
+							}
+						}
+
+						for _, id := range ids {
+							aggregate := player_model.NewPlayerAggregate(id)
+							if err := svc.Rebuild(c.Context, aggregate); err != nil {
+								return fmt.Errorf("rebuild %s: %w", aggregate.StreamID(), err)
+							}
+
+							fmt.Printf("rebuilt %s\n", aggregate.StreamID())
+						}
+
+						return nil
+					})
+				},
+			},
+			{
+				Name:  "delete",
+				Usage: "delete stored snapshots",
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{
+						Name:  "stream",
+						Usage: "stream to delete snapshots of, every stream when omitted",
+					},
+				},
+				Action: func(c *cli.Context) error {
+					return runCommand(c.Context, func(svc snapshot_service.SnapshotService) error {
+						streams := c.StringSlice("stream")
+						if len(streams) == 0 {
+							if err := svc.DeleteAll(c.Context); err != nil {
+								return err
+							}
+
+							fmt.Println("deleted all snapshots")
+
+							return nil
+						}
+
+						for _, stream := range streams {
+							if err := svc.Delete(c.Context, stream); err != nil {
+								return fmt.Errorf("delete %s: %w", stream, err)
+							}
+
+							fmt.Printf("deleted %s\n", stream)
+						}
+
+						return nil
+					})
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	command_service "github.com/tesarwijaya/ouroboros/internal/domain/command/service"
+	"github.com/tesarwijaya/ouroboros/internal/domain/team/model"
+	team_service "github.com/tesarwijaya/ouroboros/internal/domain/team/service"
+	"github.com/urfave/cli/v2"
+)
+
+func newTeamCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "team",
+		Usage: "manage teams",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "list",
+				Usage: "list teams",
+				Flags: []cli.Flag{
+					outputFlag(),
+				},
+				Action: func(c *cli.Context) error {
+					format, err := outputFormat(c)
+					if err != nil {
+						return err
+					}
+
+					return runCommand(c.Context, func(svc team_service.TeamService) error {
+						teams, err := svc.FindAll(c.Context)
+						if err != nil {
+							return err
+						}
+
+						return writeOutput(c.App.Writer, format, teamOutput(teams))
+					})
+				},
+			},
+			{
+				Name:  "get",
+				Usage: "show a team",
+				Flags: []cli.Flag{
+					&cli.Int64Flag{
+						Name:     "id",
+						Usage:    "id of the team",
+						Required: true,
+					},
+					outputFlag(),
+				},
+				Action: func(c *cli.Context) error {
+					format, err := outputFormat(c)
+					if err != nil {
+						return err
+					}
+
+					return runCommand(c.Context, func(svc team_service.TeamService) error {
+						team, err := svc.FindByID(c.Context, c.Int64("id"))
+						if err != nil {
+							return teamError(c.Int64("id"), err)
+						}
+
+						return writeOutput(c.App.Writer, format, teamItemOutput(team))
+					})
+				},
+			},
+			{
+				Name:  "create",
+				Usage: "create a team",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "name",
+						Usage:    "name of the team",
+						Required: true,
+					},
+					outputFlag(),
+					dryRunFlag(),
+				},
+				Action: func(c *cli.Context) error {
+					format, err := outputFormat(c)
+					if err != nil {
+						return err
+					}
+
+					return runCommand(c.Context, func(bus command_service.CommandBus) error {
+						team, err := command_service.Dispatch[model.TeamModel](commandContext(c), bus, team_service.InsertTeamCommand{
+							Payload: model.TeamModel{Name: c.String("name")},
+						})
+						if err != nil {
+							return err
+						}
+
+						return writeResult(c, format, teamItemOutput(team))
+					})
+				},
+			},
+			{
+				Name:  "rename",
+				Usage: "rename a team",
+				Flags: []cli.Flag{
+					&cli.Int64Flag{
+						Name:     "id",
+						Usage:    "id of the team",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:     "name",
+						Usage:    "new name of the team",
+						Required: true,
+					},
+					outputFlag(),
+					dryRunFlag(),
+				},
+				Action: func(c *cli.Context) error {
+					format, err := outputFormat(c)
+					if err != nil {
+						return err
+					}
+
+					return runCommand(c.Context, func(bus command_service.CommandBus) error {
+						team, err := command_service.Dispatch[model.TeamModel](commandContext(c), bus, team_service.RenameTeamCommand{
+							ID:   c.Int64("id"),
+							Name: c.String("name"),
+						})
+						if err != nil {
+							return teamError(c.Int64("id"), err)
+						}
+
+						return writeResult(c, format, teamItemOutput(team))
+					})
+				},
+			},
+			{
+				Name:  "delete",
+				Usage: "delete a team without players",
+				Flags: []cli.Flag{
+					&cli.Int64Flag{
+						Name:     "id",
+						Usage:    "id of the team",
+						Required: true,
+					},
+					outputFlag(),
+					dryRunFlag(),
+				},
+				Action: func(c *cli.Context) error {
+					format, err := outputFormat(c)
+					if err != nil {
+						return err
+					}
+
+					return runCommand(c.Context, func(bus command_service.CommandBus) error {
+						team, err := command_service.Dispatch[model.TeamModel](commandContext(c), bus, team_service.DeleteTeamCommand{
+							ID: c.Int64("id"),
+						})
+						if err != nil {
+							return teamError(c.Int64("id"), err)
+						}
+
+						return writeResult(c, format, teamItemOutput(team))
+					})
+				},
+			},
+		},
+	}
+}
+
+func teamError(id int64, err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("team %d not found", id)
+	}
+
+	return err
+}
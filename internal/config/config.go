@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
 )
@@ -8,6 +10,40 @@ import (
 type Config struct {
 	Port string `envconfig:"PORT" default:"8000"`
 
+	StartTimeout    time.Duration `envconfig:"APP_START_TIMEOUT" default:"5s"`
+	ShutdownTimeout time.Duration `envconfig:"APP_SHUTDOWN_TIMEOUT" default:"15s"`
+
+	GrpcEnabled bool   `envconfig:"APP_GRPC_ENABLED" default:"true"`
+	GrpcPort    string `envconfig:"APP_GRPC_PORT" default:"9000"`
+
+	TLSCertFile string `envconfig:"APP_TLS_CERT_FILE"`
+	TLSKeyFile  string `envconfig:"APP_TLS_KEY_FILE"`
+
+	CORSAllowOrigins []string `envconfig:"APP_CORS_ALLOW_ORIGINS"`
+	CORSAllowMethods []string `envconfig:"APP_CORS_ALLOW_METHODS" default:"GET,HEAD,PUT,POST,PATCH,DELETE"`
+	CORSAllowHeaders []string `envconfig:"APP_CORS_ALLOW_HEADERS" default:"Authorization,Content-Type,X-API-Key,Idempotency-Key,traceparent"`
+
+	BodyLimit  string `envconfig:"APP_BODY_LIMIT" default:"1M"`
+	HSTSMaxAge int    `envconfig:"APP_HSTS_MAX_AGE" default:"31536000"`
+
+	TrustedProxies []string `envconfig:"APP_TRUSTED_PROXIES"`
+
+	LogLevel  string `envconfig:"APP_LOG_LEVEL" default:"info"`
+	LogFormat string `envconfig:"APP_LOG_FORMAT" default:"json"`
+
+	TracingExporter     string  `envconfig:"APP_TRACING_EXPORTER" default:"none"`
+	TracingSampleRatio  float64 `envconfig:"APP_TRACING_SAMPLE_RATIO" default:"1"`
+	TracingOTLPEndpoint string  `envconfig:"APP_TRACING_OTLP_ENDPOINT" default:"localhost:4317"`
+	TracingOTLPInsecure bool    `envconfig:"APP_TRACING_OTLP_INSECURE" default:"true"`
+
+	AuthEnabled            bool   `envconfig:"APP_AUTH_ENABLED" default:"true"`
+	AuthIssuer             string `envconfig:"APP_AUTH_ISSUER"`
+	AuthAudience           string `envconfig:"APP_AUTH_AUDIENCE"`
+	AuthHS256Secret        string `envconfig:"APP_AUTH_HS256_SECRET"`
+	AuthRS256PublicKeyFile string `envconfig:"APP_AUTH_RS256_PUBLIC_KEY_FILE"`
+	AuthJWKSFile           string `envconfig:"APP_AUTH_JWKS_FILE"`
+	AuthPolicyFile         string `envconfig:"APP_AUTH_POLICY_FILE" default:"policy.yaml"`
+
 	SqlDBHost     string `envconfig:"APP_SQL_DB_HOST" default:"ouroboros-sql-db"`
 	SqlDBPort     int64  `envconfig:"APP_SQL_DB_PORT" default:"5432"`
 	SqlDBUsername string `envconfig:"APP_SQL_DB_USERNAME" default:"root"`
@@ -16,6 +52,31 @@ type Config struct {
 
 	EventStoreDBHost string `envconfig:"APP_EVENT_STORE_DB_HOST" default:"eventstoredb"`
 	EventStoreDBPort int64  `envconfig:"APP_EVENT_STORE_DB_PORT" default:"1113"`
+
+	SnapshotFrequency int64 `envconfig:"APP_SNAPSHOT_FREQUENCY" default:"100"`
+
+	HealthzCheckTimeout     time.Duration `envconfig:"APP_HEALTHZ_CHECK_TIMEOUT" default:"2s"`
+	HealthzMaxProjectionLag time.Duration `envconfig:"APP_HEALTHZ_MAX_PROJECTION_LAG" default:"30s"`
+
+	RateLimitEnabled       bool          `envconfig:"APP_RATE_LIMIT_ENABLED" default:"true"`
+	RateLimitStore         string        `envconfig:"APP_RATE_LIMIT_STORE" default:"memory"`
+	RateLimitReadRate      float64       `envconfig:"APP_RATE_LIMIT_READ_RATE" default:"20"`
+	RateLimitReadBurst     int64         `envconfig:"APP_RATE_LIMIT_READ_BURST" default:"40"`
+	RateLimitWriteRate     float64       `envconfig:"APP_RATE_LIMIT_WRITE_RATE" default:"5"`
+	RateLimitWriteBurst    int64         `envconfig:"APP_RATE_LIMIT_WRITE_BURST" default:"10"`
+	RateLimitIPRate        float64       `envconfig:"APP_RATE_LIMIT_IP_RATE" default:"50"`
+	RateLimitIPBurst       int64         `envconfig:"APP_RATE_LIMIT_IP_BURST" default:"100"`
+	RateLimitPurgeInterval time.Duration `envconfig:"APP_RATE_LIMIT_PURGE_INTERVAL" default:"1h"`
+
+	IdempotencyTTL           time.Duration `envconfig:"APP_IDEMPOTENCY_TTL" default:"24h"`
+	IdempotencyPurgeInterval time.Duration `envconfig:"APP_IDEMPOTENCY_PURGE_INTERVAL" default:"1h"`
+
+	OfferTTL            time.Duration `envconfig:"APP_OFFER_TTL" default:"72h"`
+	OfferMaxTTL         time.Duration `envconfig:"APP_OFFER_MAX_TTL" default:"720h"`
+	OfferExpiryInterval time.Duration `envconfig:"APP_OFFER_EXPIRY_INTERVAL" default:"1m"`
+
+	OutboxRelayInterval time.Duration `envconfig:"APP_OUTBOX_RELAY_INTERVAL" default:"10s"`
+	OutboxMaxAttempts   int           `envconfig:"APP_OUTBOX_MAX_ATTEMPTS" default:"5"`
 }
 
 func NewConfig() (*Config, error) {
@@ -0,0 +1,130 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/tesarwijaya/ouroboros/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Querier is implemented by both *sql.DB and *sql.Tx. The helpers below run
+// their statement in the transaction started by WithTx when ctx carries one.
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+type txKey struct{}
+
+// txState is the transaction ctx is in along the funcs to call once it is
+// committed.
+type txState struct {
+	tx          *sql.Tx
+	afterCommit []func(ctx context.Context)
+}
+
+// WithTx runs fn in a transaction, the statements run with the ctx given to fn
+// join it. The transaction is committed when fn returns nil and rolled back
+// otherwise, fn simply joins the transaction ctx is already in.
+func WithTx(ctx context.Context, db *sql.DB, fn func(ctx context.Context) error) (err error) {
+	if _, ok := ctx.Value(txKey{}).(*txState); ok {
+		return fn(ctx)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			_ = tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	state := &txState{tx: tx}
+	if err := fn(context.WithValue(ctx, txKey{}, state)); err != nil {
+		// the error of fn is the one worth reporting
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	for _, fn := range state.afterCommit {
+		fn(ctx)
+	}
+
+	return nil
+}
+
+// AfterCommit calls fn once the transaction ctx is in is committed, it is
+// never called when the transaction is rolled back. fn is called right away
+// when ctx isn't in a transaction.
+func AfterCommit(ctx context.Context, fn func(ctx context.Context)) {
+	state, ok := ctx.Value(txKey{}).(*txState)
+	if !ok {
+		fn(ctx)
+		return
+	}
+
+	state.afterCommit = append(state.afterCommit, fn)
+}
+
+// conn returns the transaction ctx is in, db otherwise.
+func conn(ctx context.Context, db Querier) Querier {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return state.tx
+	}
+
+	return db
+}
+
+// Query runs a statement returning rows inside its own span.
+func Query(ctx context.Context, db Querier, query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, span := startSpan(ctx, query)
+
+	rows, err := conn(ctx, db).QueryContext(ctx, query, args...)
+	tracing.End(span, err)
+
+	return rows, err
+}
+
+// QueryRow runs a statement returning at most one row inside its own span,
+// the error is reported by Scan so it is not recorded on the span.
+func QueryRow(ctx context.Context, db Querier, query string, args ...interface{}) *sql.Row {
+	ctx, span := startSpan(ctx, query)
+	defer span.End()
+
+	return conn(ctx, db).QueryRowContext(ctx, query, args...)
+}
+
+// Exec runs a statement without rows inside its own span.
+func Exec(ctx context.Context, db Querier, query string, args ...interface{}) (sql.Result, error) {
+	ctx, span := startSpan(ctx, query)
+
+	res, err := conn(ctx, db).ExecContext(ctx, query, args...)
+	tracing.End(span, err)
+
+	return res, err
+}
+
+func startSpan(ctx context.Context, query string) (context.Context, trace.Span) {
+	operation := query
+	if i := strings.IndexByte(query, ' '); i > 0 {
+		operation = query[:i]
+	}
+
+	return tracing.Start(ctx, "db."+strings.ToLower(operation),
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.operation", operation),
+		attribute.String("db.statement", query),
+	)
+}
@@ -0,0 +1,157 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/tesarwijaya/ouroboros/internal/database"
+)
+
+func Test_WithTx(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		MockFn    func(db sqlmock.Sqlmock)
+		Fn        func(ctx context.Context, db database.Querier) error
+		ExpectErr error
+	}{
+		{
+			Name: "when_fn_succeeds",
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectBegin()
+				db.ExpectExec(regexp.QuoteMeta("DELETE FROM team")).WillReturnResult(sqlmock.NewResult(0, 1))
+				db.ExpectExec(regexp.QuoteMeta("DELETE FROM player")).WillReturnResult(sqlmock.NewResult(0, 1))
+				db.ExpectCommit()
+			},
+			Fn: func(ctx context.Context, db database.Querier) error {
+				if _, err := database.Exec(ctx, db, "DELETE FROM team"); err != nil {
+					return err
+				}
+
+				_, err := database.Exec(ctx, db, "DELETE FROM player")
+				return err
+			},
+		},
+		{
+			Name: "when_fn_fails",
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectBegin()
+				db.ExpectExec(regexp.QuoteMeta("DELETE FROM team")).WillReturnError(errors.New("some-error"))
+				db.ExpectRollback()
+			},
+			Fn: func(ctx context.Context, db database.Querier) error {
+				_, err := database.Exec(ctx, db, "DELETE FROM team")
+				return err
+			},
+			ExpectErr: errors.New("some-error"),
+		},
+		{
+			Name: "when_begin_fails",
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectBegin().WillReturnError(errors.New("some-error"))
+			},
+			Fn: func(ctx context.Context, db database.Querier) error {
+				return nil
+			},
+			ExpectErr: errors.New("some-error"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			test.MockFn(mock)
+
+			err := database.WithTx(context.Background(), db, func(ctx context.Context) error {
+				return test.Fn(ctx, db)
+			})
+
+			assert.Equal(t, test.ExpectErr, err)
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_WithTx_Nested(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM team")).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := database.WithTx(context.Background(), db, func(ctx context.Context) error {
+		return database.WithTx(ctx, db, func(ctx context.Context) error {
+			_, err := database.Exec(ctx, db, "DELETE FROM team")
+			return err
+		})
+	})
+
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_AfterCommit(t *testing.T) {
+	testCases := []struct {
+		Name         string
+		MockFn       func(db sqlmock.Sqlmock)
+		FnErr        error
+		ExpectCalled bool
+	}{
+		{
+			Name: "when_committed",
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectBegin()
+				db.ExpectCommit()
+			},
+			ExpectCalled: true,
+		},
+		{
+			Name: "when_rolled_back",
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectBegin()
+				db.ExpectRollback()
+			},
+			FnErr: errors.New("some-error"),
+		},
+		{
+			Name: "when_commit_fails",
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectBegin()
+				db.ExpectCommit().WillReturnError(errors.New("some-error"))
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			test.MockFn(mock)
+
+			called := false
+			_ = database.WithTx(context.Background(), db, func(ctx context.Context) error {
+				database.AfterCommit(ctx, func(ctx context.Context) { called = true })
+				assert.False(t, called)
+
+				return test.FnErr
+			})
+
+			assert.Equal(t, test.ExpectCalled, called)
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Run("when_not_in_transaction", func(t *testing.T) {
+		called := false
+		database.AfterCommit(context.Background(), func(ctx context.Context) { called = true })
+
+		assert.True(t, called)
+	})
+}
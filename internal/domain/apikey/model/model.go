@@ -0,0 +1,33 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidAPIKey  = errors.New("invalid api key")
+	ErrAPIKeyNotFound = errors.New("api key not found")
+)
+
+// APIKeyModel never holds the key itself, only its hash. ExpiresAt,
+// LastUsedAt and RevokedAt are nil until set.
+type APIKeyModel struct {
+	ID         int64      `db:"id" json:"id"`
+	Name       string     `db:"name" json:"name"`
+	KeyHash    string     `db:"key_hash" json:"-"`
+	Scopes     []string   `db:"scopes" json:"scopes"`
+	CreatedAt  time.Time  `db:"created_at" json:"createdAt"`
+	ExpiresAt  *time.Time `db:"expires_at" json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `db:"last_used_at" json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `db:"revoked_at" json:"revokedAt,omitempty"`
+}
+
+// Active reports whether the key can still be used at the given time.
+func (m APIKeyModel) Active(at time.Time) bool {
+	if m.RevokedAt != nil {
+		return false
+	}
+
+	return m.ExpiresAt == nil || at.Before(*m.ExpiresAt)
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/lib/pq"
+	"github.com/tesarwijaya/ouroboros/internal/database"
+	"github.com/tesarwijaya/ouroboros/internal/domain/apikey/model"
+	"github.com/tesarwijaya/ouroboros/internal/metrics"
+	"go.uber.org/dig"
+)
+
+const (
+	API_KEYS_TABLE_NAME = "api_keys"
+)
+
+var columns = []string{"id", "name", "key_hash", "scopes", "created_at", "expires_at", "last_used_at", "revoked_at"}
+
+type APIKeyRepository interface {
+	FindAll(ctx context.Context) ([]model.APIKeyModel, error)
+	FindByHash(ctx context.Context, keyHash string) (model.APIKeyModel, error)
+	Insert(ctx context.Context, payload model.APIKeyModel) (model.APIKeyModel, error)
+	Revoke(ctx context.Context, id int64, at time.Time) error
+	Touch(ctx context.Context, id int64, at time.Time) error
+}
+
+type APIKeyRepositoryImpl struct {
+	dig.In
+	Db *sql.DB
+}
+
+func NewAPIKeyRepository(repo APIKeyRepositoryImpl) APIKeyRepository {
+	return &repo
+}
+
+func (r *APIKeyRepositoryImpl) FindAll(ctx context.Context) ([]model.APIKeyModel, error) {
+	defer metrics.ObserveQuery("apikey", "FindAll")()
+
+	res := []model.APIKeyModel{}
+	q := sqlbuilder.NewSelectBuilder()
+	query, args := q.Select(columns...).
+		From(API_KEYS_TABLE_NAME).
+		OrderBy("id").
+		BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	rows, err := database.Query(ctx, r.Db, query, args...)
+	if err != nil {
+		return []model.APIKeyModel{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		data, err := scan(rows)
+		if err != nil {
+			return []model.APIKeyModel{}, err
+		}
+
+		res = append(res, data)
+	}
+
+	if err := rows.Err(); err != nil {
+		return []model.APIKeyModel{}, err
+	}
+
+	return res, nil
+}
+
+func (r *APIKeyRepositoryImpl) FindByHash(ctx context.Context, keyHash string) (model.APIKeyModel, error) {
+	defer metrics.ObserveQuery("apikey", "FindByHash")()
+
+	q := sqlbuilder.NewSelectBuilder()
+	query, args := q.Select(columns...).
+		From(API_KEYS_TABLE_NAME).
+		Where(q.Equal("key_hash", keyHash)).
+		BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	res, err := scan(database.QueryRow(ctx, r.Db, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.APIKeyModel{}, model.ErrAPIKeyNotFound
+	}
+
+	if err != nil {
+		return model.APIKeyModel{}, err
+	}
+
+	return res, nil
+}
+
+func (r *APIKeyRepositoryImpl) Insert(ctx context.Context, payload model.APIKeyModel) (model.APIKeyModel, error) {
+	defer metrics.ObserveQuery("apikey", "Insert")()
+
+	q := sqlbuilder.NewInsertBuilder()
+	query, args := q.InsertInto(API_KEYS_TABLE_NAME).
+		Cols("name", "key_hash", "scopes", "expires_at").
+		Values(payload.Name, payload.KeyHash, pq.Array(payload.Scopes), payload.ExpiresAt).
+		SQL("RETURNING " + strings.Join(columns, ", ")).
+		BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	res, err := scan(database.QueryRow(ctx, r.Db, query, args...))
+	if err != nil {
+		return model.APIKeyModel{}, err
+	}
+
+	return res, nil
+}
+
+// Revoke is idempotent, revoking a revoked key keeps its first revocation
+// time.
+func (r *APIKeyRepositoryImpl) Revoke(ctx context.Context, id int64, at time.Time) error {
+	defer metrics.ObserveQuery("apikey", "Revoke")()
+
+	q := sqlbuilder.NewUpdateBuilder()
+	query, args := q.Update(API_KEYS_TABLE_NAME).
+		Set("revoked_at = COALESCE(revoked_at, " + q.Var(at) + ")").
+		Where(q.Equal("id", id)).
+		BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	res, err := database.Exec(ctx, r.Db, query, args...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return model.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+func (r *APIKeyRepositoryImpl) Touch(ctx context.Context, id int64, at time.Time) error {
+	defer metrics.ObserveQuery("apikey", "Touch")()
+
+	q := sqlbuilder.NewUpdateBuilder()
+	query, args := q.Update(API_KEYS_TABLE_NAME).
+		Set(q.Assign("last_used_at", at)).
+		Where(q.Equal("id", id)).
+		BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	_, err := database.Exec(ctx, r.Db, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scan(row scanner) (model.APIKeyModel, error) {
+	var res model.APIKeyModel
+
+	err := row.Scan(
+		&res.ID,
+		&res.Name,
+		&res.KeyHash,
+		pq.Array(&res.Scopes),
+		&res.CreatedAt,
+		&res.ExpiresAt,
+		&res.LastUsedAt,
+		&res.RevokedAt,
+	)
+
+	return res, err
+}
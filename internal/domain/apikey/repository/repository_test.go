@@ -0,0 +1,225 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/tesarwijaya/ouroboros/internal/domain/apikey/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/apikey/repository"
+)
+
+type mockFn func(db sqlmock.Sqlmock)
+
+var (
+	columns   = []string{"id", "name", "key_hash", "scopes", "created_at", "expires_at", "last_used_at", "revoked_at"}
+	createdAt = time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func createRepo(mockFn mockFn) repository.APIKeyRepository {
+	db, mock, _ := sqlmock.New()
+
+	mockFn(mock)
+	repo := repository.NewAPIKeyRepository(repository.APIKeyRepositoryImpl{
+		Db: db,
+	})
+
+	return repo
+}
+
+func row() []driver.Value {
+	return []driver.Value{int64(1), "some-name", "some-hash", "{players:read,teams:read}", createdAt, nil, nil, nil}
+}
+
+func Test_FindAll(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		MockFn      mockFn
+		Expected    []model.APIKeyModel
+		ExpectedErr string
+	}{
+		{
+			Name: "when_data_present",
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(regexp.QuoteMeta("SELECT id, name, key_hash, scopes, created_at, expires_at, last_used_at, revoked_at FROM api_keys ORDER BY id")).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(row()...))
+			},
+			Expected: []model.APIKeyModel{{
+				ID:        1,
+				Name:      "some-name",
+				KeyHash:   "some-hash",
+				Scopes:    []string{"players:read", "teams:read"},
+				CreatedAt: createdAt,
+			}},
+		},
+		{
+			Name: "when_data_empty",
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(regexp.QuoteMeta("SELECT id, name, key_hash, scopes, created_at, expires_at, last_used_at, revoked_at FROM api_keys ORDER BY id")).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			Expected: []model.APIKeyModel{},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := createRepo(test.MockFn)
+
+			actual, err := repo.FindAll(context.Background())
+			if test.ExpectedErr != "" {
+				assert.EqualError(t, err, test.ExpectedErr)
+			} else {
+				assert.Equal(t, test.Expected, actual)
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
+func Test_FindByHash(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		Param       string
+		MockFn      mockFn
+		Expected    model.APIKeyModel
+		ExpectedErr error
+	}{
+		{
+			Name:  "when_data_present",
+			Param: "some-hash",
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(regexp.QuoteMeta("SELECT id, name, key_hash, scopes, created_at, expires_at, last_used_at, revoked_at FROM api_keys WHERE key_hash = $1")).
+					WithArgs("some-hash").
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(row()...))
+			},
+			Expected: model.APIKeyModel{
+				ID:        1,
+				Name:      "some-name",
+				KeyHash:   "some-hash",
+				Scopes:    []string{"players:read", "teams:read"},
+				CreatedAt: createdAt,
+			},
+		},
+		{
+			Name:  "when_data_not_present",
+			Param: "some-hash",
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(regexp.QuoteMeta("SELECT id, name, key_hash, scopes, created_at, expires_at, last_used_at, revoked_at FROM api_keys WHERE key_hash = $1")).
+					WithArgs("some-hash").
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			ExpectedErr: model.ErrAPIKeyNotFound,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := createRepo(test.MockFn)
+
+			actual, err := repo.FindByHash(context.Background(), test.Param)
+			if test.ExpectedErr != nil {
+				assert.ErrorIs(t, err, test.ExpectedErr)
+			} else {
+				assert.Equal(t, test.Expected, actual)
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
+func Test_Insert(t *testing.T) {
+	expiresAt := createdAt.Add(time.Hour)
+
+	testCases := []struct {
+		Name        string
+		Param       model.APIKeyModel
+		MockFn      mockFn
+		Expected    model.APIKeyModel
+		ExpectedErr string
+	}{
+		{
+			Name: "when_success",
+			Param: model.APIKeyModel{
+				Name:      "some-name",
+				KeyHash:   "some-hash",
+				Scopes:    []string{"players:read"},
+				ExpiresAt: &expiresAt,
+			},
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(regexp.QuoteMeta("INSERT INTO api_keys (name, key_hash, scopes, expires_at) VALUES ($1, $2, $3, $4) RETURNING id, name, key_hash, scopes, created_at, expires_at, last_used_at, revoked_at")).
+					WithArgs("some-name", "some-hash", "{\"players:read\"}", &expiresAt).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(int64(1), "some-name", "some-hash", "{players:read}", createdAt, expiresAt, nil, nil))
+			},
+			Expected: model.APIKeyModel{
+				ID:        1,
+				Name:      "some-name",
+				KeyHash:   "some-hash",
+				Scopes:    []string{"players:read"},
+				CreatedAt: createdAt,
+				ExpiresAt: &expiresAt,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := createRepo(test.MockFn)
+
+			actual, err := repo.Insert(context.Background(), test.Param)
+			if test.ExpectedErr != "" {
+				assert.EqualError(t, err, test.ExpectedErr)
+			} else {
+				assert.Equal(t, test.Expected, actual)
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
+func Test_Revoke(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		Param       int64
+		MockFn      mockFn
+		ExpectedErr error
+	}{
+		{
+			Name:  "when_success",
+			Param: 1,
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectExec(regexp.QuoteMeta("UPDATE api_keys SET revoked_at = COALESCE(revoked_at, $1) WHERE id = $2")).
+					WithArgs(createdAt, int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			Name:  "when_data_not_present",
+			Param: 1,
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectExec(regexp.QuoteMeta("UPDATE api_keys SET revoked_at = COALESCE(revoked_at, $1) WHERE id = $2")).
+					WithArgs(createdAt, int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			ExpectedErr: model.ErrAPIKeyNotFound,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := createRepo(test.MockFn)
+
+			err := repo.Revoke(context.Background(), test.Param, createdAt)
+			if test.ExpectedErr != nil {
+				assert.ErrorIs(t, err, test.ExpectedErr)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/tesarwijaya/ouroboros/internal/domain/apikey/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/apikey/repository"
+	auth_model "github.com/tesarwijaya/ouroboros/internal/domain/auth/model"
+	"github.com/tesarwijaya/ouroboros/internal/logger"
+	"github.com/tesarwijaya/ouroboros/internal/tracing"
+	"go.uber.org/dig"
+	"go.uber.org/zap"
+)
+
+const (
+	KEY_PREFIX = "ouro_"
+
+	// lastUsedResolution limits the last used time updates to one write
+	// per key per minute, however many requests the key makes.
+	lastUsedResolution = time.Minute
+)
+
+type (
+	CreatePayload struct {
+		Name      string
+		Scopes    []string
+		ExpiresIn time.Duration
+	}
+)
+
+type APIKeyService interface {
+	FindAll(ctx context.Context) ([]model.APIKeyModel, error)
+	Create(ctx context.Context, payload CreatePayload) (model.APIKeyModel, string, error)
+	Revoke(ctx context.Context, id int64) error
+	Authenticate(ctx context.Context, key string) (auth_model.Claims, error)
+}
+
+type APIKeyServiceImpl struct {
+	dig.In
+	Repo repository.APIKeyRepository
+}
+
+func NewAPIKeyService(svc APIKeyServiceImpl) APIKeyService {
+	return &svc
+}
+
+func (s *APIKeyServiceImpl) FindAll(ctx context.Context) ([]model.APIKeyModel, error) {
+	ctx, span := tracing.Start(ctx, "APIKeyService.FindAll")
+	defer span.End()
+
+	return s.Repo.FindAll(ctx)
+}
+
+// Create generates a new key and stores its hash, the key itself is returned
+// once and can't be recovered afterwards. A zero ExpiresIn never expires.
+func (s *APIKeyServiceImpl) Create(ctx context.Context, payload CreatePayload) (model.APIKeyModel, string, error) {
+	ctx, span := tracing.Start(ctx, "APIKeyService.Create")
+	defer span.End()
+
+	if payload.Name == "" {
+		return model.APIKeyModel{}, "", errors.New("api key name is required")
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return model.APIKeyModel{}, "", err
+	}
+	key := KEY_PREFIX + base64.RawURLEncoding.EncodeToString(secret)
+
+	apiKey := model.APIKeyModel{
+		Name:    payload.Name,
+		KeyHash: hash(key),
+		Scopes:  payload.Scopes,
+	}
+	if apiKey.Scopes == nil {
+		apiKey.Scopes = []string{}
+	}
+	if payload.ExpiresIn > 0 {
+		expiresAt := time.Now().Add(payload.ExpiresIn)
+		apiKey.ExpiresAt = &expiresAt
+	}
+
+	res, err := s.Repo.Insert(ctx, apiKey)
+	if err != nil {
+		return model.APIKeyModel{}, "", err
+	}
+
+	return res, key, nil
+}
+
+func (s *APIKeyServiceImpl) Revoke(ctx context.Context, id int64) error {
+	ctx, span := tracing.Start(ctx, "APIKeyService.Revoke")
+	defer span.End()
+
+	return s.Repo.Revoke(ctx, id, time.Now())
+}
+
+// Authenticate maps an active key to claims carrying its scopes, so that it
+// goes through the same authorization policies as a user.
+func (s *APIKeyServiceImpl) Authenticate(ctx context.Context, key string) (auth_model.Claims, error) {
+	ctx, span := tracing.Start(ctx, "APIKeyService.Authenticate")
+	defer span.End()
+
+	apiKey, err := s.Repo.FindByHash(ctx, hash(key))
+	if errors.Is(err, model.ErrAPIKeyNotFound) {
+		return auth_model.Claims{}, model.ErrInvalidAPIKey
+	}
+
+	if err != nil {
+		return auth_model.Claims{}, err
+	}
+
+	now := time.Now()
+	if !apiKey.Active(now) {
+		return auth_model.Claims{}, model.ErrInvalidAPIKey
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= lastUsedResolution {
+		// failing to record the usage must not reject a valid key
+		if err := s.Repo.Touch(ctx, apiKey.ID, now); err != nil {
+			logger.FromContext(ctx).Warn("failed to update api key last used time", zap.Int64("api_key_id", apiKey.ID), zap.Error(err))
+		}
+	}
+
+	return auth_model.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject: fmt.Sprintf("apikey:%d", apiKey.ID),
+		},
+		Scopes: apiKey.Scopes,
+	}, nil
+}
+
+func hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(sum[:])
+}
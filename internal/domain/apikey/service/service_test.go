@@ -0,0 +1,167 @@
+package service_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/tesarwijaya/ouroboros/internal/domain/apikey/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/apikey/repository"
+	"github.com/tesarwijaya/ouroboros/internal/domain/apikey/service"
+)
+
+type resolverFn func(repo *repository.MockAPIKeyRepository)
+
+func createService(t *testing.T, resolver resolverFn) service.APIKeyService {
+	ctrl := gomock.NewController(t)
+
+	repo := repository.NewMockAPIKeyRepository(ctrl)
+	resolver(repo)
+
+	return service.NewAPIKeyService(service.APIKeyServiceImpl{
+		Repo: repo,
+	})
+}
+
+func hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func Test_Create(t *testing.T) {
+	t.Run("when_success", func(t *testing.T) {
+		var stored model.APIKeyModel
+		svc := createService(t, func(repo *repository.MockAPIKeyRepository) {
+			repo.EXPECT().Insert(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, payload model.APIKeyModel) (model.APIKeyModel, error) {
+				stored = payload
+				payload.ID = 1
+
+				return payload, nil
+			})
+		})
+
+		actual, key, err := svc.Create(context.Background(), service.CreatePayload{
+			Name:      "some-name",
+			Scopes:    []string{"players:read"},
+			ExpiresIn: time.Hour,
+		})
+
+		assert.Nil(t, err)
+		assert.True(t, strings.HasPrefix(key, service.KEY_PREFIX))
+		assert.Equal(t, hash(key), stored.KeyHash)
+		assert.Equal(t, int64(1), actual.ID)
+		assert.Equal(t, []string{"players:read"}, actual.Scopes)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), *actual.ExpiresAt, time.Minute)
+	})
+
+	t.Run("when_name_empty", func(t *testing.T) {
+		svc := createService(t, func(repo *repository.MockAPIKeyRepository) {})
+
+		_, _, err := svc.Create(context.Background(), service.CreatePayload{})
+
+		assert.EqualError(t, err, "api key name is required")
+	})
+
+	t.Run("when_never_expires", func(t *testing.T) {
+		svc := createService(t, func(repo *repository.MockAPIKeyRepository) {
+			repo.EXPECT().Insert(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, payload model.APIKeyModel) (model.APIKeyModel, error) {
+				return payload, nil
+			})
+		})
+
+		actual, _, err := svc.Create(context.Background(), service.CreatePayload{Name: "some-name"})
+
+		assert.Nil(t, err)
+		assert.Nil(t, actual.ExpiresAt)
+		assert.Equal(t, []string{}, actual.Scopes)
+	})
+}
+
+func Test_Authenticate(t *testing.T) {
+	key := "ouro_some-key"
+	past := time.Now().Add(-time.Hour)
+	recent := time.Now().Add(-time.Second)
+
+	testCases := []struct {
+		Name         string
+		Resolver     resolverFn
+		ExpectedSub  string
+		ExpectScopes []string
+		ExpectErr    string
+	}{
+		{
+			Name: "when_key_unknown",
+			Resolver: func(repo *repository.MockAPIKeyRepository) {
+				repo.EXPECT().FindByHash(gomock.Any(), hash(key)).Return(model.APIKeyModel{}, model.ErrAPIKeyNotFound)
+			},
+			ExpectErr: "invalid api key",
+		},
+		{
+			Name: "when_store_failed",
+			Resolver: func(repo *repository.MockAPIKeyRepository) {
+				repo.EXPECT().FindByHash(gomock.Any(), hash(key)).Return(model.APIKeyModel{}, errors.New("some-error"))
+			},
+			ExpectErr: "some-error",
+		},
+		{
+			Name: "when_key_revoked",
+			Resolver: func(repo *repository.MockAPIKeyRepository) {
+				repo.EXPECT().FindByHash(gomock.Any(), hash(key)).Return(model.APIKeyModel{ID: 1, RevokedAt: &past}, nil)
+			},
+			ExpectErr: "invalid api key",
+		},
+		{
+			Name: "when_key_expired",
+			Resolver: func(repo *repository.MockAPIKeyRepository) {
+				repo.EXPECT().FindByHash(gomock.Any(), hash(key)).Return(model.APIKeyModel{ID: 1, ExpiresAt: &past}, nil)
+			},
+			ExpectErr: "invalid api key",
+		},
+		{
+			Name: "when_key_active",
+			Resolver: func(repo *repository.MockAPIKeyRepository) {
+				repo.EXPECT().FindByHash(gomock.Any(), hash(key)).Return(model.APIKeyModel{ID: 1, Scopes: []string{"players:read"}, LastUsedAt: &past}, nil)
+				repo.EXPECT().Touch(gomock.Any(), int64(1), gomock.Any()).Return(nil)
+			},
+			ExpectedSub:  "apikey:1",
+			ExpectScopes: []string{"players:read"},
+		},
+		{
+			Name: "when_key_recently_used",
+			Resolver: func(repo *repository.MockAPIKeyRepository) {
+				repo.EXPECT().FindByHash(gomock.Any(), hash(key)).Return(model.APIKeyModel{ID: 1, LastUsedAt: &recent}, nil)
+			},
+			ExpectedSub: "apikey:1",
+		},
+		{
+			Name: "when_touch_failed",
+			Resolver: func(repo *repository.MockAPIKeyRepository) {
+				repo.EXPECT().FindByHash(gomock.Any(), hash(key)).Return(model.APIKeyModel{ID: 1}, nil)
+				repo.EXPECT().Touch(gomock.Any(), int64(1), gomock.Any()).Return(errors.New("some-error"))
+			},
+			ExpectedSub: "apikey:1",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			svc := createService(t, test.Resolver)
+
+			actual, err := svc.Authenticate(context.Background(), key)
+			if test.ExpectErr != "" {
+				assert.EqualError(t, err, test.ExpectErr)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.ExpectedSub, actual.Subject)
+				assert.Equal(t, test.ExpectScopes, actual.Scopes)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+var (
+	ErrMissingToken = errors.New("missing bearer token")
+	ErrInvalidToken = errors.New("invalid token")
+)
+
+// Claims are the verified claims of the caller. Roles, TeamIDs and Scopes are
+// private claims issued by our identity provider. Process and OnBehalfOf are
+// only set by AsProcess, never read from a token.
+type Claims struct {
+	jwt.RegisteredClaims
+	Roles   []string `json:"roles,omitempty"`
+	TeamIDs []int64  `json:"team_ids,omitempty"`
+	Scopes  []string `json:"scopes,omitempty"`
+
+	Process    string `json:"-"`
+	OnBehalfOf string `json:"-"`
+}
+
+type ctxKey struct{}
+
+func WithClaims(ctx context.Context, claims Claims) context.Context {
+	return context.WithValue(ctx, ctxKey{}, claims)
+}
+
+// ClaimsFromContext returns the claims of the authenticated caller, ok is
+// false for anonymous calls like the ones made from the CLI.
+func ClaimsFromContext(ctx context.Context) (claims Claims, ok bool) {
+	claims, ok = ctx.Value(ctxKey{}).(Claims)
+	return claims, ok
+}
+
+// AsProcess replaces the claims of the caller with the ones of the process,
+// a principal of its own which is only granted the actions the policies list
+// for it. The caller the process acts for is kept for the audit log. It is
+// only meant for the steps a caller was already authorized to trigger.
+func AsProcess(ctx context.Context, process string) context.Context {
+	onBehalfOf := "system"
+	if claims, ok := ClaimsFromContext(ctx); ok {
+		onBehalfOf = claims.Subject
+	}
+
+	return WithClaims(ctx, Claims{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: PROCESS_SUBJECT_PREFIX + process},
+		Process:          process,
+		OnBehalfOf:       onBehalfOf,
+	})
+}
+
+const (
+	ACTION_TEAM_READ   = "team:read"
+	ACTION_TEAM_CREATE = "team:create"
+	ACTION_TEAM_UPDATE = "team:update"
+	ACTION_TEAM_DELETE = "team:delete"
+
+	ACTION_PLAYER_READ     = "player:read"
+	ACTION_PLAYER_CREATE   = "player:create"
+	ACTION_PLAYER_TRANSFER = "player:transfer"
+	ACTION_PLAYER_UPDATE   = "player:update"
+	ACTION_PLAYER_DELETE   = "player:delete"
+
+	ACTION_OFFER_READ    = "offer:read"
+	ACTION_OFFER_MAKE    = "offer:make"
+	ACTION_OFFER_RESPOND = "offer:respond"
+
+	// ANY matches every role or scope of an authenticated caller.
+	ANY = "*"
+
+	PROCESS_SUBJECT_PREFIX = "process:"
+)
+
+// Resource is what an action is performed on, TeamID is zero when the
+// resource doesn't belong to a team.
+type Resource struct {
+	TeamID int64
+}
+
+type ForbiddenError struct {
+	Subject string
+	Action  string
+}
+
+func (e ForbiddenError) Error() string {
+	return fmt.Sprintf("%s is not allowed to %s", e.Subject, e.Action)
+}
+
+// Policy grants an action to callers holding one of Roles or Scopes, and to
+// the Processes acting for a caller. An OwnTeam rule only applies to
+// resources of a team listed in the caller's team IDs.
+type Policy struct {
+	Action    string   `yaml:"action"`
+	Roles     []string `yaml:"roles"`
+	Scopes    []string `yaml:"scopes"`
+	Processes []string `yaml:"processes"`
+	OwnTeam   bool     `yaml:"own_team"`
+}
+
+type PolicyFile struct {
+	Policies []Policy `yaml:"policies"`
+}
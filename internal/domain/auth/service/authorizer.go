@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/tesarwijaya/ouroboros/internal/config"
+	"github.com/tesarwijaya/ouroboros/internal/domain/auth/model"
+	"github.com/tesarwijaya/ouroboros/internal/logger"
+	"go.uber.org/dig"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+type Authorizer interface {
+	Authorize(ctx context.Context, action string, resource model.Resource) error
+}
+
+type AuthorizerImpl struct {
+	dig.In `ignore-unexported:"true"`
+	Config *config.Config
+
+	policies map[string][]model.Policy
+}
+
+// NewAuthorizer loads the policy file once, it is only required when
+// authentication is enabled.
+func NewAuthorizer(authz AuthorizerImpl) (Authorizer, error) {
+	authz.policies = map[string][]model.Policy{}
+	if !authz.Config.AuthEnabled {
+		return &authz, nil
+	}
+
+	raw, err := os.ReadFile(authz.Config.AuthPolicyFile)
+	if err != nil {
+		return nil, fmt.Errorf("read policy file: %w", err)
+	}
+
+	var file model.PolicyFile
+	if err := yaml.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("parse policy file %s: %w", authz.Config.AuthPolicyFile, err)
+	}
+
+	for _, policy := range file.Policies {
+		authz.policies[policy.Action] = append(authz.policies[policy.Action], policy)
+	}
+
+	return &authz, nil
+}
+
+// Authorize returns a model.ForbiddenError unless a policy grants the action
+// to the caller. Calls without claims never went through the HTTP
+// authentication, they come from the CLI or background workers and are
+// trusted. A process is only granted what the policies list for it, unless
+// authentication is disabled. Every decision is written to the audit log.
+func (a *AuthorizerImpl) Authorize(ctx context.Context, action string, resource model.Resource) error {
+	claims, ok := model.ClaimsFromContext(ctx)
+
+	subject := "system"
+	allowed := true
+	if ok {
+		subject = claims.Subject
+		allowed = !a.Config.AuthEnabled || a.allowed(claims, action, resource)
+	}
+
+	l := logger.FromContext(ctx).Named("audit").With(
+		zap.String("subject", subject),
+		zap.String("action", action),
+		zap.Int64("team_id", resource.TeamID),
+	)
+	if claims.Process != "" {
+		l = l.With(zap.String("on_behalf_of", claims.OnBehalfOf))
+	}
+
+	if !allowed {
+		l.Warn("access denied")
+		return model.ForbiddenError{Subject: subject, Action: action}
+	}
+
+	l.Info("access granted")
+
+	return nil
+}
+
+func (a *AuthorizerImpl) allowed(claims model.Claims, action string, resource model.Resource) bool {
+	for _, policy := range a.policies[action] {
+		granted := contains(policy.Roles, claims.Roles) || contains(policy.Scopes, claims.Scopes)
+		if claims.Process != "" {
+			// a process holds no roles, not even the ones "*" matches
+			granted = contains(policy.Processes, []string{claims.Process})
+		}
+
+		if !granted {
+			continue
+		}
+
+		if policy.OwnTeam && !containsTeam(claims.TeamIDs, resource.TeamID) {
+			continue
+		}
+
+		return true
+	}
+
+	return false
+}
+
+func contains(granted []string, held []string) bool {
+	for _, g := range granted {
+		if g == model.ANY {
+			return true
+		}
+
+		for _, h := range held {
+			if g == h {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func containsTeam(teamIDs []int64, teamID int64) bool {
+	if teamID == 0 {
+		return false
+	}
+
+	for _, id := range teamIDs {
+		if id == teamID {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,194 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/tesarwijaya/ouroboros/internal/config"
+	"github.com/tesarwijaya/ouroboros/internal/domain/auth/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/auth/service"
+	"github.com/tesarwijaya/ouroboros/internal/logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+const policy = `
+policies:
+  - action: team:read
+    roles: ["*"]
+  - action: team:create
+    roles: [league_admin]
+  - action: player:create
+    roles: [team_manager]
+    own_team: true
+  - action: player:create
+    scopes: [players:write]
+  - action: player:transfer
+    processes: [some-process]
+`
+
+func createAuthorizer(t *testing.T) service.Authorizer {
+	authz, err := service.NewAuthorizer(service.AuthorizerImpl{
+		Config: &config.Config{
+			AuthEnabled:    true,
+			AuthPolicyFile: writeFile(t, "policy.yaml", []byte(policy)),
+		},
+	})
+	assert.Nil(t, err)
+
+	return authz
+}
+
+func Test_NewAuthorizer(t *testing.T) {
+	t.Run("when_policy_file_missing", func(t *testing.T) {
+		_, err := service.NewAuthorizer(service.AuthorizerImpl{
+			Config: &config.Config{AuthEnabled: true, AuthPolicyFile: "some-file.yaml"},
+		})
+
+		assert.EqualError(t, err, "read policy file: open some-file.yaml: no such file or directory")
+	})
+
+	t.Run("when_disabled", func(t *testing.T) {
+		authz, err := service.NewAuthorizer(service.AuthorizerImpl{
+			Config: &config.Config{AuthPolicyFile: "some-file.yaml"},
+		})
+
+		assert.Nil(t, err)
+		assert.Implements(t, (*service.Authorizer)(nil), authz)
+
+		// every call is trusted when authentication is disabled, processes too
+		err = authz.Authorize(model.AsProcess(context.Background(), "some-process"), model.ACTION_PLAYER_TRANSFER, model.Resource{TeamID: 1})
+		assert.Nil(t, err)
+	})
+}
+
+func Test_Authorize(t *testing.T) {
+	claims := func(roles []string, scopes []string, teamIDs ...int64) *model.Claims {
+		return &model.Claims{
+			RegisteredClaims: jwt.RegisteredClaims{Subject: "some-user"},
+			Roles:            roles,
+			Scopes:           scopes,
+			TeamIDs:          teamIDs,
+		}
+	}
+
+	testCases := []struct {
+		Name        string
+		Claims      *model.Claims
+		Process     string
+		Action      string
+		Resource    model.Resource
+		ExpectedErr error
+	}{
+		{
+			Name:     "when_system",
+			Action:   model.ACTION_TEAM_CREATE,
+			Resource: model.Resource{},
+		},
+		{
+			Name:     "when_process_granted",
+			Claims:   claims([]string{"team_manager"}, nil, 1),
+			Process:  "some-process",
+			Action:   model.ACTION_PLAYER_TRANSFER,
+			Resource: model.Resource{TeamID: 2},
+		},
+		{
+			Name:        "when_process_not_granted",
+			Claims:      claims([]string{"league_admin"}, nil),
+			Process:     "other-process",
+			Action:      model.ACTION_PLAYER_TRANSFER,
+			Resource:    model.Resource{TeamID: 2},
+			ExpectedErr: model.ForbiddenError{Subject: "process:other-process", Action: model.ACTION_PLAYER_TRANSFER},
+		},
+		{
+			Name:        "when_process_any_role",
+			Process:     "some-process",
+			Action:      model.ACTION_TEAM_READ,
+			Resource:    model.Resource{},
+			ExpectedErr: model.ForbiddenError{Subject: "process:some-process", Action: model.ACTION_TEAM_READ},
+		},
+		{
+			Name:     "when_any_role",
+			Claims:   claims(nil, nil),
+			Action:   model.ACTION_TEAM_READ,
+			Resource: model.Resource{},
+		},
+		{
+			Name:     "when_role_granted",
+			Claims:   claims([]string{"league_admin"}, nil),
+			Action:   model.ACTION_TEAM_CREATE,
+			Resource: model.Resource{},
+		},
+		{
+			Name:        "when_role_not_granted",
+			Claims:      claims([]string{"team_manager"}, nil, 1),
+			Action:      model.ACTION_TEAM_CREATE,
+			Resource:    model.Resource{},
+			ExpectedErr: model.ForbiddenError{Subject: "some-user", Action: model.ACTION_TEAM_CREATE},
+		},
+		{
+			Name:     "when_own_team",
+			Claims:   claims([]string{"team_manager"}, nil, 1),
+			Action:   model.ACTION_PLAYER_CREATE,
+			Resource: model.Resource{TeamID: 1},
+		},
+		{
+			Name:        "when_other_team",
+			Claims:      claims([]string{"team_manager"}, nil, 1),
+			Action:      model.ACTION_PLAYER_CREATE,
+			Resource:    model.Resource{TeamID: 2},
+			ExpectedErr: model.ForbiddenError{Subject: "some-user", Action: model.ACTION_PLAYER_CREATE},
+		},
+		{
+			Name:     "when_scope_granted",
+			Claims:   claims(nil, []string{"players:write"}),
+			Action:   model.ACTION_PLAYER_CREATE,
+			Resource: model.Resource{TeamID: 2},
+		},
+		{
+			Name:        "when_no_policy",
+			Claims:      claims([]string{"league_admin"}, nil),
+			Action:      model.ACTION_PLAYER_DELETE,
+			Resource:    model.Resource{TeamID: 1},
+			ExpectedErr: model.ForbiddenError{Subject: "some-user", Action: model.ACTION_PLAYER_DELETE},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.InfoLevel)
+
+			ctx := logger.WithContext(context.Background(), zap.New(core))
+			if test.Claims != nil {
+				ctx = model.WithClaims(ctx, *test.Claims)
+			}
+			if test.Process != "" {
+				ctx = model.AsProcess(ctx, test.Process)
+			}
+
+			err := createAuthorizer(t).Authorize(ctx, test.Action, test.Resource)
+			assert.Equal(t, test.ExpectedErr, err)
+
+			entries := logs.AllUntimed()
+			if assert.Len(t, entries, 1) {
+				assert.Equal(t, "audit", entries[0].LoggerName)
+				assert.Equal(t, test.Action, entries[0].ContextMap()["action"])
+				if test.Process != "" {
+					onBehalfOf := "system"
+					if test.Claims != nil {
+						onBehalfOf = test.Claims.Subject
+					}
+					assert.Equal(t, onBehalfOf, entries[0].ContextMap()["on_behalf_of"])
+				}
+				if test.ExpectedErr != nil {
+					assert.Equal(t, "access denied", entries[0].Message)
+				} else {
+					assert.Equal(t, "access granted", entries[0].Message)
+				}
+			}
+		})
+	}
+}
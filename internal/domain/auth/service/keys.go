@@ -0,0 +1,132 @@
+package service
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/tesarwijaya/ouroboros/internal/config"
+)
+
+// keySet holds the verification keys by algorithm, a key without kid is
+// used for tokens which don't carry one.
+type keySet struct {
+	hmac map[string][]byte
+	rsa  map[string]*rsa.PublicKey
+}
+
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	K   string `json:"k"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+func loadKeySet(c *config.Config) (*keySet, error) {
+	keys := &keySet{
+		hmac: map[string][]byte{},
+		rsa:  map[string]*rsa.PublicKey{},
+	}
+
+	if c.AuthHS256Secret != "" {
+		keys.hmac[""] = []byte(c.AuthHS256Secret)
+	}
+
+	if c.AuthRS256PublicKeyFile != "" {
+		pem, err := os.ReadFile(c.AuthRS256PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := jwt.ParseRSAPublicKeyFromPEM(pem)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", c.AuthRS256PublicKeyFile, err)
+		}
+		keys.rsa[""] = key
+	}
+
+	if c.AuthJWKSFile != "" {
+		if err := keys.loadJWKS(c.AuthJWKSFile); err != nil {
+			return nil, fmt.Errorf("load %s: %w", c.AuthJWKSFile, err)
+		}
+	}
+
+	return keys, nil
+}
+
+func (k *keySet) empty() bool {
+	return len(k.hmac) == 0 && len(k.rsa) == 0
+}
+
+func (k *keySet) loadJWKS(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var set jwks
+	if err := json.Unmarshal(raw, &set); err != nil {
+		return err
+	}
+
+	for _, key := range set.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+
+		switch key.Kty {
+		case "oct":
+			secret, err := base64.RawURLEncoding.DecodeString(key.K)
+			if err != nil {
+				return fmt.Errorf("key %s: %w", key.Kid, err)
+			}
+			k.hmac[key.Kid] = secret
+		case "RSA":
+			n, err := base64.RawURLEncoding.DecodeString(key.N)
+			if err != nil {
+				return fmt.Errorf("key %s: %w", key.Kid, err)
+			}
+			e, err := base64.RawURLEncoding.DecodeString(key.E)
+			if err != nil {
+				return fmt.Errorf("key %s: %w", key.Kid, err)
+			}
+			k.rsa[key.Kid] = &rsa.PublicKey{
+				N: new(big.Int).SetBytes(n),
+				E: int(new(big.Int).SetBytes(e).Int64()),
+			}
+		default:
+			return fmt.Errorf("key %s: unsupported key type %q", key.Kid, key.Kty)
+		}
+	}
+
+	return nil
+}
+
+// keyFunc picks the key matching the signing method and kid of the token.
+func (k *keySet) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	switch token.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		if key, ok := k.hmac[kid]; ok {
+			return key, nil
+		}
+	case jwt.SigningMethodRS256.Alg():
+		if key, ok := k.rsa[kid]; ok {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no %s key with kid %q", token.Method.Alg(), kid)
+}
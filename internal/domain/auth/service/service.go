@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/tesarwijaya/ouroboros/internal/config"
+	"github.com/tesarwijaya/ouroboros/internal/domain/auth/model"
+	"go.uber.org/dig"
+)
+
+type AuthService interface {
+	Authenticate(ctx context.Context, token string) (model.Claims, error)
+}
+
+type AuthServiceImpl struct {
+	dig.In `ignore-unexported:"true"`
+	Config *config.Config
+
+	keys   *keySet
+	parser *jwt.Parser
+}
+
+// NewAuthService loads the verification keys once, a missing or broken key
+// fails the app start rather than every request.
+func NewAuthService(svc AuthServiceImpl) (AuthService, error) {
+	keys, err := loadKeySet(svc.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	if svc.Config.AuthEnabled && keys.empty() {
+		return nil, errors.New("auth is enabled but no JWT verification key is configured")
+	}
+
+	svc.keys = keys
+	svc.parser = jwt.NewParser(jwt.WithValidMethods([]string{
+		jwt.SigningMethodHS256.Alg(),
+		jwt.SigningMethodRS256.Alg(),
+	}))
+
+	return &svc, nil
+}
+
+// Authenticate verifies the signature of token, its expiry, and its issuer
+// and audience when they are configured.
+func (s *AuthServiceImpl) Authenticate(ctx context.Context, token string) (model.Claims, error) {
+	var claims model.Claims
+	if _, err := s.parser.ParseWithClaims(token, &claims, s.keys.keyFunc); err != nil {
+		return model.Claims{}, fmt.Errorf("%w: %v", model.ErrInvalidToken, err)
+	}
+
+	if !claims.VerifyExpiresAt(time.Now(), true) {
+		return model.Claims{}, fmt.Errorf("%w: token has no expiry", model.ErrInvalidToken)
+	}
+
+	if s.Config.AuthIssuer != "" && !claims.VerifyIssuer(s.Config.AuthIssuer, true) {
+		return model.Claims{}, fmt.Errorf("%w: unexpected issuer %q", model.ErrInvalidToken, claims.Issuer)
+	}
+
+	if s.Config.AuthAudience != "" && !claims.VerifyAudience(s.Config.AuthAudience, true) {
+		return model.Claims{}, fmt.Errorf("%w: unexpected audience %v", model.ErrInvalidToken, claims.Audience)
+	}
+
+	return claims, nil
+}
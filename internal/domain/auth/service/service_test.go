@@ -0,0 +1,223 @@
+package service_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/tesarwijaya/ouroboros/internal/config"
+	"github.com/tesarwijaya/ouroboros/internal/domain/auth/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/auth/service"
+)
+
+const secret = "some-secret"
+
+func sign(t *testing.T, method jwt.SigningMethod, key interface{}, kid string, claims model.Claims) string {
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+	assert.Nil(t, err)
+
+	return signed
+}
+
+func validClaims() model.Claims {
+	return model.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "some-user",
+			Issuer:    "some-issuer",
+			Audience:  jwt.ClaimStrings{"some-audience"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour).Truncate(time.Second)),
+		},
+		Roles:   []string{"manager"},
+		TeamIDs: []int64{1},
+	}
+}
+
+func writeFile(t *testing.T, name string, content []byte) string {
+	path := filepath.Join(t.TempDir(), name)
+	assert.Nil(t, os.WriteFile(path, content, 0o600))
+
+	return path
+}
+
+func Test_NewAuthService(t *testing.T) {
+	t.Run("when_key_configured", func(t *testing.T) {
+		svc, err := service.NewAuthService(service.AuthServiceImpl{
+			Config: &config.Config{AuthEnabled: true, AuthHS256Secret: secret},
+		})
+
+		assert.Nil(t, err)
+		assert.Implements(t, (*service.AuthService)(nil), svc)
+	})
+
+	t.Run("when_no_key_configured", func(t *testing.T) {
+		_, err := service.NewAuthService(service.AuthServiceImpl{
+			Config: &config.Config{AuthEnabled: true},
+		})
+
+		assert.EqualError(t, err, "auth is enabled but no JWT verification key is configured")
+	})
+
+	t.Run("when_disabled", func(t *testing.T) {
+		_, err := service.NewAuthService(service.AuthServiceImpl{
+			Config: &config.Config{},
+		})
+
+		assert.Nil(t, err)
+	})
+}
+
+func Test_Authenticate(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+
+	pubDer, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	assert.Nil(t, err)
+	pemFile := writeFile(t, "public.pem", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDer}))
+
+	jwksFile := writeFile(t, "jwks.json", func() []byte {
+		raw, _ := json.Marshal(map[string]interface{}{
+			"keys": []map[string]string{
+				{
+					"kid": "rsa-key",
+					"kty": "RSA",
+					"use": "sig",
+					"n":   base64.RawURLEncoding.EncodeToString(rsaKey.PublicKey.N.Bytes()),
+					"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaKey.PublicKey.E)).Bytes()),
+				},
+				{
+					"kid": "hmac-key",
+					"kty": "oct",
+					"k":   base64.RawURLEncoding.EncodeToString([]byte(secret)),
+				},
+			},
+		})
+		return raw
+	}())
+
+	expired := validClaims()
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+
+	noExpiry := validClaims()
+	noExpiry.ExpiresAt = nil
+
+	otherIssuer := validClaims()
+	otherIssuer.Issuer = "other-issuer"
+
+	otherAudience := validClaims()
+	otherAudience.Audience = jwt.ClaimStrings{"other-audience"}
+
+	testCases := []struct {
+		Name        string
+		Config      config.Config
+		Token       string
+		Expected    model.Claims
+		ExpectedErr string
+	}{
+		{
+			Name:     "when_hs256",
+			Config:   config.Config{AuthHS256Secret: secret},
+			Token:    sign(t, jwt.SigningMethodHS256, []byte(secret), "", validClaims()),
+			Expected: validClaims(),
+		},
+		{
+			Name:     "when_rs256",
+			Config:   config.Config{AuthRS256PublicKeyFile: pemFile},
+			Token:    sign(t, jwt.SigningMethodRS256, rsaKey, "", validClaims()),
+			Expected: validClaims(),
+		},
+		{
+			Name:     "when_jwks_rs256",
+			Config:   config.Config{AuthJWKSFile: jwksFile},
+			Token:    sign(t, jwt.SigningMethodRS256, rsaKey, "rsa-key", validClaims()),
+			Expected: validClaims(),
+		},
+		{
+			Name:     "when_jwks_hs256",
+			Config:   config.Config{AuthJWKSFile: jwksFile},
+			Token:    sign(t, jwt.SigningMethodHS256, []byte(secret), "hmac-key", validClaims()),
+			Expected: validClaims(),
+		},
+		{
+			Name:        "when_jwks_unknown_kid",
+			Config:      config.Config{AuthJWKSFile: jwksFile},
+			Token:       sign(t, jwt.SigningMethodRS256, rsaKey, "other-key", validClaims()),
+			ExpectedErr: `invalid token: no RS256 key with kid "other-key"`,
+		},
+		{
+			Name:        "when_wrong_secret",
+			Config:      config.Config{AuthHS256Secret: secret},
+			Token:       sign(t, jwt.SigningMethodHS256, []byte("other-secret"), "", validClaims()),
+			ExpectedErr: "invalid token: signature is invalid",
+		},
+		{
+			Name:        "when_unexpected_method",
+			Config:      config.Config{AuthHS256Secret: secret},
+			Token:       sign(t, jwt.SigningMethodHS512, []byte(secret), "", validClaims()),
+			ExpectedErr: "invalid token: signing method HS512 is invalid",
+		},
+		{
+			Name:        "when_expired",
+			Config:      config.Config{AuthHS256Secret: secret},
+			Token:       sign(t, jwt.SigningMethodHS256, []byte(secret), "", expired),
+			ExpectedErr: "invalid token: token is expired by",
+		},
+		{
+			Name:        "when_no_expiry",
+			Config:      config.Config{AuthHS256Secret: secret},
+			Token:       sign(t, jwt.SigningMethodHS256, []byte(secret), "", noExpiry),
+			ExpectedErr: "invalid token: token has no expiry",
+		},
+		{
+			Name:        "when_unexpected_issuer",
+			Config:      config.Config{AuthHS256Secret: secret, AuthIssuer: "some-issuer"},
+			Token:       sign(t, jwt.SigningMethodHS256, []byte(secret), "", otherIssuer),
+			ExpectedErr: `invalid token: unexpected issuer "other-issuer"`,
+		},
+		{
+			Name:        "when_unexpected_audience",
+			Config:      config.Config{AuthHS256Secret: secret, AuthAudience: "some-audience"},
+			Token:       sign(t, jwt.SigningMethodHS256, []byte(secret), "", otherAudience),
+			ExpectedErr: "invalid token: unexpected audience [other-audience]",
+		},
+		{
+			Name:        "when_malformed",
+			Config:      config.Config{AuthHS256Secret: secret},
+			Token:       "some-token",
+			ExpectedErr: "invalid token: token contains an invalid number of segments",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			test.Config.AuthEnabled = true
+			svc, err := service.NewAuthService(service.AuthServiceImpl{Config: &test.Config})
+			assert.Nil(t, err)
+
+			actual, err := svc.Authenticate(context.Background(), test.Token)
+			if test.ExpectedErr != "" {
+				// the expiry error carries how long ago it expired
+				assert.ErrorContains(t, err, test.ExpectedErr)
+				assert.ErrorIs(t, err, model.ErrInvalidToken)
+			} else {
+				assert.Equal(t, test.Expected, actual)
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"context"
+	"fmt"
+)
+
+// Command is a typed request to change the state of the system, every command
+// is handled by exactly one handler registered on the command bus.
+type Command interface {
+	CommandName() string
+}
+
+type dryRunKey struct{}
+
+// WithDryRun marks the commands dispatched with ctx as a dry run, their
+// handlers go through every check but don't change anything.
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+func IsDryRun(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(dryRunKey{}).(bool)
+	return dryRun
+}
+
+// Validator is implemented by commands that can be checked before they reach
+// their handler.
+type Validator interface {
+	Validate() error
+}
+
+type HandlerFunc func(ctx context.Context, cmd Command) (interface{}, error)
+
+type Handler struct {
+	Command string
+	Handle  HandlerFunc
+}
+
+// Middleware wraps every dispatched command, middlewares with a lower Order
+// run first.
+type Middleware struct {
+	Name  string
+	Order int
+	Wrap  func(next HandlerFunc) HandlerFunc
+}
+
+type ValidationError struct {
+	Err error
+}
+
+func (e ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// NewHandler registers fn as the handler of the command type C.
+func NewHandler[C Command, R any](fn func(ctx context.Context, cmd C) (R, error)) Handler {
+	var zero C
+
+	return Handler{
+		Command: zero.CommandName(),
+		Handle: func(ctx context.Context, cmd Command) (interface{}, error) {
+			typed, ok := cmd.(C)
+			if !ok {
+				return nil, fmt.Errorf("command %s: unexpected type %T", zero.CommandName(), cmd)
+			}
+
+			return fn(ctx, typed)
+		},
+	}
+}
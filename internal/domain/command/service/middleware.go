@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/tesarwijaya/ouroboros/internal/domain/command/model"
+	"github.com/tesarwijaya/ouroboros/internal/logger"
+	"go.uber.org/zap"
+)
+
+func NewLoggingMiddleware() model.Middleware {
+	return model.Middleware{
+		Name:  "logging",
+		Order: 0,
+		Wrap: func(next model.HandlerFunc) model.HandlerFunc {
+			return func(ctx context.Context, cmd model.Command) (interface{}, error) {
+				start := time.Now()
+
+				res, err := next(ctx, cmd)
+
+				l := logger.FromContext(ctx).With(
+					zap.String("command", cmd.CommandName()),
+					zap.Duration("latency", time.Since(start)),
+				)
+				if err != nil {
+					l.Warn("command failed", zap.Error(err))
+					return res, err
+				}
+
+				l.Info("command handled")
+
+				return res, nil
+			}
+		},
+	}
+}
+
+func NewValidationMiddleware() model.Middleware {
+	return model.Middleware{
+		Name:  "validation",
+		Order: 10,
+		Wrap: func(next model.HandlerFunc) model.HandlerFunc {
+			return func(ctx context.Context, cmd model.Command) (interface{}, error) {
+				if validator, ok := cmd.(model.Validator); ok {
+					if err := validator.Validate(); err != nil {
+						return nil, model.ValidationError{Err: err}
+					}
+				}
+
+				return next(ctx, cmd)
+			}
+		},
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/tesarwijaya/ouroboros/internal/domain/command/model"
+	"github.com/tesarwijaya/ouroboros/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/dig"
+)
+
+type CommandBus interface {
+	Dispatch(ctx context.Context, cmd model.Command) (interface{}, error)
+}
+
+type CommandBusImpl struct {
+	dig.In      `ignore-unexported:"true"`
+	Handlers    []model.Handler    `group:"command_handlers"`
+	Middlewares []model.Middleware `group:"command_middlewares"`
+
+	handlers map[string]model.HandlerFunc
+}
+
+func NewCommandBus(bus CommandBusImpl) (CommandBus, error) {
+	middlewares := append([]model.Middleware{}, bus.Middlewares...)
+	sort.SliceStable(middlewares, func(i, j int) bool {
+		return middlewares[i].Order < middlewares[j].Order
+	})
+
+	bus.handlers = make(map[string]model.HandlerFunc, len(bus.Handlers))
+	for _, handler := range bus.Handlers {
+		if _, ok := bus.handlers[handler.Command]; ok {
+			return nil, fmt.Errorf("command %s has more than one handler", handler.Command)
+		}
+
+		handle := handler.Handle
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			handle = middlewares[i].Wrap(handle)
+		}
+
+		bus.handlers[handler.Command] = handle
+	}
+
+	return &bus, nil
+}
+
+func (b *CommandBusImpl) Dispatch(ctx context.Context, cmd model.Command) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "CommandBus.Dispatch", attribute.String("command", cmd.CommandName()))
+	defer span.End()
+
+	handle, ok := b.handlers[cmd.CommandName()]
+	if !ok {
+		return nil, fmt.Errorf("command %s has no handler", cmd.CommandName())
+	}
+
+	return handle(ctx, cmd)
+}
+
+// Dispatch sends cmd through the bus and casts the handler result to R.
+func Dispatch[R any](ctx context.Context, bus CommandBus, cmd model.Command) (R, error) {
+	var zero R
+
+	res, err := bus.Dispatch(ctx, cmd)
+	if err != nil {
+		return zero, err
+	}
+
+	typed, ok := res.(R)
+	if !ok {
+		return zero, fmt.Errorf("command %s: unexpected result %T", cmd.CommandName(), res)
+	}
+
+	return typed, nil
+}
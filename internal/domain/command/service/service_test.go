@@ -0,0 +1,102 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tesarwijaya/ouroboros/internal/domain/command/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/command/service"
+)
+
+type someCommand struct {
+	Value string
+}
+
+func (someCommand) CommandName() string {
+	return "some.command"
+}
+
+func (c someCommand) Validate() error {
+	if c.Value == "" {
+		return errors.New("value is required")
+	}
+
+	return nil
+}
+
+func tracing(name string, order int, trace *[]string) model.Middleware {
+	return model.Middleware{
+		Name:  name,
+		Order: order,
+		Wrap: func(next model.HandlerFunc) model.HandlerFunc {
+			return func(ctx context.Context, cmd model.Command) (interface{}, error) {
+				*trace = append(*trace, name)
+				return next(ctx, cmd)
+			}
+		},
+	}
+}
+
+func Test_NewCommandBus(t *testing.T) {
+	handler := model.NewHandler(func(ctx context.Context, cmd someCommand) (string, error) {
+		return cmd.Value, nil
+	})
+
+	_, err := service.NewCommandBus(service.CommandBusImpl{
+		Handlers: []model.Handler{handler, handler},
+	})
+
+	assert.EqualError(t, err, "command some.command has more than one handler")
+}
+
+func Test_Dispatch(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		Command     model.Command
+		Expect      string
+		ExpectTrace []string
+		ExpectErr   error
+	}{
+		{
+			Name:        "when_success",
+			Command:     someCommand{Value: "some-value"},
+			Expect:      "some-value",
+			ExpectTrace: []string{"first", "second", "handler"},
+		},
+		{
+			Name:        "when_invalid",
+			Command:     someCommand{},
+			ExpectTrace: []string{"first", "second"},
+			ExpectErr:   model.ValidationError{Err: errors.New("value is required")},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			var trace []string
+
+			bus, err := service.NewCommandBus(service.CommandBusImpl{
+				Handlers: []model.Handler{
+					model.NewHandler(func(ctx context.Context, cmd someCommand) (string, error) {
+						trace = append(trace, "handler")
+						return cmd.Value, nil
+					}),
+				},
+				Middlewares: []model.Middleware{
+					service.NewValidationMiddleware(),
+					tracing("second", 5, &trace),
+					tracing("first", 1, &trace),
+				},
+			})
+			assert.Nil(t, err)
+
+			actual, err := service.Dispatch[string](context.Background(), bus, test.Command)
+
+			assert.Equal(t, test.Expect, actual)
+			assert.Equal(t, test.ExpectTrace, trace)
+			assert.Equal(t, test.ExpectErr, err)
+		})
+	}
+}
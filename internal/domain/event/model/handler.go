@@ -0,0 +1,28 @@
+package model
+
+import "context"
+
+// Handler subscribes to events published on the event bus. A handler with no
+// EventTypes receives every event, an async handler runs in its own goroutine
+// so it never delays the publisher. A sync handler failing rolls back the
+// relay of the event, an async one failing is only logged.
+type Handler struct {
+	Name       string
+	EventTypes []string
+	Async      bool
+	Handle     func(ctx context.Context, event Event) error
+}
+
+func (h Handler) Accept(eventType string) bool {
+	if len(h.EventTypes) == 0 {
+		return true
+	}
+
+	for _, t := range h.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,16 +1,47 @@
 package model
 
 import (
+	"encoding/json"
+	"time"
+
 	"github.com/EventStore/EventStore-Client-Go/esdb"
 	"github.com/gofrs/uuid"
 )
 
+// CORRELATION_ID_METADATA_KEY is the metadata key the events recorded
+// together share, EventStoreDB links them in its $by_correlation_id
+// projection.
+const CORRELATION_ID_METADATA_KEY = "$correlationId"
+
 type (
 	Event struct {
 		ID          uuid.UUID
+		StreamID    string
+		Version     uint64
 		Type        string
 		ContentType esdb.ContentType
 		Data        []byte
 		Metadata    []byte
+		CreatedAt   time.Time
 	}
 )
+
+// CorrelationMetadata returns the JSON metadata of the events correlated by id.
+func CorrelationMetadata(id string) []byte {
+	res, _ := json.Marshal(map[string]string{CORRELATION_ID_METADATA_KEY: id})
+
+	return res
+}
+
+// CorrelationID returns the id the event is correlated by, empty when its
+// metadata has none.
+func (e Event) CorrelationID() string {
+	var metadata map[string]interface{}
+	if err := json.Unmarshal(e.Metadata, &metadata); err != nil {
+		return ""
+	}
+
+	id, _ := metadata[CORRELATION_ID_METADATA_KEY].(string)
+
+	return id
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/EventStore/EventStore-Client-Go/esdb"
+	"github.com/gofrs/uuid"
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/tesarwijaya/ouroboros/internal/database"
+	"github.com/tesarwijaya/ouroboros/internal/domain/event/model"
+	"github.com/tesarwijaya/ouroboros/internal/metrics"
+	"go.uber.org/dig"
+)
+
+const (
+	EVENT_OUTBOX_TABLE_NAME      = "event_outbox"
+	EVENT_DEAD_LETTER_TABLE_NAME = "event_dead_letter"
+
+	// outboxInsertChunk keeps an insert well below the bind parameter limit.
+	outboxInsertChunk = 1000
+)
+
+var outboxColumns = []string{"event_id", "stream_id", "type", "content_type", "data", "metadata", "created_at"}
+
+// OutboxRepository holds the events written within a transaction until they
+// are appended to the event store.
+type OutboxRepository interface {
+	Insert(ctx context.Context, events ...model.Event) error
+	Claim(ctx context.Context, limit int) ([]model.Event, error)
+	Fail(ctx context.Context, eventID uuid.UUID) (int, error)
+	DeadLetter(ctx context.Context, eventID uuid.UUID, handler string, cause string) error
+}
+
+type OutboxRepositoryImpl struct {
+	dig.In
+	Db *sql.DB
+}
+
+func NewOutboxRepository(repo OutboxRepositoryImpl) OutboxRepository {
+	return &repo
+}
+
+func (r *OutboxRepositoryImpl) Insert(ctx context.Context, events ...model.Event) error {
+	defer metrics.ObserveQuery("event_outbox", "Insert")()
+
+	for start := 0; start < len(events); start += outboxInsertChunk {
+		end := start + outboxInsertChunk
+		if end > len(events) {
+			end = len(events)
+		}
+
+		q := sqlbuilder.NewInsertBuilder()
+		q.InsertInto(EVENT_OUTBOX_TABLE_NAME).Cols(outboxColumns...)
+		for _, event := range events[start:end] {
+			q.Values(event.ID, event.StreamID, event.Type, int(event.ContentType), event.Data, event.Metadata, event.CreatedAt)
+		}
+
+		query, args := q.BuildWithFlavor(sqlbuilder.PostgreSQL)
+		if _, err := database.Exec(ctx, r.Db, query, args...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Claim deletes up to limit of the oldest events and returns them in the
+// order they were written. It must run in a transaction, which holds off the
+// other claims until it ends so that events are appended in order, rolling it
+// back puts the events back.
+func (r *OutboxRepositoryImpl) Claim(ctx context.Context, limit int) ([]model.Event, error) {
+	defer metrics.ObserveQuery("event_outbox", "Claim")()
+
+	if _, err := database.Exec(ctx, r.Db, "SELECT pg_advisory_xact_lock(hashtext($1))", EVENT_OUTBOX_TABLE_NAME); err != nil {
+		return []model.Event{}, err
+	}
+
+	sub := sqlbuilder.NewSelectBuilder()
+	sub.Select("id").
+		From(EVENT_OUTBOX_TABLE_NAME).
+		OrderBy("id").
+		Limit(limit)
+
+	q := sqlbuilder.NewDeleteBuilder()
+	query, args := q.DeleteFrom(EVENT_OUTBOX_TABLE_NAME).
+		Where(q.In("id", sub)).
+		SQL("RETURNING id, " + strings.Join(outboxColumns, ", ")).
+		BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	rows, err := database.Query(ctx, r.Db, query, args...)
+	if err != nil {
+		return []model.Event{}, err
+	}
+	defer rows.Close()
+
+	type claimed struct {
+		id    int64
+		event model.Event
+	}
+
+	var res []claimed
+	for rows.Next() {
+		var (
+			item        claimed
+			contentType int
+		)
+
+		if err := rows.Scan(
+			&item.id,
+			&item.event.ID,
+			&item.event.StreamID,
+			&item.event.Type,
+			&contentType,
+			&item.event.Data,
+			&item.event.Metadata,
+			&item.event.CreatedAt,
+		); err != nil {
+			return []model.Event{}, err
+		}
+		item.event.ContentType = esdb.ContentType(contentType)
+
+		res = append(res, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return []model.Event{}, err
+	}
+
+	// RETURNING doesn't keep the order of the subquery
+	sort.Slice(res, func(i, j int) bool { return res[i].id < res[j].id })
+
+	events := make([]model.Event, 0, len(res))
+	for _, item := range res {
+		events = append(events, item.event)
+	}
+
+	return events, nil
+}
+
+// Fail counts a failed relay of the event and returns how many times its
+// relay failed so far, zero when it isn't in the outbox anymore.
+func (r *OutboxRepositoryImpl) Fail(ctx context.Context, eventID uuid.UUID) (int, error) {
+	defer metrics.ObserveQuery("event_outbox", "Fail")()
+
+	q := sqlbuilder.NewUpdateBuilder()
+	query, args := q.Update(EVENT_OUTBOX_TABLE_NAME).
+		Set("attempts = attempts + 1").
+		Where(q.Equal("event_id", eventID)).
+		SQL("RETURNING attempts").
+		BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	var attempts int
+	err := database.QueryRow(ctx, r.Db, query, args...).Scan(&attempts)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return attempts, nil
+}
+
+// DeadLetter moves the event out of the outbox to the event_dead_letter table
+// along the handler that failed it and its error, the events written after it
+// are then relayed again.
+func (r *OutboxRepositoryImpl) DeadLetter(ctx context.Context, eventID uuid.UUID, handler string, cause string) error {
+	defer metrics.ObserveQuery("event_outbox", "DeadLetter")()
+
+	columns := strings.Join(append(append([]string{}, outboxColumns...), "attempts"), ", ")
+	query := "WITH moved AS (DELETE FROM " + EVENT_OUTBOX_TABLE_NAME + " WHERE event_id = $1 RETURNING " + columns + ") " +
+		"INSERT INTO " + EVENT_DEAD_LETTER_TABLE_NAME + " (" + columns + ", handler, error) " +
+		"SELECT " + columns + ", $2, $3 FROM moved"
+
+	if _, err := database.Exec(ctx, r.Db, query, eventID, handler, cause); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package repository_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/EventStore/EventStore-Client-Go/esdb"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/tesarwijaya/ouroboros/internal/database"
+	"github.com/tesarwijaya/ouroboros/internal/domain/event/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/event/repository"
+)
+
+var (
+	firstID   = uuid.Must(uuid.FromString("6ba7b810-9dad-11d1-80b4-00c04fd430c8"))
+	secondID  = uuid.Must(uuid.FromString("6ba7b811-9dad-11d1-80b4-00c04fd430c8"))
+	createdAt = time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func Test_OutboxInsert(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO event_outbox (event_id, stream_id, type, content_type, data, metadata, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7), ($8, $9, $10, $11, $12, $13, $14)")).
+		WithArgs(
+			firstID, "player-1", "player_transfer_out", 1, []byte(`{}`), []byte(nil), createdAt,
+			secondID, "player-1", "player_transfer_in", 1, []byte(`{}`), []byte(nil), createdAt,
+		).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	repo := repository.NewOutboxRepository(repository.OutboxRepositoryImpl{Db: db})
+
+	err := repo.Insert(context.Background(),
+		model.Event{ID: firstID, StreamID: "player-1", Type: "player_transfer_out", ContentType: esdb.JsonContentType, Data: []byte(`{}`), CreatedAt: createdAt},
+		model.Event{ID: secondID, StreamID: "player-1", Type: "player_transfer_in", ContentType: esdb.JsonContentType, Data: []byte(`{}`), CreatedAt: createdAt},
+	)
+
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_OutboxClaim(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_xact_lock(hashtext($1))")).
+		WithArgs("event_outbox").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("DELETE FROM event_outbox WHERE id IN (SELECT id FROM event_outbox ORDER BY id LIMIT 100) RETURNING id, event_id, stream_id, type, content_type, data, metadata, created_at")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "event_id", "stream_id", "type", "content_type", "data", "metadata", "created_at"}).
+			AddRow(int64(2), secondID.String(), "player-1", "player_transfer_in", 1, []byte(`{}`), nil, createdAt).
+			AddRow(int64(1), firstID.String(), "player-1", "player_transfer_out", 1, []byte(`{}`), nil, createdAt))
+	mock.ExpectCommit()
+
+	repo := repository.NewOutboxRepository(repository.OutboxRepositoryImpl{Db: db})
+
+	var actual []model.Event
+	err := database.WithTx(context.Background(), db, func(ctx context.Context) error {
+		var err error
+		actual, err = repo.Claim(ctx, 100)
+
+		return err
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []model.Event{
+		{ID: firstID, StreamID: "player-1", Type: "player_transfer_out", ContentType: esdb.JsonContentType, Data: []byte(`{}`), CreatedAt: createdAt},
+		{ID: secondID, StreamID: "player-1", Type: "player_transfer_in", ContentType: esdb.JsonContentType, Data: []byte(`{}`), CreatedAt: createdAt},
+	}, actual)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_OutboxFail(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		mockFn    func(mock sqlmock.Sqlmock)
+		Expect    int
+		ExpectErr error
+	}{
+		{
+			Name: "when_successful",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("UPDATE event_outbox SET attempts = attempts + 1 WHERE event_id = $1 RETURNING attempts")).
+					WithArgs(firstID).
+					WillReturnRows(sqlmock.NewRows([]string{"attempts"}).AddRow(2))
+			},
+			Expect: 2,
+		},
+		{
+			Name: "when_not_in_outbox",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("UPDATE event_outbox SET attempts = attempts + 1 WHERE event_id = $1 RETURNING attempts")).
+					WithArgs(firstID).
+					WillReturnRows(sqlmock.NewRows([]string{"attempts"}))
+			},
+			Expect: 0,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			repo := repository.NewOutboxRepository(repository.OutboxRepositoryImpl{Db: db})
+
+			actual, err := repo.Fail(context.Background(), firstID)
+
+			assert.Equal(t, test.Expect, actual)
+			assert.Equal(t, test.ExpectErr, err)
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_OutboxDeadLetter(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta("WITH moved AS (DELETE FROM event_outbox WHERE event_id = $1 RETURNING event_id, stream_id, type, content_type, data, metadata, created_at, attempts) INSERT INTO event_dead_letter (event_id, stream_id, type, content_type, data, metadata, created_at, attempts, handler, error) SELECT event_id, stream_id, type, content_type, data, metadata, created_at, attempts, $2, $3 FROM moved")).
+		WithArgs(firstID, "some-handler", "some-error").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := repository.NewOutboxRepository(repository.OutboxRepositoryImpl{Db: db})
+
+	err := repo.DeadLetter(context.Background(), firstID, "some-handler", "some-error")
+
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
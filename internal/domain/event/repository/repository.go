@@ -2,14 +2,31 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"strings"
+	"time"
 
 	"github.com/EventStore/EventStore-Client-Go/esdb"
 	"github.com/tesarwijaya/ouroboros/internal/domain/event/model"
+	"github.com/tesarwijaya/ouroboros/internal/metrics"
+	"github.com/tesarwijaya/ouroboros/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
 	"go.uber.org/dig"
 )
 
+const (
+	TRACE_ID_METADATA_KEY = "trace_id"
+)
+
 type EventRepository interface {
 	Insert(ctx context.Context, payload model.Event) error
+	ReadStream(ctx context.Context, streamID string, from uint64) ([]model.Event, error)
+	ReadAll(ctx context.Context, fn func(model.Event) error) error
 }
 
 type EventRepositoryImpl struct {
@@ -22,18 +39,156 @@ func NewTeamReposity(repo EventRepositoryImpl) EventRepository {
 }
 
 func (r *EventRepositoryImpl) Insert(ctx context.Context, payload model.Event) error {
+	streamID := payload.StreamID
+	if streamID == "" {
+		streamID = payload.ID.String()
+	}
+
+	ctx, span := tracing.Start(ctx, "esdb.append",
+		attribute.String("db.system", "eventstoredb"),
+		attribute.String("esdb.stream", streamID),
+		attribute.String("esdb.event_type", payload.Type),
+	)
+
 	eventData := esdb.EventData{
 		EventID:     payload.ID,
 		EventType:   payload.Type,
 		ContentType: payload.ContentType,
 		Data:        payload.Data,
-		Metadata:    payload.Metadata,
+		Metadata:    withTraceContext(ctx, payload.Metadata),
 	}
 
-	_, err := r.Db.AppendToStream(ctx, payload.ID.String(), esdb.AppendToStreamOptions{}, eventData)
+	start := time.Now()
+	_, err := r.Db.AppendToStream(ctx, streamID, esdb.AppendToStreamOptions{}, eventData)
+	metrics.ObserveAppend(payload.Type, start, err)
+	tracing.End(span, err)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// withTraceContext adds the trace ID and the W3C trace context of ctx to the
+// JSON metadata of an event, so that consumers can join the trace. Metadata
+// that isn't a JSON object is left untouched.
+func withTraceContext(ctx context.Context, metadata []byte) []byte {
+	traceID := tracing.TraceID(ctx)
+	if traceID == "" {
+		return metadata
+	}
+
+	values := map[string]interface{}{}
+	if len(metadata) > 0 {
+		if err := json.Unmarshal(metadata, &values); err != nil {
+			return metadata
+		}
+	}
+
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	for k, v := range carrier {
+		values[k] = v
+	}
+	values[TRACE_ID_METADATA_KEY] = traceID
+
+	res, err := json.Marshal(values)
+	if err != nil {
+		return metadata
+	}
+
+	return res
+}
+
+// ReadStream returns every event of the stream starting at revision from,
+// an unknown stream is treated as an empty one.
+func (r *EventRepositoryImpl) ReadStream(ctx context.Context, streamID string, from uint64) ([]model.Event, error) {
+	var res []model.Event
+
+	stream, err := r.Db.ReadStream(ctx, streamID, esdb.ReadStreamOptions{
+		Direction: esdb.Forwards,
+		From:      esdb.Revision(from),
+	}, math.MaxInt64)
+	if err != nil {
+		if errors.Is(err, esdb.ErrStreamNotFound) {
+			return []model.Event{}, nil
+		}
+
+		return []model.Event{}, err
+	}
+	defer stream.Close()
+
+	for {
+		resolved, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			if errors.Is(err, esdb.ErrStreamNotFound) {
+				return []model.Event{}, nil
+			}
+
+			return []model.Event{}, err
+		}
+
+		res = append(res, toEvent(resolved.OriginalEvent()))
+	}
+
+	return res, nil
+}
+
+// ReadAll calls fn with every event of every stream in the order they were
+// appended, as they are received so that they are never all held in memory.
+// System events are left out. An error of fn stops the read and is returned.
+func (r *EventRepositoryImpl) ReadAll(ctx context.Context, fn func(model.Event) error) error {
+	stream, err := r.Db.ReadAll(ctx, esdb.ReadAllOptions{
+		Direction: esdb.Forwards,
+		From:      esdb.Start{},
+	}, math.MaxInt64)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	for {
+		resolved, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		event := resolved.OriginalEvent()
+		if strings.HasPrefix(event.EventType, "$") {
+			continue
+		}
+
+		if err := fn(toEvent(event)); err != nil {
+			return err
+		}
+	}
+}
+
+func toEvent(event *esdb.RecordedEvent) model.Event {
+	return model.Event{
+		ID:          event.EventID,
+		StreamID:    event.StreamID,
+		Version:     event.EventNumber,
+		Type:        event.EventType,
+		ContentType: toContentType(event.ContentType),
+		Data:        event.Data,
+		Metadata:    event.UserMetadata,
+		CreatedAt:   event.CreatedDate,
+	}
+}
+
+func toContentType(contentType string) esdb.ContentType {
+	if contentType == "application/json" {
+		return esdb.JsonContentType
+	}
+
+	return esdb.BinaryContentType
+}
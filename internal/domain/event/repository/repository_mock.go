@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/event/repository/repository.go
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/tesarwijaya/ouroboros/internal/domain/event/model"
+)
+
+// MockEventRepository is a mock of EventRepository interface.
+type MockEventRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventRepositoryMockRecorder
+}
+
+// MockEventRepositoryMockRecorder is the mock recorder for MockEventRepository.
+type MockEventRepositoryMockRecorder struct {
+	mock *MockEventRepository
+}
+
+// NewMockEventRepository creates a new mock instance.
+func NewMockEventRepository(ctrl *gomock.Controller) *MockEventRepository {
+	mock := &MockEventRepository{ctrl: ctrl}
+	mock.recorder = &MockEventRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventRepository) EXPECT() *MockEventRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Insert mocks base method.
+func (m *MockEventRepository) Insert(ctx context.Context, payload model.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Insert", ctx, payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Insert indicates an expected call of Insert.
+func (mr *MockEventRepositoryMockRecorder) Insert(ctx, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockEventRepository)(nil).Insert), ctx, payload)
+}
+
+// ReadAll mocks base method.
+func (m *MockEventRepository) ReadAll(ctx context.Context, fn func(model.Event) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadAll", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReadAll indicates an expected call of ReadAll.
+func (mr *MockEventRepositoryMockRecorder) ReadAll(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadAll", reflect.TypeOf((*MockEventRepository)(nil).ReadAll), ctx, fn)
+}
+
+// ReadStream mocks base method.
+func (m *MockEventRepository) ReadStream(ctx context.Context, streamID string, from uint64) ([]model.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadStream", ctx, streamID, from)
+	ret0, _ := ret[0].([]model.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadStream indicates an expected call of ReadStream.
+func (mr *MockEventRepositoryMockRecorder) ReadStream(ctx, streamID, from interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadStream", reflect.TypeOf((*MockEventRepository)(nil).ReadStream), ctx, streamID, from)
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tesarwijaya/ouroboros/internal/config"
+	"github.com/tesarwijaya/ouroboros/internal/domain/event/repository"
+	healthz_model "github.com/tesarwijaya/ouroboros/internal/domain/healthz/model"
+)
+
+// healthzStream is never appended to, reading it only proves the event store
+// answers.
+const healthzStream = "ouroboros-healthz"
+
+func NewEventStoreCheck(repo repository.EventRepository) healthz_model.Check {
+	return healthz_model.Check{
+		Name:     "eventstoredb",
+		Required: true,
+		Check: func(ctx context.Context) error {
+			_, err := repo.ReadStream(ctx, healthzStream, 0)
+			return err
+		},
+	}
+}
+
+// NewProjectionLagCheck fails when a handler of the event bus is still behind
+// on an event appended longer ago than the configured maximum.
+func NewProjectionLagCheck(cfg *config.Config, bus EventBus) healthz_model.Check {
+	return healthz_model.Check{
+		Name:     "projection_lag",
+		Required: true,
+		Check: func(ctx context.Context) error {
+			for handler, lag := range bus.Lag() {
+				if lag > cfg.HealthzMaxProjectionLag {
+					return fmt.Errorf("%s is %s behind, over the %s limit", handler, lag, cfg.HealthzMaxProjectionLag)
+				}
+			}
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,332 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/tesarwijaya/ouroboros/internal/config"
+	"github.com/tesarwijaya/ouroboros/internal/database"
+	"github.com/tesarwijaya/ouroboros/internal/domain/event/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/event/repository"
+	"github.com/tesarwijaya/ouroboros/internal/logger"
+	"github.com/tesarwijaya/ouroboros/internal/metrics"
+	"github.com/tesarwijaya/ouroboros/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/dig"
+	"go.uber.org/zap"
+)
+
+// relayBatchSize bounds the events appended within one relay transaction.
+const relayBatchSize = 100
+
+type EventBus interface {
+	Publish(ctx context.Context, events ...model.Event) error
+	Relay(ctx context.Context) error
+	Close(ctx context.Context) error
+	Lag() map[string]time.Duration
+}
+
+// HandlerError is a sync handler failing an event, which rolls back the relay
+// of the batch the event is in.
+type HandlerError struct {
+	Handler string
+	Event   model.Event
+	Err     error
+}
+
+func (e HandlerError) Error() string {
+	return fmt.Sprintf("event handler %s failed on event %s: %s", e.Handler, e.Event.ID, e.Err)
+}
+
+func (e HandlerError) Unwrap() error {
+	return e.Err
+}
+
+type EventBusImpl struct {
+	dig.In   `ignore-unexported:"true"`
+	Config   *config.Config
+	Db       *sql.DB
+	Repo     repository.EventRepository
+	Outbox   repository.OutboxRepository
+	Handlers []model.Handler `group:"event_handlers"`
+
+	workers  *sync.WaitGroup
+	mu       *sync.Mutex
+	seq      uint64
+	inFlight map[string]map[uint64]time.Time
+}
+
+func NewEventBus(bus EventBusImpl) EventBus {
+	bus.workers = &sync.WaitGroup{}
+	bus.mu = &sync.Mutex{}
+	bus.inFlight = map[string]map[uint64]time.Time{}
+
+	return &bus
+}
+
+// Publish writes the events to the outbox within the transaction ctx is in,
+// or a transaction of its own, so that they are only recorded when it
+// commits. Once it is, the events are relayed to the event store and the
+// subscribed handlers.
+func (b *EventBusImpl) Publish(ctx context.Context, events ...model.Event) error {
+	ctx, span := tracing.Start(ctx, "EventBus.Publish")
+	defer span.End()
+
+	events = append([]model.Event(nil), events...)
+	for i, event := range events {
+		if event.ID == uuid.Nil {
+			events[i].ID, _ = uuid.NewV4()
+		}
+
+		// the time the event was recorded is what the projection lag is
+		// measured against
+		if event.CreatedAt.IsZero() {
+			events[i].CreatedAt = time.Now()
+		}
+	}
+
+	return database.WithTx(ctx, b.Db, func(ctx context.Context) error {
+		if err := b.Outbox.Insert(ctx, events...); err != nil {
+			return err
+		}
+
+		database.AfterCommit(ctx, func(ctx context.Context) {
+			// the events stay in the outbox for the relay worker to retry
+			if err := b.Relay(ctx); err != nil {
+				logger.FromContext(ctx).Error("failed to relay events", zap.Error(err))
+			}
+		})
+
+		return nil
+	})
+}
+
+// Relay appends the events of the outbox to the event store in the order they
+// were written, until it is empty. The sync handlers run within the
+// transaction that takes the events out of the outbox, so that their writes
+// are committed along, the async ones once it is. An append, a sync handler or
+// a commit failing leaves the events in the outbox, the event store ignores
+// the events appended again on the next relay since they keep their id. An
+// event failing a sync handler APP_OUTBOX_MAX_ATTEMPTS times is dead lettered
+// so that it doesn't hold off the events after it.
+func (b *EventBusImpl) Relay(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "EventBus.Relay")
+	defer span.End()
+
+	for {
+		var events []model.Event
+
+		err := database.WithTx(ctx, b.Db, func(ctx context.Context) error {
+			claimed, err := b.Outbox.Claim(ctx, relayBatchSize)
+			if err != nil {
+				return err
+			}
+
+			for _, event := range claimed {
+				if err := b.Repo.Insert(ctx, event); err != nil {
+					return err
+				}
+			}
+
+			for _, event := range claimed {
+				if err := b.dispatch(ctx, event, false); err != nil {
+					return err
+				}
+			}
+			events = claimed
+
+			return nil
+		})
+
+		var handlerErr HandlerError
+		if errors.As(err, &handlerErr) {
+			deadLettered, failErr := b.fail(ctx, handlerErr)
+			if failErr != nil {
+				return failErr
+			}
+			if deadLettered {
+				continue
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(events) == 0 {
+			return nil
+		}
+
+		for _, event := range events {
+			_ = b.dispatch(ctx, event, true)
+		}
+	}
+}
+
+// fail counts the failed relay of the event a sync handler failed, in a
+// transaction of its own since the one of the relay is rolled back. The event
+// is dead lettered once it failed APP_OUTBOX_MAX_ATTEMPTS times.
+func (b *EventBusImpl) fail(ctx context.Context, handlerErr HandlerError) (bool, error) {
+	deadLettered := false
+
+	err := database.WithTx(ctx, b.Db, func(ctx context.Context) error {
+		attempts, err := b.Outbox.Fail(ctx, handlerErr.Event.ID)
+		if err != nil {
+			return err
+		}
+		if attempts < b.Config.OutboxMaxAttempts {
+			return nil
+		}
+
+		if err := b.Outbox.DeadLetter(ctx, handlerErr.Event.ID, handlerErr.Handler, handlerErr.Err.Error()); err != nil {
+			return err
+		}
+		deadLettered = true
+
+		database.AfterCommit(ctx, func(ctx context.Context) {
+			metrics.EventDeadLetters.WithLabelValues(handlerErr.Handler).Inc()
+			logger.FromContext(ctx).Error("event dead lettered",
+				zap.String("handler", handlerErr.Handler),
+				zap.String("event_type", handlerErr.Event.Type),
+				zap.String("event_id", handlerErr.Event.ID.String()),
+				zap.Int("attempts", attempts),
+				zap.Error(handlerErr.Err),
+			)
+		})
+
+		return nil
+	})
+
+	return deadLettered, err
+}
+
+// Close waits for the running async handlers until ctx is done.
+func (b *EventBusImpl) Close(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		b.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Lag returns, per handler, how long ago the oldest event it hasn't finished
+// handling yet was appended. Handlers that are caught up aren't listed.
+func (b *EventBusImpl) Lag() map[string]time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	res := make(map[string]time.Duration, len(b.inFlight))
+	for name, events := range b.inFlight {
+		for _, createdAt := range events {
+			if lag := time.Since(createdAt); lag > res[name] {
+				res[name] = lag
+			}
+		}
+	}
+
+	return res
+}
+
+// track records the event as in flight for the handler until the returned
+// func is called.
+func (b *EventBusImpl) track(handler string, event model.Event) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	id := b.seq
+
+	if b.inFlight[handler] == nil {
+		b.inFlight[handler] = map[uint64]time.Time{}
+	}
+	b.inFlight[handler][id] = event.CreatedAt
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		delete(b.inFlight[handler], id)
+		if len(b.inFlight[handler]) == 0 {
+			delete(b.inFlight, handler)
+		}
+	}
+}
+
+// dispatch hands the event to either the async or the sync handlers accepting
+// it. It stops at the first sync handler failing, the async ones are only
+// logged when they fail.
+func (b *EventBusImpl) dispatch(ctx context.Context, event model.Event, async bool) error {
+	for _, handler := range b.Handlers {
+		if handler.Async != async || !handler.Accept(event.Type) {
+			continue
+		}
+
+		done := b.track(handler.Name, event)
+
+		if !handler.Async {
+			err := b.handle(ctx, handler, event)
+			done()
+			if err != nil {
+				return HandlerError{Handler: handler.Name, Event: event, Err: err}
+			}
+			continue
+		}
+
+		b.workers.Add(1)
+		go func(handler model.Handler) {
+			defer b.workers.Done()
+			defer done()
+
+			// the request may be done by now, only its logger and trace are kept
+			asyncCtx := logger.WithContext(context.Background(), logger.FromContext(ctx))
+			asyncCtx = trace.ContextWithSpanContext(asyncCtx, trace.SpanContextFromContext(ctx))
+
+			_ = b.handle(asyncCtx, handler, event)
+		}(handler)
+	}
+
+	return nil
+}
+
+// handle runs the handler, a panicking handler is reported as failing.
+func (b *EventBusImpl) handle(ctx context.Context, handler model.Handler, event model.Event) (err error) {
+	ctx, span := tracing.Start(ctx, "EventHandler."+handler.Name,
+		attribute.String("event.type", event.Type),
+		attribute.String("event.id", event.ID.String()),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	l := logger.FromContext(ctx).With(
+		zap.String("handler", handler.Name),
+		zap.String("event_type", event.Type),
+		zap.String("event_id", event.ID.String()),
+	)
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+			l.Error("event handler panicked", zap.Any("panic", r))
+		}
+	}()
+
+	if err = handler.Handle(ctx, event); err != nil {
+		l.Error("event handler failed", zap.Error(err))
+		return err
+	}
+
+	metrics.ProjectionLag.WithLabelValues(handler.Name).Set(time.Since(event.CreatedAt).Seconds())
+
+	return nil
+}
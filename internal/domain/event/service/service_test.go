@@ -0,0 +1,347 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/tesarwijaya/ouroboros/internal/config"
+	"github.com/tesarwijaya/ouroboros/internal/database"
+	"github.com/tesarwijaya/ouroboros/internal/domain/event/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/event/repository"
+	"github.com/tesarwijaya/ouroboros/internal/domain/event/service"
+)
+
+type resolverFn func(repo *repository.MockEventRepository, outbox *repository.MockOutboxRepository, db sqlmock.Sqlmock)
+
+func createBus(t *testing.T, handlers []model.Handler, resolver resolverFn) (service.EventBus, sqlmock.Sqlmock, *gomock.Controller) {
+	ctrl := gomock.NewController(t)
+
+	db, dbMock, _ := sqlmock.New()
+	t.Cleanup(func() { db.Close() })
+
+	repo := repository.NewMockEventRepository(ctrl)
+	outbox := repository.NewMockOutboxRepository(ctrl)
+	resolver(repo, outbox, dbMock)
+
+	return service.NewEventBus(service.EventBusImpl{
+		Config:   &config.Config{OutboxMaxAttempts: 3},
+		Db:       db,
+		Repo:     repo,
+		Outbox:   outbox,
+		Handlers: handlers,
+	}), dbMock, ctrl
+}
+
+// expectRelay expects a relay of events, followed by the claim finding the
+// outbox empty.
+func expectRelay(repo *repository.MockEventRepository, outbox *repository.MockOutboxRepository, db sqlmock.Sqlmock, events ...model.Event) {
+	db.ExpectBegin()
+	outbox.EXPECT().Claim(gomock.Any(), 100).Return(events, nil)
+	for _, event := range events {
+		repo.EXPECT().Insert(gomock.Any(), event).Return(nil)
+	}
+	db.ExpectCommit()
+
+	db.ExpectBegin()
+	outbox.EXPECT().Claim(gomock.Any(), 100).Return([]model.Event{}, nil)
+	db.ExpectCommit()
+}
+
+func Test_NewEventBus(t *testing.T) {
+	bus := service.NewEventBus(service.EventBusImpl{})
+
+	assert.Implements(t, (*service.EventBus)(nil), bus)
+}
+
+func Test_Publish(t *testing.T) {
+	event := model.Event{Type: "some-event"}
+
+	record := func(mu *sync.Mutex, received *[]string, name string) func(ctx context.Context, event model.Event) error {
+		return func(ctx context.Context, event model.Event) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			*received = append(*received, name)
+			return nil
+		}
+	}
+
+	t.Run("when_success", func(t *testing.T) {
+		var mu sync.Mutex
+		var received []string
+
+		bus, db, mock := createBus(t, []model.Handler{
+			{Name: "sync", Handle: record(&mu, &received, "sync")},
+			{Name: "async", Async: true, Handle: record(&mu, &received, "async")},
+			{Name: "other", EventTypes: []string{"other-event"}, Handle: record(&mu, &received, "other")},
+		}, func(repo *repository.MockEventRepository, outbox *repository.MockOutboxRepository, db sqlmock.Sqlmock) {
+			db.ExpectBegin()
+			outbox.EXPECT().Insert(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, events ...model.Event) error {
+				assert.Len(t, events, 1)
+				assert.NotEqual(t, model.Event{}.ID, events[0].ID)
+				assert.False(t, events[0].CreatedAt.IsZero())
+				return nil
+			})
+			db.ExpectCommit()
+
+			expectRelay(repo, outbox, db, event)
+		})
+		defer mock.Finish()
+
+		err := bus.Publish(context.Background(), event)
+		assert.Nil(t, err)
+		assert.Nil(t, bus.Close(context.Background()))
+
+		assert.ElementsMatch(t, []string{"sync", "async"}, received)
+		assert.Nil(t, db.ExpectationsWereMet())
+	})
+
+	t.Run("when_transaction_rolled_back", func(t *testing.T) {
+		var mu sync.Mutex
+		var received []string
+
+		bus, _, mock := createBus(t, []model.Handler{
+			{Name: "sync", Handle: record(&mu, &received, "sync")},
+		}, func(repo *repository.MockEventRepository, outbox *repository.MockOutboxRepository, db sqlmock.Sqlmock) {
+			outbox.EXPECT().Insert(gomock.Any(), gomock.Any()).Return(nil)
+		})
+		defer mock.Finish()
+
+		// the bus joins the transaction of the caller
+		callerDb, db, _ := sqlmock.New()
+		defer callerDb.Close()
+		db.ExpectBegin()
+		db.ExpectRollback()
+
+		err := database.WithTx(context.Background(), callerDb, func(ctx context.Context) error {
+			if err := bus.Publish(ctx, event); err != nil {
+				return err
+			}
+
+			return errors.New("some-error")
+		})
+
+		assert.Equal(t, errors.New("some-error"), err)
+		assert.Empty(t, received)
+		assert.Nil(t, db.ExpectationsWereMet())
+	})
+
+	t.Run("when_outbox_fails", func(t *testing.T) {
+		called := false
+
+		bus, db, mock := createBus(t, []model.Handler{
+			{Name: "handler", Handle: func(ctx context.Context, event model.Event) error {
+				called = true
+				return nil
+			}},
+		}, func(repo *repository.MockEventRepository, outbox *repository.MockOutboxRepository, db sqlmock.Sqlmock) {
+			db.ExpectBegin()
+			outbox.EXPECT().Insert(gomock.Any(), gomock.Any()).Return(errors.New("some-error"))
+			db.ExpectRollback()
+		})
+		defer mock.Finish()
+
+		err := bus.Publish(context.Background(), event)
+
+		assert.Equal(t, errors.New("some-error"), err)
+		assert.False(t, called)
+		assert.Nil(t, db.ExpectationsWereMet())
+	})
+
+	t.Run("when_relay_fails", func(t *testing.T) {
+		called := false
+
+		bus, db, mock := createBus(t, []model.Handler{
+			{Name: "handler", Handle: func(ctx context.Context, event model.Event) error {
+				called = true
+				return nil
+			}},
+		}, func(repo *repository.MockEventRepository, outbox *repository.MockOutboxRepository, db sqlmock.Sqlmock) {
+			db.ExpectBegin()
+			outbox.EXPECT().Insert(gomock.Any(), gomock.Any()).Return(nil)
+			db.ExpectCommit()
+
+			db.ExpectBegin()
+			outbox.EXPECT().Claim(gomock.Any(), 100).Return([]model.Event{event}, nil)
+			repo.EXPECT().Insert(gomock.Any(), event).Return(errors.New("some-error"))
+			db.ExpectRollback()
+		})
+		defer mock.Finish()
+
+		// the events were committed to the outbox, the relay worker retries
+		err := bus.Publish(context.Background(), event)
+
+		assert.Nil(t, err)
+		assert.False(t, called)
+		assert.Nil(t, db.ExpectationsWereMet())
+	})
+}
+
+func Test_Relay(t *testing.T) {
+	first := model.Event{Type: "some-event", StreamID: "some-stream"}
+	second := model.Event{Type: "some-event", StreamID: "other-stream"}
+
+	t.Run("when_success", func(t *testing.T) {
+		var received []string
+
+		bus, db, mock := createBus(t, []model.Handler{
+			{Name: "healthy", Handle: func(ctx context.Context, event model.Event) error {
+				received = append(received, event.StreamID)
+				return nil
+			}},
+		}, func(repo *repository.MockEventRepository, outbox *repository.MockOutboxRepository, db sqlmock.Sqlmock) {
+			gomock.InOrder(
+				repo.EXPECT().Insert(gomock.Any(), first).Return(nil),
+				repo.EXPECT().Insert(gomock.Any(), second).Return(nil),
+			)
+
+			db.ExpectBegin()
+			outbox.EXPECT().Claim(gomock.Any(), 100).Return([]model.Event{first, second}, nil)
+			db.ExpectCommit()
+
+			db.ExpectBegin()
+			outbox.EXPECT().Claim(gomock.Any(), 100).Return([]model.Event{}, nil)
+			db.ExpectCommit()
+		})
+		defer mock.Finish()
+
+		err := bus.Relay(context.Background())
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"some-stream", "other-stream"}, received)
+		assert.Nil(t, db.ExpectationsWereMet())
+	})
+
+	t.Run("when_handler_fails", func(t *testing.T) {
+		bus, db, mock := createBus(t, []model.Handler{
+			{Name: "failing", Handle: func(ctx context.Context, event model.Event) error {
+				return errors.New("some-error")
+			}},
+		}, func(repo *repository.MockEventRepository, outbox *repository.MockOutboxRepository, db sqlmock.Sqlmock) {
+			db.ExpectBegin()
+			outbox.EXPECT().Claim(gomock.Any(), 100).Return([]model.Event{first, second}, nil)
+			repo.EXPECT().Insert(gomock.Any(), first).Return(nil)
+			repo.EXPECT().Insert(gomock.Any(), second).Return(nil)
+			db.ExpectRollback()
+
+			db.ExpectBegin()
+			outbox.EXPECT().Fail(gomock.Any(), first.ID).Return(1, nil)
+			db.ExpectCommit()
+		})
+		defer mock.Finish()
+
+		err := bus.Relay(context.Background())
+
+		assert.Equal(t, service.HandlerError{Handler: "failing", Event: first, Err: errors.New("some-error")}, err)
+		assert.Nil(t, db.ExpectationsWereMet())
+	})
+
+	t.Run("when_handler_panics", func(t *testing.T) {
+		bus, db, mock := createBus(t, []model.Handler{
+			{Name: "panicking", Handle: func(ctx context.Context, event model.Event) error {
+				panic("some-panic")
+			}},
+		}, func(repo *repository.MockEventRepository, outbox *repository.MockOutboxRepository, db sqlmock.Sqlmock) {
+			db.ExpectBegin()
+			outbox.EXPECT().Claim(gomock.Any(), 100).Return([]model.Event{first}, nil)
+			repo.EXPECT().Insert(gomock.Any(), first).Return(nil)
+			db.ExpectRollback()
+
+			db.ExpectBegin()
+			outbox.EXPECT().Fail(gomock.Any(), first.ID).Return(1, nil)
+			db.ExpectCommit()
+		})
+		defer mock.Finish()
+
+		err := bus.Relay(context.Background())
+
+		assert.Equal(t, service.HandlerError{Handler: "panicking", Event: first, Err: errors.New("panic: some-panic")}, err)
+		assert.Nil(t, db.ExpectationsWereMet())
+	})
+
+	t.Run("when_handler_fails_too_often", func(t *testing.T) {
+		var received []string
+
+		bus, db, mock := createBus(t, []model.Handler{
+			{Name: "failing", Handle: func(ctx context.Context, event model.Event) error {
+				if event.StreamID == first.StreamID {
+					return errors.New("some-error")
+				}
+
+				received = append(received, event.StreamID)
+				return nil
+			}},
+		}, func(repo *repository.MockEventRepository, outbox *repository.MockOutboxRepository, db sqlmock.Sqlmock) {
+			db.ExpectBegin()
+			outbox.EXPECT().Claim(gomock.Any(), 100).Return([]model.Event{first, second}, nil)
+			repo.EXPECT().Insert(gomock.Any(), first).Return(nil)
+			repo.EXPECT().Insert(gomock.Any(), second).Return(nil)
+			db.ExpectRollback()
+
+			db.ExpectBegin()
+			outbox.EXPECT().Fail(gomock.Any(), first.ID).Return(3, nil)
+			outbox.EXPECT().DeadLetter(gomock.Any(), first.ID, "failing", "some-error").Return(nil)
+			db.ExpectCommit()
+
+			// the events after the dead lettered one are relayed right away
+			expectRelay(repo, outbox, db, second)
+		})
+		defer mock.Finish()
+
+		err := bus.Relay(context.Background())
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"other-stream"}, received)
+		assert.Nil(t, db.ExpectationsWereMet())
+	})
+
+	t.Run("when_claim_fails", func(t *testing.T) {
+		bus, db, mock := createBus(t, nil, func(repo *repository.MockEventRepository, outbox *repository.MockOutboxRepository, db sqlmock.Sqlmock) {
+			db.ExpectBegin()
+			outbox.EXPECT().Claim(gomock.Any(), 100).Return(nil, errors.New("some-error"))
+			db.ExpectRollback()
+		})
+		defer mock.Finish()
+
+		err := bus.Relay(context.Background())
+
+		assert.Equal(t, errors.New("some-error"), err)
+		assert.Nil(t, db.ExpectationsWereMet())
+	})
+}
+
+func Test_Lag(t *testing.T) {
+	release := make(chan struct{})
+	event := model.Event{Type: "some-event", CreatedAt: time.Now().Add(-time.Minute)}
+
+	bus, _, mock := createBus(t, []model.Handler{
+		{Name: "some-handler", Handle: func(ctx context.Context, event model.Event) error { return nil }},
+		{Name: "slow-handler", Async: true, Handle: func(ctx context.Context, event model.Event) error {
+			<-release
+			return nil
+		}},
+	}, func(repo *repository.MockEventRepository, outbox *repository.MockOutboxRepository, db sqlmock.Sqlmock) {
+		expectRelay(repo, outbox, db, event)
+	})
+	defer mock.Finish()
+
+	assert.Empty(t, bus.Lag())
+
+	err := bus.Relay(context.Background())
+	assert.Nil(t, err)
+
+	actual := bus.Lag()
+	assert.Len(t, actual, 1)
+	assert.GreaterOrEqual(t, actual["slow-handler"], time.Minute)
+
+	close(release)
+	assert.Nil(t, bus.Close(context.Background()))
+
+	assert.Empty(t, bus.Lag())
+}
@@ -0,0 +1,16 @@
+package service
+
+import (
+	"github.com/tesarwijaya/ouroboros/internal/config"
+	"github.com/tesarwijaya/ouroboros/internal/worker"
+)
+
+// NewRelayWorker periodically relays the events left in the outbox, e.g. when
+// the event store was down once they were committed.
+func NewRelayWorker(cfg *config.Config, bus EventBus) worker.Worker {
+	return worker.Worker{
+		Name:     "outbox_relay",
+		Interval: cfg.OutboxRelayInterval,
+		Run:      bus.Relay,
+	}
+}
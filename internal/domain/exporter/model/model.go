@@ -0,0 +1,96 @@
+package model
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+const (
+	FORMAT_CSV    = "csv"
+	FORMAT_NDJSON = "ndjson"
+
+	KIND_TEAMS     = "teams"
+	KIND_PLAYERS   = "players"
+	KIND_TRANSFERS = "transfers"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown format, use csv or ndjson")
+	ErrUnknownKind   = errors.New("unknown export, use teams, players or transfers")
+)
+
+// Filter narrows an export down like the list commands do. Players and
+// transfers are filtered by team, a transfer matching when the player left or
+// joined it, and transfers by player as well. Zero values don't filter.
+type Filter struct {
+	TeamID   int64
+	PlayerID int64
+}
+
+// Encoder writes the exported rows one at a time, values make a CSV record
+// while v is encoded as a JSON line.
+type Encoder interface {
+	Encode(values []string, v interface{}) error
+	Flush() error
+}
+
+// NewEncoder writes the CSV header along the first row, or on Flush when
+// there is none, so that nothing is written to w before a row was read.
+func NewEncoder(w io.Writer, format string, header []string) (Encoder, error) {
+	switch format {
+	case FORMAT_CSV:
+		return &csvEncoder{w: csv.NewWriter(w), header: header}, nil
+	case FORMAT_NDJSON:
+		return &ndjsonEncoder{enc: json.NewEncoder(w)}, nil
+	}
+
+	return nil, ErrUnknownFormat
+}
+
+type csvEncoder struct {
+	w      *csv.Writer
+	header []string
+}
+
+func (e *csvEncoder) writeHeader() error {
+	if e.header == nil {
+		return nil
+	}
+
+	header := e.header
+	e.header = nil
+
+	return e.w.Write(header)
+}
+
+func (e *csvEncoder) Encode(values []string, v interface{}) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+
+	return e.w.Write(values)
+}
+
+func (e *csvEncoder) Flush() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+
+	e.w.Flush()
+
+	return e.w.Error()
+}
+
+type ndjsonEncoder struct {
+	enc *json.Encoder
+}
+
+func (e *ndjsonEncoder) Encode(values []string, v interface{}) error {
+	return e.enc.Encode(v)
+}
+
+func (e *ndjsonEncoder) Flush() error {
+	return nil
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	auth_model "github.com/tesarwijaya/ouroboros/internal/domain/auth/model"
+	auth_service "github.com/tesarwijaya/ouroboros/internal/domain/auth/service"
+	command_model "github.com/tesarwijaya/ouroboros/internal/domain/command/model"
+	event_model "github.com/tesarwijaya/ouroboros/internal/domain/event/model"
+	event_repository "github.com/tesarwijaya/ouroboros/internal/domain/event/repository"
+	"github.com/tesarwijaya/ouroboros/internal/domain/exporter/model"
+	player_model "github.com/tesarwijaya/ouroboros/internal/domain/player/model"
+	player_repository "github.com/tesarwijaya/ouroboros/internal/domain/player/repository"
+	team_model "github.com/tesarwijaya/ouroboros/internal/domain/team/model"
+	team_repository "github.com/tesarwijaya/ouroboros/internal/domain/team/repository"
+	"github.com/tesarwijaya/ouroboros/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/dig"
+)
+
+var (
+	teamHeader     = []string{"id", "name"}
+	playerHeader   = []string{"id", "name", "team_id"}
+	transferHeader = []string{"player_id", "from_team_id", "to_team_id", "transferred_at"}
+)
+
+type ExportService interface {
+	Export(ctx context.Context, kind string, format string, filter model.Filter, w io.Writer) error
+}
+
+type ExportServiceImpl struct {
+	dig.In
+	TeamRepo   team_repository.TeamRepository
+	PlayerRepo player_repository.PlayerRepository
+	EventRepo  event_repository.EventRepository
+	Authz      auth_service.Authorizer
+}
+
+func NewExportService(svc ExportServiceImpl) ExportService {
+	return &svc
+}
+
+// Export writes every row of kind to w in format as it is read, teams and
+// players from the database and transfers from the event store, so that the
+// memory used doesn't grow with the data. The arguments are checked and the
+// caller authorized before anything is written.
+func (s *ExportServiceImpl) Export(ctx context.Context, kind string, format string, filter model.Filter, w io.Writer) error {
+	ctx, span := tracing.Start(ctx, "ExportService.Export",
+		attribute.String("export.kind", kind),
+		attribute.String("export.format", format),
+	)
+	defer span.End()
+
+	if format != model.FORMAT_CSV && format != model.FORMAT_NDJSON {
+		return command_model.ValidationError{Err: model.ErrUnknownFormat}
+	}
+
+	var err error
+	switch kind {
+	case model.KIND_TEAMS:
+		err = s.exportTeams(ctx, format, filter, w)
+	case model.KIND_PLAYERS:
+		err = s.exportPlayers(ctx, format, filter, w)
+	case model.KIND_TRANSFERS:
+		err = s.exportTransfers(ctx, format, filter, w)
+	default:
+		err = command_model.ValidationError{Err: model.ErrUnknownKind}
+	}
+	tracing.End(span, err)
+
+	return err
+}
+
+func (s *ExportServiceImpl) exportTeams(ctx context.Context, format string, filter model.Filter, w io.Writer) error {
+	if filter != (model.Filter{}) {
+		return command_model.ValidationError{Err: errors.New("teams can't be filtered")}
+	}
+
+	if err := s.Authz.Authorize(ctx, auth_model.ACTION_TEAM_READ, auth_model.Resource{}); err != nil {
+		return err
+	}
+
+	enc, _ := model.NewEncoder(w, format, teamHeader)
+	if err := s.TeamRepo.StreamAll(ctx, func(team team_model.TeamModel) error {
+		return enc.Encode([]string{formatID(team.ID), team.Name}, team)
+	}); err != nil {
+		return err
+	}
+
+	return enc.Flush()
+}
+
+func (s *ExportServiceImpl) exportPlayers(ctx context.Context, format string, filter model.Filter, w io.Writer) error {
+	if filter.PlayerID != 0 {
+		return command_model.ValidationError{Err: errors.New("players can only be filtered by team")}
+	}
+
+	if err := s.Authz.Authorize(ctx, auth_model.ACTION_PLAYER_READ, auth_model.Resource{TeamID: filter.TeamID}); err != nil {
+		return err
+	}
+
+	enc, _ := model.NewEncoder(w, format, playerHeader)
+	if err := s.PlayerRepo.StreamAll(ctx, filter.TeamID, func(player player_model.PlayerModel) error {
+		return enc.Encode([]string{formatID(player.ID), player.Name, formatID(player.TeamID)}, player)
+	}); err != nil {
+		return err
+	}
+
+	return enc.Flush()
+}
+
+// exportTransfers reads the stream of the player when filtered by player,
+// the whole event store otherwise.
+func (s *ExportServiceImpl) exportTransfers(ctx context.Context, format string, filter model.Filter, w io.Writer) error {
+	resource := auth_model.Resource{TeamID: filter.TeamID}
+	if filter.PlayerID != 0 {
+		player, err := s.PlayerRepo.FindByID(ctx, filter.PlayerID)
+		if err != nil {
+			return err
+		}
+
+		resource.TeamID = player.TeamID
+	}
+
+	if err := s.Authz.Authorize(ctx, auth_model.ACTION_PLAYER_READ, resource); err != nil {
+		return err
+	}
+
+	enc, _ := model.NewEncoder(w, format, transferHeader)
+	reader := player_model.NewTransferReader()
+	read := func(event event_model.Event) error {
+		if !strings.HasPrefix(event.StreamID, player_model.PLAYER_STREAM_PREFIX) {
+			return nil
+		}
+
+		transfer, ok, err := reader.Read(event)
+		if err != nil || !ok {
+			return err
+		}
+
+		if filter.TeamID != 0 && transfer.FromTeamID != filter.TeamID && transfer.ToTeamID != filter.TeamID {
+			return nil
+		}
+
+		return enc.Encode([]string{
+			formatID(transfer.PlayerID),
+			formatID(transfer.FromTeamID),
+			formatID(transfer.ToTeamID),
+			transfer.TransferredAt.UTC().Format(time.RFC3339Nano),
+		}, transfer)
+	}
+
+	if filter.PlayerID == 0 {
+		if err := s.EventRepo.ReadAll(ctx, read); err != nil {
+			return err
+		}
+
+		return enc.Flush()
+	}
+
+	events, err := s.EventRepo.ReadStream(ctx, player_model.PlayerStreamID(filter.PlayerID), 0)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		if err := read(event); err != nil {
+			return err
+		}
+	}
+
+	return enc.Flush()
+}
+
+// formatID leaves unset ids, e.g. the team a player came from on its first
+// transfer, empty.
+func formatID(id int64) string {
+	if id == 0 {
+		return ""
+	}
+
+	return strconv.FormatInt(id, 10)
+}
@@ -0,0 +1,249 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	auth_model "github.com/tesarwijaya/ouroboros/internal/domain/auth/model"
+	auth_service "github.com/tesarwijaya/ouroboros/internal/domain/auth/service"
+	command_model "github.com/tesarwijaya/ouroboros/internal/domain/command/model"
+	event_model "github.com/tesarwijaya/ouroboros/internal/domain/event/model"
+	event_repository "github.com/tesarwijaya/ouroboros/internal/domain/event/repository"
+	"github.com/tesarwijaya/ouroboros/internal/domain/exporter/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/exporter/service"
+	player_model "github.com/tesarwijaya/ouroboros/internal/domain/player/model"
+	player_repository "github.com/tesarwijaya/ouroboros/internal/domain/player/repository"
+	team_model "github.com/tesarwijaya/ouroboros/internal/domain/team/model"
+	team_repository "github.com/tesarwijaya/ouroboros/internal/domain/team/repository"
+)
+
+type resolverFn func(teamRepo *team_repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository, eventRepo *event_repository.MockEventRepository)
+
+func createService(t *testing.T, resolver resolverFn, authz func(authz *auth_service.MockAuthorizer)) (*service.ExportServiceImpl, *gomock.Controller) {
+	ctrl := gomock.NewController(t)
+
+	teamRepo := team_repository.NewMockTeamRepository(ctrl)
+	playerRepo := player_repository.NewMockPlayerRepository(ctrl)
+	eventRepo := event_repository.NewMockEventRepository(ctrl)
+	resolver(teamRepo, playerRepo, eventRepo)
+
+	authorizer := auth_service.NewMockAuthorizer(ctrl)
+	authz(authorizer)
+
+	return &service.ExportServiceImpl{
+		TeamRepo:   teamRepo,
+		PlayerRepo: playerRepo,
+		EventRepo:  eventRepo,
+		Authz:      authorizer,
+	}, ctrl
+}
+
+func allow(authz *auth_service.MockAuthorizer) {
+	authz.EXPECT().Authorize(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+}
+
+func streamTeams(teams ...team_model.TeamModel) func(ctx context.Context, fn func(team_model.TeamModel) error) error {
+	return func(ctx context.Context, fn func(team_model.TeamModel) error) error {
+		for _, team := range teams {
+			if err := fn(team); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+func streamPlayers(players ...player_model.PlayerModel) func(ctx context.Context, teamID int64, fn func(player_model.PlayerModel) error) error {
+	return func(ctx context.Context, teamID int64, fn func(player_model.PlayerModel) error) error {
+		for _, player := range players {
+			if err := fn(player); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+func streamEvents(events ...event_model.Event) func(ctx context.Context, fn func(event_model.Event) error) error {
+	return func(ctx context.Context, fn func(event_model.Event) error) error {
+		for _, event := range events {
+			if err := fn(event); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+func Test_NewExportService(t *testing.T) {
+	svc := service.NewExportService(service.ExportServiceImpl{})
+
+	assert.Implements(t, (*service.ExportService)(nil), svc)
+}
+
+func Test_Export(t *testing.T) {
+	at := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	transfers := []event_model.Event{
+		{StreamID: "player-1", Type: player_model.PLAYER_TRANSFER_OUT_EVENT, Data: []byte(`{"PlayerID":1,"TeamID":2}`), CreatedAt: at},
+		{StreamID: "player-2", Type: player_model.PLAYER_TRANSFER_OUT_EVENT, Data: []byte(`{"PlayerID":2,"TeamID":3}`), CreatedAt: at},
+		{StreamID: "team-2", Type: "team_renamed", Data: []byte(`{}`), CreatedAt: at},
+		{StreamID: "player-2", Type: player_model.PLAYER_TRANSFER_IN_EVENT, Data: []byte(`{"PlayerID":2,"TeamID":4}`), CreatedAt: at},
+		{StreamID: "player-1", Type: player_model.PLAYER_TRANSFER_IN_EVENT, Data: []byte(`{"PlayerID":1,"TeamID":3}`), CreatedAt: at},
+	}
+
+	testCases := []struct {
+		Name      string
+		Kind      string
+		Format    string
+		Filter    model.Filter
+		Resolver  resolverFn
+		Authz     func(authz *auth_service.MockAuthorizer)
+		Expect    string
+		ExpectErr error
+	}{
+		{
+			Name:   "when_teams_csv",
+			Kind:   model.KIND_TEAMS,
+			Format: model.FORMAT_CSV,
+			Resolver: func(teamRepo *team_repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository, eventRepo *event_repository.MockEventRepository) {
+				teamRepo.EXPECT().StreamAll(gomock.Any(), gomock.Any()).
+					DoAndReturn(streamTeams(team_model.TeamModel{ID: 1, Name: "some-team-name"}, team_model.TeamModel{ID: 2, Name: "other, team"}))
+			},
+			Authz:  allow,
+			Expect: "id,name\n1,some-team-name\n2,\"other, team\"\n",
+		},
+		{
+			Name:   "when_teams_empty",
+			Kind:   model.KIND_TEAMS,
+			Format: model.FORMAT_CSV,
+			Resolver: func(teamRepo *team_repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository, eventRepo *event_repository.MockEventRepository) {
+				teamRepo.EXPECT().StreamAll(gomock.Any(), gomock.Any()).DoAndReturn(streamTeams())
+			},
+			Authz:  allow,
+			Expect: "id,name\n",
+		},
+		{
+			Name:   "when_players_ndjson",
+			Kind:   model.KIND_PLAYERS,
+			Format: model.FORMAT_NDJSON,
+			Filter: model.Filter{TeamID: 1},
+			Resolver: func(teamRepo *team_repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository, eventRepo *event_repository.MockEventRepository) {
+				playerRepo.EXPECT().StreamAll(gomock.Any(), int64(1), gomock.Any()).
+					DoAndReturn(streamPlayers(player_model.PlayerModel{ID: 1, Name: "some-player-name", TeamID: 1}))
+			},
+			Authz: func(authz *auth_service.MockAuthorizer) {
+				authz.EXPECT().Authorize(gomock.Any(), auth_model.ACTION_PLAYER_READ, auth_model.Resource{TeamID: 1}).Return(nil)
+			},
+			Expect: "{\"id\":1,\"name\":\"some-player-name\",\"teamId\":1}\n",
+		},
+		{
+			Name:   "when_transfers_csv",
+			Kind:   model.KIND_TRANSFERS,
+			Format: model.FORMAT_CSV,
+			Resolver: func(teamRepo *team_repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository, eventRepo *event_repository.MockEventRepository) {
+				eventRepo.EXPECT().ReadAll(gomock.Any(), gomock.Any()).DoAndReturn(streamEvents(transfers...))
+			},
+			Authz:  allow,
+			Expect: "player_id,from_team_id,to_team_id,transferred_at\n2,3,4,2023-01-02T03:04:05Z\n1,2,3,2023-01-02T03:04:05Z\n",
+		},
+		{
+			Name:   "when_transfers_by_team",
+			Kind:   model.KIND_TRANSFERS,
+			Format: model.FORMAT_NDJSON,
+			Filter: model.Filter{TeamID: 2},
+			Resolver: func(teamRepo *team_repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository, eventRepo *event_repository.MockEventRepository) {
+				eventRepo.EXPECT().ReadAll(gomock.Any(), gomock.Any()).DoAndReturn(streamEvents(transfers...))
+			},
+			Authz: func(authz *auth_service.MockAuthorizer) {
+				authz.EXPECT().Authorize(gomock.Any(), auth_model.ACTION_PLAYER_READ, auth_model.Resource{TeamID: 2}).Return(nil)
+			},
+			Expect: "{\"playerId\":1,\"fromTeamId\":2,\"toTeamId\":3,\"transferredAt\":\"2023-01-02T03:04:05Z\"}\n",
+		},
+		{
+			Name:   "when_transfers_by_player",
+			Kind:   model.KIND_TRANSFERS,
+			Format: model.FORMAT_CSV,
+			Filter: model.Filter{PlayerID: 1},
+			Resolver: func(teamRepo *team_repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository, eventRepo *event_repository.MockEventRepository) {
+				playerRepo.EXPECT().FindByID(gomock.Any(), int64(1)).Return(player_model.PlayerModel{ID: 1, TeamID: 3}, nil)
+				eventRepo.EXPECT().ReadStream(gomock.Any(), "player-1", uint64(0)).Return([]event_model.Event{transfers[0], transfers[4]}, nil)
+			},
+			Authz: func(authz *auth_service.MockAuthorizer) {
+				authz.EXPECT().Authorize(gomock.Any(), auth_model.ACTION_PLAYER_READ, auth_model.Resource{TeamID: 3}).Return(nil)
+			},
+			Expect: "player_id,from_team_id,to_team_id,transferred_at\n1,2,3,2023-01-02T03:04:05Z\n",
+		},
+		{
+			Name:   "when_forbidden",
+			Kind:   model.KIND_PLAYERS,
+			Format: model.FORMAT_CSV,
+			Resolver: func(teamRepo *team_repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository, eventRepo *event_repository.MockEventRepository) {
+			},
+			Authz: func(authz *auth_service.MockAuthorizer) {
+				authz.EXPECT().Authorize(gomock.Any(), auth_model.ACTION_PLAYER_READ, auth_model.Resource{}).
+					Return(auth_model.ForbiddenError{Subject: "some-user", Action: auth_model.ACTION_PLAYER_READ})
+			},
+			ExpectErr: auth_model.ForbiddenError{Subject: "some-user", Action: auth_model.ACTION_PLAYER_READ},
+		},
+		{
+			Name:   "when_stream_fails",
+			Kind:   model.KIND_PLAYERS,
+			Format: model.FORMAT_CSV,
+			Resolver: func(teamRepo *team_repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository, eventRepo *event_repository.MockEventRepository) {
+				playerRepo.EXPECT().StreamAll(gomock.Any(), int64(0), gomock.Any()).Return(errors.New("some-error"))
+			},
+			Authz:     allow,
+			ExpectErr: errors.New("some-error"),
+		},
+		{
+			Name:   "when_teams_filtered",
+			Kind:   model.KIND_TEAMS,
+			Format: model.FORMAT_CSV,
+			Filter: model.Filter{TeamID: 1},
+			Resolver: func(teamRepo *team_repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository, eventRepo *event_repository.MockEventRepository) {
+			},
+			Authz:     allow,
+			ExpectErr: command_model.ValidationError{Err: errors.New("teams can't be filtered")},
+		},
+		{
+			Name:   "when_kind_unknown",
+			Kind:   "coaches",
+			Format: model.FORMAT_CSV,
+			Resolver: func(teamRepo *team_repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository, eventRepo *event_repository.MockEventRepository) {
+			},
+			Authz:     allow,
+			ExpectErr: command_model.ValidationError{Err: model.ErrUnknownKind},
+		},
+		{
+			Name:   "when_format_unknown",
+			Kind:   model.KIND_TEAMS,
+			Format: "xml",
+			Resolver: func(teamRepo *team_repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository, eventRepo *event_repository.MockEventRepository) {
+			},
+			Authz:     allow,
+			ExpectErr: command_model.ValidationError{Err: model.ErrUnknownFormat},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			svc, mock := createService(t, test.Resolver, test.Authz)
+			defer mock.Finish()
+
+			var w bytes.Buffer
+			err := svc.Export(context.Background(), test.Kind, test.Format, test.Filter, &w)
+
+			assert.Equal(t, test.ExpectErr, err)
+			assert.Equal(t, test.Expect, w.String())
+		})
+	}
+}
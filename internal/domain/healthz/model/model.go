@@ -0,0 +1,34 @@
+package model
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	STATUS_UP   = "up"
+	STATUS_DOWN = "down"
+)
+
+// Check is a dependency probed by the readiness endpoint. A failing check
+// only fails the readiness when it is Required, a zero Timeout falls back to
+// the configured default.
+type Check struct {
+	Name     string
+	Required bool
+	Timeout  time.Duration
+	Check    func(ctx context.Context) error
+}
+
+type CheckResult struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	Required  bool    `json:"required"`
+	LatencyMs float64 `json:"latencyMs"`
+	Error     string  `json:"error,omitempty"`
+}
+
+type Report struct {
+	Status string        `json:"status"`
+	Checks []CheckResult `json:"checks,omitempty"`
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/tesarwijaya/ouroboros/internal/domain/healthz/model"
+	"github.com/tesarwijaya/ouroboros/migrations"
+)
+
+func NewSQLCheck(db *sql.DB) model.Check {
+	return model.Check{
+		Name:     "postgres",
+		Required: true,
+		Check: func(ctx context.Context) error {
+			return db.PingContext(ctx)
+		},
+	}
+}
+
+// NewMigrationCheck fails when the schema is behind the migrations shipped
+// with this build or when the last migration was left dirty.
+func NewMigrationCheck(db *sql.DB) model.Check {
+	return model.Check{
+		Name:     "migrations",
+		Required: true,
+		Check: func(ctx context.Context) error {
+			expected, err := migrations.LatestVersion()
+			if err != nil {
+				return err
+			}
+
+			var (
+				version uint
+				dirty   bool
+			)
+			err = db.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+			if err != nil {
+				return fmt.Errorf("read schema version: %w", err)
+			}
+
+			if dirty {
+				return fmt.Errorf("migration %d is dirty", version)
+			}
+
+			if version < expected {
+				return fmt.Errorf("schema version is %d, expected %d", version, expected)
+			}
+
+			return nil
+		},
+	}
+}
@@ -2,32 +2,112 @@ package service
 
 import (
 	"context"
-	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
 
+	"github.com/tesarwijaya/ouroboros/internal/config"
+	"github.com/tesarwijaya/ouroboros/internal/domain/healthz/model"
+	"github.com/tesarwijaya/ouroboros/internal/tracing"
 	"go.uber.org/dig"
 )
 
 type HealthzService interface {
-	Healthz(ctx context.Context) (map[string]interface{}, error)
+	Livez(ctx context.Context) model.Report
+	Readyz(ctx context.Context) model.Report
 }
 
 type HealthzServiceImpl struct {
 	dig.In
-	Sql *sql.DB
+	Config *config.Config
+	Checks []model.Check `group:"healthz_checks"`
 }
 
 func NewHealthzService(svc HealthzServiceImpl) HealthzService {
 	return &svc
 }
 
-func (s *HealthzServiceImpl) Healthz(ctx context.Context) (map[string]interface{}, error) {
-	DBStatus := "UP!"
-	err := s.Sql.Ping()
+// Livez only tells that the process is able to serve requests, it must not
+// depend on anything outside of it or a broken dependency restarts every pod.
+func (s *HealthzServiceImpl) Livez(ctx context.Context) model.Report {
+	return model.Report{Status: model.STATUS_UP}
+}
+
+// Readyz runs every registered check concurrently, the report is down when
+// any required check fails.
+func (s *HealthzServiceImpl) Readyz(ctx context.Context) model.Report {
+	ctx, span := tracing.Start(ctx, "HealthzService.Readyz")
+	defer span.End()
+
+	report := model.Report{
+		Status: model.STATUS_UP,
+		Checks: make([]model.CheckResult, len(s.Checks)),
+	}
+
+	var wg sync.WaitGroup
+	for i, check := range s.Checks {
+		wg.Add(1)
+		go func(i int, check model.Check) {
+			defer wg.Done()
+
+			report.Checks[i] = s.run(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	for _, res := range report.Checks {
+		if res.Required && res.Status != model.STATUS_UP {
+			report.Status = model.STATUS_DOWN
+		}
+	}
+
+	return report
+}
+
+func (s *HealthzServiceImpl) run(ctx context.Context, check model.Check) model.CheckResult {
+	timeout := check.Timeout
+	if timeout == 0 {
+		timeout = s.Config.HealthzCheckTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+
+	// a check ignoring ctx must not hold the probe past its timeout
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+
+		done <- check.Check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	res := model.CheckResult{
+		Name:      check.Name,
+		Status:    model.STATUS_UP,
+		Required:  check.Required,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
 	if err != nil {
-		DBStatus = err.Error()
+		res.Status = model.STATUS_DOWN
+		res.Error = err.Error()
+		if errors.Is(err, context.DeadlineExceeded) {
+			res.Error = fmt.Sprintf("timed out after %s", timeout)
+		}
 	}
 
-	return map[string]interface{}{
-		"DBStatus": DBStatus,
-	}, nil
+	return res
 }
@@ -0,0 +1,135 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tesarwijaya/ouroboros/internal/config"
+	"github.com/tesarwijaya/ouroboros/internal/domain/healthz/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/healthz/service"
+)
+
+func createService(checks ...model.Check) *service.HealthzServiceImpl {
+	return &service.HealthzServiceImpl{
+		Config: &config.Config{HealthzCheckTimeout: 50 * time.Millisecond},
+		Checks: checks,
+	}
+}
+
+func up(ctx context.Context) error {
+	return nil
+}
+
+func down(ctx context.Context) error {
+	return errors.New("some-error")
+}
+
+func Test_NewHealthzService(t *testing.T) {
+	svc := service.NewHealthzService(service.HealthzServiceImpl{})
+
+	assert.Implements(t, (*service.HealthzService)(nil), svc)
+}
+
+func Test_Livez(t *testing.T) {
+	svc := createService(model.Check{Name: "some-check", Required: true, Check: down})
+
+	actual := svc.Livez(context.Background())
+
+	assert.Equal(t, model.Report{Status: model.STATUS_UP}, actual)
+}
+
+func Test_Readyz(t *testing.T) {
+	testCases := []struct {
+		Name           string
+		Checks         []model.Check
+		ExpectedStatus string
+		ExpectedChecks []model.CheckResult
+	}{
+		{
+			Name:           "when_no_checks",
+			ExpectedStatus: model.STATUS_UP,
+			ExpectedChecks: []model.CheckResult{},
+		},
+		{
+			Name: "when_all_up",
+			Checks: []model.Check{
+				{Name: "some-check", Required: true, Check: up},
+				{Name: "other-check", Check: up},
+			},
+			ExpectedStatus: model.STATUS_UP,
+			ExpectedChecks: []model.CheckResult{
+				{Name: "some-check", Status: model.STATUS_UP, Required: true},
+				{Name: "other-check", Status: model.STATUS_UP},
+			},
+		},
+		{
+			Name: "when_optional_down",
+			Checks: []model.Check{
+				{Name: "some-check", Required: true, Check: up},
+				{Name: "other-check", Check: down},
+			},
+			ExpectedStatus: model.STATUS_UP,
+			ExpectedChecks: []model.CheckResult{
+				{Name: "some-check", Status: model.STATUS_UP, Required: true},
+				{Name: "other-check", Status: model.STATUS_DOWN, Error: "some-error"},
+			},
+		},
+		{
+			Name: "when_required_down",
+			Checks: []model.Check{
+				{Name: "some-check", Required: true, Check: down},
+				{Name: "other-check", Check: up},
+			},
+			ExpectedStatus: model.STATUS_DOWN,
+			ExpectedChecks: []model.CheckResult{
+				{Name: "some-check", Status: model.STATUS_DOWN, Required: true, Error: "some-error"},
+				{Name: "other-check", Status: model.STATUS_UP},
+			},
+		},
+		{
+			Name: "when_timed_out",
+			Checks: []model.Check{
+				{Name: "some-check", Required: true, Timeout: 10 * time.Millisecond, Check: func(ctx context.Context) error {
+					time.Sleep(time.Second)
+					return nil
+				}},
+			},
+			ExpectedStatus: model.STATUS_DOWN,
+			ExpectedChecks: []model.CheckResult{
+				{Name: "some-check", Status: model.STATUS_DOWN, Required: true, Error: "timed out after 10ms"},
+			},
+		},
+		{
+			Name: "when_panicked",
+			Checks: []model.Check{
+				{Name: "some-check", Required: true, Check: func(ctx context.Context) error {
+					panic("some-panic")
+				}},
+			},
+			ExpectedStatus: model.STATUS_DOWN,
+			ExpectedChecks: []model.CheckResult{
+				{Name: "some-check", Status: model.STATUS_DOWN, Required: true, Error: "panic: some-panic"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			svc := createService(test.Checks...)
+
+			actual := svc.Readyz(context.Background())
+
+			// latency can't be asserted, only that it was measured
+			for i := range actual.Checks {
+				assert.GreaterOrEqual(t, actual.Checks[i].LatencyMs, float64(0))
+				actual.Checks[i].LatencyMs = 0
+			}
+
+			assert.Equal(t, test.ExpectedStatus, actual.Status)
+			assert.Equal(t, test.ExpectedChecks, actual.Checks)
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrFingerprintMismatch = errors.New("idempotency key was already used with a different request")
+	ErrInProgress          = errors.New("a request with the same idempotency key is still in progress")
+)
+
+type IdempotencyKeyModel struct {
+	Key         string    `db:"key" json:"key"`
+	Fingerprint string    `db:"fingerprint" json:"fingerprint"`
+	StatusCode  int       `db:"status_code" json:"statusCode"`
+	ContentType string    `db:"content_type" json:"contentType"`
+	Location    string    `db:"location" json:"location"`
+	Body        []byte    `db:"body" json:"body"`
+	CreatedAt   time.Time `db:"created_at" json:"createdAt"`
+	ExpiresAt   time.Time `db:"expires_at" json:"expiresAt"`
+}
+
+// Completed reports whether a response was already stored for the key.
+func (m IdempotencyKeyModel) Completed() bool {
+	return m.StatusCode != 0
+}
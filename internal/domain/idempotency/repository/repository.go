@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/tesarwijaya/ouroboros/internal/database"
+	"github.com/tesarwijaya/ouroboros/internal/domain/idempotency/model"
+	"github.com/tesarwijaya/ouroboros/internal/metrics"
+	"go.uber.org/dig"
+)
+
+const (
+	IDEMPOTENCY_KEY_TABLE_NAME = "idempotency_key"
+)
+
+type IdempotencyRepository interface {
+	FindByKey(ctx context.Context, key string) (model.IdempotencyKeyModel, error)
+	Lock(ctx context.Context, key string, fingerprint string, expiresAt time.Time) (bool, error)
+	Complete(ctx context.Context, payload model.IdempotencyKeyModel) error
+	Delete(ctx context.Context, key string) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+type IdempotencyRepositoryImpl struct {
+	dig.In
+	Db *sql.DB
+}
+
+func NewIdempotencyRepository(repo IdempotencyRepositoryImpl) IdempotencyRepository {
+	return &repo
+}
+
+func (r *IdempotencyRepositoryImpl) FindByKey(ctx context.Context, key string) (model.IdempotencyKeyModel, error) {
+	defer metrics.ObserveQuery("idempotency", "FindByKey")()
+
+	var (
+		res         model.IdempotencyKeyModel
+		statusCode  sql.NullInt64
+		contentType sql.NullString
+		location    sql.NullString
+	)
+
+	q := sqlbuilder.NewSelectBuilder()
+	query, args := q.Select("key", "fingerprint", "status_code", "content_type", "location", "body", "created_at", "expires_at").
+		From(IDEMPOTENCY_KEY_TABLE_NAME).
+		Where(q.Equal("key", key)).
+		BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	row := database.QueryRow(ctx, r.Db, query, args...)
+	if err := row.Err(); err != nil {
+		return model.IdempotencyKeyModel{}, err
+	}
+
+	if err := row.Scan(
+		&res.Key,
+		&res.Fingerprint,
+		&statusCode,
+		&contentType,
+		&location,
+		&res.Body,
+		&res.CreatedAt,
+		&res.ExpiresAt,
+	); err != nil {
+		return model.IdempotencyKeyModel{}, err
+	}
+
+	res.StatusCode = int(statusCode.Int64)
+	res.ContentType = contentType.String
+	res.Location = location.String
+
+	return res, nil
+}
+
+// Lock reserves the key for the request, an expired key is taken over as if
+// it never existed. It reports false when the key is held by another request.
+func (r *IdempotencyRepositoryImpl) Lock(ctx context.Context, key string, fingerprint string, expiresAt time.Time) (bool, error) {
+	defer metrics.ObserveQuery("idempotency", "Lock")()
+
+	var locked string
+
+	q := sqlbuilder.NewInsertBuilder()
+	query, args := q.InsertInto(IDEMPOTENCY_KEY_TABLE_NAME).
+		Cols("key", "fingerprint", "expires_at").
+		Values(key, fingerprint, expiresAt).
+		SQL("ON CONFLICT (key) DO UPDATE SET fingerprint = EXCLUDED.fingerprint, status_code = NULL, content_type = NULL, location = NULL, body = NULL, created_at = now(), expires_at = EXCLUDED.expires_at").
+		SQL("WHERE idempotency_key.expires_at < now()").
+		SQL("RETURNING key").
+		BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	err := database.QueryRow(ctx, r.Db, query, args...).Scan(&locked)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *IdempotencyRepositoryImpl) Complete(ctx context.Context, payload model.IdempotencyKeyModel) error {
+	defer metrics.ObserveQuery("idempotency", "Complete")()
+
+	q := sqlbuilder.NewUpdateBuilder()
+	query, args := q.Update(IDEMPOTENCY_KEY_TABLE_NAME).
+		Set(
+			q.Assign("status_code", payload.StatusCode),
+			q.Assign("content_type", payload.ContentType),
+			q.Assign("location", payload.Location),
+			q.Assign("body", payload.Body),
+		).
+		Where(q.Equal("key", payload.Key)).
+		BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	_, err := database.Exec(ctx, r.Db, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *IdempotencyRepositoryImpl) Delete(ctx context.Context, key string) error {
+	defer metrics.ObserveQuery("idempotency", "Delete")()
+
+	q := sqlbuilder.NewDeleteBuilder()
+	query, args := q.DeleteFrom(IDEMPOTENCY_KEY_TABLE_NAME).
+		Where(q.Equal("key", key)).
+		BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	_, err := database.Exec(ctx, r.Db, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *IdempotencyRepositoryImpl) DeleteExpired(ctx context.Context) (int64, error) {
+	defer metrics.ObserveQuery("idempotency", "DeleteExpired")()
+
+	q := sqlbuilder.NewDeleteBuilder()
+	query, args := q.DeleteFrom(IDEMPOTENCY_KEY_TABLE_NAME).
+		Where(q.LessThan("expires_at", sqlbuilder.Raw("now()"))).
+		BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	res, err := database.Exec(ctx, r.Db, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
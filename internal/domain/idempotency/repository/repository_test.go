@@ -0,0 +1,122 @@
+package repository_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/tesarwijaya/ouroboros/internal/domain/idempotency/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/idempotency/repository"
+)
+
+type mockFn func(db sqlmock.Sqlmock)
+
+func createRepo(mockFn mockFn) repository.IdempotencyRepository {
+	db, mock, _ := sqlmock.New()
+
+	mockFn(mock)
+	repo := repository.NewIdempotencyRepository(repository.IdempotencyRepositoryImpl{
+		Db: db,
+	})
+
+	return repo
+}
+
+func Test_Lock(t *testing.T) {
+	expiresAt := time.Date(2022, 8, 2, 0, 0, 0, 0, time.UTC)
+	lockQuery := regexp.QuoteMeta("INSERT INTO idempotency_key (key, fingerprint, expires_at) VALUES ($1, $2, $3) ON CONFLICT (key) DO UPDATE SET fingerprint = EXCLUDED.fingerprint, status_code = NULL, content_type = NULL, location = NULL, body = NULL, created_at = now(), expires_at = EXCLUDED.expires_at WHERE idempotency_key.expires_at < now() RETURNING key")
+
+	testCases := []struct {
+		Name      string
+		mockFn    mockFn
+		Expect    bool
+		ExpectErr error
+	}{
+		{
+			Name: "when_acquired",
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(lockQuery).
+					WithArgs("some-key", "some-fingerprint", expiresAt).
+					WillReturnRows(sqlmock.NewRows([]string{"key"}).AddRow("some-key"))
+			},
+			Expect: true,
+		},
+		{
+			Name: "when_held",
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(lockQuery).
+					WithArgs("some-key", "some-fingerprint", expiresAt).
+					WillReturnRows(sqlmock.NewRows([]string{"key"}))
+			},
+			Expect: false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := createRepo(test.mockFn)
+
+			actual, err := repo.Lock(context.Background(), "some-key", "some-fingerprint", expiresAt)
+
+			assert.Equal(t, test.Expect, actual)
+			assert.Equal(t, test.ExpectErr, err)
+		})
+	}
+}
+
+func Test_FindByKey(t *testing.T) {
+	createdAt := time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)
+	expiresAt := time.Date(2022, 8, 2, 0, 0, 0, 0, time.UTC)
+
+	repo := createRepo(func(db sqlmock.Sqlmock) {
+		db.ExpectQuery(regexp.QuoteMeta("SELECT key, fingerprint, status_code, content_type, location, body, created_at, expires_at FROM idempotency_key WHERE key = $1")).
+			WithArgs("some-key").
+			WillReturnRows(
+				sqlmock.NewRows([]string{"key", "fingerprint", "status_code", "content_type", "location", "body", "created_at", "expires_at"}).
+					AddRow("some-key", "some-fingerprint", nil, nil, nil, nil, createdAt, expiresAt),
+			)
+	})
+
+	actual, err := repo.FindByKey(context.Background(), "some-key")
+
+	assert.Nil(t, err)
+	assert.Equal(t, model.IdempotencyKeyModel{
+		Key:         "some-key",
+		Fingerprint: "some-fingerprint",
+		CreatedAt:   createdAt,
+		ExpiresAt:   expiresAt,
+	}, actual)
+}
+
+func Test_Complete(t *testing.T) {
+	repo := createRepo(func(db sqlmock.Sqlmock) {
+		db.ExpectExec(regexp.QuoteMeta("UPDATE idempotency_key SET status_code = $1, content_type = $2, location = $3, body = $4 WHERE key = $5")).
+			WithArgs(201, "application/json", "/player/1", []byte(`{"id":1}`), "some-key").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	})
+
+	err := repo.Complete(context.Background(), model.IdempotencyKeyModel{
+		Key:         "some-key",
+		StatusCode:  201,
+		ContentType: "application/json",
+		Location:    "/player/1",
+		Body:        []byte(`{"id":1}`),
+	})
+
+	assert.Nil(t, err)
+}
+
+func Test_DeleteExpired(t *testing.T) {
+	repo := createRepo(func(db sqlmock.Sqlmock) {
+		db.ExpectExec(regexp.QuoteMeta("DELETE FROM idempotency_key WHERE expires_at < now()")).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+	})
+
+	actual, err := repo.DeleteExpired(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), actual)
+}
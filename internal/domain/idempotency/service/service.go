@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/tesarwijaya/ouroboros/internal/config"
+	"github.com/tesarwijaya/ouroboros/internal/domain/idempotency/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/idempotency/repository"
+	"github.com/tesarwijaya/ouroboros/internal/tracing"
+	"go.uber.org/dig"
+)
+
+type IdempotencyService interface {
+	Begin(ctx context.Context, key string, fingerprint string) (model.IdempotencyKeyModel, bool, error)
+	Complete(ctx context.Context, response model.IdempotencyKeyModel) error
+	Release(ctx context.Context, key string) error
+	Purge(ctx context.Context) (int64, error)
+}
+
+type IdempotencyServiceImpl struct {
+	dig.In
+	Config *config.Config
+	Repo   repository.IdempotencyRepository
+}
+
+func NewIdempotencyService(svc IdempotencyServiceImpl) IdempotencyService {
+	return &svc
+}
+
+// Begin reserves the key for a new request. When the key was already used for
+// the same request it returns the stored response to be replayed instead.
+func (s *IdempotencyServiceImpl) Begin(ctx context.Context, key string, fingerprint string) (model.IdempotencyKeyModel, bool, error) {
+	ctx, span := tracing.Start(ctx, "IdempotencyService.Begin")
+	defer span.End()
+
+	locked, err := s.Repo.Lock(ctx, key, fingerprint, time.Now().Add(s.Config.IdempotencyTTL))
+	if err != nil {
+		return model.IdempotencyKeyModel{}, false, err
+	}
+
+	if locked {
+		return model.IdempotencyKeyModel{}, false, nil
+	}
+
+	stored, err := s.Repo.FindByKey(ctx, key)
+	if err != nil {
+		return model.IdempotencyKeyModel{}, false, err
+	}
+
+	if stored.Fingerprint != fingerprint {
+		return model.IdempotencyKeyModel{}, false, model.ErrFingerprintMismatch
+	}
+
+	if !stored.Completed() {
+		return model.IdempotencyKeyModel{}, false, model.ErrInProgress
+	}
+
+	return stored, true, nil
+}
+
+// Complete stores the response sent for the key.
+func (s *IdempotencyServiceImpl) Complete(ctx context.Context, response model.IdempotencyKeyModel) error {
+	ctx, span := tracing.Start(ctx, "IdempotencyService.Complete")
+	defer span.End()
+
+	return s.Repo.Complete(ctx, response)
+}
+
+// Release frees the key so that a failed request can be retried with it.
+func (s *IdempotencyServiceImpl) Release(ctx context.Context, key string) error {
+	ctx, span := tracing.Start(ctx, "IdempotencyService.Release")
+	defer span.End()
+
+	return s.Repo.Delete(ctx, key)
+}
+
+// Purge deletes the expired keys and reports how many were deleted.
+func (s *IdempotencyServiceImpl) Purge(ctx context.Context) (int64, error) {
+	ctx, span := tracing.Start(ctx, "IdempotencyService.Purge")
+	defer span.End()
+
+	return s.Repo.DeleteExpired(ctx)
+}
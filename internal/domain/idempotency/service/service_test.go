@@ -0,0 +1,107 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/tesarwijaya/ouroboros/internal/config"
+	"github.com/tesarwijaya/ouroboros/internal/domain/idempotency/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/idempotency/repository"
+	"github.com/tesarwijaya/ouroboros/internal/domain/idempotency/service"
+)
+
+type resolverFn func(repo *repository.MockIdempotencyRepository)
+
+func createService(t *testing.T, resolver resolverFn) (*service.IdempotencyServiceImpl, *gomock.Controller) {
+	ctrl := gomock.NewController(t)
+
+	repo := repository.NewMockIdempotencyRepository(ctrl)
+	resolver(repo)
+
+	return &service.IdempotencyServiceImpl{
+		Config: &config.Config{IdempotencyTTL: time.Hour},
+		Repo:   repo,
+	}, ctrl
+}
+
+func Test_NewIdempotencyService(t *testing.T) {
+	svc := service.NewIdempotencyService(service.IdempotencyServiceImpl{})
+
+	assert.Implements(t, (*service.IdempotencyService)(nil), svc)
+}
+
+func Test_Begin(t *testing.T) {
+	stored := model.IdempotencyKeyModel{
+		Key:         "some-key",
+		Fingerprint: "some-fingerprint",
+		StatusCode:  201,
+		ContentType: "application/json",
+		Body:        []byte(`{"id":1}`),
+	}
+
+	testCases := []struct {
+		Name         string
+		Resolver     resolverFn
+		Expect       model.IdempotencyKeyModel
+		ExpectReplay bool
+		ExpectErr    error
+	}{
+		{
+			Name: "when_acquired",
+			Resolver: func(repo *repository.MockIdempotencyRepository) {
+				repo.EXPECT().Lock(gomock.Any(), "some-key", "some-fingerprint", gomock.Any()).Return(true, nil)
+			},
+		},
+		{
+			Name: "when_completed",
+			Resolver: func(repo *repository.MockIdempotencyRepository) {
+				repo.EXPECT().Lock(gomock.Any(), "some-key", "some-fingerprint", gomock.Any()).Return(false, nil)
+				repo.EXPECT().FindByKey(gomock.Any(), "some-key").Return(stored, nil)
+			},
+			Expect:       stored,
+			ExpectReplay: true,
+		},
+		{
+			Name: "when_in_progress",
+			Resolver: func(repo *repository.MockIdempotencyRepository) {
+				repo.EXPECT().Lock(gomock.Any(), "some-key", "some-fingerprint", gomock.Any()).Return(false, nil)
+				repo.EXPECT().FindByKey(gomock.Any(), "some-key").
+					Return(model.IdempotencyKeyModel{Key: "some-key", Fingerprint: "some-fingerprint"}, nil)
+			},
+			ExpectErr: model.ErrInProgress,
+		},
+		{
+			Name: "when_fingerprint_mismatch",
+			Resolver: func(repo *repository.MockIdempotencyRepository) {
+				repo.EXPECT().Lock(gomock.Any(), "some-key", "some-fingerprint", gomock.Any()).Return(false, nil)
+				repo.EXPECT().FindByKey(gomock.Any(), "some-key").
+					Return(model.IdempotencyKeyModel{Key: "some-key", Fingerprint: "other-fingerprint"}, nil)
+			},
+			ExpectErr: model.ErrFingerprintMismatch,
+		},
+		{
+			Name: "when_lock_fails",
+			Resolver: func(repo *repository.MockIdempotencyRepository) {
+				repo.EXPECT().Lock(gomock.Any(), "some-key", "some-fingerprint", gomock.Any()).Return(false, errors.New("some-error"))
+			},
+			ExpectErr: errors.New("some-error"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			svc, mock := createService(t, test.Resolver)
+			defer mock.Finish()
+
+			actual, replay, err := svc.Begin(context.Background(), "some-key", "some-fingerprint")
+
+			assert.Equal(t, test.Expect, actual)
+			assert.Equal(t, test.ExpectReplay, replay)
+			assert.Equal(t, test.ExpectErr, err)
+		})
+	}
+}
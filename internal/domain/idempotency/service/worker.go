@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+
+	"github.com/tesarwijaya/ouroboros/internal/config"
+	"github.com/tesarwijaya/ouroboros/internal/logger"
+	"github.com/tesarwijaya/ouroboros/internal/worker"
+	"go.uber.org/zap"
+)
+
+// NewPurgeWorker periodically deletes the expired idempotency keys.
+func NewPurgeWorker(cfg *config.Config, svc IdempotencyService) worker.Worker {
+	return worker.Worker{
+		Name:     "idempotency_purge",
+		Interval: cfg.IdempotencyPurgeInterval,
+		Run: func(ctx context.Context) error {
+			purged, err := svc.Purge(ctx)
+			if err != nil {
+				return err
+			}
+
+			if purged > 0 {
+				logger.FromContext(ctx).Info("purged expired idempotency keys", zap.Int64("count", purged))
+			}
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,78 @@
+package model
+
+const (
+	FORMAT_CSV    = "csv"
+	FORMAT_JSON   = "json"
+	FORMAT_NDJSON = "ndjson"
+
+	ACTION_CREATED = "created"
+	ACTION_UPDATED = "updated"
+	ACTION_SKIPPED = "skipped"
+	ACTION_FAILED  = "failed"
+)
+
+// Row imports a team, matched by name and created when no team has it yet,
+// and optionally a player of the team. A named player is created unless the
+// team already has a player with that name, while PlayerID moves an existing
+// player to the team.
+type Row struct {
+	Number   int    `json:"-"`
+	Team     string `json:"team"`
+	Player   string `json:"player,omitempty"`
+	PlayerID int64  `json:"playerId,omitempty"`
+
+	// Err is set when the row couldn't be parsed
+	Err error `json:"-"`
+}
+
+type Options struct {
+	DryRun bool
+	// ChunkSize is the number of rows imported per transaction, the whole
+	// import runs in a single transaction when it is 0.
+	ChunkSize int
+}
+
+type RowResult struct {
+	Row      int    `json:"row"`
+	Team     string `json:"team"`
+	Player   string `json:"player,omitempty"`
+	PlayerID int64  `json:"playerId,omitempty"`
+	Action   string `json:"action"`
+	// Applied is set once the chunk of the row is committed
+	Applied bool   `json:"applied"`
+	Error   string `json:"error,omitempty"`
+}
+
+type Summary struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+	Skipped int `json:"skipped"`
+	Failed  int `json:"failed"`
+}
+
+// ImportReport lists what happened to every row, or what would have happened on a
+// dry run. Nothing is applied when a row fails validation, Applied is set once
+// the import ran and the rows tell which of them were committed.
+type ImportReport struct {
+	DryRun  bool        `json:"dryRun"`
+	Applied bool        `json:"applied"`
+	Summary Summary     `json:"summary"`
+	Rows    []RowResult `json:"rows"`
+}
+
+// Count recomputes the summary from the row results.
+func (r *ImportReport) Count() {
+	r.Summary = Summary{}
+	for _, row := range r.Rows {
+		switch row.Action {
+		case ACTION_CREATED:
+			r.Summary.Created++
+		case ACTION_UPDATED:
+			r.Summary.Updated++
+		case ACTION_SKIPPED:
+			r.Summary.Skipped++
+		case ACTION_FAILED:
+			r.Summary.Failed++
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package model
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var ErrUnknownFormat = errors.New("unknown format, use csv, json or ndjson")
+
+// FormatFromFilename guesses the format from the file extension, it returns
+// an empty string for an unknown one.
+func FormatFromFilename(filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return FORMAT_CSV
+	case ".json":
+		return FORMAT_JSON
+	case ".ndjson", ".jsonl":
+		return FORMAT_NDJSON
+	}
+
+	return ""
+}
+
+// FormatFromContentType guesses the format from a MIME type, it returns an
+// empty string for an unknown one.
+func FormatFromContentType(contentType string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	switch mediaType {
+	case "text/csv":
+		return FORMAT_CSV
+	case "application/json":
+		return FORMAT_JSON
+	case "application/x-ndjson", "application/ndjson", "application/jsonl":
+		return FORMAT_NDJSON
+	}
+
+	return ""
+}
+
+// Parse reads every row of r, rows are numbered from 1 without counting the
+// CSV header. A row that can't be parsed is returned with its Err set so that
+// it is reported along the others, only an unreadable file fails.
+func Parse(r io.Reader, format string) ([]Row, error) {
+	switch format {
+	case FORMAT_CSV:
+		return parseCSV(r)
+	case FORMAT_JSON:
+		return parseJSON(r)
+	case FORMAT_NDJSON:
+		return parseNDJSON(r)
+	}
+
+	return nil, ErrUnknownFormat
+}
+
+func parseCSV(r io.Reader) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return []Row{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read csv header: %w", err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	if _, ok := columns["team"]; !ok {
+		return nil, errors.New("csv header has no team column")
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+
+		return strings.TrimSpace(record[i])
+	}
+
+	rows := []Row{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		row := Row{Number: len(rows) + 1}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, err
+			}
+
+			row.Err = parseErr.Err
+			rows = append(rows, row)
+			continue
+		}
+
+		row.Team = field(record, "team")
+		row.Player = field(record, "player")
+		if playerID := field(record, "player_id"); playerID != "" {
+			row.PlayerID, err = strconv.ParseInt(playerID, 10, 64)
+			if err != nil {
+				row.Err = fmt.Errorf("invalid player_id %q", playerID)
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func parseJSON(r io.Reader) ([]Row, error) {
+	dec := json.NewDecoder(r)
+
+	token, err := dec.Token()
+	if err != nil {
+		return nil, fmt.Errorf("read json: %w", err)
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return nil, errors.New("json must be an array of rows")
+	}
+
+	rows := []Row{}
+	for dec.More() {
+		row := Row{Number: len(rows) + 1}
+		if err := dec.Decode(&row); err != nil {
+			var typeErr *json.UnmarshalTypeError
+			if !errors.As(err, &typeErr) {
+				return nil, fmt.Errorf("read json row %d: %w", row.Number, err)
+			}
+
+			row = Row{Number: row.Number, Err: err}
+		}
+
+		rows = append(rows, trim(row))
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return nil, fmt.Errorf("read json: %w", err)
+	}
+
+	return rows, nil
+}
+
+func parseNDJSON(r io.Reader) ([]Row, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	rows := []Row{}
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		row := Row{Number: len(rows) + 1}
+		if err := json.Unmarshal(line, &row); err != nil {
+			row = Row{Number: row.Number, Err: err}
+		}
+
+		rows = append(rows, trim(row))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read ndjson: %w", err)
+	}
+
+	return rows, nil
+}
+
+func trim(row Row) Row {
+	row.Team = strings.TrimSpace(row.Team)
+	row.Player = strings.TrimSpace(row.Player)
+
+	return row
+}
@@ -0,0 +1,361 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/tesarwijaya/ouroboros/internal/database"
+	command_service "github.com/tesarwijaya/ouroboros/internal/domain/command/service"
+	"github.com/tesarwijaya/ouroboros/internal/domain/importer/model"
+	player_model "github.com/tesarwijaya/ouroboros/internal/domain/player/model"
+	player_service "github.com/tesarwijaya/ouroboros/internal/domain/player/service"
+	team_model "github.com/tesarwijaya/ouroboros/internal/domain/team/model"
+	team_service "github.com/tesarwijaya/ouroboros/internal/domain/team/service"
+	"github.com/tesarwijaya/ouroboros/internal/logger"
+	"github.com/tesarwijaya/ouroboros/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/dig"
+	"go.uber.org/zap"
+)
+
+type ImportService interface {
+	Import(ctx context.Context, rows []model.Row, opts model.Options) (model.ImportReport, error)
+}
+
+type ImportServiceImpl struct {
+	dig.In
+	Db            *sql.DB
+	Bus           command_service.CommandBus
+	TeamService   team_service.TeamService
+	PlayerService player_service.PlayerService
+}
+
+func NewImportService(svc ImportServiceImpl) ImportService {
+	return &svc
+}
+
+// step is the planned change of a row.
+type step struct {
+	row    model.Row
+	result model.RowResult
+}
+
+// Import plans every row against the current teams and players first, nothing
+// is applied when a row fails or on a dry run. The rows are then applied in
+// chunks of opts.ChunkSize rows, each in its own transaction, through the same
+// commands as the API. Transfers write their events to the outbox of the
+// chunk transaction, so a failing row rolls back its whole chunk. The first
+// failure stops the import, the chunks committed before it are kept and their
+// rows reported as applied.
+func (s *ImportServiceImpl) Import(ctx context.Context, rows []model.Row, opts model.Options) (model.ImportReport, error) {
+	ctx, span := tracing.Start(ctx, "ImportService.Import",
+		attribute.Int("import.rows", len(rows)),
+		attribute.Bool("import.dry_run", opts.DryRun),
+	)
+	defer span.End()
+
+	steps, teamIDs, err := s.plan(ctx, rows)
+	if err != nil {
+		return model.ImportReport{}, err
+	}
+
+	report := model.ImportReport{DryRun: opts.DryRun}
+	report.Rows = results(steps)
+	report.Count()
+
+	if opts.DryRun || report.Summary.Failed > 0 {
+		return report, nil
+	}
+
+	s.apply(ctx, steps, teamIDs, opts.ChunkSize)
+
+	report.Applied = true
+	report.Rows = results(steps)
+	report.Count()
+
+	logger.FromContext(ctx).Info("import applied",
+		zap.Int("created", report.Summary.Created),
+		zap.Int("updated", report.Summary.Updated),
+		zap.Int("skipped", report.Summary.Skipped),
+		zap.Int("failed", report.Summary.Failed),
+	)
+
+	return report, nil
+}
+
+func (s *ImportServiceImpl) plan(ctx context.Context, rows []model.Row) ([]step, map[string]int64, error) {
+	var (
+		names     []string
+		playerIDs []int64
+	)
+	for _, row := range rows {
+		if row.Err != nil {
+			continue
+		}
+
+		if row.Team != "" {
+			names = append(names, row.Team)
+		}
+
+		if row.PlayerID != 0 {
+			playerIDs = append(playerIDs, row.PlayerID)
+		}
+	}
+
+	teams, err := s.TeamService.FindByNames(ctx, unique(names))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	teamIDs := make(map[string]int64, len(teams))
+	ids := make([]int64, 0, len(teams))
+	for _, team := range teams {
+		teamIDs[team.Name] = team.ID
+		ids = append(ids, team.ID)
+	}
+
+	players, err := s.PlayerService.FindByIDs(ctx, unique(playerIDs))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	playersByID := make(map[int64]player_model.PlayerModel, len(players))
+	for _, player := range players {
+		playersByID[player.ID] = player
+	}
+
+	teamPlayers, err := s.PlayerService.FindByTeamIDs(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// players already in a team, or planned to be, by team name then player name
+	existing := map[string]map[string]bool{}
+	addPlayer := func(team, player string) {
+		if existing[team] == nil {
+			existing[team] = map[string]bool{}
+		}
+		existing[team][player] = true
+	}
+
+	teamNames := make(map[int64]string, len(teams))
+	for _, team := range teams {
+		teamNames[team.ID] = team.Name
+	}
+
+	for _, player := range teamPlayers {
+		addPlayer(teamNames[player.TeamID], player.Name)
+	}
+
+	plannedTeams := map[string]bool{}
+	movedBy := map[int64]int{}
+
+	steps := make([]step, 0, len(rows))
+	for _, row := range rows {
+		st := step{
+			row: row,
+			result: model.RowResult{
+				Row:      row.Number,
+				Team:     row.Team,
+				Player:   row.Player,
+				PlayerID: row.PlayerID,
+			},
+		}
+
+		_, teamExists := teamIDs[row.Team]
+		newTeam := !teamExists && !plannedTeams[row.Team]
+
+		switch err := validate(row); {
+		case err != nil:
+			st.fail(err)
+		case row.PlayerID != 0:
+			player, ok := playersByID[row.PlayerID]
+
+			switch {
+			case !ok:
+				st.fail(fmt.Errorf("player %d not found", row.PlayerID))
+			case movedBy[row.PlayerID] != 0:
+				st.fail(fmt.Errorf("player %d is already moved by row %d", row.PlayerID, movedBy[row.PlayerID]))
+			case teamExists && player.TeamID == teamIDs[row.Team]:
+				st.result.Action = model.ACTION_SKIPPED
+			default:
+				st.result.Action = model.ACTION_UPDATED
+				movedBy[row.PlayerID] = row.Number
+			}
+		case row.Player != "":
+			if existing[row.Team][row.Player] {
+				st.result.Action = model.ACTION_SKIPPED
+				break
+			}
+
+			st.result.Action = model.ACTION_CREATED
+			addPlayer(row.Team, row.Player)
+		case newTeam:
+			st.result.Action = model.ACTION_CREATED
+		default:
+			st.result.Action = model.ACTION_SKIPPED
+		}
+
+		if st.result.Action != model.ACTION_FAILED && !teamExists {
+			plannedTeams[row.Team] = true
+		}
+
+		steps = append(steps, st)
+	}
+
+	return steps, teamIDs, nil
+}
+
+// validate runs the validation of the commands the row is applied with, the
+// team of a new player may not exist yet so it can't be checked here.
+func validate(row model.Row) error {
+	if row.Err != nil {
+		return row.Err
+	}
+
+	if err := (team_service.InsertTeamCommand{Payload: team_model.TeamModel{Name: row.Team}}).Validate(); err != nil {
+		return fmt.Errorf("team %w", err)
+	}
+
+	if row.Player != "" && row.PlayerID != 0 {
+		return errors.New("either player or playerId can be set")
+	}
+
+	return nil
+}
+
+func (s *ImportServiceImpl) apply(ctx context.Context, steps []step, teamIDs map[string]int64, chunkSize int) {
+	if chunkSize <= 0 {
+		chunkSize = len(steps)
+	}
+
+	for start := 0; start < len(steps); start += chunkSize {
+		end := start + chunkSize
+		if end > len(steps) {
+			end = len(steps)
+		}
+		chunk := steps[start:end]
+
+		// teams created in the chunk are only known once it is committed
+		created := map[string]int64{}
+		teamID := func(ctx context.Context, name string) (int64, error) {
+			if id, ok := teamIDs[name]; ok {
+				return id, nil
+			}
+
+			if id, ok := created[name]; ok {
+				return id, nil
+			}
+
+			team, err := command_service.Dispatch[team_model.TeamModel](ctx, s.Bus, team_service.InsertTeamCommand{
+				Payload: team_model.TeamModel{Name: name},
+			})
+			if err != nil {
+				return 0, err
+			}
+			created[name] = team.ID
+
+			return team.ID, nil
+		}
+
+		failed := -1
+		err := database.WithTx(ctx, s.Db, func(ctx context.Context) error {
+			for i := range chunk {
+				st := &chunk[i]
+				if st.result.Action == model.ACTION_SKIPPED {
+					continue
+				}
+
+				id, err := teamID(ctx, st.row.Team)
+				if err != nil {
+					failed = i
+					return err
+				}
+
+				switch {
+				case st.row.PlayerID != 0:
+					_, err = s.Bus.Dispatch(ctx, player_service.TransferPlayerCommand{
+						Payload: player_service.TransferPayload{PlayerID: st.row.PlayerID, TeamID: id},
+					})
+				case st.row.Player != "":
+					_, err = command_service.Dispatch[player_model.PlayerModel](ctx, s.Bus, player_service.InsertPlayerCommand{
+						Payload: player_model.PlayerModel{Name: st.row.Player, TeamID: id},
+					})
+				}
+				if err != nil {
+					failed = i
+					return err
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			for i := range chunk {
+				if i == failed || failed < 0 {
+					chunk[i].fail(err)
+					continue
+				}
+
+				chunk[i].fail(fmt.Errorf("rolled back, row %d failed", chunk[failed].row.Number))
+			}
+
+			abort(steps[end:], chunk[max(failed, 0)].row.Number)
+			return
+		}
+
+		for name, id := range created {
+			teamIDs[name] = id
+		}
+
+		for i := range chunk {
+			chunk[i].result.Applied = chunk[i].result.Action != model.ACTION_SKIPPED
+		}
+	}
+}
+
+func (st *step) fail(err error) {
+	st.result.Action = model.ACTION_FAILED
+	st.result.Error = err.Error()
+}
+
+// abort fails the rows that are left once row failed.
+func abort(steps []step, row int) {
+	for i := range steps {
+		steps[i].fail(fmt.Errorf("not imported, row %d failed", row))
+	}
+}
+
+func results(steps []step) []model.RowResult {
+	res := make([]model.RowResult, 0, len(steps))
+	for _, st := range steps {
+		res = append(res, st.result)
+	}
+
+	return res
+}
+
+func unique[T comparable](values []T) []T {
+	seen := make(map[T]bool, len(values))
+	res := make([]T, 0, len(values))
+	for _, value := range values {
+		if seen[value] {
+			continue
+		}
+
+		seen[value] = true
+		res = append(res, value)
+	}
+
+	return res
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
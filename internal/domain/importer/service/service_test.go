@@ -0,0 +1,279 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	command_model "github.com/tesarwijaya/ouroboros/internal/domain/command/model"
+	command_service "github.com/tesarwijaya/ouroboros/internal/domain/command/service"
+	"github.com/tesarwijaya/ouroboros/internal/domain/importer/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/importer/service"
+	player_model "github.com/tesarwijaya/ouroboros/internal/domain/player/model"
+	player_service "github.com/tesarwijaya/ouroboros/internal/domain/player/service"
+	team_model "github.com/tesarwijaya/ouroboros/internal/domain/team/model"
+	team_service "github.com/tesarwijaya/ouroboros/internal/domain/team/service"
+)
+
+type resolverFn func(db sqlmock.Sqlmock, teamSvc *team_service.MockTeamService, playerSvc *player_service.MockPlayerService)
+
+func createService(t *testing.T, resolver resolverFn) (*service.ImportServiceImpl, func()) {
+	ctrl := gomock.NewController(t)
+
+	db, mock, _ := sqlmock.New()
+
+	teamSvc := team_service.NewMockTeamService(ctrl)
+	playerSvc := player_service.NewMockPlayerService(ctrl)
+	resolver(mock, teamSvc, playerSvc)
+
+	bus, _ := command_service.NewCommandBus(command_service.CommandBusImpl{
+		Handlers:    append(team_service.NewCommandHandlers(teamSvc).Handlers, player_service.NewCommandHandlers(playerSvc).Handlers...),
+		Middlewares: []command_model.Middleware{command_service.NewValidationMiddleware()},
+	})
+
+	return &service.ImportServiceImpl{
+		Db:            db,
+		Bus:           bus,
+		TeamService:   teamSvc,
+		PlayerService: playerSvc,
+	}, func() {
+		assert.Nil(t, mock.ExpectationsWereMet())
+		db.Close()
+		ctrl.Finish()
+	}
+}
+
+// existing mocks the lookups of the planning with one team and its player.
+func existing(teamSvc *team_service.MockTeamService, playerSvc *player_service.MockPlayerService) {
+	teamSvc.EXPECT().FindByNames(gomock.Any(), gomock.Any()).
+		Return([]team_model.TeamModel{{ID: 1, Name: "owls"}}, nil)
+	playerSvc.EXPECT().FindByIDs(gomock.Any(), gomock.Any()).
+		Return([]player_model.PlayerModel{{ID: 10, Name: "jane", TeamID: 1}}, nil)
+	playerSvc.EXPECT().FindByTeamIDs(gomock.Any(), []int64{1}).
+		Return([]player_model.PlayerModel{{ID: 10, Name: "jane", TeamID: 1}}, nil)
+}
+
+func Test_NewImportService(t *testing.T) {
+	svc := service.NewImportService(service.ImportServiceImpl{})
+
+	assert.Implements(t, (*service.ImportService)(nil), svc)
+}
+
+func Test_Import(t *testing.T) {
+	rows := []model.Row{
+		{Number: 1, Team: "owls"},
+		{Number: 2, Team: "owls", Player: "jane"},
+		{Number: 3, Team: "larks", Player: "john"},
+		{Number: 4, Team: "larks", PlayerID: 10},
+		{Number: 5, Team: "larks"},
+	}
+
+	testCases := []struct {
+		Name      string
+		Rows      []model.Row
+		Options   model.Options
+		Resolver  resolverFn
+		Expect    model.ImportReport
+		ExpectErr error
+	}{
+		{
+			Name:    "when_dry_run",
+			Rows:    rows,
+			Options: model.Options{DryRun: true},
+			Resolver: func(db sqlmock.Sqlmock, teamSvc *team_service.MockTeamService, playerSvc *player_service.MockPlayerService) {
+				existing(teamSvc, playerSvc)
+			},
+			Expect: model.ImportReport{
+				DryRun:  true,
+				Summary: model.Summary{Created: 1, Updated: 1, Skipped: 3},
+				Rows: []model.RowResult{
+					{Row: 1, Team: "owls", Action: model.ACTION_SKIPPED},
+					{Row: 2, Team: "owls", Player: "jane", Action: model.ACTION_SKIPPED},
+					{Row: 3, Team: "larks", Player: "john", Action: model.ACTION_CREATED},
+					{Row: 4, Team: "larks", PlayerID: 10, Action: model.ACTION_UPDATED},
+					{Row: 5, Team: "larks", Action: model.ACTION_SKIPPED},
+				},
+			},
+		},
+		{
+			Name: "when_success",
+			Rows: rows,
+			Resolver: func(db sqlmock.Sqlmock, teamSvc *team_service.MockTeamService, playerSvc *player_service.MockPlayerService) {
+				existing(teamSvc, playerSvc)
+
+				db.ExpectBegin()
+				teamSvc.EXPECT().Insert(gomock.Any(), team_model.TeamModel{Name: "larks"}).
+					Return(team_model.TeamModel{ID: 2, Name: "larks"}, nil)
+				playerSvc.EXPECT().Insert(gomock.Any(), player_model.PlayerModel{Name: "john", TeamID: 2}).
+					Return(player_model.PlayerModel{ID: 11, Name: "john", TeamID: 2}, nil)
+				playerSvc.EXPECT().Transfer(gomock.Any(), player_service.TransferPayload{PlayerID: 10, TeamID: 2}).Return(nil)
+				db.ExpectCommit()
+			},
+			Expect: model.ImportReport{
+				Applied: true,
+				Summary: model.Summary{Created: 1, Updated: 1, Skipped: 3},
+				Rows: []model.RowResult{
+					{Row: 1, Team: "owls", Action: model.ACTION_SKIPPED},
+					{Row: 2, Team: "owls", Player: "jane", Action: model.ACTION_SKIPPED},
+					{Row: 3, Team: "larks", Player: "john", Action: model.ACTION_CREATED, Applied: true},
+					{Row: 4, Team: "larks", PlayerID: 10, Action: model.ACTION_UPDATED, Applied: true},
+					{Row: 5, Team: "larks", Action: model.ACTION_SKIPPED},
+				},
+			},
+		},
+		{
+			Name: "when_transfer_fails",
+			Rows: []model.Row{
+				{Number: 1, Team: "larks", Player: "john"},
+				{Number: 2, Team: "larks", PlayerID: 10},
+			},
+			Resolver: func(db sqlmock.Sqlmock, teamSvc *team_service.MockTeamService, playerSvc *player_service.MockPlayerService) {
+				existing(teamSvc, playerSvc)
+
+				db.ExpectBegin()
+				teamSvc.EXPECT().Insert(gomock.Any(), team_model.TeamModel{Name: "larks"}).
+					Return(team_model.TeamModel{ID: 2, Name: "larks"}, nil)
+				playerSvc.EXPECT().Insert(gomock.Any(), player_model.PlayerModel{Name: "john", TeamID: 2}).
+					Return(player_model.PlayerModel{ID: 11, Name: "john", TeamID: 2}, nil)
+				playerSvc.EXPECT().Transfer(gomock.Any(), player_service.TransferPayload{PlayerID: 10, TeamID: 2}).
+					Return(errors.New("some-error"))
+				db.ExpectRollback()
+			},
+			Expect: model.ImportReport{
+				Applied: true,
+				Summary: model.Summary{Failed: 2},
+				Rows: []model.RowResult{
+					{Row: 1, Team: "larks", Player: "john", Action: model.ACTION_FAILED, Error: "rolled back, row 2 failed"},
+					{Row: 2, Team: "larks", PlayerID: 10, Action: model.ACTION_FAILED, Error: "some-error"},
+				},
+			},
+		},
+		{
+			Name: "when_row_is_invalid",
+			Rows: []model.Row{
+				{Number: 1, Team: "larks", Player: "john"},
+				{Number: 2, Team: ""},
+				{Number: 3, Team: "larks", PlayerID: 99},
+				{Number: 4, Err: errors.New("some-parse-error")},
+			},
+			Resolver: func(db sqlmock.Sqlmock, teamSvc *team_service.MockTeamService, playerSvc *player_service.MockPlayerService) {
+				teamSvc.EXPECT().FindByNames(gomock.Any(), []string{"larks"}).Return([]team_model.TeamModel{}, nil)
+				playerSvc.EXPECT().FindByIDs(gomock.Any(), []int64{99}).Return([]player_model.PlayerModel{}, nil)
+				playerSvc.EXPECT().FindByTeamIDs(gomock.Any(), []int64{}).Return([]player_model.PlayerModel{}, nil)
+			},
+			Expect: model.ImportReport{
+				Summary: model.Summary{Created: 1, Failed: 3},
+				Rows: []model.RowResult{
+					{Row: 1, Team: "larks", Player: "john", Action: model.ACTION_CREATED},
+					{Row: 2, Action: model.ACTION_FAILED, Error: "team name is required"},
+					{Row: 3, Team: "larks", PlayerID: 99, Action: model.ACTION_FAILED, Error: "player 99 not found"},
+					{Row: 4, Action: model.ACTION_FAILED, Error: "some-parse-error"},
+				},
+			},
+		},
+		{
+			Name: "when_player_is_moved_twice",
+			Rows: []model.Row{
+				{Number: 1, Team: "larks", PlayerID: 10},
+				{Number: 2, Team: "larks", PlayerID: 10},
+			},
+			Resolver: func(db sqlmock.Sqlmock, teamSvc *team_service.MockTeamService, playerSvc *player_service.MockPlayerService) {
+				existing(teamSvc, playerSvc)
+			},
+			Expect: model.ImportReport{
+				Summary: model.Summary{Updated: 1, Failed: 1},
+				Rows: []model.RowResult{
+					{Row: 1, Team: "larks", PlayerID: 10, Action: model.ACTION_UPDATED},
+					{Row: 2, Team: "larks", PlayerID: 10, Action: model.ACTION_FAILED, Error: "player 10 is already moved by row 1"},
+				},
+			},
+		},
+		{
+			Name: "when_chunk_fails",
+			Rows: []model.Row{
+				{Number: 1, Team: "larks"},
+				{Number: 2, Team: "robins", Player: "john"},
+				{Number: 3, Team: "robins", Player: "jim"},
+				{Number: 4, Team: "wrens"},
+			},
+			Options: model.Options{ChunkSize: 1},
+			Resolver: func(db sqlmock.Sqlmock, teamSvc *team_service.MockTeamService, playerSvc *player_service.MockPlayerService) {
+				teamSvc.EXPECT().FindByNames(gomock.Any(), gomock.Any()).Return([]team_model.TeamModel{}, nil)
+				playerSvc.EXPECT().FindByIDs(gomock.Any(), gomock.Any()).Return([]player_model.PlayerModel{}, nil)
+				playerSvc.EXPECT().FindByTeamIDs(gomock.Any(), gomock.Any()).Return([]player_model.PlayerModel{}, nil)
+
+				db.ExpectBegin()
+				teamSvc.EXPECT().Insert(gomock.Any(), team_model.TeamModel{Name: "larks"}).
+					Return(team_model.TeamModel{ID: 2, Name: "larks"}, nil)
+				db.ExpectCommit()
+
+				db.ExpectBegin()
+				teamSvc.EXPECT().Insert(gomock.Any(), team_model.TeamModel{Name: "robins"}).
+					Return(team_model.TeamModel{ID: 3, Name: "robins"}, nil)
+				playerSvc.EXPECT().Insert(gomock.Any(), player_model.PlayerModel{Name: "john", TeamID: 3}).
+					Return(player_model.PlayerModel{}, errors.New("some-error"))
+				db.ExpectRollback()
+			},
+			Expect: model.ImportReport{
+				Applied: true,
+				Summary: model.Summary{Created: 1, Failed: 3},
+				Rows: []model.RowResult{
+					{Row: 1, Team: "larks", Action: model.ACTION_CREATED, Applied: true},
+					{Row: 2, Team: "robins", Player: "john", Action: model.ACTION_FAILED, Error: "some-error"},
+					{Row: 3, Team: "robins", Player: "jim", Action: model.ACTION_FAILED, Error: "not imported, row 2 failed"},
+					{Row: 4, Team: "wrens", Action: model.ACTION_FAILED, Error: "not imported, row 2 failed"},
+				},
+			},
+		},
+		{
+			Name: "when_transaction_is_rolled_back",
+			Rows: []model.Row{
+				{Number: 1, Team: "larks"},
+				{Number: 2, Team: "robins"},
+			},
+			Resolver: func(db sqlmock.Sqlmock, teamSvc *team_service.MockTeamService, playerSvc *player_service.MockPlayerService) {
+				teamSvc.EXPECT().FindByNames(gomock.Any(), gomock.Any()).Return([]team_model.TeamModel{}, nil)
+				playerSvc.EXPECT().FindByIDs(gomock.Any(), gomock.Any()).Return([]player_model.PlayerModel{}, nil)
+				playerSvc.EXPECT().FindByTeamIDs(gomock.Any(), gomock.Any()).Return([]player_model.PlayerModel{}, nil)
+
+				db.ExpectBegin()
+				teamSvc.EXPECT().Insert(gomock.Any(), team_model.TeamModel{Name: "larks"}).
+					Return(team_model.TeamModel{ID: 2, Name: "larks"}, nil)
+				teamSvc.EXPECT().Insert(gomock.Any(), team_model.TeamModel{Name: "robins"}).
+					Return(team_model.TeamModel{}, errors.New("some-error"))
+				db.ExpectRollback()
+			},
+			Expect: model.ImportReport{
+				Applied: true,
+				Summary: model.Summary{Failed: 2},
+				Rows: []model.RowResult{
+					{Row: 1, Team: "larks", Action: model.ACTION_FAILED, Error: "rolled back, row 2 failed"},
+					{Row: 2, Team: "robins", Action: model.ACTION_FAILED, Error: "some-error"},
+				},
+			},
+		},
+		{
+			Name: "when_lookup_fails",
+			Rows: rows,
+			Resolver: func(db sqlmock.Sqlmock, teamSvc *team_service.MockTeamService, playerSvc *player_service.MockPlayerService) {
+				teamSvc.EXPECT().FindByNames(gomock.Any(), gomock.Any()).Return(nil, errors.New("some-error"))
+			},
+			ExpectErr: errors.New("some-error"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			svc, finish := createService(t, test.Resolver)
+			defer finish()
+
+			actual, err := svc.Import(context.Background(), test.Rows, test.Options)
+
+			assert.Equal(t, test.ExpectErr, err)
+			assert.Equal(t, test.Expect, actual)
+		})
+	}
+}
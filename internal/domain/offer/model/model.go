@@ -0,0 +1,84 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	OFFER_STREAM_PREFIX = "offer-"
+
+	OFFER_MADE_EVENT      = "offer_made"
+	OFFER_ACCEPTED_EVENT  = "offer_accepted"
+	OFFER_REJECTED_EVENT  = "offer_rejected"
+	OFFER_COUNTERED_EVENT = "offer_countered"
+	OFFER_EXPIRED_EVENT   = "offer_expired"
+	OFFER_WITHDRAWN_EVENT = "offer_withdrawn"
+
+	// TRANSFER_PROCESS is the principal transferring the player of an
+	// accepted offer.
+	TRANSFER_PROCESS = "offer_transfer"
+)
+
+// An offer is pending until the team it was made to accepts, rejects or
+// counters it, or until it expires. A pending offer is withdrawn when its
+// player leaves the selling team by other means.
+const (
+	STATUS_PENDING   = "pending"
+	STATUS_ACCEPTED  = "accepted"
+	STATUS_REJECTED  = "rejected"
+	STATUS_COUNTERED = "countered"
+	STATUS_EXPIRED   = "expired"
+	STATUS_WITHDRAWN = "withdrawn"
+)
+
+var ErrOfferNotFound = errors.New("offer not found")
+
+var STATUSES = map[string]bool{
+	STATUS_PENDING:   true,
+	STATUS_ACCEPTED:  true,
+	STATUS_REJECTED:  true,
+	STATUS_COUNTERED: true,
+	STATUS_EXPIRED:   true,
+	STATUS_WITHDRAWN: true,
+}
+
+// OfferModel is an offer of ToTeamID, the buying team, for a player of
+// FromTeamID, the selling team. A counter offer is made by the other team and
+// links the offer it counters through ParentID.
+type OfferModel struct {
+	ID               int64     `db:"id" json:"id"`
+	ParentID         int64     `db:"parent_id" json:"parentId,omitempty"`
+	PlayerID         int64     `db:"player_id" json:"playerId"`
+	FromTeamID       int64     `db:"from_team_id" json:"fromTeamId"`
+	ToTeamID         int64     `db:"to_team_id" json:"toTeamId"`
+	ProposedByTeamID int64     `db:"proposed_by_team_id" json:"proposedByTeamId"`
+	Fee              int64     `db:"fee" json:"fee"`
+	Status           string    `db:"status" json:"status"`
+	ExpiresAt        time.Time `db:"expires_at" json:"expiresAt"`
+	CreatedAt        time.Time `db:"created_at" json:"createdAt"`
+	UpdatedAt        time.Time `db:"updated_at" json:"updatedAt"`
+}
+
+// RespondingTeamID returns the team which accepts, rejects or counters the
+// offer, the one that didn't propose it.
+func (o OfferModel) RespondingTeamID() int64 {
+	if o.ProposedByTeamID == o.ToTeamID {
+		return o.FromTeamID
+	}
+
+	return o.ToTeamID
+}
+
+// OfferEventData is the offer once its status changed, CounterOfferID is the
+// offer made in return of a countered one.
+type OfferEventData struct {
+	OfferID        int64
+	CounterOfferID int64
+	Offer          OfferModel
+}
+
+func OfferStreamID(id int64) string {
+	return fmt.Sprintf("%s%d", OFFER_STREAM_PREFIX, id)
+}
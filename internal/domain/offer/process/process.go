@@ -0,0 +1,63 @@
+package process
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/EventStore/EventStore-Client-Go/esdb"
+	"github.com/gofrs/uuid"
+	event_model "github.com/tesarwijaya/ouroboros/internal/domain/event/model"
+	event_repository "github.com/tesarwijaya/ouroboros/internal/domain/event/repository"
+	"github.com/tesarwijaya/ouroboros/internal/domain/offer/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/offer/repository"
+	player_model "github.com/tesarwijaya/ouroboros/internal/domain/player/model"
+)
+
+// NewWithdrawalProcess withdraws the pending offers for a player once it is
+// transferred, they were made to the team it left. It runs within the
+// transaction relaying the transfer events and writes the offer_withdrawn
+// events, which share the metadata of the transfer, to the outbox of that
+// transaction rather than through the event bus, which depends on its
+// handlers. The relay publishes them with the next batch it claims, a failure
+// rolls the withdrawal back along the relay so that it is retried.
+func NewWithdrawalProcess(repo repository.OfferRepository, outbox event_repository.OutboxRepository) event_model.Handler {
+	return event_model.Handler{
+		Name:       "offer_withdrawal_process",
+		EventTypes: []string{player_model.PLAYER_TRANSFER_IN_EVENT},
+		Handle: func(ctx context.Context, event event_model.Event) error {
+			var data player_model.TransferEventData
+			if err := json.Unmarshal(event.Data, &data); err != nil {
+				return err
+			}
+
+			withdrawn, err := repo.WithdrawPending(ctx, data.PlayerID, data.TeamID)
+			if err != nil {
+				return err
+			}
+
+			if len(withdrawn) == 0 {
+				return nil
+			}
+
+			gen := uuid.NewGen()
+			events := make([]event_model.Event, 0, len(withdrawn))
+			for _, offer := range withdrawn {
+				raw, _ := json.Marshal(model.OfferEventData{OfferID: offer.ID, Offer: offer})
+				id, _ := gen.NewV4()
+
+				events = append(events, event_model.Event{
+					ID:          id,
+					StreamID:    model.OfferStreamID(offer.ID),
+					Type:        model.OFFER_WITHDRAWN_EVENT,
+					ContentType: esdb.JsonContentType,
+					Data:        raw,
+					Metadata:    event.Metadata,
+					CreatedAt:   time.Now(),
+				})
+			}
+
+			return outbox.Insert(ctx, events...)
+		},
+	}
+}
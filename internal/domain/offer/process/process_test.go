@@ -0,0 +1,144 @@
+package process_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	event_model "github.com/tesarwijaya/ouroboros/internal/domain/event/model"
+	event_repository "github.com/tesarwijaya/ouroboros/internal/domain/event/repository"
+	"github.com/tesarwijaya/ouroboros/internal/domain/offer/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/offer/process"
+	"github.com/tesarwijaya/ouroboros/internal/domain/offer/repository"
+	player_model "github.com/tesarwijaya/ouroboros/internal/domain/player/model"
+)
+
+type resolverFn func(repo *repository.MockOfferRepository, outbox *event_repository.MockOutboxRepository, written *[]event_model.Event)
+
+// record keeps the events written to the outbox in written.
+func record(written *[]event_model.Event, err error) func(ctx context.Context, events ...event_model.Event) error {
+	return func(ctx context.Context, events ...event_model.Event) error {
+		*written = append(*written, events...)
+		return err
+	}
+}
+
+func transferIn(data string) event_model.Event {
+	return event_model.Event{
+		StreamID: player_model.PlayerStreamID(2),
+		Type:     player_model.PLAYER_TRANSFER_IN_EVENT,
+		Data:     []byte(data),
+		Metadata: event_model.CorrelationMetadata("some-correlation-id"),
+	}
+}
+
+func withdrawn(id int64) model.OfferModel {
+	return model.OfferModel{
+		ID:               id,
+		PlayerID:         2,
+		FromTeamID:       3,
+		ToTeamID:         4,
+		ProposedByTeamID: 4,
+		Fee:              100,
+		Status:           model.STATUS_WITHDRAWN,
+	}
+}
+
+func Test_NewWithdrawalProcess(t *testing.T) {
+	handler := process.NewWithdrawalProcess(nil, nil)
+
+	assert.False(t, handler.Async)
+	assert.True(t, handler.Accept(player_model.PLAYER_TRANSFER_IN_EVENT))
+	assert.False(t, handler.Accept(player_model.PLAYER_TRANSFER_OUT_EVENT))
+}
+
+func Test_WithdrawalProcess(t *testing.T) {
+	testCases := []struct {
+		Name         string
+		Event        event_model.Event
+		Resolver     resolverFn
+		ExpectOffers []model.OfferModel
+		ExpectErr    error
+	}{
+		{
+			Name:  "when_offers_withdrawn",
+			Event: transferIn(`{"PlayerID": 2, "TeamID": 5}`),
+			Resolver: func(repo *repository.MockOfferRepository, outbox *event_repository.MockOutboxRepository, written *[]event_model.Event) {
+				repo.EXPECT().WithdrawPending(gomock.Any(), int64(2), int64(5)).
+					Return([]model.OfferModel{withdrawn(1), withdrawn(7)}, nil)
+				outbox.EXPECT().Insert(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(record(written, nil))
+			},
+			ExpectOffers: []model.OfferModel{withdrawn(1), withdrawn(7)},
+		},
+		{
+			Name:  "when_no_offer_pending",
+			Event: transferIn(`{"PlayerID": 2, "TeamID": 5}`),
+			Resolver: func(repo *repository.MockOfferRepository, outbox *event_repository.MockOutboxRepository, written *[]event_model.Event) {
+				repo.EXPECT().WithdrawPending(gomock.Any(), int64(2), int64(5)).Return([]model.OfferModel{}, nil)
+			},
+		},
+		{
+			Name:  "when_event_malformed",
+			Event: transferIn(`{`),
+			Resolver: func(repo *repository.MockOfferRepository, outbox *event_repository.MockOutboxRepository, written *[]event_model.Event) {
+			},
+			ExpectErr: errors.New("unexpected end of JSON input"),
+		},
+		{
+			Name:  "when_withdraw_fails",
+			Event: transferIn(`{"PlayerID": 2, "TeamID": 5}`),
+			Resolver: func(repo *repository.MockOfferRepository, outbox *event_repository.MockOutboxRepository, written *[]event_model.Event) {
+				repo.EXPECT().WithdrawPending(gomock.Any(), int64(2), int64(5)).Return(nil, errors.New("some-error"))
+			},
+			ExpectErr: errors.New("some-error"),
+		},
+		{
+			Name:  "when_outbox_fails",
+			Event: transferIn(`{"PlayerID": 2, "TeamID": 5}`),
+			Resolver: func(repo *repository.MockOfferRepository, outbox *event_repository.MockOutboxRepository, written *[]event_model.Event) {
+				repo.EXPECT().WithdrawPending(gomock.Any(), int64(2), int64(5)).
+					Return([]model.OfferModel{withdrawn(1)}, nil)
+				outbox.EXPECT().Insert(gomock.Any(), gomock.Any()).DoAndReturn(record(written, errors.New("some-error")))
+			},
+			ExpectErr: errors.New("some-error"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := repository.NewMockOfferRepository(ctrl)
+			outbox := event_repository.NewMockOutboxRepository(ctrl)
+
+			var written []event_model.Event
+			test.Resolver(repo, outbox, &written)
+
+			handler := process.NewWithdrawalProcess(repo, outbox)
+
+			err := handler.Handle(context.Background(), test.Event)
+
+			if test.ExpectErr != nil {
+				assert.EqualError(t, err, test.ExpectErr.Error())
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Len(t, written, len(test.ExpectOffers))
+			for i, event := range written {
+				assert.Equal(t, model.OFFER_WITHDRAWN_EVENT, event.Type)
+				assert.Equal(t, model.OfferStreamID(test.ExpectOffers[i].ID), event.StreamID)
+				assert.Equal(t, "some-correlation-id", event.CorrelationID())
+				assert.False(t, event.CreatedAt.IsZero())
+
+				var data model.OfferEventData
+				assert.Nil(t, json.Unmarshal(event.Data, &data))
+				assert.Equal(t, model.OfferEventData{OfferID: test.ExpectOffers[i].ID, Offer: test.ExpectOffers[i]}, data)
+			}
+		})
+	}
+}
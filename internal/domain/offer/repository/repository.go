@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/tesarwijaya/ouroboros/internal/database"
+	"github.com/tesarwijaya/ouroboros/internal/domain/offer/model"
+	"github.com/tesarwijaya/ouroboros/internal/metrics"
+	"go.uber.org/dig"
+)
+
+const (
+	TRANSFER_OFFER_TABLE_NAME = "transfer_offer"
+)
+
+var columns = []string{"id", "parent_id", "player_id", "from_team_id", "to_team_id", "proposed_by_team_id", "fee", "status", "expires_at", "created_at", "updated_at"}
+
+type OfferRepository interface {
+	FindByID(ctx context.Context, id int64) (model.OfferModel, error)
+	FindByTeamID(ctx context.Context, teamID int64, status string) ([]model.OfferModel, error)
+	FindPending(ctx context.Context, playerID int64, toTeamID int64) ([]model.OfferModel, error)
+	Insert(ctx context.Context, payload model.OfferModel) (model.OfferModel, error)
+	Transition(ctx context.Context, id int64, from string, to string) (model.OfferModel, bool, error)
+	ExpirePending(ctx context.Context) ([]model.OfferModel, error)
+	WithdrawPending(ctx context.Context, playerID int64, teamID int64) ([]model.OfferModel, error)
+}
+
+type OfferRepositoryImpl struct {
+	dig.In
+	Db *sql.DB
+}
+
+func NewOfferRepository(repo OfferRepositoryImpl) OfferRepository {
+	return &repo
+}
+
+func (r *OfferRepositoryImpl) FindByID(ctx context.Context, id int64) (model.OfferModel, error) {
+	defer metrics.ObserveQuery("offer", "FindByID")()
+
+	q := sqlbuilder.NewSelectBuilder()
+	query, args := q.Select(columns...).
+		From(TRANSFER_OFFER_TABLE_NAME).
+		Where(q.Equal("id", id)).
+		BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	res, err := scan(database.QueryRow(ctx, r.Db, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.OfferModel{}, model.ErrOfferNotFound
+	}
+
+	if err != nil {
+		return model.OfferModel{}, err
+	}
+
+	return res, nil
+}
+
+// FindByTeamID returns the offers the team makes or receives in the given
+// status, the soonest to expire first.
+func (r *OfferRepositoryImpl) FindByTeamID(ctx context.Context, teamID int64, status string) ([]model.OfferModel, error) {
+	defer metrics.ObserveQuery("offer", "FindByTeamID")()
+
+	q := sqlbuilder.NewSelectBuilder()
+	query, args := q.Select(columns...).
+		From(TRANSFER_OFFER_TABLE_NAME).
+		Where(
+			q.Or(q.Equal("from_team_id", teamID), q.Equal("to_team_id", teamID)),
+			q.Equal("status", status),
+		).
+		OrderBy("expires_at", "id").
+		BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	return r.query(ctx, query, args...)
+}
+
+// FindPending returns the pending offers of the buying team for the player
+// which are not past their deadline.
+func (r *OfferRepositoryImpl) FindPending(ctx context.Context, playerID int64, toTeamID int64) ([]model.OfferModel, error) {
+	defer metrics.ObserveQuery("offer", "FindPending")()
+
+	q := sqlbuilder.NewSelectBuilder()
+	query, args := q.Select(columns...).
+		From(TRANSFER_OFFER_TABLE_NAME).
+		Where(
+			q.Equal("player_id", playerID),
+			q.Equal("to_team_id", toTeamID),
+			q.Equal("status", model.STATUS_PENDING),
+			q.GreaterThan("expires_at", sqlbuilder.Raw("now()")),
+		).
+		OrderBy("id").
+		BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	return r.query(ctx, query, args...)
+}
+
+func (r *OfferRepositoryImpl) Insert(ctx context.Context, payload model.OfferModel) (model.OfferModel, error) {
+	defer metrics.ObserveQuery("offer", "Insert")()
+
+	parentID := sql.NullInt64{Int64: payload.ParentID, Valid: payload.ParentID != 0}
+
+	q := sqlbuilder.NewInsertBuilder()
+	query, args := q.InsertInto(TRANSFER_OFFER_TABLE_NAME).
+		Cols("parent_id", "player_id", "from_team_id", "to_team_id", "proposed_by_team_id", "fee", "status", "expires_at").
+		Values(parentID, payload.PlayerID, payload.FromTeamID, payload.ToTeamID, payload.ProposedByTeamID, payload.Fee, payload.Status, payload.ExpiresAt).
+		SQL("RETURNING " + strings.Join(columns, ", ")).
+		BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	res, err := scan(database.QueryRow(ctx, r.Db, query, args...))
+	if err != nil {
+		return model.OfferModel{}, err
+	}
+
+	return res, nil
+}
+
+// Transition moves the offer from one status to another, it reports false
+// when the offer isn't in the from status anymore, e.g. when it was answered
+// or expired concurrently.
+func (r *OfferRepositoryImpl) Transition(ctx context.Context, id int64, from string, to string) (model.OfferModel, bool, error) {
+	defer metrics.ObserveQuery("offer", "Transition")()
+
+	q := sqlbuilder.NewUpdateBuilder()
+	query, args := q.Update(TRANSFER_OFFER_TABLE_NAME).
+		Set(
+			q.Assign("status", to),
+			"updated_at = now()",
+		).
+		Where(q.Equal("id", id), q.Equal("status", from)).
+		SQL("RETURNING " + strings.Join(columns, ", ")).
+		BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	res, err := scan(database.QueryRow(ctx, r.Db, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.OfferModel{}, false, nil
+	}
+
+	if err != nil {
+		return model.OfferModel{}, false, err
+	}
+
+	return res, true, nil
+}
+
+// ExpirePending expires the pending offers past their deadline and returns
+// them.
+func (r *OfferRepositoryImpl) ExpirePending(ctx context.Context) ([]model.OfferModel, error) {
+	defer metrics.ObserveQuery("offer", "ExpirePending")()
+
+	q := sqlbuilder.NewUpdateBuilder()
+	query, args := q.Update(TRANSFER_OFFER_TABLE_NAME).
+		Set(
+			q.Assign("status", model.STATUS_EXPIRED),
+			"updated_at = now()",
+		).
+		Where(
+			q.Equal("status", model.STATUS_PENDING),
+			q.LessThan("expires_at", sqlbuilder.Raw("now()")),
+		).
+		SQL("RETURNING " + strings.Join(columns, ", ")).
+		BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	return r.query(ctx, query, args...)
+}
+
+// WithdrawPending withdraws the pending offers for the player that were made
+// to another team than the one it now plays for, and returns them.
+func (r *OfferRepositoryImpl) WithdrawPending(ctx context.Context, playerID int64, teamID int64) ([]model.OfferModel, error) {
+	defer metrics.ObserveQuery("offer", "WithdrawPending")()
+
+	q := sqlbuilder.NewUpdateBuilder()
+	query, args := q.Update(TRANSFER_OFFER_TABLE_NAME).
+		Set(
+			q.Assign("status", model.STATUS_WITHDRAWN),
+			"updated_at = now()",
+		).
+		Where(
+			q.Equal("player_id", playerID),
+			q.Equal("status", model.STATUS_PENDING),
+			q.NotEqual("from_team_id", teamID),
+		).
+		SQL("RETURNING " + strings.Join(columns, ", ")).
+		BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	return r.query(ctx, query, args...)
+}
+
+func (r *OfferRepositoryImpl) query(ctx context.Context, query string, args ...interface{}) ([]model.OfferModel, error) {
+	res := []model.OfferModel{}
+
+	rows, err := database.Query(ctx, r.Db, query, args...)
+	if err != nil {
+		return []model.OfferModel{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		data, err := scan(rows)
+		if err != nil {
+			return []model.OfferModel{}, err
+		}
+
+		res = append(res, data)
+	}
+
+	if err := rows.Err(); err != nil {
+		return []model.OfferModel{}, err
+	}
+
+	return res, nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scan(row scanner) (model.OfferModel, error) {
+	var (
+		res      model.OfferModel
+		parentID sql.NullInt64
+	)
+
+	err := row.Scan(
+		&res.ID,
+		&parentID,
+		&res.PlayerID,
+		&res.FromTeamID,
+		&res.ToTeamID,
+		&res.ProposedByTeamID,
+		&res.Fee,
+		&res.Status,
+		&res.ExpiresAt,
+		&res.CreatedAt,
+		&res.UpdatedAt,
+	)
+	res.ParentID = parentID.Int64
+
+	return res, err
+}
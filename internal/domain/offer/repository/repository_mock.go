@@ -0,0 +1,183 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/offer/repository/repository.go
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/tesarwijaya/ouroboros/internal/domain/offer/model"
+)
+
+// MockOfferRepository is a mock of OfferRepository interface.
+type MockOfferRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOfferRepositoryMockRecorder
+}
+
+// MockOfferRepositoryMockRecorder is the mock recorder for MockOfferRepository.
+type MockOfferRepositoryMockRecorder struct {
+	mock *MockOfferRepository
+}
+
+// NewMockOfferRepository creates a new mock instance.
+func NewMockOfferRepository(ctrl *gomock.Controller) *MockOfferRepository {
+	mock := &MockOfferRepository{ctrl: ctrl}
+	mock.recorder = &MockOfferRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOfferRepository) EXPECT() *MockOfferRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ExpirePending mocks base method.
+func (m *MockOfferRepository) ExpirePending(ctx context.Context) ([]model.OfferModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpirePending", ctx)
+	ret0, _ := ret[0].([]model.OfferModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpirePending indicates an expected call of ExpirePending.
+func (mr *MockOfferRepositoryMockRecorder) ExpirePending(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpirePending", reflect.TypeOf((*MockOfferRepository)(nil).ExpirePending), ctx)
+}
+
+// FindByID mocks base method.
+func (m *MockOfferRepository) FindByID(ctx context.Context, id int64) (model.OfferModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(model.OfferModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockOfferRepositoryMockRecorder) FindByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockOfferRepository)(nil).FindByID), ctx, id)
+}
+
+// FindByTeamID mocks base method.
+func (m *MockOfferRepository) FindByTeamID(ctx context.Context, teamID int64, status string) ([]model.OfferModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByTeamID", ctx, teamID, status)
+	ret0, _ := ret[0].([]model.OfferModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByTeamID indicates an expected call of FindByTeamID.
+func (mr *MockOfferRepositoryMockRecorder) FindByTeamID(ctx, teamID, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByTeamID", reflect.TypeOf((*MockOfferRepository)(nil).FindByTeamID), ctx, teamID, status)
+}
+
+// FindPending mocks base method.
+func (m *MockOfferRepository) FindPending(ctx context.Context, playerID, toTeamID int64) ([]model.OfferModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPending", ctx, playerID, toTeamID)
+	ret0, _ := ret[0].([]model.OfferModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPending indicates an expected call of FindPending.
+func (mr *MockOfferRepositoryMockRecorder) FindPending(ctx, playerID, toTeamID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPending", reflect.TypeOf((*MockOfferRepository)(nil).FindPending), ctx, playerID, toTeamID)
+}
+
+// Insert mocks base method.
+func (m *MockOfferRepository) Insert(ctx context.Context, payload model.OfferModel) (model.OfferModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Insert", ctx, payload)
+	ret0, _ := ret[0].(model.OfferModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Insert indicates an expected call of Insert.
+func (mr *MockOfferRepositoryMockRecorder) Insert(ctx, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockOfferRepository)(nil).Insert), ctx, payload)
+}
+
+// Transition mocks base method.
+func (m *MockOfferRepository) Transition(ctx context.Context, id int64, from, to string) (model.OfferModel, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transition", ctx, id, from, to)
+	ret0, _ := ret[0].(model.OfferModel)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Transition indicates an expected call of Transition.
+func (mr *MockOfferRepositoryMockRecorder) Transition(ctx, id, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transition", reflect.TypeOf((*MockOfferRepository)(nil).Transition), ctx, id, from, to)
+}
+
+// WithdrawPending mocks base method.
+func (m *MockOfferRepository) WithdrawPending(ctx context.Context, playerID, teamID int64) ([]model.OfferModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithdrawPending", ctx, playerID, teamID)
+	ret0, _ := ret[0].([]model.OfferModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WithdrawPending indicates an expected call of WithdrawPending.
+func (mr *MockOfferRepositoryMockRecorder) WithdrawPending(ctx, playerID, teamID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithdrawPending", reflect.TypeOf((*MockOfferRepository)(nil).WithdrawPending), ctx, playerID, teamID)
+}
+
+// Mockscanner is a mock of scanner interface.
+type Mockscanner struct {
+	ctrl     *gomock.Controller
+	recorder *MockscannerMockRecorder
+}
+
+// MockscannerMockRecorder is the mock recorder for Mockscanner.
+type MockscannerMockRecorder struct {
+	mock *Mockscanner
+}
+
+// NewMockscanner creates a new mock instance.
+func NewMockscanner(ctrl *gomock.Controller) *Mockscanner {
+	mock := &Mockscanner{ctrl: ctrl}
+	mock.recorder = &MockscannerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockscanner) EXPECT() *MockscannerMockRecorder {
+	return m.recorder
+}
+
+// Scan mocks base method.
+func (m *Mockscanner) Scan(dest ...interface{}) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range dest {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Scan", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Scan indicates an expected call of Scan.
+func (mr *MockscannerMockRecorder) Scan(dest ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scan", reflect.TypeOf((*Mockscanner)(nil).Scan), dest...)
+}
@@ -0,0 +1,203 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/tesarwijaya/ouroboros/internal/domain/offer/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/offer/repository"
+)
+
+type mockFn func(db sqlmock.Sqlmock)
+
+const selectColumns = "id, parent_id, player_id, from_team_id, to_team_id, proposed_by_team_id, fee, status, expires_at, created_at, updated_at"
+
+var (
+	columns   = []string{"id", "parent_id", "player_id", "from_team_id", "to_team_id", "proposed_by_team_id", "fee", "status", "expires_at", "created_at", "updated_at"}
+	createdAt = time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)
+	expiresAt = createdAt.Add(72 * time.Hour)
+)
+
+func createRepo(mockFn mockFn) repository.OfferRepository {
+	db, mock, _ := sqlmock.New()
+
+	mockFn(mock)
+	repo := repository.NewOfferRepository(repository.OfferRepositoryImpl{
+		Db: db,
+	})
+
+	return repo
+}
+
+func row(status string) []driver.Value {
+	return []driver.Value{int64(1), nil, int64(2), int64(3), int64(4), int64(4), int64(100), status, expiresAt, createdAt, createdAt}
+}
+
+func offer(status string) model.OfferModel {
+	return model.OfferModel{
+		ID:               1,
+		PlayerID:         2,
+		FromTeamID:       3,
+		ToTeamID:         4,
+		ProposedByTeamID: 4,
+		Fee:              100,
+		Status:           status,
+		ExpiresAt:        expiresAt,
+		CreatedAt:        createdAt,
+		UpdatedAt:        createdAt,
+	}
+}
+
+func Test_FindByID(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		MockFn      mockFn
+		Expected    model.OfferModel
+		ExpectedErr error
+	}{
+		{
+			Name: "when_data_present",
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(regexp.QuoteMeta("SELECT " + selectColumns + " FROM transfer_offer WHERE id = $1")).
+					WithArgs(int64(1)).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(row(model.STATUS_PENDING)...))
+			},
+			Expected: offer(model.STATUS_PENDING),
+		},
+		{
+			Name: "when_data_not_present",
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(regexp.QuoteMeta("SELECT " + selectColumns + " FROM transfer_offer WHERE id = $1")).
+					WithArgs(int64(1)).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			ExpectedErr: model.ErrOfferNotFound,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := createRepo(test.MockFn)
+
+			actual, err := repo.FindByID(context.Background(), 1)
+
+			assert.Equal(t, test.ExpectedErr, err)
+			assert.Equal(t, test.Expected, actual)
+		})
+	}
+}
+
+func Test_FindByTeamID(t *testing.T) {
+	repo := createRepo(func(db sqlmock.Sqlmock) {
+		db.ExpectQuery(regexp.QuoteMeta("SELECT "+selectColumns+" FROM transfer_offer WHERE (from_team_id = $1 OR to_team_id = $2) AND status = $3 ORDER BY expires_at, id")).
+			WithArgs(int64(3), int64(3), model.STATUS_PENDING).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(row(model.STATUS_PENDING)...))
+	})
+
+	actual, err := repo.FindByTeamID(context.Background(), 3, model.STATUS_PENDING)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []model.OfferModel{offer(model.STATUS_PENDING)}, actual)
+}
+
+func Test_FindPending(t *testing.T) {
+	repo := createRepo(func(db sqlmock.Sqlmock) {
+		db.ExpectQuery(regexp.QuoteMeta("SELECT "+selectColumns+" FROM transfer_offer WHERE player_id = $1 AND to_team_id = $2 AND status = $3 AND expires_at > now() ORDER BY id")).
+			WithArgs(int64(2), int64(4), model.STATUS_PENDING).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(row(model.STATUS_PENDING)...))
+	})
+
+	actual, err := repo.FindPending(context.Background(), 2, 4)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []model.OfferModel{offer(model.STATUS_PENDING)}, actual)
+}
+
+func Test_Insert(t *testing.T) {
+	payload := offer(model.STATUS_PENDING)
+	payload.ParentID = 7
+
+	repo := createRepo(func(db sqlmock.Sqlmock) {
+		db.ExpectQuery(regexp.QuoteMeta("INSERT INTO transfer_offer (parent_id, player_id, from_team_id, to_team_id, proposed_by_team_id, fee, status, expires_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING "+selectColumns)).
+			WithArgs(sql.NullInt64{Int64: 7, Valid: true}, int64(2), int64(3), int64(4), int64(4), int64(100), model.STATUS_PENDING, expiresAt).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(int64(1), int64(7), int64(2), int64(3), int64(4), int64(4), int64(100), model.STATUS_PENDING, expiresAt, createdAt, createdAt))
+	})
+
+	actual, err := repo.Insert(context.Background(), payload)
+
+	assert.Nil(t, err)
+	assert.Equal(t, payload, actual)
+}
+
+func Test_Transition(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		MockFn   mockFn
+		Expected model.OfferModel
+		ExpectOK bool
+	}{
+		{
+			Name: "when_pending",
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(regexp.QuoteMeta("UPDATE transfer_offer SET status = $1, updated_at = now() WHERE id = $2 AND status = $3 RETURNING "+selectColumns)).
+					WithArgs(model.STATUS_ACCEPTED, int64(1), model.STATUS_PENDING).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(row(model.STATUS_ACCEPTED)...))
+			},
+			Expected: offer(model.STATUS_ACCEPTED),
+			ExpectOK: true,
+		},
+		{
+			Name: "when_not_pending_anymore",
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(regexp.QuoteMeta("UPDATE transfer_offer SET status = $1, updated_at = now() WHERE id = $2 AND status = $3 RETURNING "+selectColumns)).
+					WithArgs(model.STATUS_ACCEPTED, int64(1), model.STATUS_PENDING).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := createRepo(test.MockFn)
+
+			actual, ok, err := repo.Transition(context.Background(), 1, model.STATUS_PENDING, model.STATUS_ACCEPTED)
+
+			assert.Nil(t, err)
+			assert.Equal(t, test.ExpectOK, ok)
+			assert.Equal(t, test.Expected, actual)
+		})
+	}
+}
+
+func Test_ExpirePending(t *testing.T) {
+	repo := createRepo(func(db sqlmock.Sqlmock) {
+		db.ExpectQuery(regexp.QuoteMeta("UPDATE transfer_offer SET status = $1, updated_at = now() WHERE status = $2 AND expires_at < now() RETURNING "+selectColumns)).
+			WithArgs(model.STATUS_EXPIRED, model.STATUS_PENDING).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(row(model.STATUS_EXPIRED)...))
+	})
+
+	actual, err := repo.ExpirePending(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, []model.OfferModel{offer(model.STATUS_EXPIRED)}, actual)
+}
+
+func Test_WithdrawPending(t *testing.T) {
+	repo := createRepo(func(db sqlmock.Sqlmock) {
+		db.ExpectQuery(regexp.QuoteMeta("UPDATE transfer_offer SET status = $1, updated_at = now() WHERE player_id = $2 AND status = $3 AND from_team_id <> $4 RETURNING "+selectColumns)).
+			WithArgs(model.STATUS_WITHDRAWN, int64(2), model.STATUS_PENDING, int64(5)).
+			WillReturnRows(sqlmock.NewRows(columns))
+	})
+
+	actual, err := repo.WithdrawPending(context.Background(), 2, 5)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []model.OfferModel{}, actual)
+}
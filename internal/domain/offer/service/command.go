@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	command_model "github.com/tesarwijaya/ouroboros/internal/domain/command/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/offer/model"
+	"go.uber.org/dig"
+)
+
+type (
+	MakeOfferCommand struct {
+		Payload MakeOfferPayload
+	}
+
+	AcceptOfferCommand struct {
+		ID int64
+	}
+
+	RejectOfferCommand struct {
+		ID int64
+	}
+
+	CounterOfferCommand struct {
+		ID      int64
+		Payload CounterOfferPayload
+	}
+
+	CommandHandlers struct {
+		dig.Out
+		Handlers []command_model.Handler `group:"command_handlers,flatten"`
+	}
+)
+
+func (MakeOfferCommand) CommandName() string {
+	return "offer.make"
+}
+
+func (c MakeOfferCommand) Validate() error {
+	if c.Payload.PlayerID <= 0 {
+		return errors.New("playerId is required")
+	}
+
+	if c.Payload.ToTeamID <= 0 {
+		return errors.New("toTeamId is required")
+	}
+
+	if c.Payload.Fee < 0 {
+		return errors.New("fee can't be negative")
+	}
+
+	return nil
+}
+
+func (AcceptOfferCommand) CommandName() string {
+	return "offer.accept"
+}
+
+func (c AcceptOfferCommand) Validate() error {
+	if c.ID <= 0 {
+		return errors.New("id is required")
+	}
+
+	return nil
+}
+
+func (RejectOfferCommand) CommandName() string {
+	return "offer.reject"
+}
+
+func (c RejectOfferCommand) Validate() error {
+	if c.ID <= 0 {
+		return errors.New("id is required")
+	}
+
+	return nil
+}
+
+func (CounterOfferCommand) CommandName() string {
+	return "offer.counter"
+}
+
+func (c CounterOfferCommand) Validate() error {
+	if c.ID <= 0 {
+		return errors.New("id is required")
+	}
+
+	if c.Payload.Fee < 0 {
+		return errors.New("fee can't be negative")
+	}
+
+	return nil
+}
+
+// NewCommandHandlers registers the offer service methods as the handlers of
+// the offer commands.
+func NewCommandHandlers(svc OfferService) CommandHandlers {
+	return CommandHandlers{
+		Handlers: []command_model.Handler{
+			command_model.NewHandler(func(ctx context.Context, cmd MakeOfferCommand) (model.OfferModel, error) {
+				return svc.Make(ctx, cmd.Payload)
+			}),
+			command_model.NewHandler(func(ctx context.Context, cmd AcceptOfferCommand) (model.OfferModel, error) {
+				return svc.Accept(ctx, cmd.ID)
+			}),
+			command_model.NewHandler(func(ctx context.Context, cmd RejectOfferCommand) (model.OfferModel, error) {
+				return svc.Reject(ctx, cmd.ID)
+			}),
+			command_model.NewHandler(func(ctx context.Context, cmd CounterOfferCommand) (model.OfferModel, error) {
+				return svc.Counter(ctx, cmd.ID, cmd.Payload)
+			}),
+		},
+	}
+}
@@ -0,0 +1,421 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/EventStore/EventStore-Client-Go/esdb"
+	"github.com/gofrs/uuid"
+	"github.com/tesarwijaya/ouroboros/internal/config"
+	"github.com/tesarwijaya/ouroboros/internal/database"
+	auth_model "github.com/tesarwijaya/ouroboros/internal/domain/auth/model"
+	auth_service "github.com/tesarwijaya/ouroboros/internal/domain/auth/service"
+	command_model "github.com/tesarwijaya/ouroboros/internal/domain/command/model"
+	event_model "github.com/tesarwijaya/ouroboros/internal/domain/event/model"
+	event_service "github.com/tesarwijaya/ouroboros/internal/domain/event/service"
+	"github.com/tesarwijaya/ouroboros/internal/domain/offer/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/offer/repository"
+	player_repository "github.com/tesarwijaya/ouroboros/internal/domain/player/repository"
+	player_service "github.com/tesarwijaya/ouroboros/internal/domain/player/service"
+	team_repository "github.com/tesarwijaya/ouroboros/internal/domain/team/repository"
+	"github.com/tesarwijaya/ouroboros/internal/logger"
+	"github.com/tesarwijaya/ouroboros/internal/tracing"
+	"go.uber.org/dig"
+	"go.uber.org/zap"
+)
+
+type (
+	// MakeOfferPayload is an offer of ToTeamID for a player of another team,
+	// it expires after the configured TTL unless ExpiresAt is given.
+	MakeOfferPayload struct {
+		PlayerID  int64     `json:"playerId"`
+		ToTeamID  int64     `json:"toTeamId"`
+		Fee       int64     `json:"fee"`
+		ExpiresAt time.Time `json:"expiresAt,omitempty"`
+	}
+
+	CounterOfferPayload struct {
+		Fee       int64     `json:"fee"`
+		ExpiresAt time.Time `json:"expiresAt,omitempty"`
+	}
+)
+
+type OfferService interface {
+	FindByTeamID(ctx context.Context, teamID int64, status string) ([]model.OfferModel, error)
+	Make(ctx context.Context, payload MakeOfferPayload) (model.OfferModel, error)
+	Accept(ctx context.Context, id int64) (model.OfferModel, error)
+	Reject(ctx context.Context, id int64) (model.OfferModel, error)
+	Counter(ctx context.Context, id int64, payload CounterOfferPayload) (model.OfferModel, error)
+	Expire(ctx context.Context) (int64, error)
+}
+
+// OfferServiceImpl is the process manager of the transfer offers, their state
+// is kept in SQL and every change of it is recorded on the offer stream. The
+// player is only transferred once an offer is accepted.
+type OfferServiceImpl struct {
+	dig.In
+	Db            *sql.DB
+	Config        *config.Config
+	Repo          repository.OfferRepository
+	PlayerRepo    player_repository.PlayerRepository
+	TeamRepo      team_repository.TeamRepository
+	PlayerService player_service.PlayerService
+	EventBus      event_service.EventBus
+	Authz         auth_service.Authorizer
+}
+
+func NewOfferService(svc OfferServiceImpl) OfferService {
+	return &svc
+}
+
+// FindByTeamID returns the offers the team makes or receives in the given
+// status.
+func (s *OfferServiceImpl) FindByTeamID(ctx context.Context, teamID int64, status string) ([]model.OfferModel, error) {
+	ctx, span := tracing.Start(ctx, "OfferService.FindByTeamID")
+	defer span.End()
+
+	if !model.STATUSES[status] {
+		return []model.OfferModel{}, command_model.ValidationError{Err: fmt.Errorf("unknown status %q", status)}
+	}
+
+	if err := s.Authz.Authorize(ctx, auth_model.ACTION_OFFER_READ, auth_model.Resource{TeamID: teamID}); err != nil {
+		return []model.OfferModel{}, err
+	}
+
+	return s.Repo.FindByTeamID(ctx, teamID, status)
+}
+
+// Make records the offer of the buying team for a player of another team, the
+// selling team then accepts, rejects or counters it. The player is locked
+// while it is checked, so that a buying team can't have two offers pending
+// for it.
+func (s *OfferServiceImpl) Make(ctx context.Context, payload MakeOfferPayload) (model.OfferModel, error) {
+	ctx, span := tracing.Start(ctx, "OfferService.Make")
+	defer span.End()
+
+	if err := s.Authz.Authorize(ctx, auth_model.ACTION_OFFER_MAKE, auth_model.Resource{TeamID: payload.ToTeamID}); err != nil {
+		return model.OfferModel{}, err
+	}
+
+	expiresAt, err := s.expiresAt(payload.ExpiresAt)
+	if err != nil {
+		return model.OfferModel{}, err
+	}
+
+	var res model.OfferModel
+	err = database.WithTx(ctx, s.Db, func(ctx context.Context) error {
+		player, err := s.PlayerRepo.FindByIDForUpdate(ctx, payload.PlayerID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return command_model.ValidationError{Err: fmt.Errorf("player %d not found", payload.PlayerID)}
+		}
+		if err != nil {
+			return err
+		}
+
+		if player.TeamID == 0 {
+			return command_model.ValidationError{Err: fmt.Errorf("player %d plays for no team", player.ID)}
+		}
+
+		if player.TeamID == payload.ToTeamID {
+			return command_model.ValidationError{Err: fmt.Errorf("player %d already plays for team %d", player.ID, payload.ToTeamID)}
+		}
+
+		_, err = s.TeamRepo.FindByID(ctx, payload.ToTeamID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return command_model.ValidationError{Err: fmt.Errorf("team %d not found", payload.ToTeamID)}
+		}
+		if err != nil {
+			return err
+		}
+
+		pending, err := s.Repo.FindPending(ctx, player.ID, payload.ToTeamID)
+		if err != nil {
+			return err
+		}
+
+		if len(pending) > 0 {
+			return command_model.ValidationError{Err: fmt.Errorf("team %d already has offer %d pending for player %d", payload.ToTeamID, pending[0].ID, player.ID)}
+		}
+
+		res = model.OfferModel{
+			PlayerID:         player.ID,
+			FromTeamID:       player.TeamID,
+			ToTeamID:         payload.ToTeamID,
+			ProposedByTeamID: payload.ToTeamID,
+			Fee:              payload.Fee,
+			Status:           model.STATUS_PENDING,
+			ExpiresAt:        expiresAt,
+		}
+
+		if command_model.IsDryRun(ctx) {
+			return nil
+		}
+
+		res, err = s.Repo.Insert(ctx, res)
+		if err != nil {
+			return err
+		}
+
+		return s.EventBus.Publish(ctx, event(model.OFFER_MADE_EVENT, model.OfferEventData{OfferID: res.ID, Offer: res}))
+	})
+	if err != nil {
+		return model.OfferModel{}, err
+	}
+
+	return res, nil
+}
+
+// Accept closes the offer and transfers the player to the buying team in the
+// same transaction, along with their events. The player is locked from the
+// check that it still plays for the selling team until the transfer, which
+// only moves it from that team. The responding team already agreed to the
+// transfer by accepting, so the transfer runs as the offer transfer process
+// rather than the caller, which may not transfer the player itself: a buying
+// team accepting a counter offer doesn't own the player.
+func (s *OfferServiceImpl) Accept(ctx context.Context, id int64) (model.OfferModel, error) {
+	ctx, span := tracing.Start(ctx, "OfferService.Accept")
+	defer span.End()
+
+	offer, err := s.findPending(ctx, id)
+	if err != nil {
+		return model.OfferModel{}, err
+	}
+
+	var res model.OfferModel
+	err = database.WithTx(ctx, s.Db, func(ctx context.Context) error {
+		player, err := s.PlayerRepo.FindByIDForUpdate(ctx, offer.PlayerID)
+		if err != nil {
+			return err
+		}
+
+		if player.TeamID != offer.FromTeamID {
+			return command_model.ValidationError{Err: fmt.Errorf("player %d doesn't play for team %d anymore", player.ID, offer.FromTeamID)}
+		}
+
+		if command_model.IsDryRun(ctx) {
+			res = offer
+			res.Status = model.STATUS_ACCEPTED
+			return nil
+		}
+
+		res, err = s.transition(ctx, offer, model.STATUS_ACCEPTED)
+		if err != nil {
+			return err
+		}
+
+		_, err = s.PlayerService.TransferBatch(auth_model.AsProcess(ctx, model.TRANSFER_PROCESS), []player_service.TransferPayload{
+			{PlayerID: res.PlayerID, TeamID: res.ToTeamID},
+		})
+		if err != nil {
+			return err
+		}
+
+		return s.EventBus.Publish(ctx, event(model.OFFER_ACCEPTED_EVENT, model.OfferEventData{OfferID: res.ID, Offer: res}))
+	})
+	if err != nil {
+		return model.OfferModel{}, err
+	}
+
+	if command_model.IsDryRun(ctx) {
+		return res, nil
+	}
+
+	logger.FromContext(ctx).Info("accepted transfer offer",
+		zap.Int64("offer_id", res.ID),
+		zap.Int64("player_id", res.PlayerID),
+		zap.Int64("from_team_id", res.FromTeamID),
+		zap.Int64("to_team_id", res.ToTeamID),
+	)
+
+	return res, nil
+}
+
+func (s *OfferServiceImpl) Reject(ctx context.Context, id int64) (model.OfferModel, error) {
+	ctx, span := tracing.Start(ctx, "OfferService.Reject")
+	defer span.End()
+
+	offer, err := s.findPending(ctx, id)
+	if err != nil {
+		return model.OfferModel{}, err
+	}
+
+	if command_model.IsDryRun(ctx) {
+		offer.Status = model.STATUS_REJECTED
+		return offer, nil
+	}
+
+	var res model.OfferModel
+	err = database.WithTx(ctx, s.Db, func(ctx context.Context) error {
+		res, err = s.transition(ctx, offer, model.STATUS_REJECTED)
+		if err != nil {
+			return err
+		}
+
+		return s.EventBus.Publish(ctx, event(model.OFFER_REJECTED_EVENT, model.OfferEventData{OfferID: res.ID, Offer: res}))
+	})
+	if err != nil {
+		return model.OfferModel{}, err
+	}
+
+	return res, nil
+}
+
+// Counter closes the offer and makes a new one in return, from the
+// responding team, which the other team then accepts, rejects or counters.
+func (s *OfferServiceImpl) Counter(ctx context.Context, id int64, payload CounterOfferPayload) (model.OfferModel, error) {
+	ctx, span := tracing.Start(ctx, "OfferService.Counter")
+	defer span.End()
+
+	offer, err := s.findPending(ctx, id)
+	if err != nil {
+		return model.OfferModel{}, err
+	}
+
+	expiresAt, err := s.expiresAt(payload.ExpiresAt)
+	if err != nil {
+		return model.OfferModel{}, err
+	}
+
+	counter := model.OfferModel{
+		ParentID:         offer.ID,
+		PlayerID:         offer.PlayerID,
+		FromTeamID:       offer.FromTeamID,
+		ToTeamID:         offer.ToTeamID,
+		ProposedByTeamID: offer.RespondingTeamID(),
+		Fee:              payload.Fee,
+		Status:           model.STATUS_PENDING,
+		ExpiresAt:        expiresAt,
+	}
+
+	if command_model.IsDryRun(ctx) {
+		return counter, nil
+	}
+
+	var res model.OfferModel
+	err = database.WithTx(ctx, s.Db, func(ctx context.Context) error {
+		countered, err := s.transition(ctx, offer, model.STATUS_COUNTERED)
+		if err != nil {
+			return err
+		}
+
+		res, err = s.Repo.Insert(ctx, counter)
+		if err != nil {
+			return err
+		}
+
+		return s.EventBus.Publish(ctx,
+			event(model.OFFER_COUNTERED_EVENT, model.OfferEventData{OfferID: countered.ID, CounterOfferID: res.ID, Offer: countered}),
+			event(model.OFFER_MADE_EVENT, model.OfferEventData{OfferID: res.ID, Offer: res}),
+		)
+	})
+	if err != nil {
+		return model.OfferModel{}, err
+	}
+
+	return res, nil
+}
+
+// Expire expires the pending offers past their deadline, it returns how many
+// were.
+func (s *OfferServiceImpl) Expire(ctx context.Context) (int64, error) {
+	ctx, span := tracing.Start(ctx, "OfferService.Expire")
+	defer span.End()
+
+	var expired []model.OfferModel
+	err := database.WithTx(ctx, s.Db, func(ctx context.Context) error {
+		var err error
+		expired, err = s.Repo.ExpirePending(ctx)
+		if err != nil || len(expired) == 0 {
+			return err
+		}
+
+		events := make([]event_model.Event, 0, len(expired))
+		for _, offer := range expired {
+			events = append(events, event(model.OFFER_EXPIRED_EVENT, model.OfferEventData{OfferID: offer.ID, Offer: offer}))
+		}
+
+		return s.EventBus.Publish(ctx, events...)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(expired)), nil
+}
+
+// findPending returns the offer the caller responds to, which must still be
+// pending and not past its deadline.
+func (s *OfferServiceImpl) findPending(ctx context.Context, id int64) (model.OfferModel, error) {
+	offer, err := s.Repo.FindByID(ctx, id)
+	if err != nil {
+		return model.OfferModel{}, err
+	}
+
+	if err := s.Authz.Authorize(ctx, auth_model.ACTION_OFFER_RESPOND, auth_model.Resource{TeamID: offer.RespondingTeamID()}); err != nil {
+		return model.OfferModel{}, err
+	}
+
+	if offer.Status != model.STATUS_PENDING {
+		return model.OfferModel{}, command_model.ValidationError{Err: fmt.Errorf("offer %d is %s", offer.ID, offer.Status)}
+	}
+
+	// the expiry worker may not have caught up with the deadline yet
+	if !offer.ExpiresAt.After(time.Now()) {
+		return model.OfferModel{}, command_model.ValidationError{Err: fmt.Errorf("offer %d is %s", offer.ID, model.STATUS_EXPIRED)}
+	}
+
+	return offer, nil
+}
+
+// transition moves the pending offer to status, it may have been answered or
+// expired since it was read.
+func (s *OfferServiceImpl) transition(ctx context.Context, offer model.OfferModel, status string) (model.OfferModel, error) {
+	res, ok, err := s.Repo.Transition(ctx, offer.ID, model.STATUS_PENDING, status)
+	if err != nil {
+		return model.OfferModel{}, err
+	}
+
+	if !ok {
+		return model.OfferModel{}, command_model.ValidationError{Err: fmt.Errorf("offer %d isn't pending anymore", offer.ID)}
+	}
+
+	return res, nil
+}
+
+// expiresAt returns the deadline of an offer, the configured TTL from now
+// when none is given. A deadline can't be further than the configured max
+// TTL, an offer would block the buying team from making another one until
+// then.
+func (s *OfferServiceImpl) expiresAt(at time.Time) (time.Time, error) {
+	now := time.Now().UTC()
+	if at.IsZero() {
+		return now.Add(s.Config.OfferTTL), nil
+	}
+
+	if !at.After(now) {
+		return time.Time{}, command_model.ValidationError{Err: errors.New("expiresAt must be in the future")}
+	}
+
+	if at.After(now.Add(s.Config.OfferMaxTTL)) {
+		return time.Time{}, command_model.ValidationError{Err: fmt.Errorf("expiresAt must be within %s", s.Config.OfferMaxTTL)}
+	}
+
+	return at, nil
+}
+
+func event(eventType string, data model.OfferEventData) event_model.Event {
+	raw, _ := json.Marshal(data)
+	id, _ := uuid.NewGen().NewV4()
+
+	return event_model.Event{
+		ID:          id,
+		StreamID:    model.OfferStreamID(data.OfferID),
+		Type:        eventType,
+		ContentType: esdb.JsonContentType,
+		Data:        raw,
+	}
+}
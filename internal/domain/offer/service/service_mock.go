@@ -0,0 +1,126 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/offer/service/service.go
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/tesarwijaya/ouroboros/internal/domain/offer/model"
+)
+
+// MockOfferService is a mock of OfferService interface.
+type MockOfferService struct {
+	ctrl     *gomock.Controller
+	recorder *MockOfferServiceMockRecorder
+}
+
+// MockOfferServiceMockRecorder is the mock recorder for MockOfferService.
+type MockOfferServiceMockRecorder struct {
+	mock *MockOfferService
+}
+
+// NewMockOfferService creates a new mock instance.
+func NewMockOfferService(ctrl *gomock.Controller) *MockOfferService {
+	mock := &MockOfferService{ctrl: ctrl}
+	mock.recorder = &MockOfferServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOfferService) EXPECT() *MockOfferServiceMockRecorder {
+	return m.recorder
+}
+
+// Accept mocks base method.
+func (m *MockOfferService) Accept(ctx context.Context, id int64) (model.OfferModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Accept", ctx, id)
+	ret0, _ := ret[0].(model.OfferModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Accept indicates an expected call of Accept.
+func (mr *MockOfferServiceMockRecorder) Accept(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Accept", reflect.TypeOf((*MockOfferService)(nil).Accept), ctx, id)
+}
+
+// Counter mocks base method.
+func (m *MockOfferService) Counter(ctx context.Context, id int64, payload CounterOfferPayload) (model.OfferModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Counter", ctx, id, payload)
+	ret0, _ := ret[0].(model.OfferModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Counter indicates an expected call of Counter.
+func (mr *MockOfferServiceMockRecorder) Counter(ctx, id, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Counter", reflect.TypeOf((*MockOfferService)(nil).Counter), ctx, id, payload)
+}
+
+// Expire mocks base method.
+func (m *MockOfferService) Expire(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Expire", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Expire indicates an expected call of Expire.
+func (mr *MockOfferServiceMockRecorder) Expire(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Expire", reflect.TypeOf((*MockOfferService)(nil).Expire), ctx)
+}
+
+// FindByTeamID mocks base method.
+func (m *MockOfferService) FindByTeamID(ctx context.Context, teamID int64, status string) ([]model.OfferModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByTeamID", ctx, teamID, status)
+	ret0, _ := ret[0].([]model.OfferModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByTeamID indicates an expected call of FindByTeamID.
+func (mr *MockOfferServiceMockRecorder) FindByTeamID(ctx, teamID, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByTeamID", reflect.TypeOf((*MockOfferService)(nil).FindByTeamID), ctx, teamID, status)
+}
+
+// Make mocks base method.
+func (m *MockOfferService) Make(ctx context.Context, payload MakeOfferPayload) (model.OfferModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Make", ctx, payload)
+	ret0, _ := ret[0].(model.OfferModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Make indicates an expected call of Make.
+func (mr *MockOfferServiceMockRecorder) Make(ctx, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Make", reflect.TypeOf((*MockOfferService)(nil).Make), ctx, payload)
+}
+
+// Reject mocks base method.
+func (m *MockOfferService) Reject(ctx context.Context, id int64) (model.OfferModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reject", ctx, id)
+	ret0, _ := ret[0].(model.OfferModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reject indicates an expected call of Reject.
+func (mr *MockOfferServiceMockRecorder) Reject(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reject", reflect.TypeOf((*MockOfferService)(nil).Reject), ctx, id)
+}
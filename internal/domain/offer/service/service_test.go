@@ -0,0 +1,525 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/tesarwijaya/ouroboros/internal/config"
+	auth_model "github.com/tesarwijaya/ouroboros/internal/domain/auth/model"
+	auth_service "github.com/tesarwijaya/ouroboros/internal/domain/auth/service"
+	command_model "github.com/tesarwijaya/ouroboros/internal/domain/command/model"
+	event_model "github.com/tesarwijaya/ouroboros/internal/domain/event/model"
+	event_service "github.com/tesarwijaya/ouroboros/internal/domain/event/service"
+	"github.com/tesarwijaya/ouroboros/internal/domain/offer/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/offer/repository"
+	"github.com/tesarwijaya/ouroboros/internal/domain/offer/service"
+	player_model "github.com/tesarwijaya/ouroboros/internal/domain/player/model"
+	player_repository "github.com/tesarwijaya/ouroboros/internal/domain/player/repository"
+	player_service "github.com/tesarwijaya/ouroboros/internal/domain/player/service"
+	team_model "github.com/tesarwijaya/ouroboros/internal/domain/team/model"
+	team_repository "github.com/tesarwijaya/ouroboros/internal/domain/team/repository"
+)
+
+type mocks struct {
+	repo       *repository.MockOfferRepository
+	playerRepo *player_repository.MockPlayerRepository
+	teamRepo   *team_repository.MockTeamRepository
+	playerSvc  *player_service.MockPlayerService
+}
+
+type resolverFn func(m mocks)
+
+var expiresAt = time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+func pending() model.OfferModel {
+	return model.OfferModel{
+		ID:               1,
+		PlayerID:         2,
+		FromTeamID:       3,
+		ToTeamID:         4,
+		ProposedByTeamID: 4,
+		Fee:              100,
+		Status:           model.STATUS_PENDING,
+		ExpiresAt:        expiresAt,
+	}
+}
+
+func with(offer model.OfferModel, status string) model.OfferModel {
+	offer.Status = status
+	return offer
+}
+
+// createService wires the service with a transaction expected to begin and
+// to be committed, or rolled back when rollback is set.
+func createService(t *testing.T, resolver resolverFn, tx bool, rollback bool) (*service.OfferServiceImpl, *[]event_model.Event, func()) {
+	ctrl := gomock.NewController(t)
+
+	m := mocks{
+		repo:       repository.NewMockOfferRepository(ctrl),
+		playerRepo: player_repository.NewMockPlayerRepository(ctrl),
+		teamRepo:   team_repository.NewMockTeamRepository(ctrl),
+		playerSvc:  player_service.NewMockPlayerService(ctrl),
+	}
+	resolver(m)
+
+	authz := auth_service.NewMockAuthorizer(ctrl)
+	authz.EXPECT().Authorize(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	published := &[]event_model.Event{}
+	bus := event_service.NewMockEventBus(ctrl)
+	bus.EXPECT().Publish(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, events ...event_model.Event) error {
+		*published = append(*published, events...)
+		return nil
+	}).AnyTimes()
+
+	db, dbMock, _ := sqlmock.New()
+	if tx {
+		dbMock.ExpectBegin()
+		if rollback {
+			dbMock.ExpectRollback()
+		} else {
+			dbMock.ExpectCommit()
+		}
+	}
+
+	svc := &service.OfferServiceImpl{
+		Db:            db,
+		Config:        &config.Config{OfferTTL: time.Hour, OfferMaxTTL: 24 * time.Hour},
+		Repo:          m.repo,
+		PlayerRepo:    m.playerRepo,
+		TeamRepo:      m.teamRepo,
+		PlayerService: m.playerSvc,
+		EventBus:      bus,
+		Authz:         authz,
+	}
+
+	return svc, published, func() {
+		assert.Nil(t, dbMock.ExpectationsWereMet())
+		db.Close()
+		ctrl.Finish()
+	}
+}
+
+func eventTypes(events []event_model.Event) []string {
+	res := []string{}
+	for _, event := range events {
+		res = append(res, event.Type)
+	}
+
+	return res
+}
+
+func Test_NewOfferService(t *testing.T) {
+	svc := service.NewOfferService(service.OfferServiceImpl{})
+
+	assert.Implements(t, (*service.OfferService)(nil), svc)
+}
+
+func Test_FindByTeamID(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Status    string
+		Resolver  resolverFn
+		Expect    []model.OfferModel
+		ExpectErr error
+	}{
+		{
+			Name:   "when_success",
+			Status: model.STATUS_PENDING,
+			Resolver: func(m mocks) {
+				m.repo.EXPECT().FindByTeamID(gomock.Any(), int64(3), model.STATUS_PENDING).
+					Return([]model.OfferModel{pending()}, nil)
+			},
+			Expect: []model.OfferModel{pending()},
+		},
+		{
+			Name:      "when_status_is_unknown",
+			Status:    "some-status",
+			Resolver:  func(m mocks) {},
+			Expect:    []model.OfferModel{},
+			ExpectErr: command_model.ValidationError{Err: errors.New("unknown status \"some-status\"")},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			svc, _, finish := createService(t, test.Resolver, false, false)
+			defer finish()
+
+			actual, err := svc.FindByTeamID(context.Background(), 3, test.Status)
+
+			assert.Equal(t, test.ExpectErr, err)
+			assert.Equal(t, test.Expect, actual)
+		})
+	}
+}
+
+func Test_Make(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Param         service.MakeOfferPayload
+		Resolver      resolverFn
+		Tx            bool
+		Rollback      bool
+		Expect        model.OfferModel
+		ExpectErr     error
+		ExpectPublish []string
+	}{
+		{
+			Name:  "when_success",
+			Param: service.MakeOfferPayload{PlayerID: 2, ToTeamID: 4, Fee: 100, ExpiresAt: expiresAt},
+			Resolver: func(m mocks) {
+				m.playerRepo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(2)).
+					Return(player_model.PlayerModel{ID: 2, TeamID: 3}, nil)
+				m.teamRepo.EXPECT().FindByID(gomock.Any(), int64(4)).
+					Return(team_model.TeamModel{ID: 4}, nil)
+				m.repo.EXPECT().FindPending(gomock.Any(), int64(2), int64(4)).Return([]model.OfferModel{}, nil)
+				offer := pending()
+				offer.ID = 0
+				m.repo.EXPECT().Insert(gomock.Any(), offer).Return(pending(), nil)
+			},
+			Tx:            true,
+			Expect:        pending(),
+			ExpectPublish: []string{model.OFFER_MADE_EVENT},
+		},
+		{
+			Name:  "when_player_not_found",
+			Param: service.MakeOfferPayload{PlayerID: 2, ToTeamID: 4},
+			Resolver: func(m mocks) {
+				m.playerRepo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(2)).
+					Return(player_model.PlayerModel{}, sql.ErrNoRows)
+			},
+			Tx:        true,
+			Rollback:  true,
+			ExpectErr: command_model.ValidationError{Err: errors.New("player 2 not found")},
+		},
+		{
+			Name:  "when_player_already_in_team",
+			Param: service.MakeOfferPayload{PlayerID: 2, ToTeamID: 3},
+			Resolver: func(m mocks) {
+				m.playerRepo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(2)).
+					Return(player_model.PlayerModel{ID: 2, TeamID: 3}, nil)
+			},
+			Tx:        true,
+			Rollback:  true,
+			ExpectErr: command_model.ValidationError{Err: errors.New("player 2 already plays for team 3")},
+		},
+		{
+			Name:  "when_team_not_found",
+			Param: service.MakeOfferPayload{PlayerID: 2, ToTeamID: 4},
+			Resolver: func(m mocks) {
+				m.playerRepo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(2)).
+					Return(player_model.PlayerModel{ID: 2, TeamID: 3}, nil)
+				m.teamRepo.EXPECT().FindByID(gomock.Any(), int64(4)).
+					Return(team_model.TeamModel{}, sql.ErrNoRows)
+			},
+			Tx:        true,
+			Rollback:  true,
+			ExpectErr: command_model.ValidationError{Err: errors.New("team 4 not found")},
+		},
+		{
+			Name:  "when_offer_already_pending",
+			Param: service.MakeOfferPayload{PlayerID: 2, ToTeamID: 4, Fee: 200},
+			Resolver: func(m mocks) {
+				m.playerRepo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(2)).
+					Return(player_model.PlayerModel{ID: 2, TeamID: 3}, nil)
+				m.teamRepo.EXPECT().FindByID(gomock.Any(), int64(4)).
+					Return(team_model.TeamModel{ID: 4}, nil)
+				m.repo.EXPECT().FindPending(gomock.Any(), int64(2), int64(4)).Return([]model.OfferModel{pending()}, nil)
+			},
+			Tx:        true,
+			Rollback:  true,
+			ExpectErr: command_model.ValidationError{Err: errors.New("team 4 already has offer 1 pending for player 2")},
+		},
+		{
+			Name:      "when_deadline_passed",
+			Param:     service.MakeOfferPayload{PlayerID: 2, ToTeamID: 4, ExpiresAt: time.Now().Add(-time.Minute)},
+			Resolver:  func(m mocks) {},
+			ExpectErr: command_model.ValidationError{Err: errors.New("expiresAt must be in the future")},
+		},
+		{
+			Name:      "when_deadline_too_far",
+			Param:     service.MakeOfferPayload{PlayerID: 2, ToTeamID: 4, ExpiresAt: time.Now().Add(25 * time.Hour)},
+			Resolver:  func(m mocks) {},
+			ExpectErr: command_model.ValidationError{Err: errors.New("expiresAt must be within 24h0m0s")},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			svc, published, finish := createService(t, test.Resolver, test.Tx, test.Rollback)
+			defer finish()
+
+			actual, err := svc.Make(context.Background(), test.Param)
+
+			assert.Equal(t, test.ExpectErr, err)
+			assert.Equal(t, test.Expect, actual)
+			if test.ExpectPublish != nil {
+				assert.Equal(t, test.ExpectPublish, eventTypes(*published))
+				assert.Equal(t, model.OfferStreamID(1), (*published)[0].StreamID)
+			}
+		})
+	}
+}
+
+func Test_Make_DefaultDeadline(t *testing.T) {
+	svc, _, finish := createService(t, func(m mocks) {
+		m.playerRepo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(2)).
+			Return(player_model.PlayerModel{ID: 2, TeamID: 3}, nil)
+		m.teamRepo.EXPECT().FindByID(gomock.Any(), int64(4)).
+			Return(team_model.TeamModel{ID: 4}, nil)
+		m.repo.EXPECT().FindPending(gomock.Any(), int64(2), int64(4)).Return([]model.OfferModel{}, nil)
+	}, true, false)
+	defer finish()
+
+	before := time.Now()
+	actual, err := svc.Make(command_model.WithDryRun(context.Background()), service.MakeOfferPayload{PlayerID: 2, ToTeamID: 4})
+
+	assert.Nil(t, err)
+	assert.Equal(t, model.STATUS_PENDING, actual.Status)
+	assert.WithinDuration(t, before.Add(time.Hour), actual.ExpiresAt, time.Second)
+}
+
+func Test_Accept(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		DryRun        bool
+		Resolver      resolverFn
+		Tx            bool
+		Rollback      bool
+		Expect        model.OfferModel
+		ExpectErr     error
+		ExpectPublish []string
+	}{
+		{
+			Name: "when_success",
+			Resolver: func(m mocks) {
+				m.repo.EXPECT().FindByID(gomock.Any(), int64(1)).Return(pending(), nil)
+				m.playerRepo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(2)).
+					Return(player_model.PlayerModel{ID: 2, TeamID: 3}, nil)
+				m.repo.EXPECT().Transition(gomock.Any(), int64(1), model.STATUS_PENDING, model.STATUS_ACCEPTED).
+					Return(with(pending(), model.STATUS_ACCEPTED), true, nil)
+				m.playerSvc.EXPECT().TransferBatch(gomock.Any(), []player_service.TransferPayload{{PlayerID: 2, TeamID: 4}}).
+					DoAndReturn(func(ctx context.Context, transfers []player_service.TransferPayload) (player_model.TransferBatchModel, error) {
+						// the transfer was agreed on, it runs as the process
+						claims, ok := auth_model.ClaimsFromContext(ctx)
+						assert.True(t, ok)
+						assert.Equal(t, model.TRANSFER_PROCESS, claims.Process)
+						assert.Equal(t, "some-user", claims.OnBehalfOf)
+
+						return player_model.TransferBatchModel{}, nil
+					})
+			},
+			Tx:            true,
+			Expect:        with(pending(), model.STATUS_ACCEPTED),
+			ExpectPublish: []string{model.OFFER_ACCEPTED_EVENT},
+		},
+		{
+			Name:   "when_dry_run",
+			DryRun: true,
+			Resolver: func(m mocks) {
+				m.repo.EXPECT().FindByID(gomock.Any(), int64(1)).Return(pending(), nil)
+				m.playerRepo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(2)).
+					Return(player_model.PlayerModel{ID: 2, TeamID: 3}, nil)
+			},
+			Tx:            true,
+			Expect:        with(pending(), model.STATUS_ACCEPTED),
+			ExpectPublish: []string{},
+		},
+		{
+			Name: "when_not_pending",
+			Resolver: func(m mocks) {
+				m.repo.EXPECT().FindByID(gomock.Any(), int64(1)).Return(with(pending(), model.STATUS_REJECTED), nil)
+			},
+			ExpectErr: command_model.ValidationError{Err: errors.New("offer 1 is rejected")},
+		},
+		{
+			Name: "when_deadline_passed",
+			Resolver: func(m mocks) {
+				offer := pending()
+				offer.ExpiresAt = time.Now().Add(-time.Minute)
+				m.repo.EXPECT().FindByID(gomock.Any(), int64(1)).Return(offer, nil)
+			},
+			ExpectErr: command_model.ValidationError{Err: errors.New("offer 1 is expired")},
+		},
+		{
+			Name: "when_player_left_team",
+			Resolver: func(m mocks) {
+				m.repo.EXPECT().FindByID(gomock.Any(), int64(1)).Return(pending(), nil)
+				m.playerRepo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(2)).
+					Return(player_model.PlayerModel{ID: 2, TeamID: 5}, nil)
+			},
+			Tx:        true,
+			Rollback:  true,
+			ExpectErr: command_model.ValidationError{Err: errors.New("player 2 doesn't play for team 3 anymore")},
+		},
+		{
+			Name: "when_answered_concurrently",
+			Resolver: func(m mocks) {
+				m.repo.EXPECT().FindByID(gomock.Any(), int64(1)).Return(pending(), nil)
+				m.playerRepo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(2)).
+					Return(player_model.PlayerModel{ID: 2, TeamID: 3}, nil)
+				m.repo.EXPECT().Transition(gomock.Any(), int64(1), model.STATUS_PENDING, model.STATUS_ACCEPTED).
+					Return(model.OfferModel{}, false, nil)
+			},
+			Tx:        true,
+			Rollback:  true,
+			ExpectErr: command_model.ValidationError{Err: errors.New("offer 1 isn't pending anymore")},
+		},
+		{
+			Name: "when_transfer_fails",
+			Resolver: func(m mocks) {
+				m.repo.EXPECT().FindByID(gomock.Any(), int64(1)).Return(pending(), nil)
+				m.playerRepo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(2)).
+					Return(player_model.PlayerModel{ID: 2, TeamID: 3}, nil)
+				m.repo.EXPECT().Transition(gomock.Any(), int64(1), model.STATUS_PENDING, model.STATUS_ACCEPTED).
+					Return(with(pending(), model.STATUS_ACCEPTED), true, nil)
+				m.playerSvc.EXPECT().TransferBatch(gomock.Any(), gomock.Any()).
+					Return(player_model.TransferBatchModel{}, errors.New("some-error"))
+			},
+			Tx:            true,
+			Rollback:      true,
+			ExpectErr:     errors.New("some-error"),
+			ExpectPublish: []string{},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			svc, published, finish := createService(t, test.Resolver, test.Tx, test.Rollback)
+			defer finish()
+
+			ctx := auth_model.WithClaims(context.Background(), auth_model.Claims{
+				RegisteredClaims: jwt.RegisteredClaims{Subject: "some-user"},
+				TeamIDs:          []int64{3},
+			})
+			if test.DryRun {
+				ctx = command_model.WithDryRun(ctx)
+			}
+
+			actual, err := svc.Accept(ctx, 1)
+
+			assert.Equal(t, test.ExpectErr, err)
+			assert.Equal(t, test.Expect, actual)
+			if test.ExpectPublish != nil {
+				assert.Equal(t, test.ExpectPublish, eventTypes(*published))
+			}
+		})
+	}
+}
+
+func Test_Reject(t *testing.T) {
+	svc, published, finish := createService(t, func(m mocks) {
+		m.repo.EXPECT().FindByID(gomock.Any(), int64(1)).Return(pending(), nil)
+		m.repo.EXPECT().Transition(gomock.Any(), int64(1), model.STATUS_PENDING, model.STATUS_REJECTED).
+			Return(with(pending(), model.STATUS_REJECTED), true, nil)
+	}, true, false)
+	defer finish()
+
+	actual, err := svc.Reject(context.Background(), 1)
+
+	assert.Nil(t, err)
+	assert.Equal(t, with(pending(), model.STATUS_REJECTED), actual)
+	assert.Equal(t, []string{model.OFFER_REJECTED_EVENT}, eventTypes(*published))
+}
+
+func Test_Counter(t *testing.T) {
+	counter := pending()
+	counter.ID = 0
+	counter.ParentID = 1
+	counter.ProposedByTeamID = 3
+	counter.Fee = 150
+
+	svc, published, finish := createService(t, func(m mocks) {
+		m.repo.EXPECT().FindByID(gomock.Any(), int64(1)).Return(pending(), nil)
+		m.repo.EXPECT().Transition(gomock.Any(), int64(1), model.STATUS_PENDING, model.STATUS_COUNTERED).
+			Return(with(pending(), model.STATUS_COUNTERED), true, nil)
+
+		inserted := counter
+		inserted.ID = 5
+		m.repo.EXPECT().Insert(gomock.Any(), counter).Return(inserted, nil)
+	}, true, false)
+	defer finish()
+
+	actual, err := svc.Counter(context.Background(), 1, service.CounterOfferPayload{Fee: 150, ExpiresAt: expiresAt})
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), actual.ID)
+	assert.Equal(t, int64(4), actual.RespondingTeamID())
+	assert.Equal(t, []string{model.OFFER_COUNTERED_EVENT, model.OFFER_MADE_EVENT}, eventTypes(*published))
+	assert.Equal(t, model.OfferStreamID(1), (*published)[0].StreamID)
+	assert.Equal(t, model.OfferStreamID(5), (*published)[1].StreamID)
+}
+
+func Test_Expire(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Resolver      resolverFn
+		Tx            bool
+		Expect        int64
+		ExpectPublish []string
+	}{
+		{
+			Name: "when_expired",
+			Resolver: func(m mocks) {
+				offer := with(pending(), model.STATUS_EXPIRED)
+				other := offer
+				other.ID = 2
+				m.repo.EXPECT().ExpirePending(gomock.Any()).Return([]model.OfferModel{offer, other}, nil)
+			},
+			Tx:            true,
+			Expect:        2,
+			ExpectPublish: []string{model.OFFER_EXPIRED_EVENT, model.OFFER_EXPIRED_EVENT},
+		},
+		{
+			Name: "when_none_expired",
+			Resolver: func(m mocks) {
+				m.repo.EXPECT().ExpirePending(gomock.Any()).Return([]model.OfferModel{}, nil)
+			},
+			Tx:            true,
+			ExpectPublish: []string{},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			svc, published, finish := createService(t, test.Resolver, test.Tx, false)
+			defer finish()
+
+			actual, err := svc.Expire(context.Background())
+
+			assert.Nil(t, err)
+			assert.Equal(t, test.Expect, actual)
+			assert.Equal(t, test.ExpectPublish, eventTypes(*published))
+		})
+	}
+}
+
+func Test_Authorize(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := repository.NewMockOfferRepository(ctrl)
+	repo.EXPECT().FindByID(gomock.Any(), int64(1)).Return(pending(), nil)
+
+	forbidden := auth_model.ForbiddenError{Subject: "some-subject", Action: auth_model.ACTION_OFFER_RESPOND}
+	authz := auth_service.NewMockAuthorizer(ctrl)
+	// the selling team responds to the offer of the buying team
+	authz.EXPECT().Authorize(gomock.Any(), auth_model.ACTION_OFFER_RESPOND, auth_model.Resource{TeamID: 3}).Return(forbidden)
+
+	svc := service.NewOfferService(service.OfferServiceImpl{
+		Repo:  repo,
+		Authz: authz,
+	})
+
+	_, err := svc.Accept(context.Background(), 1)
+
+	assert.Equal(t, forbidden, err)
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+
+	"github.com/tesarwijaya/ouroboros/internal/config"
+	"github.com/tesarwijaya/ouroboros/internal/logger"
+	"github.com/tesarwijaya/ouroboros/internal/worker"
+	"go.uber.org/zap"
+)
+
+// NewExpiryWorker periodically expires the pending offers past their
+// deadline.
+func NewExpiryWorker(cfg *config.Config, svc OfferService) worker.Worker {
+	return worker.Worker{
+		Name:     "offer_expiry",
+		Interval: cfg.OfferExpiryInterval,
+		Run: func(ctx context.Context) error {
+			expired, err := svc.Expire(ctx)
+			if err != nil {
+				return err
+			}
+
+			if expired > 0 {
+				logger.FromContext(ctx).Info("expired transfer offers", zap.Int64("count", expired))
+			}
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,141 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+
+	event_model "github.com/tesarwijaya/ouroboros/internal/domain/event/model"
+)
+
+const (
+	PLAYER_STREAM_PREFIX = "player-"
+
+	PLAYER_TRANSFER_OUT_EVENT = "player_transfer_out"
+	PLAYER_TRANSFER_IN_EVENT  = "player_transfer_in"
+	PLAYER_UPDATED_EVENT      = "player_updated"
+)
+
+type TransferEventData struct {
+	PlayerID int64
+	TeamID   int64
+}
+
+// UpdatedEventData lists the JSON fields of the player a patch changed, along
+// the player once updated.
+type UpdatedEventData struct {
+	PlayerID int64
+	Fields   []string
+	Player   PlayerModel
+}
+
+// PlayerAggregate is the player state rebuilt from the player event stream,
+// which is what snapshots are taken of. The stream starts with the first
+// transfer or update of the player, the name of a player that was never
+// updated is therefore left empty. Commands read the player table instead,
+// which the events are written along.
+type PlayerAggregate struct {
+	PlayerModel
+}
+
+func NewPlayerAggregate(id int64) *PlayerAggregate {
+	return &PlayerAggregate{
+		PlayerModel: PlayerModel{ID: id},
+	}
+}
+
+func PlayerStreamID(id int64) string {
+	return fmt.Sprintf("%s%d", PLAYER_STREAM_PREFIX, id)
+}
+
+func (a *PlayerAggregate) StreamID() string {
+	return PlayerStreamID(a.ID)
+}
+
+func (a *PlayerAggregate) Apply(event event_model.Event) error {
+	switch event.Type {
+	case PLAYER_TRANSFER_IN_EVENT:
+		var data TransferEventData
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return err
+		}
+
+		a.TeamID = data.TeamID
+	case PLAYER_TRANSFER_OUT_EVENT:
+		a.TeamID = 0
+	case PLAYER_UPDATED_EVENT:
+		var data UpdatedEventData
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return err
+		}
+
+		a.Name = data.Player.Name
+		a.TeamID = data.Player.TeamID
+	}
+
+	return nil
+}
+
+// Transfers pairs the transfer out and in events of a player stream into the
+// transfers of the player, in the order they were appended.
+func Transfers(events []event_model.Event) ([]TransferModel, error) {
+	res := []TransferModel{}
+
+	reader := NewTransferReader()
+	for _, event := range events {
+		transfer, ok, err := reader.Read(event)
+		if err != nil {
+			return []TransferModel{}, err
+		}
+
+		if ok {
+			res = append(res, transfer)
+		}
+	}
+
+	return res, nil
+}
+
+// TransferReader pairs the transfer out and in events of player streams as
+// they are read, the events of several streams can be interleaved. Only the
+// streams in the middle of a transfer are kept track of.
+type TransferReader struct {
+	from map[string]int64
+}
+
+func NewTransferReader() *TransferReader {
+	return &TransferReader{
+		from: map[string]int64{},
+	}
+}
+
+// Read returns the transfer completed by event, ok is false when event
+// doesn't complete one.
+func (r *TransferReader) Read(event event_model.Event) (TransferModel, bool, error) {
+	switch event.Type {
+	case PLAYER_TRANSFER_OUT_EVENT:
+		var data TransferEventData
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return TransferModel{}, false, err
+		}
+
+		r.from[event.StreamID] = data.TeamID
+	case PLAYER_TRANSFER_IN_EVENT:
+		var data TransferEventData
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return TransferModel{}, false, err
+		}
+
+		from := r.from[event.StreamID]
+		delete(r.from, event.StreamID)
+
+		return TransferModel{
+			PlayerID:      data.PlayerID,
+			FromTeamID:    from,
+			ToTeamID:      data.TeamID,
+			TransferredAt: event.CreatedAt,
+			CorrelationID: event.CorrelationID(),
+		}, true, nil
+	}
+
+	return TransferModel{}, false, nil
+}
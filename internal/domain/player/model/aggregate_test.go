@@ -0,0 +1,72 @@
+package model_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	event_model "github.com/tesarwijaya/ouroboros/internal/domain/event/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/player/model"
+)
+
+func Test_PlayerAggregateApply(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Events    []event_model.Event
+		Expect    model.PlayerModel
+		ExpectErr error
+	}{
+		{
+			Name: "when_transferred",
+			Events: []event_model.Event{
+				{Type: model.PLAYER_TRANSFER_OUT_EVENT, Data: []byte(`{"PlayerID":1,"TeamID":2}`)},
+				{Type: model.PLAYER_TRANSFER_IN_EVENT, Data: []byte(`{"PlayerID":1,"TeamID":3}`)},
+			},
+			Expect: model.PlayerModel{ID: 1, TeamID: 3},
+		},
+		{
+			Name: "when_updated",
+			Events: []event_model.Event{
+				{Type: model.PLAYER_UPDATED_EVENT, Data: []byte(`{"PlayerID":1,"Fields":["name"],"Player":{"id":1,"name":"jane","teamId":2}}`)},
+				{Type: model.PLAYER_TRANSFER_OUT_EVENT, Data: []byte(`{"PlayerID":1,"TeamID":2}`)},
+				{Type: model.PLAYER_TRANSFER_IN_EVENT, Data: []byte(`{"PlayerID":1,"TeamID":3}`)},
+			},
+			Expect: model.PlayerModel{ID: 1, Name: "jane", TeamID: 3},
+		},
+		{
+			Name: "when_event_unknown",
+			Events: []event_model.Event{
+				{Type: "some-event", Data: []byte(`{}`)},
+			},
+			Expect: model.PlayerModel{ID: 1},
+		},
+		{
+			Name: "when_event_malformed",
+			Events: []event_model.Event{
+				{Type: model.PLAYER_UPDATED_EVENT, Data: []byte(`{`)},
+			},
+			ExpectErr: errors.New("unexpected end of JSON input"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			aggregate := model.NewPlayerAggregate(1)
+
+			var err error
+			for _, event := range test.Events {
+				if err = aggregate.Apply(event); err != nil {
+					break
+				}
+			}
+
+			if test.ExpectErr != nil {
+				assert.EqualError(t, err, test.ExpectErr.Error())
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, test.Expect, aggregate.PlayerModel)
+		})
+	}
+}
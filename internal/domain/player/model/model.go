@@ -1,7 +1,64 @@
 package model
 
+import "time"
+
 type PlayerModel struct {
 	ID     int64  `db:"id" json:"id"`
 	Name   string `db:"name" json:"name,omitempty"`
 	TeamID int64  `db:"team_id" json:"teamId,omitempty"`
 }
+
+// PLAYER_COLUMNS maps the JSON fields of a player to their column.
+var PLAYER_COLUMNS = map[string]string{
+	"id":     "id",
+	"name":   "name",
+	"teamId": "team_id",
+}
+
+// PlayerLookupRespModel lists the players found by id, with the requested
+// fields only, and the ids no player has.
+type PlayerLookupRespModel struct {
+	Items   []map[string]interface{} `json:"items"`
+	Missing []int64                  `json:"missing"`
+}
+
+// Sparse returns the given JSON fields of the player only, every field when
+// fields is empty.
+func (p PlayerModel) Sparse(fields []string) map[string]interface{} {
+	values := map[string]interface{}{
+		"id":     p.ID,
+		"name":   p.Name,
+		"teamId": p.TeamID,
+	}
+	if len(fields) == 0 {
+		return values
+	}
+
+	res := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		res[field] = values[field]
+	}
+
+	return res
+}
+
+// TransferModel is a transfer read back from the player event stream,
+// FromTeamID is 0 when the player had no team before it. Transfers applied
+// together share their CorrelationID.
+type TransferModel struct {
+	PlayerID      int64     `json:"playerId"`
+	FromTeamID    int64     `json:"fromTeamId,omitempty"`
+	ToTeamID      int64     `json:"toTeamId"`
+	TransferredAt time.Time `json:"transferredAt"`
+	CorrelationID string    `json:"correlationId,omitempty"`
+}
+
+// MAX_BATCH_TRANSFERS bounds the transfers applied together.
+const MAX_BATCH_TRANSFERS = 100
+
+// TransferBatchModel lists the transfers applied together, their events
+// share CorrelationID.
+type TransferBatchModel struct {
+	CorrelationID string          `json:"correlationId"`
+	Transfers     []TransferModel `json:"transfers"`
+}
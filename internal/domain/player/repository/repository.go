@@ -3,9 +3,13 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"sort"
 
 	"github.com/huandu/go-sqlbuilder"
+	"github.com/tesarwijaya/ouroboros/internal/database"
 	"github.com/tesarwijaya/ouroboros/internal/domain/player/model"
+	"github.com/tesarwijaya/ouroboros/internal/metrics"
 	"go.uber.org/dig"
 )
 
@@ -16,8 +20,15 @@ const (
 type PlayerRepository interface {
 	FindAll(ctx context.Context) ([]model.PlayerModel, error)
 	FindByID(ctx context.Context, id int64) (model.PlayerModel, error)
+	FindByIDForUpdate(ctx context.Context, id int64) (model.PlayerModel, error)
+	FindByIDs(ctx context.Context, ids []int64) ([]model.PlayerModel, error)
 	FindByTeamID(ctx context.Context, teamID int64) ([]model.PlayerModel, error)
-	Insert(ctx context.Context, payload model.PlayerModel) error
+	FindByTeamIDs(ctx context.Context, teamIDs []int64) ([]model.PlayerModel, error)
+	StreamAll(ctx context.Context, teamID int64, fn func(model.PlayerModel) error) error
+	FindFields(ctx context.Context, ids []int64, columns []string) ([]model.PlayerModel, error)
+	Insert(ctx context.Context, payload model.PlayerModel) (model.PlayerModel, error)
+	MoveTeam(ctx context.Context, id int64, fromTeamID int64, toTeamID int64) (bool, error)
+	Update(ctx context.Context, id int64, values map[string]interface{}) (model.PlayerModel, error)
 }
 
 type PlayerRepositoryImpl struct {
@@ -30,11 +41,13 @@ func NewPlayerReposity(repo PlayerRepositoryImpl) PlayerRepository {
 }
 
 func (r *PlayerRepositoryImpl) FindAll(ctx context.Context) ([]model.PlayerModel, error) {
+	defer metrics.ObserveQuery("player", "FindAll")()
+
 	var res []model.PlayerModel
 	q := sqlbuilder.NewSelectBuilder()
 	query, _ := q.Select("*").From(PLAYER_TABLE_NAME).Build()
 
-	rows, err := r.Db.Query(query)
+	rows, err := database.Query(ctx, r.Db, query)
 	if err != nil {
 		return []model.PlayerModel{}, err
 	}
@@ -63,11 +76,30 @@ func (r *PlayerRepositoryImpl) FindAll(ctx context.Context) ([]model.PlayerModel
 }
 
 func (r *PlayerRepositoryImpl) FindByID(ctx context.Context, id int64) (model.PlayerModel, error) {
-	var res model.PlayerModel
+	defer metrics.ObserveQuery("player", "FindByID")()
+
 	q := sqlbuilder.NewSelectBuilder()
-	query, args := q.Select("*").From(PLAYER_TABLE_NAME).Where(q.Equal("id", id)).BuildWithFlavor(sqlbuilder.PostgreSQL)
+	q.Select("*").From(PLAYER_TABLE_NAME).Where(q.Equal("id", id))
+
+	return r.findOne(ctx, q)
+}
+
+// FindByIDForUpdate reads the player as FindByID does and locks its row until
+// the transaction ctx is in ends.
+func (r *PlayerRepositoryImpl) FindByIDForUpdate(ctx context.Context, id int64) (model.PlayerModel, error) {
+	defer metrics.ObserveQuery("player", "FindByIDForUpdate")()
 
-	row := r.Db.QueryRow(query, args...)
+	q := sqlbuilder.NewSelectBuilder()
+	q.Select("*").From(PLAYER_TABLE_NAME).Where(q.Equal("id", id)).ForUpdate()
+
+	return r.findOne(ctx, q)
+}
+
+func (r *PlayerRepositoryImpl) findOne(ctx context.Context, q *sqlbuilder.SelectBuilder) (model.PlayerModel, error) {
+	var res model.PlayerModel
+	query, args := q.BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	row := database.QueryRow(ctx, r.Db, query, args...)
 	if err := row.Err(); err != nil {
 		return model.PlayerModel{}, err
 	}
@@ -83,12 +115,91 @@ func (r *PlayerRepositoryImpl) FindByID(ctx context.Context, id int64) (model.Pl
 	return res, nil
 }
 
+// FindByIDs returns the players matching ids in a single query, unknown ids
+// are left out of the result.
+func (r *PlayerRepositoryImpl) FindByIDs(ctx context.Context, ids []int64) ([]model.PlayerModel, error) {
+	defer metrics.ObserveQuery("player", "FindByIDs")()
+
+	res := []model.PlayerModel{}
+	if len(ids) == 0 {
+		return res, nil
+	}
+
+	q := sqlbuilder.NewSelectBuilder()
+	query, args := q.Select("*").From(PLAYER_TABLE_NAME).Where(q.In("id", sqlbuilder.Flatten(ids)...)).BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	rows, err := database.Query(ctx, r.Db, query, args...)
+	if err != nil {
+		return []model.PlayerModel{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item model.PlayerModel
+		if err := rows.Scan(
+			&item.ID,
+			&item.Name,
+			&item.TeamID,
+		); err != nil {
+			return []model.PlayerModel{}, err
+		}
+
+		res = append(res, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return []model.PlayerModel{}, err
+	}
+
+	return res, nil
+}
+
 func (r *PlayerRepositoryImpl) FindByTeamID(ctx context.Context, teamID int64) ([]model.PlayerModel, error) {
+	defer metrics.ObserveQuery("player", "FindByTeamID")()
+
 	var res []model.PlayerModel
 	q := sqlbuilder.NewSelectBuilder()
 	query, args := q.Select("*").From(PLAYER_TABLE_NAME).Where(q.Equal("team_id", teamID)).BuildWithFlavor(sqlbuilder.PostgreSQL)
 
-	rows, err := r.Db.Query(query, args...)
+	rows, err := database.Query(ctx, r.Db, query, args...)
+	if err != nil {
+		return []model.PlayerModel{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item model.PlayerModel
+		if err := rows.Scan(
+			&item.ID,
+			&item.Name,
+			&item.TeamID,
+		); err != nil {
+			return []model.PlayerModel{}, err
+		}
+
+		res = append(res, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return []model.PlayerModel{}, err
+	}
+
+	return res, nil
+}
+
+// FindByTeamIDs returns the players of every given team in a single query.
+func (r *PlayerRepositoryImpl) FindByTeamIDs(ctx context.Context, teamIDs []int64) ([]model.PlayerModel, error) {
+	defer metrics.ObserveQuery("player", "FindByTeamIDs")()
+
+	res := []model.PlayerModel{}
+	if len(teamIDs) == 0 {
+		return res, nil
+	}
+
+	q := sqlbuilder.NewSelectBuilder()
+	query, args := q.Select("*").From(PLAYER_TABLE_NAME).Where(q.In("team_id", sqlbuilder.Flatten(teamIDs)...)).BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	rows, err := database.Query(ctx, r.Db, query, args...)
 	if err != nil {
 		return []model.PlayerModel{}, err
 	}
@@ -114,17 +225,173 @@ func (r *PlayerRepositoryImpl) FindByTeamID(ctx context.Context, teamID int64) (
 	return res, nil
 }
 
-func (r *PlayerRepositoryImpl) Insert(ctx context.Context, payload model.PlayerModel) error {
+// StreamAll calls fn with every player ordered by id, only with the players
+// of teamID unless it is 0, as they are read from the result set so that they
+// are never all held in memory. An error of fn stops the iteration and is
+// returned.
+func (r *PlayerRepositoryImpl) StreamAll(ctx context.Context, teamID int64, fn func(model.PlayerModel) error) error {
+	defer metrics.ObserveQuery("player", "StreamAll")()
+
+	q := sqlbuilder.NewSelectBuilder()
+	q.Select("*").From(PLAYER_TABLE_NAME).OrderBy("id")
+	if teamID != 0 {
+		q.Where(q.Equal("team_id", teamID))
+	}
+	query, args := q.BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	rows, err := database.Query(ctx, r.Db, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item model.PlayerModel
+		if err := rows.Scan(
+			&item.ID,
+			&item.Name,
+			&item.TeamID,
+		); err != nil {
+			return err
+		}
+
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// FindFields selects only columns of the players matching ids in a single
+// query, of every player when ids is empty, the other fields are left unset.
+func (r *PlayerRepositoryImpl) FindFields(ctx context.Context, ids []int64, columns []string) ([]model.PlayerModel, error) {
+	defer metrics.ObserveQuery("player", "FindFields")()
+
+	q := sqlbuilder.NewSelectBuilder()
+	q.Select(columns...).From(PLAYER_TABLE_NAME).OrderBy("id")
+	if len(ids) > 0 {
+		q.Where(q.In("id", sqlbuilder.Flatten(ids)...))
+	}
+	query, args := q.BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	rows, err := database.Query(ctx, r.Db, query, args...)
+	if err != nil {
+		return []model.PlayerModel{}, err
+	}
+	defer rows.Close()
+
+	res := []model.PlayerModel{}
+	for rows.Next() {
+		var item model.PlayerModel
+
+		dest := make([]interface{}, 0, len(columns))
+		for _, column := range columns {
+			switch column {
+			case "id":
+				dest = append(dest, &item.ID)
+			case "name":
+				dest = append(dest, &item.Name)
+			case "team_id":
+				dest = append(dest, &item.TeamID)
+			default:
+				return []model.PlayerModel{}, fmt.Errorf("unknown player column %s", column)
+			}
+		}
+
+		if err := rows.Scan(dest...); err != nil {
+			return []model.PlayerModel{}, err
+		}
+
+		res = append(res, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return []model.PlayerModel{}, err
+	}
+
+	return res, nil
+}
+
+func (r *PlayerRepositoryImpl) Insert(ctx context.Context, payload model.PlayerModel) (model.PlayerModel, error) {
+	defer metrics.ObserveQuery("player", "Insert")()
+
+	var res model.PlayerModel
 	q := sqlbuilder.NewInsertBuilder()
 	query, args := q.InsertInto(PLAYER_TABLE_NAME).
 		Cols("name", "team_id").
 		Values(payload.Name, payload.TeamID).
+		SQL("RETURNING id, name, team_id").
+		BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	if err := database.QueryRow(ctx, r.Db, query, args...).Scan(
+		&res.ID,
+		&res.Name,
+		&res.TeamID,
+	); err != nil {
+		return model.PlayerModel{}, err
+	}
+
+	return res, nil
+}
+
+// MoveTeam changes the team of the player from fromTeamID to toTeamID, it
+// reports false when the player doesn't play for fromTeamID anymore, e.g.
+// when it was transferred concurrently.
+func (r *PlayerRepositoryImpl) MoveTeam(ctx context.Context, id int64, fromTeamID int64, toTeamID int64) (bool, error) {
+	defer metrics.ObserveQuery("player", "MoveTeam")()
+
+	q := sqlbuilder.NewUpdateBuilder()
+	query, args := q.Update(PLAYER_TABLE_NAME).
+		Set(q.Assign("team_id", toTeamID)).
+		Where(q.Equal("id", id), q.Equal("team_id", fromTeamID)).
 		BuildWithFlavor(sqlbuilder.PostgreSQL)
 
-	_, err := r.Db.Exec(query, args...)
+	res, err := database.Exec(ctx, r.Db, query, args...)
 	if err != nil {
-		return err
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// Update sets only the given columns of the player, it returns sql.ErrNoRows
+// when the player doesn't exist.
+func (r *PlayerRepositoryImpl) Update(ctx context.Context, id int64, values map[string]interface{}) (model.PlayerModel, error) {
+	defer metrics.ObserveQuery("player", "Update")()
+
+	columns := make([]string, 0, len(values))
+	for column := range values {
+		if column != "name" && column != "team_id" {
+			return model.PlayerModel{}, fmt.Errorf("unknown player column %s", column)
+		}
+
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	q := sqlbuilder.NewUpdateBuilder()
+	q.Update(PLAYER_TABLE_NAME)
+	for _, column := range columns {
+		q.SetMore(q.Assign(column, values[column]))
+	}
+	query, args := q.Where(q.Equal("id", id)).
+		SQL("RETURNING id, name, team_id").
+		BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	var res model.PlayerModel
+	if err := database.QueryRow(ctx, r.Db, query, args...).Scan(
+		&res.ID,
+		&res.Name,
+		&res.TeamID,
+	); err != nil {
+		return model.PlayerModel{}, err
 	}
 
-	return nil
+	return res, nil
 }
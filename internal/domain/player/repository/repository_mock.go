@@ -65,6 +65,36 @@ func (mr *MockPlayerRepositoryMockRecorder) FindByID(ctx, id interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockPlayerRepository)(nil).FindByID), ctx, id)
 }
 
+// FindByIDForUpdate mocks base method.
+func (m *MockPlayerRepository) FindByIDForUpdate(ctx context.Context, id int64) (model.PlayerModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByIDForUpdate", ctx, id)
+	ret0, _ := ret[0].(model.PlayerModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByIDForUpdate indicates an expected call of FindByIDForUpdate.
+func (mr *MockPlayerRepositoryMockRecorder) FindByIDForUpdate(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByIDForUpdate", reflect.TypeOf((*MockPlayerRepository)(nil).FindByIDForUpdate), ctx, id)
+}
+
+// FindByIDs mocks base method.
+func (m *MockPlayerRepository) FindByIDs(ctx context.Context, ids []int64) ([]model.PlayerModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByIDs", ctx, ids)
+	ret0, _ := ret[0].([]model.PlayerModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByIDs indicates an expected call of FindByIDs.
+func (mr *MockPlayerRepositoryMockRecorder) FindByIDs(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByIDs", reflect.TypeOf((*MockPlayerRepository)(nil).FindByIDs), ctx, ids)
+}
+
 // FindByTeamID mocks base method.
 func (m *MockPlayerRepository) FindByTeamID(ctx context.Context, teamID int64) ([]model.PlayerModel, error) {
 	m.ctrl.T.Helper()
@@ -80,12 +110,43 @@ func (mr *MockPlayerRepositoryMockRecorder) FindByTeamID(ctx, teamID interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByTeamID", reflect.TypeOf((*MockPlayerRepository)(nil).FindByTeamID), ctx, teamID)
 }
 
+// FindByTeamIDs mocks base method.
+func (m *MockPlayerRepository) FindByTeamIDs(ctx context.Context, teamIDs []int64) ([]model.PlayerModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByTeamIDs", ctx, teamIDs)
+	ret0, _ := ret[0].([]model.PlayerModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByTeamIDs indicates an expected call of FindByTeamIDs.
+func (mr *MockPlayerRepositoryMockRecorder) FindByTeamIDs(ctx, teamIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByTeamIDs", reflect.TypeOf((*MockPlayerRepository)(nil).FindByTeamIDs), ctx, teamIDs)
+}
+
+// FindFields mocks base method.
+func (m *MockPlayerRepository) FindFields(ctx context.Context, ids []int64, columns []string) ([]model.PlayerModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFields", ctx, ids, columns)
+	ret0, _ := ret[0].([]model.PlayerModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindFields indicates an expected call of FindFields.
+func (mr *MockPlayerRepositoryMockRecorder) FindFields(ctx, ids, columns interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFields", reflect.TypeOf((*MockPlayerRepository)(nil).FindFields), ctx, ids, columns)
+}
+
 // Insert mocks base method.
-func (m *MockPlayerRepository) Insert(ctx context.Context, payload model.PlayerModel) error {
+func (m *MockPlayerRepository) Insert(ctx context.Context, payload model.PlayerModel) (model.PlayerModel, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Insert", ctx, payload)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(model.PlayerModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Insert indicates an expected call of Insert.
@@ -93,3 +154,47 @@ func (mr *MockPlayerRepositoryMockRecorder) Insert(ctx, payload interface{}) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockPlayerRepository)(nil).Insert), ctx, payload)
 }
+
+// MoveTeam mocks base method.
+func (m *MockPlayerRepository) MoveTeam(ctx context.Context, id, fromTeamID, toTeamID int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveTeam", ctx, id, fromTeamID, toTeamID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveTeam indicates an expected call of MoveTeam.
+func (mr *MockPlayerRepositoryMockRecorder) MoveTeam(ctx, id, fromTeamID, toTeamID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveTeam", reflect.TypeOf((*MockPlayerRepository)(nil).MoveTeam), ctx, id, fromTeamID, toTeamID)
+}
+
+// StreamAll mocks base method.
+func (m *MockPlayerRepository) StreamAll(ctx context.Context, teamID int64, fn func(model.PlayerModel) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamAll", ctx, teamID, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamAll indicates an expected call of StreamAll.
+func (mr *MockPlayerRepositoryMockRecorder) StreamAll(ctx, teamID, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamAll", reflect.TypeOf((*MockPlayerRepository)(nil).StreamAll), ctx, teamID, fn)
+}
+
+// Update mocks base method.
+func (m *MockPlayerRepository) Update(ctx context.Context, id int64, values map[string]interface{}) (model.PlayerModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, values)
+	ret0, _ := ret[0].(model.PlayerModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockPlayerRepositoryMockRecorder) Update(ctx, id, values interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPlayerRepository)(nil).Update), ctx, id, values)
+}
@@ -2,6 +2,8 @@ package repository_test
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"regexp"
 	"testing"
 
@@ -105,6 +107,66 @@ func Test_FindByID(t *testing.T) {
 	}
 }
 
+func Test_FindByIDForUpdate(t *testing.T) {
+	repo := createRepo(func(db sqlmock.Sqlmock) {
+		db.ExpectQuery(regexp.QuoteMeta("SELECT * FROM player WHERE id = $1 FOR UPDATE")).
+			WithArgs(int64(1)).
+			WillReturnRows(
+				sqlmock.NewRows([]string{"id", "name", "team_id"}).
+					AddRow(int64(1), "some-player-name", int64(1)),
+			)
+	})
+
+	actual, err := repo.FindByIDForUpdate(context.Background(), 1)
+
+	assert.Nil(t, err)
+	assert.Equal(t, model.PlayerModel{ID: 1, Name: "some-player-name", TeamID: 1}, actual)
+}
+
+func Test_FindByIDs(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Param     []int64
+		mockFn    mockFn
+		Expect    []model.PlayerModel
+		ExpectErr error
+	}{
+		{
+			Name:  "when success",
+			Param: []int64{1, 2},
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(regexp.QuoteMeta("SELECT * FROM player WHERE id IN ($1, $2)")).
+					WithArgs(int64(1), int64(2)).
+					WillReturnRows(
+						sqlmock.NewRows([]string{"id", "name", "team_id"}).
+							AddRow(1, "some-player-name", 1),
+					)
+			},
+			Expect: []model.PlayerModel{{ID: 1, Name: "some-player-name", TeamID: 1}},
+		},
+		{
+			Name:   "when ids empty",
+			Param:  []int64{},
+			mockFn: func(db sqlmock.Sqlmock) {},
+			Expect: []model.PlayerModel{},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := createRepo(test.mockFn)
+
+			actual, err := repo.FindByIDs(context.Background(), test.Param)
+			if test.ExpectErr == nil {
+				assert.Equal(t, test.Expect, actual)
+				assert.Nil(t, err)
+			}
+
+			assert.Equal(t, test.ExpectErr, err)
+		})
+	}
+}
+
 func Test_FindByTeamID(t *testing.T) {
 	testCases := []struct {
 		Name      string
@@ -147,6 +209,195 @@ func Test_FindByTeamID(t *testing.T) {
 	}
 }
 
+func Test_FindByTeamIDs(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Param     []int64
+		mockFn    mockFn
+		Expect    []model.PlayerModel
+		ExpectErr error
+	}{
+		{
+			Name:  "when success",
+			Param: []int64{1, 2},
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(regexp.QuoteMeta("SELECT * FROM player WHERE team_id IN ($1, $2)")).
+					WithArgs(int64(1), int64(2)).
+					WillReturnRows(
+						sqlmock.NewRows([]string{"id", "name", "team_id"}).
+							AddRow(1, "some-player-name", 1).
+							AddRow(2, "other-player-name", 2),
+					)
+			},
+			Expect: []model.PlayerModel{
+				{ID: 1, Name: "some-player-name", TeamID: 1},
+				{ID: 2, Name: "other-player-name", TeamID: 2},
+			},
+		},
+		{
+			Name:   "when team ids empty",
+			Param:  []int64{},
+			mockFn: func(db sqlmock.Sqlmock) {},
+			Expect: []model.PlayerModel{},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := createRepo(test.mockFn)
+
+			actual, err := repo.FindByTeamIDs(context.Background(), test.Param)
+			if test.ExpectErr == nil {
+				assert.Equal(t, test.Expect, actual)
+				assert.Nil(t, err)
+			}
+
+			assert.Equal(t, test.ExpectErr, err)
+		})
+	}
+}
+
+func Test_StreamAll(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		TeamID    int64
+		FnErr     error
+		mockFn    mockFn
+		Expect    []model.PlayerModel
+		ExpectErr error
+	}{
+		{
+			Name: "when success",
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(regexp.QuoteMeta("SELECT * FROM player ORDER BY id")).
+					WillReturnRows(
+						sqlmock.NewRows([]string{"id", "name", "team_id"}).
+							AddRow(1, "some-player-name", 1).
+							AddRow(2, "other-player-name", 2),
+					)
+			},
+			Expect: []model.PlayerModel{
+				{ID: 1, Name: "some-player-name", TeamID: 1},
+				{ID: 2, Name: "other-player-name", TeamID: 2},
+			},
+		},
+		{
+			Name:   "when team id given",
+			TeamID: 1,
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(regexp.QuoteMeta("SELECT * FROM player WHERE team_id = $1 ORDER BY id")).
+					WithArgs(int64(1)).
+					WillReturnRows(
+						sqlmock.NewRows([]string{"id", "name", "team_id"}).
+							AddRow(1, "some-player-name", 1),
+					)
+			},
+			Expect: []model.PlayerModel{
+				{ID: 1, Name: "some-player-name", TeamID: 1},
+			},
+		},
+		{
+			Name:  "when fn fails",
+			FnErr: errors.New("some-error"),
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(regexp.QuoteMeta("SELECT * FROM player ORDER BY id")).
+					WillReturnRows(
+						sqlmock.NewRows([]string{"id", "name", "team_id"}).
+							AddRow(1, "some-player-name", 1).
+							AddRow(2, "other-player-name", 2),
+					)
+			},
+			Expect:    []model.PlayerModel{{ID: 1, Name: "some-player-name", TeamID: 1}},
+			ExpectErr: errors.New("some-error"),
+		},
+		{
+			Name: "when query fails",
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(regexp.QuoteMeta("SELECT * FROM player ORDER BY id")).
+					WillReturnError(errors.New("some-error"))
+			},
+			Expect:    []model.PlayerModel{},
+			ExpectErr: errors.New("some-error"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := createRepo(test.mockFn)
+
+			actual := []model.PlayerModel{}
+			err := repo.StreamAll(context.Background(), test.TeamID, func(player model.PlayerModel) error {
+				actual = append(actual, player)
+				return test.FnErr
+			})
+
+			assert.Equal(t, test.Expect, actual)
+			assert.Equal(t, test.ExpectErr, err)
+		})
+	}
+}
+
+func Test_FindFields(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		IDs       []int64
+		Columns   []string
+		mockFn    mockFn
+		Expect    []model.PlayerModel
+		ExpectErr error
+	}{
+		{
+			Name:    "when ids given",
+			IDs:     []int64{1, 2},
+			Columns: []string{"id", "team_id", "name"},
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(regexp.QuoteMeta("SELECT id, team_id, name FROM player WHERE id IN ($1, $2) ORDER BY id")).
+					WithArgs(int64(1), int64(2)).
+					WillReturnRows(
+						sqlmock.NewRows([]string{"id", "team_id", "name"}).
+							AddRow(1, 3, "some-player-name"),
+					)
+			},
+			Expect: []model.PlayerModel{{ID: 1, Name: "some-player-name", TeamID: 3}},
+		},
+		{
+			Name:    "when every player",
+			Columns: []string{"id", "team_id"},
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(regexp.QuoteMeta("SELECT id, team_id FROM player ORDER BY id")).
+					WillReturnRows(
+						sqlmock.NewRows([]string{"id", "team_id"}).
+							AddRow(1, 3),
+					)
+			},
+			Expect: []model.PlayerModel{{ID: 1, TeamID: 3}},
+		},
+		{
+			Name:    "when query fails",
+			Columns: []string{"id"},
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(regexp.QuoteMeta("SELECT id FROM player ORDER BY id")).
+					WillReturnError(errors.New("some-error"))
+			},
+			ExpectErr: errors.New("some-error"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := createRepo(test.mockFn)
+
+			actual, err := repo.FindFields(context.Background(), test.IDs, test.Columns)
+			if test.ExpectErr == nil {
+				assert.Equal(t, test.Expect, actual)
+				assert.Nil(t, err)
+			}
+
+			assert.Equal(t, test.ExpectErr, err)
+		})
+	}
+}
+
 func Test_Insert(t *testing.T) {
 	testCases := []struct {
 		Name      string
@@ -162,9 +413,17 @@ func Test_Insert(t *testing.T) {
 				TeamID: 1,
 			},
 			mockFn: func(db sqlmock.Sqlmock) {
-				db.ExpectExec(regexp.QuoteMeta("INSERT INTO player (name, team_id) VALUES ($1, $2)")).
+				db.ExpectQuery(regexp.QuoteMeta("INSERT INTO player (name, team_id) VALUES ($1, $2) RETURNING id, name, team_id")).
 					WithArgs("some-player-name", int64(1)).
-					WillReturnResult(sqlmock.NewResult(1, 1))
+					WillReturnRows(
+						sqlmock.NewRows([]string{"id", "name", "team_id"}).
+							AddRow(int64(1), "some-player-name", int64(1)),
+					)
+			},
+			Expect: model.PlayerModel{
+				ID:     1,
+				Name:   "some-player-name",
+				TeamID: 1,
 			},
 		},
 	}
@@ -173,9 +432,121 @@ func Test_Insert(t *testing.T) {
 		t.Run(test.Name, func(t *testing.T) {
 			repo := createRepo(test.mockFn)
 
-			err := repo.Insert(context.Background(), test.Param)
+			actual, err := repo.Insert(context.Background(), test.Param)
 
+			assert.Equal(t, test.Expect, actual)
 			assert.Equal(t, test.ExpectErr, err)
 		})
 	}
 }
+
+func Test_MoveTeam(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		mockFn    mockFn
+		Expect    bool
+		ExpectErr error
+	}{
+		{
+			Name: "when_successful",
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectExec(regexp.QuoteMeta("UPDATE player SET team_id = $1 WHERE id = $2 AND team_id = $3")).
+					WithArgs(int64(3), int64(1), int64(2)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			Expect: true,
+		},
+		{
+			Name: "when_moved_concurrently",
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectExec(regexp.QuoteMeta("UPDATE player SET team_id = $1 WHERE id = $2 AND team_id = $3")).
+					WithArgs(int64(3), int64(1), int64(2)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			Name: "when_error",
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectExec(regexp.QuoteMeta("UPDATE player SET team_id = $1 WHERE id = $2 AND team_id = $3")).
+					WillReturnError(errors.New("some-error"))
+			},
+			ExpectErr: errors.New("some-error"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := createRepo(test.mockFn)
+
+			actual, err := repo.MoveTeam(context.Background(), 1, 2, 3)
+
+			assert.Equal(t, test.Expect, actual)
+			assert.Equal(t, test.ExpectErr, err)
+		})
+	}
+}
+
+func Test_Update(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		ID        int64
+		Values    map[string]interface{}
+		mockFn    mockFn
+		Expect    model.PlayerModel
+		ExpectErr error
+	}{
+		{
+			Name:   "when_successful",
+			ID:     1,
+			Values: map[string]interface{}{"name": "jane", "team_id": int64(2)},
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(regexp.QuoteMeta("UPDATE player SET name = $1, team_id = $2 WHERE id = $3 RETURNING id, name, team_id")).
+					WithArgs("jane", int64(2), int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "team_id"}).AddRow(int64(1), "jane", int64(2)))
+			},
+			Expect: model.PlayerModel{ID: 1, Name: "jane", TeamID: 2},
+		},
+		{
+			Name:   "when_only_name",
+			ID:     1,
+			Values: map[string]interface{}{"name": "jane"},
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(regexp.QuoteMeta("UPDATE player SET name = $1 WHERE id = $2 RETURNING id, name, team_id")).
+					WithArgs("jane", int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "team_id"}).AddRow(int64(1), "jane", int64(2)))
+			},
+			Expect: model.PlayerModel{ID: 1, Name: "jane", TeamID: 2},
+		},
+		{
+			Name:   "when_not_found",
+			ID:     1,
+			Values: map[string]interface{}{"name": "jane"},
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(regexp.QuoteMeta("UPDATE player SET name = $1 WHERE id = $2 RETURNING id, name, team_id")).
+					WithArgs("jane", int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "team_id"}))
+			},
+			ExpectErr: sql.ErrNoRows,
+		},
+		{
+			Name:      "when_column_is_unknown",
+			ID:        1,
+			Values:    map[string]interface{}{"id": int64(2)},
+			mockFn:    func(db sqlmock.Sqlmock) {},
+			ExpectErr: errors.New("unknown player column id"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := createRepo(test.mockFn)
+
+			actual, err := repo.Update(context.Background(), test.ID, test.Values)
+
+			assert.Equal(t, test.ExpectErr, err)
+			if test.ExpectErr == nil {
+				assert.Equal(t, test.Expect, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	command_model "github.com/tesarwijaya/ouroboros/internal/domain/command/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/player/model"
+	"github.com/tesarwijaya/ouroboros/internal/patch"
+	"go.uber.org/dig"
+)
+
+type (
+	InsertPlayerCommand struct {
+		Payload model.PlayerModel
+	}
+
+	TransferPlayerCommand struct {
+		Payload TransferPayload
+	}
+
+	TransferBatchCommand struct {
+		Payload TransferBatchPayload
+	}
+
+	SwapPlayersCommand struct {
+		Payload SwapPayload
+	}
+
+	PatchPlayerCommand struct {
+		ID    int64
+		Patch patch.Patch
+	}
+
+	CommandHandlers struct {
+		dig.Out
+		Handlers []command_model.Handler `group:"command_handlers,flatten"`
+	}
+)
+
+func (InsertPlayerCommand) CommandName() string {
+	return "player.insert"
+}
+
+func (c InsertPlayerCommand) Validate() error {
+	if strings.TrimSpace(c.Payload.Name) == "" {
+		return errors.New("name is required")
+	}
+
+	if c.Payload.TeamID <= 0 {
+		return errors.New("teamId is required")
+	}
+
+	return nil
+}
+
+func (TransferPlayerCommand) CommandName() string {
+	return "player.transfer"
+}
+
+func (c TransferPlayerCommand) Validate() error {
+	if c.Payload.PlayerID <= 0 {
+		return errors.New("playerID is required")
+	}
+
+	if c.Payload.TeamID <= 0 {
+		return errors.New("teamID is required")
+	}
+
+	return nil
+}
+
+func (TransferBatchCommand) CommandName() string {
+	return "player.transfer_batch"
+}
+
+func (c TransferBatchCommand) Validate() error {
+	if len(c.Payload.Transfers) == 0 {
+		return errors.New("transfers are required")
+	}
+
+	for i, transfer := range c.Payload.Transfers {
+		if err := (TransferPlayerCommand{Payload: transfer}).Validate(); err != nil {
+			return fmt.Errorf("transfers[%d]: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+func (SwapPlayersCommand) CommandName() string {
+	return "player.swap"
+}
+
+func (c SwapPlayersCommand) Validate() error {
+	if c.Payload.PlayerID <= 0 {
+		return errors.New("playerId is required")
+	}
+
+	if c.Payload.OtherPlayerID <= 0 {
+		return errors.New("otherPlayerId is required")
+	}
+
+	if c.Payload.PlayerID == c.Payload.OtherPlayerID {
+		return errors.New("a player can't be swapped with itself")
+	}
+
+	return nil
+}
+
+func (PatchPlayerCommand) CommandName() string {
+	return "player.patch"
+}
+
+func (c PatchPlayerCommand) Validate() error {
+	if c.ID <= 0 {
+		return errors.New("id is required")
+	}
+
+	return c.Patch.Validate()
+}
+
+// NewCommandHandlers registers the player service methods as the handlers of
+// the player commands.
+func NewCommandHandlers(svc PlayerService) CommandHandlers {
+	return CommandHandlers{
+		Handlers: []command_model.Handler{
+			command_model.NewHandler(func(ctx context.Context, cmd InsertPlayerCommand) (model.PlayerModel, error) {
+				return svc.Insert(ctx, cmd.Payload)
+			}),
+			command_model.NewHandler(func(ctx context.Context, cmd TransferPlayerCommand) (struct{}, error) {
+				return struct{}{}, svc.Transfer(ctx, cmd.Payload)
+			}),
+			command_model.NewHandler(func(ctx context.Context, cmd TransferBatchCommand) (model.TransferBatchModel, error) {
+				return svc.TransferBatch(ctx, cmd.Payload.Transfers)
+			}),
+			command_model.NewHandler(func(ctx context.Context, cmd SwapPlayersCommand) (model.TransferBatchModel, error) {
+				return svc.Swap(ctx, cmd.Payload)
+			}),
+			command_model.NewHandler(func(ctx context.Context, cmd PatchPlayerCommand) (model.PlayerModel, error) {
+				return svc.Patch(ctx, cmd.ID, cmd.Patch)
+			}),
+		},
+	}
+}
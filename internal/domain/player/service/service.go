@@ -2,16 +2,31 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/EventStore/EventStore-Client-Go/esdb"
 	"github.com/gofrs/uuid"
+	"github.com/tesarwijaya/ouroboros/internal/database"
+	auth_model "github.com/tesarwijaya/ouroboros/internal/domain/auth/model"
+	auth_service "github.com/tesarwijaya/ouroboros/internal/domain/auth/service"
+	command_model "github.com/tesarwijaya/ouroboros/internal/domain/command/model"
 	event_model "github.com/tesarwijaya/ouroboros/internal/domain/event/model"
 	event_repository "github.com/tesarwijaya/ouroboros/internal/domain/event/repository"
+	event_service "github.com/tesarwijaya/ouroboros/internal/domain/event/service"
 	"github.com/tesarwijaya/ouroboros/internal/domain/player/model"
 	"github.com/tesarwijaya/ouroboros/internal/domain/player/repository"
 	team_repository "github.com/tesarwijaya/ouroboros/internal/domain/team/repository"
+	"github.com/tesarwijaya/ouroboros/internal/logger"
+	"github.com/tesarwijaya/ouroboros/internal/patch"
+	"github.com/tesarwijaya/ouroboros/internal/tracing"
 	"go.uber.org/dig"
+	"go.uber.org/zap"
 )
 
 type (
@@ -19,20 +34,43 @@ type (
 		PlayerID int64
 		TeamID   int64
 	}
+
+	TransferBatchPayload struct {
+		Transfers []TransferPayload `json:"transfers"`
+	}
+
+	SwapPayload struct {
+		PlayerID      int64 `json:"playerId"`
+		OtherPlayerID int64 `json:"otherPlayerId"`
+	}
 )
 
+// maxConcurrentStreamReads bounds the player streams read at once.
+const maxConcurrentStreamReads = 8
+
 type PlayerService interface {
 	FindAll(ctx context.Context) ([]model.PlayerModel, error)
 	FindByID(ctx context.Context, id int64) (model.PlayerModel, error)
+	FindByIDs(ctx context.Context, ids []int64) ([]model.PlayerModel, error)
+	FindByTeamIDs(ctx context.Context, teamIDs []int64) ([]model.PlayerModel, error)
+	FindFields(ctx context.Context, ids []int64, fields []string) ([]model.PlayerModel, []int64, error)
+	FindTransfers(ctx context.Context, id int64) ([]model.TransferModel, error)
+	FindTransfersOf(ctx context.Context, players []model.PlayerModel) (map[int64][]model.TransferModel, error)
 	Insert(ctx context.Context, payload model.PlayerModel) (model.PlayerModel, error)
 	Transfer(ctx context.Context, payload TransferPayload) error
+	TransferBatch(ctx context.Context, transfers []TransferPayload) (model.TransferBatchModel, error)
+	Swap(ctx context.Context, payload SwapPayload) (model.TransferBatchModel, error)
+	Patch(ctx context.Context, id int64, p patch.Patch) (model.PlayerModel, error)
 }
 
 type PlayerServiceImpl struct {
 	dig.In
+	Db        *sql.DB
 	Repo      repository.PlayerRepository
 	TeamRepo  team_repository.TeamRepository
+	EventBus  event_service.EventBus
 	EventRepo event_repository.EventRepository
+	Authz     auth_service.Authorizer
 }
 
 func NewPlayerService(svc PlayerServiceImpl) PlayerService {
@@ -40,62 +78,532 @@ func NewPlayerService(svc PlayerServiceImpl) PlayerService {
 }
 
 func (s *PlayerServiceImpl) FindAll(ctx context.Context) ([]model.PlayerModel, error) {
+	ctx, span := tracing.Start(ctx, "PlayerService.FindAll")
+	defer span.End()
+
+	if err := s.Authz.Authorize(ctx, auth_model.ACTION_PLAYER_READ, auth_model.Resource{}); err != nil {
+		return []model.PlayerModel{}, err
+	}
+
 	return s.Repo.FindAll(ctx)
 }
 
 func (s *PlayerServiceImpl) FindByID(ctx context.Context, id int64) (model.PlayerModel, error) {
-	return s.Repo.FindByID(ctx, id)
+	ctx, span := tracing.Start(ctx, "PlayerService.FindByID")
+	defer span.End()
+
+	player, err := s.Repo.FindByID(ctx, id)
+	if err != nil {
+		return model.PlayerModel{}, err
+	}
+
+	if err := s.Authz.Authorize(ctx, auth_model.ACTION_PLAYER_READ, auth_model.Resource{TeamID: player.TeamID}); err != nil {
+		return model.PlayerModel{}, err
+	}
+
+	return player, nil
 }
 
-func (s *PlayerServiceImpl) Insert(ctx context.Context, payload model.PlayerModel) (model.PlayerModel, error) {
-	_, err := s.TeamRepo.FindByID(ctx, payload.TeamID)
+// FindByIDs returns the given players at once, the caller must be allowed to
+// read the players of each of their teams.
+func (s *PlayerServiceImpl) FindByIDs(ctx context.Context, ids []int64) ([]model.PlayerModel, error) {
+	ctx, span := tracing.Start(ctx, "PlayerService.FindByIDs")
+	defer span.End()
+
+	players, err := s.Repo.FindByIDs(ctx, ids)
+	if err != nil {
+		return []model.PlayerModel{}, err
+	}
+
+	for _, player := range players {
+		if err := s.Authz.Authorize(ctx, auth_model.ACTION_PLAYER_READ, auth_model.Resource{TeamID: player.TeamID}); err != nil {
+			return []model.PlayerModel{}, err
+		}
+	}
+
+	return players, nil
+}
+
+// FindByTeamIDs returns the players of every given team at once, the caller
+// must be allowed to read the players of each of them.
+func (s *PlayerServiceImpl) FindByTeamIDs(ctx context.Context, teamIDs []int64) ([]model.PlayerModel, error) {
+	ctx, span := tracing.Start(ctx, "PlayerService.FindByTeamIDs")
+	defer span.End()
+
+	for _, teamID := range teamIDs {
+		if err := s.Authz.Authorize(ctx, auth_model.ACTION_PLAYER_READ, auth_model.Resource{TeamID: teamID}); err != nil {
+			return []model.PlayerModel{}, err
+		}
+	}
+
+	return s.Repo.FindByTeamIDs(ctx, teamIDs)
+}
+
+// FindFields selects only the given JSON fields of the players matching ids,
+// of every player when ids is empty, and returns the ids no player has apart.
+// Every field is selected when fields is empty.
+func (s *PlayerServiceImpl) FindFields(ctx context.Context, ids []int64, fields []string) ([]model.PlayerModel, []int64, error) {
+	ctx, span := tracing.Start(ctx, "PlayerService.FindFields")
+	defer span.End()
+
+	// the id tells which players are missing and the team authorizes them
+	columns := []string{"id", "team_id"}
+	for _, field := range fields {
+		column, ok := model.PLAYER_COLUMNS[field]
+		if !ok {
+			return []model.PlayerModel{}, []int64{}, command_model.ValidationError{Err: fmt.Errorf("unknown field %q", field)}
+		}
+
+		if column != "id" && column != "team_id" {
+			columns = append(columns, column)
+		}
+	}
+	if len(fields) == 0 {
+		columns = append(columns, "name")
+	}
+
+	if len(ids) == 0 {
+		if err := s.Authz.Authorize(ctx, auth_model.ACTION_PLAYER_READ, auth_model.Resource{}); err != nil {
+			return []model.PlayerModel{}, []int64{}, err
+		}
+	}
+
+	players, err := s.Repo.FindFields(ctx, ids, columns)
 	if err != nil {
+		return []model.PlayerModel{}, []int64{}, err
+	}
+
+	found := make(map[int64]bool, len(players))
+	for _, player := range players {
+		if len(ids) > 0 {
+			if err := s.Authz.Authorize(ctx, auth_model.ACTION_PLAYER_READ, auth_model.Resource{TeamID: player.TeamID}); err != nil {
+				return []model.PlayerModel{}, []int64{}, err
+			}
+		}
+
+		found[player.ID] = true
+	}
+
+	missing := []int64{}
+	for _, id := range ids {
+		if !found[id] {
+			missing = append(missing, id)
+			found[id] = true
+		}
+	}
+
+	return players, missing, nil
+}
+
+// FindTransfers returns the transfer history of the player, read from its
+// event stream.
+func (s *PlayerServiceImpl) FindTransfers(ctx context.Context, id int64) ([]model.TransferModel, error) {
+	ctx, span := tracing.Start(ctx, "PlayerService.FindTransfers")
+	defer span.End()
+
+	if _, err := s.FindByID(ctx, id); err != nil {
+		return []model.TransferModel{}, err
+	}
+
+	return s.readTransfers(ctx, id)
+}
+
+// FindTransfersOf returns the transfer history of players that were already
+// loaded, by player id. The caller must be allowed to read the players of
+// each of their teams, the streams are read concurrently.
+func (s *PlayerServiceImpl) FindTransfersOf(ctx context.Context, players []model.PlayerModel) (map[int64][]model.TransferModel, error) {
+	ctx, span := tracing.Start(ctx, "PlayerService.FindTransfersOf")
+	defer span.End()
+
+	authorized := map[int64]bool{}
+	for _, player := range players {
+		if authorized[player.TeamID] {
+			continue
+		}
+
+		if err := s.Authz.Authorize(ctx, auth_model.ACTION_PLAYER_READ, auth_model.Resource{TeamID: player.TeamID}); err != nil {
+			return map[int64][]model.TransferModel{}, err
+		}
+		authorized[player.TeamID] = true
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		sem      = make(chan struct{}, maxConcurrentStreamReads)
+		res      = make(map[int64][]model.TransferModel, len(players))
+	)
+	for _, player := range players {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(id int64) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			transfers, err := s.readTransfers(ctx, id)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+			res[id] = transfers
+		}(player.ID)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return map[int64][]model.TransferModel{}, firstErr
+	}
+
+	return res, nil
+}
+
+func (s *PlayerServiceImpl) readTransfers(ctx context.Context, id int64) ([]model.TransferModel, error) {
+	events, err := s.EventRepo.ReadStream(ctx, model.PlayerStreamID(id), 0)
+	if err != nil {
+		return []model.TransferModel{}, err
+	}
+
+	return model.Transfers(events)
+}
+
+// Insert adds the player to its team, which is locked until the player is
+// written so that it can't be deleted meanwhile.
+func (s *PlayerServiceImpl) Insert(ctx context.Context, payload model.PlayerModel) (model.PlayerModel, error) {
+	ctx, span := tracing.Start(ctx, "PlayerService.Insert")
+	defer span.End()
+
+	if err := s.Authz.Authorize(ctx, auth_model.ACTION_PLAYER_CREATE, auth_model.Resource{TeamID: payload.TeamID}); err != nil {
 		return model.PlayerModel{}, err
 	}
 
-	if err := s.Repo.Insert(ctx, payload); err != nil {
+	res := payload
+	err := database.WithTx(ctx, s.Db, func(ctx context.Context) error {
+		teams, err := s.TeamRepo.FindByIDsForShare(ctx, []int64{payload.TeamID})
+		if err != nil {
+			return err
+		}
+
+		if len(teams) == 0 {
+			return sql.ErrNoRows
+		}
+
+		if command_model.IsDryRun(ctx) {
+			return nil
+		}
+
+		res, err = s.Repo.Insert(ctx, payload)
+
+		return err
+	})
+	if err != nil {
 		return model.PlayerModel{}, err
 	}
 
-	return payload, nil
+	return res, nil
 }
 
+// Transfer moves a single player, as TransferBatch does.
 func (s *PlayerServiceImpl) Transfer(ctx context.Context, payload TransferPayload) error {
-	gen := uuid.NewGen()
+	ctx, span := tracing.Start(ctx, "PlayerService.Transfer")
+	defer span.End()
+
+	logger.FromContext(ctx).Debug("transferring player",
+		zap.Int64("player_id", payload.PlayerID),
+		zap.Int64("to_team_id", payload.TeamID),
+	)
+
+	_, err := s.TransferBatch(ctx, []TransferPayload{payload})
+
+	return err
+}
+
+// TransferBatch applies every transfer or none of them. The transfers are
+// validated together first, then the teams of the players are changed in a
+// single transaction which the transfer events, correlated by a shared id, are
+// written to the outbox within, so that they are only published once it
+// commits. A player transferred concurrently fails the whole batch.
+func (s *PlayerServiceImpl) TransferBatch(ctx context.Context, transfers []TransferPayload) (model.TransferBatchModel, error) {
+	ctx, span := tracing.Start(ctx, "PlayerService.TransferBatch")
+	defer span.End()
 
-	payloadByte, _ := json.Marshal(payload)
+	if len(transfers) == 0 {
+		return model.TransferBatchModel{}, command_model.ValidationError{Err: errors.New("transfers are required")}
+	}
+
+	if len(transfers) > model.MAX_BATCH_TRANSFERS {
+		return model.TransferBatchModel{}, command_model.ValidationError{Err: fmt.Errorf("at most %d transfers can be applied together", model.MAX_BATCH_TRANSFERS)}
+	}
 
-	currPlayer, err := s.Repo.FindByID(ctx, payload.PlayerID)
+	ids := make([]int64, 0, len(transfers))
+	moved := make(map[int64]bool, len(transfers))
+	for _, transfer := range transfers {
+		if moved[transfer.PlayerID] {
+			return model.TransferBatchModel{}, command_model.ValidationError{Err: fmt.Errorf("player %d is transferred twice", transfer.PlayerID)}
+		}
+
+		moved[transfer.PlayerID] = true
+		ids = append(ids, transfer.PlayerID)
+	}
+
+	players, err := s.findPlayers(ctx, ids)
 	if err != nil {
-		return err
+		return model.TransferBatchModel{}, err
 	}
 
-	playerOutByte, _ := json.Marshal(TransferPayload{
-		PlayerID: currPlayer.ID,
-		TeamID:   currPlayer.TeamID,
+	return s.transferAll(ctx, players, transfers)
+}
+
+// Swap exchanges the teams of two players of different teams at once, as
+// TransferBatch does.
+func (s *PlayerServiceImpl) Swap(ctx context.Context, payload SwapPayload) (model.TransferBatchModel, error) {
+	ctx, span := tracing.Start(ctx, "PlayerService.Swap")
+	defer span.End()
+
+	players, err := s.findPlayers(ctx, []int64{payload.PlayerID, payload.OtherPlayerID})
+	if err != nil {
+		return model.TransferBatchModel{}, err
+	}
+
+	player, other := players[payload.PlayerID], players[payload.OtherPlayerID]
+	if player.TeamID == other.TeamID {
+		return model.TransferBatchModel{}, command_model.ValidationError{Err: fmt.Errorf("players %d and %d both play for team %d", player.ID, other.ID, player.TeamID)}
+	}
+
+	return s.transferAll(ctx, players, []TransferPayload{
+		{PlayerID: player.ID, TeamID: other.TeamID},
+		{PlayerID: other.ID, TeamID: player.TeamID},
 	})
+}
+
+// findPlayers reads the given players at once, a missing one fails the
+// validation.
+func (s *PlayerServiceImpl) findPlayers(ctx context.Context, ids []int64) (map[int64]model.PlayerModel, error) {
+	players, err := s.Repo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[int64]model.PlayerModel, len(players))
+	for _, player := range players {
+		res[player.ID] = player
+	}
+
+	for _, id := range ids {
+		if _, ok := res[id]; !ok {
+			return nil, command_model.ValidationError{Err: fmt.Errorf("player %d not found", id)}
+		}
+	}
+
+	return res, nil
+}
+
+func (s *PlayerServiceImpl) transferAll(ctx context.Context, players map[int64]model.PlayerModel, transfers []TransferPayload) (model.TransferBatchModel, error) {
+	teamIDs := make([]int64, 0, len(transfers))
+	seen := make(map[int64]bool, len(transfers))
+	for _, transfer := range transfers {
+		player := players[transfer.PlayerID]
+
+		// a player is transferred by the team it currently plays for
+		if err := s.Authz.Authorize(ctx, auth_model.ACTION_PLAYER_TRANSFER, auth_model.Resource{TeamID: player.TeamID}); err != nil {
+			return model.TransferBatchModel{}, err
+		}
+
+		if player.TeamID == transfer.TeamID {
+			return model.TransferBatchModel{}, command_model.ValidationError{Err: fmt.Errorf("player %d already plays for team %d", player.ID, transfer.TeamID)}
+		}
+
+		if !seen[transfer.TeamID] {
+			seen[transfer.TeamID] = true
+			teamIDs = append(teamIDs, transfer.TeamID)
+		}
+	}
 
-	outId, _ := gen.NewV4()
-	err = s.EventRepo.Insert(ctx, event_model.Event{
-		ID:          outId,
-		Type:        "player_transfer_out",
-		ContentType: esdb.JsonContentType,
-		Data:        playerOutByte,
+	var res model.TransferBatchModel
+	err := database.WithTx(ctx, s.Db, func(ctx context.Context) error {
+		// the target teams are locked so that they can't be deleted before
+		// the players joined them
+		teams, err := s.TeamRepo.FindByIDsForShare(ctx, teamIDs)
+		if err != nil {
+			return err
+		}
+
+		found := make(map[int64]bool, len(teams))
+		for _, team := range teams {
+			found[team.ID] = true
+		}
+
+		gen := uuid.NewGen()
+		correlationID, _ := gen.NewV4()
+		metadata := event_model.CorrelationMetadata(correlationID.String())
+
+		res = model.TransferBatchModel{
+			CorrelationID: correlationID.String(),
+			Transfers:     make([]model.TransferModel, 0, len(transfers)),
+		}
+		events := make([]event_model.Event, 0, 2*len(transfers))
+		now := time.Now().UTC()
+		for _, transfer := range transfers {
+			if !found[transfer.TeamID] {
+				return command_model.ValidationError{Err: fmt.Errorf("team %d not found", transfer.TeamID)}
+			}
+
+			player := players[transfer.PlayerID]
+			res.Transfers = append(res.Transfers, model.TransferModel{
+				PlayerID:      player.ID,
+				FromTeamID:    player.TeamID,
+				ToTeamID:      transfer.TeamID,
+				TransferredAt: now,
+				CorrelationID: res.CorrelationID,
+			})
+
+			outData, _ := json.Marshal(TransferPayload{PlayerID: player.ID, TeamID: player.TeamID})
+			inData, _ := json.Marshal(transfer)
+			outID, _ := gen.NewV4()
+			inID, _ := gen.NewV4()
+
+			events = append(events,
+				event_model.Event{
+					ID:          outID,
+					StreamID:    model.PlayerStreamID(player.ID),
+					Type:        model.PLAYER_TRANSFER_OUT_EVENT,
+					ContentType: esdb.JsonContentType,
+					Data:        outData,
+					Metadata:    metadata,
+				},
+				event_model.Event{
+					ID:          inID,
+					StreamID:    model.PlayerStreamID(player.ID),
+					Type:        model.PLAYER_TRANSFER_IN_EVENT,
+					ContentType: esdb.JsonContentType,
+					Data:        inData,
+					Metadata:    metadata,
+				},
+			)
+		}
+
+		if command_model.IsDryRun(ctx) {
+			return nil
+		}
+
+		for _, transfer := range transfers {
+			// the players were read outside of the transaction, a player moved
+			// since would be recorded as leaving the wrong team
+			fromTeamID := players[transfer.PlayerID].TeamID
+
+			ok, err := s.Repo.MoveTeam(ctx, transfer.PlayerID, fromTeamID, transfer.TeamID)
+			if err != nil {
+				return err
+			}
+
+			if !ok {
+				return command_model.ValidationError{Err: fmt.Errorf("player %d no longer plays for team %d, try again", transfer.PlayerID, fromTeamID)}
+			}
+		}
+
+		return s.EventBus.Publish(ctx, events...)
 	})
 	if err != nil {
-		return err
+		return model.TransferBatchModel{}, err
 	}
 
-	inId, _ := gen.NewV4()
-	err = s.EventRepo.Insert(ctx, event_model.Event{
-		ID:          inId,
-		Type:        "player_transfer_in",
-		ContentType: esdb.JsonContentType,
-		Data:        payloadByte,
+	if command_model.IsDryRun(ctx) {
+		return res, nil
+	}
+
+	logger.FromContext(ctx).Info("transferred players",
+		zap.String("correlation_id", res.CorrelationID),
+		zap.Int("transfers", len(res.Transfers)),
+	)
+
+	return res, nil
+}
+
+// Patch applies p to the player and writes only the changed columns, a
+// player_updated event lists the changed fields. The team of a player is only
+// changed by a transfer. The caller is authorized before the player is locked
+// from the read to the write, and again should it have been transferred
+// meanwhile.
+func (s *PlayerServiceImpl) Patch(ctx context.Context, id int64, p patch.Patch) (model.PlayerModel, error) {
+	ctx, span := tracing.Start(ctx, "PlayerService.Patch")
+	defer span.End()
+
+	found, err := s.Repo.FindByID(ctx, id)
+	if err != nil {
+		return model.PlayerModel{}, err
+	}
+
+	if err := s.Authz.Authorize(ctx, auth_model.ACTION_PLAYER_UPDATE, auth_model.Resource{TeamID: found.TeamID}); err != nil {
+		return model.PlayerModel{}, err
+	}
+
+	var res model.PlayerModel
+	err = database.WithTx(ctx, s.Db, func(ctx context.Context) error {
+		currPlayer, err := s.Repo.FindByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if currPlayer.TeamID != found.TeamID {
+			if err := s.Authz.Authorize(ctx, auth_model.ACTION_PLAYER_UPDATE, auth_model.Resource{TeamID: currPlayer.TeamID}); err != nil {
+				return err
+			}
+		}
+
+		var player model.PlayerModel
+		if err := p.Apply(currPlayer, &player); err != nil {
+			return command_model.ValidationError{Err: err}
+		}
+
+		after := player.Sparse(nil)
+		fields := patch.Changed(currPlayer.Sparse(nil), after)
+
+		values := make(map[string]interface{}, len(fields))
+		for _, field := range fields {
+			switch field {
+			case "id":
+				return command_model.ValidationError{Err: errors.New("id can't be changed")}
+			case "teamId":
+				return command_model.ValidationError{Err: errors.New("teamId can't be patched, transfer the player instead")}
+			}
+
+			values[model.PLAYER_COLUMNS[field]] = after[field]
+		}
+
+		if strings.TrimSpace(player.Name) == "" {
+			return command_model.ValidationError{Err: errors.New("name is required")}
+		}
+
+		if len(fields) == 0 || command_model.IsDryRun(ctx) {
+			res = player
+			return nil
+		}
+
+		res, err = s.Repo.Update(ctx, id, values)
+		if err != nil {
+			return err
+		}
+
+		data, _ := json.Marshal(model.UpdatedEventData{
+			PlayerID: id,
+			Fields:   fields,
+			Player:   res,
+		})
+		eventID, _ := uuid.NewGen().NewV4()
+
+		return s.EventBus.Publish(ctx, event_model.Event{
+			ID:          eventID,
+			StreamID:    model.PlayerStreamID(id),
+			Type:        model.PLAYER_UPDATED_EVENT,
+			ContentType: esdb.JsonContentType,
+			Data:        data,
+		})
 	})
 	if err != nil {
-		return err
+		return model.PlayerModel{}, err
 	}
 
-	return nil
+	return res, nil
 }
@@ -10,6 +10,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	model "github.com/tesarwijaya/ouroboros/internal/domain/player/model"
+	patch "github.com/tesarwijaya/ouroboros/internal/patch"
 )
 
 // MockPlayerService is a mock of PlayerService interface.
@@ -65,6 +66,82 @@ func (mr *MockPlayerServiceMockRecorder) FindByID(ctx, id interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockPlayerService)(nil).FindByID), ctx, id)
 }
 
+// FindByIDs mocks base method.
+func (m *MockPlayerService) FindByIDs(ctx context.Context, ids []int64) ([]model.PlayerModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByIDs", ctx, ids)
+	ret0, _ := ret[0].([]model.PlayerModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByIDs indicates an expected call of FindByIDs.
+func (mr *MockPlayerServiceMockRecorder) FindByIDs(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByIDs", reflect.TypeOf((*MockPlayerService)(nil).FindByIDs), ctx, ids)
+}
+
+// FindByTeamIDs mocks base method.
+func (m *MockPlayerService) FindByTeamIDs(ctx context.Context, teamIDs []int64) ([]model.PlayerModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByTeamIDs", ctx, teamIDs)
+	ret0, _ := ret[0].([]model.PlayerModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByTeamIDs indicates an expected call of FindByTeamIDs.
+func (mr *MockPlayerServiceMockRecorder) FindByTeamIDs(ctx, teamIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByTeamIDs", reflect.TypeOf((*MockPlayerService)(nil).FindByTeamIDs), ctx, teamIDs)
+}
+
+// FindFields mocks base method.
+func (m *MockPlayerService) FindFields(ctx context.Context, ids []int64, fields []string) ([]model.PlayerModel, []int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFields", ctx, ids, fields)
+	ret0, _ := ret[0].([]model.PlayerModel)
+	ret1, _ := ret[1].([]int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindFields indicates an expected call of FindFields.
+func (mr *MockPlayerServiceMockRecorder) FindFields(ctx, ids, fields interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFields", reflect.TypeOf((*MockPlayerService)(nil).FindFields), ctx, ids, fields)
+}
+
+// FindTransfers mocks base method.
+func (m *MockPlayerService) FindTransfers(ctx context.Context, id int64) ([]model.TransferModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTransfers", ctx, id)
+	ret0, _ := ret[0].([]model.TransferModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTransfers indicates an expected call of FindTransfers.
+func (mr *MockPlayerServiceMockRecorder) FindTransfers(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTransfers", reflect.TypeOf((*MockPlayerService)(nil).FindTransfers), ctx, id)
+}
+
+// FindTransfersOf mocks base method.
+func (m *MockPlayerService) FindTransfersOf(ctx context.Context, players []model.PlayerModel) (map[int64][]model.TransferModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTransfersOf", ctx, players)
+	ret0, _ := ret[0].(map[int64][]model.TransferModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTransfersOf indicates an expected call of FindTransfersOf.
+func (mr *MockPlayerServiceMockRecorder) FindTransfersOf(ctx, players interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTransfersOf", reflect.TypeOf((*MockPlayerService)(nil).FindTransfersOf), ctx, players)
+}
+
 // Insert mocks base method.
 func (m *MockPlayerService) Insert(ctx context.Context, payload model.PlayerModel) (model.PlayerModel, error) {
 	m.ctrl.T.Helper()
@@ -80,6 +157,36 @@ func (mr *MockPlayerServiceMockRecorder) Insert(ctx, payload interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockPlayerService)(nil).Insert), ctx, payload)
 }
 
+// Patch mocks base method.
+func (m *MockPlayerService) Patch(ctx context.Context, id int64, p patch.Patch) (model.PlayerModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Patch", ctx, id, p)
+	ret0, _ := ret[0].(model.PlayerModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Patch indicates an expected call of Patch.
+func (mr *MockPlayerServiceMockRecorder) Patch(ctx, id, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockPlayerService)(nil).Patch), ctx, id, p)
+}
+
+// Swap mocks base method.
+func (m *MockPlayerService) Swap(ctx context.Context, payload SwapPayload) (model.TransferBatchModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Swap", ctx, payload)
+	ret0, _ := ret[0].(model.TransferBatchModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Swap indicates an expected call of Swap.
+func (mr *MockPlayerServiceMockRecorder) Swap(ctx, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Swap", reflect.TypeOf((*MockPlayerService)(nil).Swap), ctx, payload)
+}
+
 // Transfer mocks base method.
 func (m *MockPlayerService) Transfer(ctx context.Context, payload TransferPayload) error {
 	m.ctrl.T.Helper()
@@ -93,3 +200,18 @@ func (mr *MockPlayerServiceMockRecorder) Transfer(ctx, payload interface{}) *gom
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transfer", reflect.TypeOf((*MockPlayerService)(nil).Transfer), ctx, payload)
 }
+
+// TransferBatch mocks base method.
+func (m *MockPlayerService) TransferBatch(ctx context.Context, transfers []TransferPayload) (model.TransferBatchModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferBatch", ctx, transfers)
+	ret0, _ := ret[0].(model.TransferBatchModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransferBatch indicates an expected call of TransferBatch.
+func (mr *MockPlayerServiceMockRecorder) TransferBatch(ctx, transfers interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferBatch", reflect.TypeOf((*MockPlayerService)(nil).TransferBatch), ctx, transfers)
+}
@@ -2,31 +2,46 @@ package service_test
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	auth_model "github.com/tesarwijaya/ouroboros/internal/domain/auth/model"
+	auth_service "github.com/tesarwijaya/ouroboros/internal/domain/auth/service"
+	command_model "github.com/tesarwijaya/ouroboros/internal/domain/command/model"
+	event_model "github.com/tesarwijaya/ouroboros/internal/domain/event/model"
+	event_repository "github.com/tesarwijaya/ouroboros/internal/domain/event/repository"
+	event_service "github.com/tesarwijaya/ouroboros/internal/domain/event/service"
 	"github.com/tesarwijaya/ouroboros/internal/domain/player/model"
 	"github.com/tesarwijaya/ouroboros/internal/domain/player/repository"
 	"github.com/tesarwijaya/ouroboros/internal/domain/player/service"
 	team_model "github.com/tesarwijaya/ouroboros/internal/domain/team/model"
 	team_repository "github.com/tesarwijaya/ouroboros/internal/domain/team/repository"
+	"github.com/tesarwijaya/ouroboros/internal/patch"
 )
 
 type resolverFn func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository)
 
-func createService(t *testing.T, resolver resolverFn) (*service.PlayerServiceImpl, gomock.Controller) {
+func createService(t *testing.T, resolver resolverFn) (*service.PlayerServiceImpl, *gomock.Controller) {
 	ctrl := gomock.NewController(t)
 
 	repo := repository.NewMockPlayerRepository(ctrl)
 	teamRepo := team_repository.NewMockTeamRepository(ctrl)
 	resolver(repo, teamRepo)
 
+	authz := auth_service.NewMockAuthorizer(ctrl)
+	authz.EXPECT().Authorize(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
 	return &service.PlayerServiceImpl{
 		Repo:     repo,
 		TeamRepo: teamRepo,
-	}, *ctrl
+		Authz:    authz,
+	}, ctrl
 }
 
 func Test_NewPlayerService(t *testing.T) {
@@ -117,6 +132,288 @@ func Test_FindByID(t *testing.T) {
 	}
 }
 
+func Test_FindByIDs(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Param     []int64
+		Resolver  resolverFn
+		Expect    []model.PlayerModel
+		ExpectErr error
+	}{
+		{
+			Name:  "when_success",
+			Param: []int64{1, 2},
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+				repo.EXPECT().FindByIDs(gomock.Any(), []int64{1, 2}).
+					Return([]model.PlayerModel{{ID: 1, TeamID: 1}}, nil)
+			},
+			Expect: []model.PlayerModel{{ID: 1, TeamID: 1}},
+		},
+		{
+			Name:  "when_not_success",
+			Param: []int64{1},
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+				repo.EXPECT().FindByIDs(gomock.Any(), []int64{1}).
+					Return([]model.PlayerModel{}, errors.New("some-error"))
+			},
+			ExpectErr: errors.New("some-error"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			svc, mock := createService(t, test.Resolver)
+			defer mock.Finish()
+
+			actual, err := svc.FindByIDs(context.Background(), test.Param)
+			if test.ExpectErr == nil {
+				assert.Equal(t, test.Expect, actual)
+				assert.Nil(t, err)
+			}
+
+			assert.Equal(t, test.ExpectErr, err)
+		})
+	}
+}
+
+func Test_FindFields(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		IDs           []int64
+		Fields        []string
+		Resolver      resolverFn
+		Expect        []model.PlayerModel
+		ExpectMissing []int64
+		ExpectErr     error
+	}{
+		{
+			Name:   "when_ids_given",
+			IDs:    []int64{1, 2, 3},
+			Fields: []string{"name"},
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+				repo.EXPECT().FindFields(gomock.Any(), []int64{1, 2, 3}, []string{"id", "team_id", "name"}).
+					Return([]model.PlayerModel{{ID: 2, Name: "some-player-name", TeamID: 1}}, nil)
+			},
+			Expect:        []model.PlayerModel{{ID: 2, Name: "some-player-name", TeamID: 1}},
+			ExpectMissing: []int64{1, 3},
+		},
+		{
+			Name:   "when_every_player",
+			Fields: []string{"id", "teamId"},
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+				repo.EXPECT().FindFields(gomock.Any(), nil, []string{"id", "team_id"}).
+					Return([]model.PlayerModel{{ID: 1, TeamID: 1}}, nil)
+			},
+			Expect:        []model.PlayerModel{{ID: 1, TeamID: 1}},
+			ExpectMissing: []int64{},
+		},
+		{
+			Name:      "when_field_unknown",
+			Fields:    []string{"age"},
+			Resolver:  func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {},
+			ExpectErr: command_model.ValidationError{Err: errors.New(`unknown field "age"`)},
+		},
+		{
+			Name: "when_not_success",
+			IDs:  []int64{1},
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+				repo.EXPECT().FindFields(gomock.Any(), []int64{1}, []string{"id", "team_id", "name"}).
+					Return([]model.PlayerModel{}, errors.New("some-error"))
+			},
+			ExpectErr: errors.New("some-error"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			svc, mock := createService(t, test.Resolver)
+			defer mock.Finish()
+
+			actual, missing, err := svc.FindFields(context.Background(), test.IDs, test.Fields)
+			if test.ExpectErr == nil {
+				assert.Equal(t, test.Expect, actual)
+				assert.Equal(t, test.ExpectMissing, missing)
+				assert.Nil(t, err)
+			}
+
+			assert.Equal(t, test.ExpectErr, err)
+		})
+	}
+}
+
+func Test_FindByTeamIDs(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Param     []int64
+		Resolver  resolverFn
+		Expect    []model.PlayerModel
+		ExpectErr error
+	}{
+		{
+			Name:  "when_success",
+			Param: []int64{1, 2},
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+				repo.EXPECT().FindByTeamIDs(gomock.Any(), []int64{1, 2}).
+					Return([]model.PlayerModel{{ID: 1, TeamID: 1}, {ID: 2, TeamID: 2}}, nil)
+			},
+			Expect: []model.PlayerModel{{ID: 1, TeamID: 1}, {ID: 2, TeamID: 2}},
+		},
+		{
+			Name:  "when_not_success",
+			Param: []int64{1},
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+				repo.EXPECT().FindByTeamIDs(gomock.Any(), []int64{1}).
+					Return([]model.PlayerModel{}, errors.New("some-error"))
+			},
+			ExpectErr: errors.New("some-error"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			svc, mock := createService(t, test.Resolver)
+			defer mock.Finish()
+
+			actual, err := svc.FindByTeamIDs(context.Background(), test.Param)
+			if test.ExpectErr == nil {
+				assert.Equal(t, test.Expect, actual)
+				assert.Nil(t, err)
+			}
+
+			assert.Equal(t, test.ExpectErr, err)
+		})
+	}
+}
+
+func Test_FindTransfers(t *testing.T) {
+	at := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	testCases := []struct {
+		Name      string
+		Resolver  resolverFn
+		EventFn   func(eventRepo *event_repository.MockEventRepository)
+		Expect    []model.TransferModel
+		ExpectErr error
+	}{
+		{
+			Name: "when_success",
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+				repo.EXPECT().FindByID(gomock.Any(), int64(1)).Return(model.PlayerModel{ID: 1, TeamID: 3}, nil)
+			},
+			EventFn: func(eventRepo *event_repository.MockEventRepository) {
+				eventRepo.EXPECT().ReadStream(gomock.Any(), "player-1", uint64(0)).
+					Return([]event_model.Event{
+						{Type: model.PLAYER_TRANSFER_OUT_EVENT, Data: []byte(`{"PlayerID":1,"TeamID":2}`), CreatedAt: at},
+						{Type: model.PLAYER_TRANSFER_IN_EVENT, Data: []byte(`{"PlayerID":1,"TeamID":3}`), CreatedAt: at},
+					}, nil)
+			},
+			Expect: []model.TransferModel{{PlayerID: 1, FromTeamID: 2, ToTeamID: 3, TransferredAt: at}},
+		},
+		{
+			Name: "when_player_not_found",
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+				repo.EXPECT().FindByID(gomock.Any(), int64(1)).Return(model.PlayerModel{}, sql.ErrNoRows)
+			},
+			EventFn:   func(eventRepo *event_repository.MockEventRepository) {},
+			ExpectErr: sql.ErrNoRows,
+		},
+		{
+			Name: "when_read_stream_fails",
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+				repo.EXPECT().FindByID(gomock.Any(), int64(1)).Return(model.PlayerModel{ID: 1, TeamID: 3}, nil)
+			},
+			EventFn: func(eventRepo *event_repository.MockEventRepository) {
+				eventRepo.EXPECT().ReadStream(gomock.Any(), "player-1", uint64(0)).
+					Return([]event_model.Event{}, errors.New("some-error"))
+			},
+			ExpectErr: errors.New("some-error"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			svc, mock := createService(t, test.Resolver)
+			defer mock.Finish()
+
+			eventRepo := event_repository.NewMockEventRepository(mock)
+			test.EventFn(eventRepo)
+			svc.EventRepo = eventRepo
+
+			actual, err := svc.FindTransfers(context.Background(), 1)
+			if test.ExpectErr == nil {
+				assert.Equal(t, test.Expect, actual)
+				assert.Nil(t, err)
+			}
+
+			assert.Equal(t, test.ExpectErr, err)
+		})
+	}
+}
+
+func Test_FindTransfersOf(t *testing.T) {
+	at := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	players := []model.PlayerModel{{ID: 1, TeamID: 3}, {ID: 2, TeamID: 3}}
+
+	testCases := []struct {
+		Name      string
+		EventFn   func(eventRepo *event_repository.MockEventRepository)
+		Expect    map[int64][]model.TransferModel
+		ExpectErr error
+	}{
+		{
+			Name: "when_success",
+			EventFn: func(eventRepo *event_repository.MockEventRepository) {
+				eventRepo.EXPECT().ReadStream(gomock.Any(), "player-1", uint64(0)).
+					Return([]event_model.Event{
+						{Type: model.PLAYER_TRANSFER_OUT_EVENT, Data: []byte(`{"PlayerID":1,"TeamID":2}`), CreatedAt: at},
+						{Type: model.PLAYER_TRANSFER_IN_EVENT, Data: []byte(`{"PlayerID":1,"TeamID":3}`), CreatedAt: at},
+					}, nil)
+				eventRepo.EXPECT().ReadStream(gomock.Any(), "player-2", uint64(0)).
+					Return([]event_model.Event{}, nil)
+			},
+			Expect: map[int64][]model.TransferModel{
+				1: {{PlayerID: 1, FromTeamID: 2, ToTeamID: 3, TransferredAt: at}},
+				2: {},
+			},
+		},
+		{
+			Name: "when_read_stream_fails",
+			EventFn: func(eventRepo *event_repository.MockEventRepository) {
+				eventRepo.EXPECT().ReadStream(gomock.Any(), "player-1", uint64(0)).
+					Return([]event_model.Event{}, nil)
+				eventRepo.EXPECT().ReadStream(gomock.Any(), "player-2", uint64(0)).
+					Return([]event_model.Event{}, errors.New("some-error"))
+			},
+			Expect:    map[int64][]model.TransferModel{},
+			ExpectErr: errors.New("some-error"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// the team of both players is authorized once
+			authz := auth_service.NewMockAuthorizer(ctrl)
+			authz.EXPECT().Authorize(gomock.Any(), auth_model.ACTION_PLAYER_READ, auth_model.Resource{TeamID: 3}).Return(nil)
+
+			eventRepo := event_repository.NewMockEventRepository(ctrl)
+			test.EventFn(eventRepo)
+
+			svc := &service.PlayerServiceImpl{
+				EventRepo: eventRepo,
+				Authz:     authz,
+			}
+
+			actual, err := svc.FindTransfersOf(context.Background(), players)
+
+			assert.Equal(t, test.ExpectErr, err)
+			assert.Equal(t, test.Expect, actual)
+		})
+	}
+}
+
 func Test_Insert(t *testing.T) {
 	testCases := []struct {
 		Name      string
@@ -129,37 +426,657 @@ func Test_Insert(t *testing.T) {
 			Name:  "when_success",
 			Param: model.PlayerModel{Name: "some-player-name", TeamID: 1},
 			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
-				teamRepo.EXPECT().FindByID(gomock.Any(), int64(1)).
-					Return(team_model.TeamModel{}, nil)
+				teamRepo.EXPECT().FindByIDsForShare(gomock.Any(), []int64{1}).
+					Return([]team_model.TeamModel{{ID: 1}}, nil)
 				repo.EXPECT().Insert(gomock.Any(), model.PlayerModel{Name: "some-player-name", TeamID: 1}).
-					Return(nil)
+					Return(model.PlayerModel{ID: 1, Name: "some-player-name", TeamID: 1}, nil)
+			},
+			Expect: model.PlayerModel{ID: 1, Name: "some-player-name", TeamID: 1},
+		},
+		{
+			Name:  "when_team_not_found",
+			Param: model.PlayerModel{Name: "some-player-name", TeamID: 1},
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+				teamRepo.EXPECT().FindByIDsForShare(gomock.Any(), []int64{1}).
+					Return([]team_model.TeamModel{}, nil)
 			},
-			Expect: model.PlayerModel{Name: "some-player-name", TeamID: 1},
+			ExpectErr: sql.ErrNoRows,
 		},
 		{
 			Name:  "when_not_success",
 			Param: model.PlayerModel{Name: "some-player-name", TeamID: 1},
 			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
-				teamRepo.EXPECT().FindByID(gomock.Any(), int64(1)).
-					Return(team_model.TeamModel{}, nil)
+				teamRepo.EXPECT().FindByIDsForShare(gomock.Any(), []int64{1}).
+					Return([]team_model.TeamModel{{ID: 1}}, nil)
 				repo.EXPECT().Insert(gomock.Any(), model.PlayerModel{Name: "some-player-name", TeamID: 1}).
-					Return(errors.New("some-error"))
+					Return(model.PlayerModel{}, errors.New("some-error"))
 			},
 			ExpectErr: errors.New("some-error"),
 		},
 	}
 
 	for _, test := range testCases {
-		svc, mock := createService(t, test.Resolver)
-		defer mock.Finish()
+		t.Run(test.Name, func(t *testing.T) {
+			svc, mock := createService(t, test.Resolver)
+			defer mock.Finish()
 
-		actual, err := svc.Insert(context.Background(), test.Param)
+			db, dbMock, _ := sqlmock.New()
+			defer db.Close()
+			dbMock.ExpectBegin()
+			if test.ExpectErr != nil {
+				dbMock.ExpectRollback()
+			} else {
+				dbMock.ExpectCommit()
+			}
+			svc.Db = db
+
+			actual, err := svc.Insert(context.Background(), test.Param)
+
+			if test.ExpectErr == nil {
+				assert.Equal(t, test.Expect, actual)
+				assert.Nil(t, err)
+			}
+
+			assert.Equal(t, test.ExpectErr, err)
+			assert.Nil(t, dbMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_TransferBatch(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Param     []service.TransferPayload
+		Resolver  resolverFn
+		MockFn    func(db sqlmock.Sqlmock)
+		Publish   error
+		Expect    []model.TransferModel
+		ExpectErr error
+	}{
+		{
+			Name:  "when_success",
+			Param: []service.TransferPayload{{PlayerID: 1, TeamID: 3}, {PlayerID: 2, TeamID: 3}},
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+				repo.EXPECT().FindByIDs(gomock.Any(), []int64{1, 2}).
+					Return([]model.PlayerModel{{ID: 1, TeamID: 2}, {ID: 2, TeamID: 4}}, nil)
+				teamRepo.EXPECT().FindByIDsForShare(gomock.Any(), []int64{3}).
+					Return([]team_model.TeamModel{{ID: 3}}, nil)
+				repo.EXPECT().MoveTeam(gomock.Any(), int64(1), int64(2), int64(3)).Return(true, nil)
+				repo.EXPECT().MoveTeam(gomock.Any(), int64(2), int64(4), int64(3)).Return(true, nil)
+			},
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectBegin()
+				db.ExpectCommit()
+			},
+			Expect: []model.TransferModel{
+				{PlayerID: 1, FromTeamID: 2, ToTeamID: 3},
+				{PlayerID: 2, FromTeamID: 4, ToTeamID: 3},
+			},
+		},
+		{
+			Name:      "when_empty",
+			Resolver:  func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {},
+			ExpectErr: command_model.ValidationError{Err: errors.New("transfers are required")},
+		},
+		{
+			Name:      "when_player_transferred_twice",
+			Param:     []service.TransferPayload{{PlayerID: 1, TeamID: 3}, {PlayerID: 1, TeamID: 4}},
+			Resolver:  func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {},
+			ExpectErr: command_model.ValidationError{Err: errors.New("player 1 is transferred twice")},
+		},
+		{
+			Name:  "when_player_not_found",
+			Param: []service.TransferPayload{{PlayerID: 1, TeamID: 3}, {PlayerID: 2, TeamID: 3}},
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+				repo.EXPECT().FindByIDs(gomock.Any(), []int64{1, 2}).
+					Return([]model.PlayerModel{{ID: 1, TeamID: 2}}, nil)
+			},
+			ExpectErr: command_model.ValidationError{Err: errors.New("player 2 not found")},
+		},
+		{
+			Name:  "when_team_not_found",
+			Param: []service.TransferPayload{{PlayerID: 1, TeamID: 3}, {PlayerID: 2, TeamID: 5}},
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+				repo.EXPECT().FindByIDs(gomock.Any(), []int64{1, 2}).
+					Return([]model.PlayerModel{{ID: 1, TeamID: 2}, {ID: 2, TeamID: 4}}, nil)
+				teamRepo.EXPECT().FindByIDsForShare(gomock.Any(), []int64{3, 5}).
+					Return([]team_model.TeamModel{{ID: 3}}, nil)
+			},
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectBegin()
+				db.ExpectRollback()
+			},
+			ExpectErr: command_model.ValidationError{Err: errors.New("team 5 not found")},
+		},
+		{
+			Name:  "when_player_already_in_team",
+			Param: []service.TransferPayload{{PlayerID: 1, TeamID: 3}, {PlayerID: 2, TeamID: 4}},
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+				repo.EXPECT().FindByIDs(gomock.Any(), []int64{1, 2}).
+					Return([]model.PlayerModel{{ID: 1, TeamID: 2}, {ID: 2, TeamID: 4}}, nil)
+			},
+			ExpectErr: command_model.ValidationError{Err: errors.New("player 2 already plays for team 4")},
+		},
+		{
+			Name:  "when_publish_fails",
+			Param: []service.TransferPayload{{PlayerID: 1, TeamID: 3}},
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+				repo.EXPECT().FindByIDs(gomock.Any(), []int64{1}).
+					Return([]model.PlayerModel{{ID: 1, TeamID: 2}}, nil)
+				teamRepo.EXPECT().FindByIDsForShare(gomock.Any(), []int64{3}).
+					Return([]team_model.TeamModel{{ID: 3}}, nil)
+				repo.EXPECT().MoveTeam(gomock.Any(), int64(1), int64(2), int64(3)).Return(true, nil)
+			},
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectBegin()
+				db.ExpectRollback()
+			},
+			Publish:   errors.New("some-error"),
+			ExpectErr: errors.New("some-error"),
+		},
+		{
+			Name:  "when_player_transferred_concurrently",
+			Param: []service.TransferPayload{{PlayerID: 1, TeamID: 3}, {PlayerID: 2, TeamID: 3}},
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+				repo.EXPECT().FindByIDs(gomock.Any(), []int64{1, 2}).
+					Return([]model.PlayerModel{{ID: 1, TeamID: 2}, {ID: 2, TeamID: 4}}, nil)
+				teamRepo.EXPECT().FindByIDsForShare(gomock.Any(), []int64{3}).
+					Return([]team_model.TeamModel{{ID: 3}}, nil)
+				repo.EXPECT().MoveTeam(gomock.Any(), int64(1), int64(2), int64(3)).Return(true, nil)
+				repo.EXPECT().MoveTeam(gomock.Any(), int64(2), int64(4), int64(3)).Return(false, nil)
+			},
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectBegin()
+				db.ExpectRollback()
+			},
+			ExpectErr: command_model.ValidationError{Err: errors.New("player 2 no longer plays for team 4, try again")},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			svc, mock := createService(t, test.Resolver)
+			defer mock.Finish()
+
+			db, dbMock, _ := sqlmock.New()
+			defer db.Close()
+			if test.MockFn != nil {
+				test.MockFn(dbMock)
+			}
+			svc.Db = db
+
+			var published []event_model.Event
+			bus := event_service.NewMockEventBus(mock)
+			bus.EXPECT().Publish(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, events ...event_model.Event) error {
+				published = append(published, events...)
+				return test.Publish
+			}).AnyTimes()
+			svc.EventBus = bus
+
+			actual, err := svc.TransferBatch(context.Background(), test.Param)
+
+			assert.Equal(t, test.ExpectErr, err)
+			assert.Nil(t, dbMock.ExpectationsWereMet())
+			if test.ExpectErr != nil {
+				return
+			}
+
+			assert.NotEmpty(t, actual.CorrelationID)
+			for i := range actual.Transfers {
+				assert.Equal(t, actual.CorrelationID, actual.Transfers[i].CorrelationID)
+				actual.Transfers[i].CorrelationID = ""
+				actual.Transfers[i].TransferredAt = time.Time{}
+			}
+			assert.Equal(t, test.Expect, actual.Transfers)
+
+			assert.Len(t, published, 2*len(test.Param))
+			for i, event := range published {
+				expectType := model.PLAYER_TRANSFER_OUT_EVENT
+				if i%2 == 1 {
+					expectType = model.PLAYER_TRANSFER_IN_EVENT
+				}
+
+				assert.Equal(t, expectType, event.Type)
+				assert.Equal(t, model.PlayerStreamID(test.Param[i/2].PlayerID), event.StreamID)
+				assert.Equal(t, actual.CorrelationID, event.CorrelationID())
+			}
+		})
+	}
+}
+
+func Test_Swap(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Param     service.SwapPayload
+		Resolver  resolverFn
+		Expect    []model.TransferModel
+		ExpectErr error
+	}{
+		{
+			Name:  "when_success",
+			Param: service.SwapPayload{PlayerID: 1, OtherPlayerID: 2},
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+				repo.EXPECT().FindByIDs(gomock.Any(), []int64{1, 2}).
+					Return([]model.PlayerModel{{ID: 1, TeamID: 3}, {ID: 2, TeamID: 4}}, nil)
+				teamRepo.EXPECT().FindByIDsForShare(gomock.Any(), []int64{4, 3}).
+					Return([]team_model.TeamModel{{ID: 3}, {ID: 4}}, nil)
+				repo.EXPECT().MoveTeam(gomock.Any(), int64(1), int64(3), int64(4)).Return(true, nil)
+				repo.EXPECT().MoveTeam(gomock.Any(), int64(2), int64(4), int64(3)).Return(true, nil)
+			},
+			Expect: []model.TransferModel{
+				{PlayerID: 1, FromTeamID: 3, ToTeamID: 4},
+				{PlayerID: 2, FromTeamID: 4, ToTeamID: 3},
+			},
+		},
+		{
+			Name:  "when_same_team",
+			Param: service.SwapPayload{PlayerID: 1, OtherPlayerID: 2},
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+				repo.EXPECT().FindByIDs(gomock.Any(), []int64{1, 2}).
+					Return([]model.PlayerModel{{ID: 1, TeamID: 3}, {ID: 2, TeamID: 3}}, nil)
+			},
+			ExpectErr: command_model.ValidationError{Err: errors.New("players 1 and 2 both play for team 3")},
+		},
+		{
+			Name:  "when_player_not_found",
+			Param: service.SwapPayload{PlayerID: 1, OtherPlayerID: 2},
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+				repo.EXPECT().FindByIDs(gomock.Any(), []int64{1, 2}).
+					Return([]model.PlayerModel{{ID: 2, TeamID: 3}}, nil)
+			},
+			ExpectErr: command_model.ValidationError{Err: errors.New("player 1 not found")},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			svc, mock := createService(t, test.Resolver)
+			defer mock.Finish()
+
+			db, dbMock, _ := sqlmock.New()
+			defer db.Close()
+			dbMock.ExpectBegin()
+			dbMock.ExpectCommit()
+			svc.Db = db
+
+			bus := event_service.NewMockEventBus(mock)
+			bus.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+			svc.EventBus = bus
+
+			actual, err := svc.Swap(context.Background(), test.Param)
+
+			assert.Equal(t, test.ExpectErr, err)
+			if test.ExpectErr != nil {
+				return
+			}
+
+			for i := range actual.Transfers {
+				actual.Transfers[i].CorrelationID = ""
+				actual.Transfers[i].TransferredAt = time.Time{}
+			}
+			assert.Equal(t, test.Expect, actual.Transfers)
+		})
+	}
+}
+
+func Test_Patch(t *testing.T) {
+	mergePatch := func(body string) patch.Patch {
+		return patch.Patch{Type: patch.MIME_MERGE_PATCH, Body: []byte(body)}
+	}
+
+	testCases := []struct {
+		Name        string
+		Patch       patch.Patch
+		Resolver    resolverFn
+		MockFn      func(db sqlmock.Sqlmock)
+		Bus         func(bus *event_service.MockEventBus)
+		Expect      model.PlayerModel
+		ExpectEvent model.UpdatedEventData
+		ExpectErr   string
+	}{
+		{
+			Name:  "when_merge_patch",
+			Patch: mergePatch(`{"name": "jane"}`),
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+				repo.EXPECT().FindByID(gomock.Any(), int64(1)).Return(model.PlayerModel{ID: 1, Name: "joe", TeamID: 2}, nil)
+				repo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(1)).Return(model.PlayerModel{ID: 1, Name: "joe", TeamID: 2}, nil)
+				repo.EXPECT().Update(gomock.Any(), int64(1), map[string]interface{}{"name": "jane"}).
+					Return(model.PlayerModel{ID: 1, Name: "jane", TeamID: 2}, nil)
+			},
+			Expect:      model.PlayerModel{ID: 1, Name: "jane", TeamID: 2},
+			ExpectEvent: model.UpdatedEventData{PlayerID: 1, Fields: []string{"name"}, Player: model.PlayerModel{ID: 1, Name: "jane", TeamID: 2}},
+		},
+		{
+			Name:  "when_json_patch",
+			Patch: patch.Patch{Type: patch.MIME_JSON_PATCH, Body: []byte(`[{"op": "replace", "path": "/name", "value": "jane"}]`)},
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+				repo.EXPECT().FindByID(gomock.Any(), int64(1)).Return(model.PlayerModel{ID: 1, Name: "joe", TeamID: 2}, nil)
+				repo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(1)).Return(model.PlayerModel{ID: 1, Name: "joe", TeamID: 2}, nil)
+				repo.EXPECT().Update(gomock.Any(), int64(1), map[string]interface{}{"name": "jane"}).
+					Return(model.PlayerModel{ID: 1, Name: "jane", TeamID: 2}, nil)
+			},
+			Expect:      model.PlayerModel{ID: 1, Name: "jane", TeamID: 2},
+			ExpectEvent: model.UpdatedEventData{PlayerID: 1, Fields: []string{"name"}, Player: model.PlayerModel{ID: 1, Name: "jane", TeamID: 2}},
+		},
+		{
+			Name:  "when_nothing_changes",
+			Patch: mergePatch(`{"name": "joe"}`),
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+				repo.EXPECT().FindByID(gomock.Any(), int64(1)).Return(model.PlayerModel{ID: 1, Name: "joe", TeamID: 2}, nil)
+				repo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(1)).Return(model.PlayerModel{ID: 1, Name: "joe", TeamID: 2}, nil)
+			},
+			Expect: model.PlayerModel{ID: 1, Name: "joe", TeamID: 2},
+		},
+		{
+			Name:  "when_team_changes",
+			Patch: mergePatch(`{"teamId": 3}`),
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+				repo.EXPECT().FindByID(gomock.Any(), int64(1)).Return(model.PlayerModel{ID: 1, Name: "joe", TeamID: 2}, nil)
+				repo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(1)).Return(model.PlayerModel{ID: 1, Name: "joe", TeamID: 2}, nil)
+			},
+			ExpectErr: "teamId can't be patched, transfer the player instead",
+		},
+		{
+			Name:  "when_id_changes",
+			Patch: mergePatch(`{"id": 3}`),
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+				repo.EXPECT().FindByID(gomock.Any(), int64(1)).Return(model.PlayerModel{ID: 1, Name: "joe", TeamID: 2}, nil)
+				repo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(1)).Return(model.PlayerModel{ID: 1, Name: "joe", TeamID: 2}, nil)
+			},
+			ExpectErr: "id can't be changed",
+		},
+		{
+			Name:  "when_name_is_removed",
+			Patch: mergePatch(`{"name": null}`),
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+				repo.EXPECT().FindByID(gomock.Any(), int64(1)).Return(model.PlayerModel{ID: 1, Name: "joe", TeamID: 2}, nil)
+				repo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(1)).Return(model.PlayerModel{ID: 1, Name: "joe", TeamID: 2}, nil)
+			},
+			ExpectErr: "name is required",
+		},
+		{
+			Name:  "when_field_is_unknown",
+			Patch: mergePatch(`{"age": 30}`),
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+				repo.EXPECT().FindByID(gomock.Any(), int64(1)).Return(model.PlayerModel{ID: 1, Name: "joe", TeamID: 2}, nil)
+				repo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(1)).Return(model.PlayerModel{ID: 1, Name: "joe", TeamID: 2}, nil)
+			},
+			ExpectErr: "invalid patched document: json: unknown field \"age\"",
+		},
+		{
+			Name:  "when_player_not_found",
+			Patch: mergePatch(`{"name": "jane"}`),
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+				repo.EXPECT().FindByID(gomock.Any(), int64(1)).Return(model.PlayerModel{}, sql.ErrNoRows)
+			},
+			MockFn:    func(db sqlmock.Sqlmock) {},
+			ExpectErr: sql.ErrNoRows.Error(),
+		},
+		{
+			Name:  "when_update_fails",
+			Patch: mergePatch(`{"name": "jane"}`),
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+				repo.EXPECT().FindByID(gomock.Any(), int64(1)).Return(model.PlayerModel{ID: 1, Name: "joe", TeamID: 2}, nil)
+				repo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(1)).Return(model.PlayerModel{ID: 1, Name: "joe", TeamID: 2}, nil)
+				repo.EXPECT().Update(gomock.Any(), int64(1), gomock.Any()).Return(model.PlayerModel{}, errors.New("some-error"))
+			},
+			ExpectErr: "some-error",
+		},
+		{
+			Name:  "when_transferred_meanwhile",
+			Patch: mergePatch(`{"name": "jane"}`),
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+				repo.EXPECT().FindByID(gomock.Any(), int64(1)).Return(model.PlayerModel{ID: 1, Name: "joe", TeamID: 2}, nil)
+				repo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(1)).Return(model.PlayerModel{ID: 1, Name: "joe", TeamID: 3}, nil)
+				repo.EXPECT().Update(gomock.Any(), int64(1), map[string]interface{}{"name": "jane"}).
+					Return(model.PlayerModel{ID: 1, Name: "jane", TeamID: 3}, nil)
+			},
+			Expect:      model.PlayerModel{ID: 1, Name: "jane", TeamID: 3},
+			ExpectEvent: model.UpdatedEventData{PlayerID: 1, Fields: []string{"name"}, Player: model.PlayerModel{ID: 1, Name: "jane", TeamID: 3}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			svc, mock := createService(t, test.Resolver)
+			defer mock.Finish()
+
+			db, dbMock, _ := sqlmock.New()
+			defer db.Close()
+			if test.MockFn != nil {
+				test.MockFn(dbMock)
+			} else if test.ExpectErr != "" {
+				dbMock.ExpectBegin()
+				dbMock.ExpectRollback()
+			} else {
+				dbMock.ExpectBegin()
+				dbMock.ExpectCommit()
+			}
+			svc.Db = db
+
+			var published []event_model.Event
+			bus := event_service.NewMockEventBus(mock)
+			bus.EXPECT().Publish(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, events ...event_model.Event) error {
+				published = append(published, events...)
+				return nil
+			}).AnyTimes()
+			svc.EventBus = bus
+
+			actual, err := svc.Patch(context.Background(), 1, test.Patch)
+			assert.Nil(t, dbMock.ExpectationsWereMet())
+
+			if test.ExpectErr != "" {
+				assert.EqualError(t, err, test.ExpectErr)
+				assert.Empty(t, published)
+				return
+			}
 
-		if test.ExpectErr == nil {
-			assert.Equal(t, test.Expect, actual)
 			assert.Nil(t, err)
-		}
+			assert.Equal(t, test.Expect, actual)
 
-		assert.Equal(t, test.ExpectErr, err)
+			if test.ExpectEvent.PlayerID == 0 {
+				assert.Empty(t, published)
+				return
+			}
+
+			assert.Len(t, published, 1)
+			assert.Equal(t, model.PLAYER_UPDATED_EVENT, published[0].Type)
+			assert.Equal(t, model.PlayerStreamID(1), published[0].StreamID)
+
+			var data model.UpdatedEventData
+			assert.Nil(t, json.Unmarshal(published[0].Data, &data))
+			assert.Equal(t, test.ExpectEvent, data)
+		})
+	}
+}
+
+func Test_DryRun(t *testing.T) {
+	t.Run("when_insert", func(t *testing.T) {
+		svc, mock := createService(t, func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+			teamRepo.EXPECT().FindByIDsForShare(gomock.Any(), []int64{2}).Return([]team_model.TeamModel{{ID: 2}}, nil)
+		})
+		defer mock.Finish()
+
+		db, dbMock, _ := sqlmock.New()
+		defer db.Close()
+		dbMock.ExpectBegin()
+		dbMock.ExpectCommit()
+		svc.Db = db
+
+		actual, err := svc.Insert(command_model.WithDryRun(context.Background()), model.PlayerModel{Name: "some-player", TeamID: 2})
+
+		assert.Nil(t, err)
+		assert.Equal(t, model.PlayerModel{Name: "some-player", TeamID: 2}, actual)
+	})
+
+	t.Run("when_transfer", func(t *testing.T) {
+		svc, mock := createService(t, func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+			repo.EXPECT().FindByIDs(gomock.Any(), []int64{1}).Return([]model.PlayerModel{{ID: 1, TeamID: 2}}, nil)
+			teamRepo.EXPECT().FindByIDsForShare(gomock.Any(), []int64{3}).Return([]team_model.TeamModel{{ID: 3}}, nil)
+		})
+		defer mock.Finish()
+
+		db, dbMock, _ := sqlmock.New()
+		defer db.Close()
+		dbMock.ExpectBegin()
+		dbMock.ExpectCommit()
+		svc.Db = db
+
+		err := svc.Transfer(command_model.WithDryRun(context.Background()), service.TransferPayload{PlayerID: 1, TeamID: 3})
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("when_transfer_batch", func(t *testing.T) {
+		svc, mock := createService(t, func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+			repo.EXPECT().FindByIDs(gomock.Any(), []int64{1}).Return([]model.PlayerModel{{ID: 1, TeamID: 2}}, nil)
+			teamRepo.EXPECT().FindByIDsForShare(gomock.Any(), []int64{3}).Return([]team_model.TeamModel{{ID: 3}}, nil)
+		})
+		defer mock.Finish()
+
+		db, dbMock, _ := sqlmock.New()
+		defer db.Close()
+		dbMock.ExpectBegin()
+		dbMock.ExpectCommit()
+		svc.Db = db
+
+		actual, err := svc.TransferBatch(command_model.WithDryRun(context.Background()), []service.TransferPayload{{PlayerID: 1, TeamID: 3}})
+
+		assert.Nil(t, err)
+		assert.Len(t, actual.Transfers, 1)
+	})
+
+	t.Run("when_patch", func(t *testing.T) {
+		svc, mock := createService(t, func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+			repo.EXPECT().FindByID(gomock.Any(), int64(1)).Return(model.PlayerModel{ID: 1, Name: "joe", TeamID: 2}, nil)
+			repo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(1)).Return(model.PlayerModel{ID: 1, Name: "joe", TeamID: 2}, nil)
+		})
+		defer mock.Finish()
+
+		db, dbMock, _ := sqlmock.New()
+		defer db.Close()
+		dbMock.ExpectBegin()
+		dbMock.ExpectCommit()
+		svc.Db = db
+
+		actual, err := svc.Patch(command_model.WithDryRun(context.Background()), 1, patch.Patch{Type: patch.MIME_MERGE_PATCH, Body: []byte(`{"name": "jane"}`)})
+
+		assert.Nil(t, err)
+		assert.Equal(t, model.PlayerModel{ID: 1, Name: "jane", TeamID: 2}, actual)
+	})
+}
+
+func Test_Authorize(t *testing.T) {
+	forbidden := func(action string) error {
+		return auth_model.ForbiddenError{Subject: "some-user", Action: action}
+	}
+
+	testCases := []struct {
+		Name     string
+		Action   string
+		Resource auth_model.Resource
+		Resolver resolverFn
+		Call     func(svc *service.PlayerServiceImpl) error
+	}{
+		{
+			Name:     "when_find_all",
+			Action:   auth_model.ACTION_PLAYER_READ,
+			Resource: auth_model.Resource{},
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {},
+			Call: func(svc *service.PlayerServiceImpl) error {
+				_, err := svc.FindAll(context.Background())
+				return err
+			},
+		},
+		{
+			Name:     "when_find_by_id",
+			Action:   auth_model.ACTION_PLAYER_READ,
+			Resource: auth_model.Resource{TeamID: 2},
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+				repo.EXPECT().FindByID(gomock.Any(), int64(1)).Return(model.PlayerModel{ID: 1, TeamID: 2}, nil)
+			},
+			Call: func(svc *service.PlayerServiceImpl) error {
+				_, err := svc.FindByID(context.Background(), 1)
+				return err
+			},
+		},
+		{
+			Name:     "when_find_by_team_ids",
+			Action:   auth_model.ACTION_PLAYER_READ,
+			Resource: auth_model.Resource{TeamID: 2},
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {},
+			Call: func(svc *service.PlayerServiceImpl) error {
+				_, err := svc.FindByTeamIDs(context.Background(), []int64{2})
+				return err
+			},
+		},
+		{
+			Name:     "when_insert",
+			Action:   auth_model.ACTION_PLAYER_CREATE,
+			Resource: auth_model.Resource{TeamID: 2},
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {},
+			Call: func(svc *service.PlayerServiceImpl) error {
+				_, err := svc.Insert(context.Background(), model.PlayerModel{Name: "some-player", TeamID: 2})
+				return err
+			},
+		},
+		{
+			Name:     "when_transfer",
+			Action:   auth_model.ACTION_PLAYER_TRANSFER,
+			Resource: auth_model.Resource{TeamID: 2},
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+				repo.EXPECT().FindByIDs(gomock.Any(), []int64{1}).Return([]model.PlayerModel{{ID: 1, TeamID: 2}}, nil)
+			},
+			Call: func(svc *service.PlayerServiceImpl) error {
+				return svc.Transfer(context.Background(), service.TransferPayload{PlayerID: 1, TeamID: 3})
+			},
+		},
+		{
+			Name:     "when_swap",
+			Action:   auth_model.ACTION_PLAYER_TRANSFER,
+			Resource: auth_model.Resource{TeamID: 2},
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+				repo.EXPECT().FindByIDs(gomock.Any(), []int64{1, 3}).Return([]model.PlayerModel{{ID: 1, TeamID: 2}, {ID: 3, TeamID: 4}}, nil)
+			},
+			Call: func(svc *service.PlayerServiceImpl) error {
+				_, err := svc.Swap(context.Background(), service.SwapPayload{PlayerID: 1, OtherPlayerID: 3})
+				return err
+			},
+		},
+		{
+			Name:     "when_patch",
+			Action:   auth_model.ACTION_PLAYER_UPDATE,
+			Resource: auth_model.Resource{TeamID: 2},
+			Resolver: func(repo *repository.MockPlayerRepository, teamRepo *team_repository.MockTeamRepository) {
+				repo.EXPECT().FindByID(gomock.Any(), int64(1)).Return(model.PlayerModel{ID: 1, TeamID: 2}, nil)
+			},
+			Call: func(svc *service.PlayerServiceImpl) error {
+				_, err := svc.Patch(context.Background(), 1, patch.Patch{Type: patch.MIME_MERGE_PATCH, Body: []byte(`{"name": "jane"}`)})
+				return err
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := repository.NewMockPlayerRepository(ctrl)
+			teamRepo := team_repository.NewMockTeamRepository(ctrl)
+			test.Resolver(repo, teamRepo)
+
+			authz := auth_service.NewMockAuthorizer(ctrl)
+			authz.EXPECT().Authorize(gomock.Any(), test.Action, test.Resource).Return(forbidden(test.Action))
+
+			svc := &service.PlayerServiceImpl{
+				Repo:     repo,
+				TeamRepo: teamRepo,
+				Authz:    authz,
+			}
+
+			err := test.Call(svc)
+			assert.Equal(t, forbidden(test.Action), err)
+		})
 	}
 }
@@ -0,0 +1,78 @@
+package model
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	STORE_MEMORY   = "memory"
+	STORE_POSTGRES = "postgres"
+
+	CLASS_READ  = "read"
+	CLASS_WRITE = "write"
+	CLASS_IP    = "ip"
+)
+
+// Limit is a token bucket refilled with Rate tokens per second up to Burst
+// tokens, each request takes one token. A zero Rate means no limit.
+type Limit struct {
+	Rate  float64
+	Burst int64
+}
+
+func (l Limit) Unlimited() bool {
+	return l.Rate <= 0
+}
+
+// Bucket holds the tokens left for a key as of UpdatedAt.
+type Bucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+// Take refills the bucket up to at and takes a token out of it when one is
+// left. A zero bucket is a new one and starts full.
+func (b Bucket) Take(limit Limit, at time.Time) (Bucket, bool) {
+	tokens := float64(limit.Burst)
+	if !b.UpdatedAt.IsZero() {
+		elapsed := math.Max(0, at.Sub(b.UpdatedAt).Seconds())
+		tokens = math.Min(tokens, b.Tokens+elapsed*limit.Rate)
+	}
+
+	if tokens < 1 {
+		return Bucket{Tokens: tokens, UpdatedAt: at}, false
+	}
+
+	return Bucket{Tokens: tokens - 1, UpdatedAt: at}, true
+}
+
+// Result is the outcome of a request against a limit, as advertised by the
+// RateLimit-* and Retry-After headers.
+type Result struct {
+	Allowed    bool
+	Limit      int64
+	Remaining  int64
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// NewResult describes a bucket left with the given tokens after a request.
+func NewResult(limit Limit, tokens float64, allowed bool) Result {
+	res := Result{
+		Allowed:   allowed,
+		Limit:     limit.Burst,
+		Remaining: int64(math.Max(0, math.Floor(tokens))),
+		Reset:     seconds((float64(limit.Burst) - tokens) / limit.Rate),
+	}
+
+	if !allowed {
+		res.RetryAfter = seconds((1 - tokens) / limit.Rate)
+	}
+
+	return res
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Max(0, s) * float64(time.Second))
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/tesarwijaya/ouroboros/internal/database"
+	"github.com/tesarwijaya/ouroboros/internal/domain/ratelimit/model"
+	"github.com/tesarwijaya/ouroboros/internal/metrics"
+	"go.uber.org/dig"
+)
+
+const (
+	RATE_LIMIT_BUCKET_TABLE_NAME = "rate_limit_bucket"
+)
+
+type RateLimitRepository interface {
+	Take(ctx context.Context, key string, limit model.Limit) (model.Bucket, bool, error)
+	DeleteStale(ctx context.Context, before time.Time) (int64, error)
+}
+
+type RateLimitRepositoryImpl struct {
+	dig.In
+	Db *sql.DB
+}
+
+func NewRateLimitRepository(repo RateLimitRepositoryImpl) RateLimitRepository {
+	return &repo
+}
+
+// Take refills the bucket of the key and takes a token out of it in a single
+// statement, so that replicas sharing the database share the bucket. Time is
+// read from the database clock to keep replicas with skewed clocks in line.
+func (r *RateLimitRepositoryImpl) Take(ctx context.Context, key string, limit model.Limit) (model.Bucket, bool, error) {
+	defer metrics.ObserveQuery("ratelimit", "Take")()
+
+	var (
+		res     model.Bucket
+		allowed bool
+	)
+
+	q := sqlbuilder.NewInsertBuilder()
+	refill := fmt.Sprintf(
+		"LEAST(%s, %s.tokens + EXTRACT(EPOCH FROM now() - %s.updated_at)::float8 * %s)",
+		q.Var(float64(limit.Burst)), RATE_LIMIT_BUCKET_TABLE_NAME, RATE_LIMIT_BUCKET_TABLE_NAME, q.Var(limit.Rate),
+	)
+	query, args := q.InsertInto(RATE_LIMIT_BUCKET_TABLE_NAME).
+		Cols("key", "tokens", "allowed", "updated_at").
+		Values(key, float64(limit.Burst-1), limit.Burst >= 1, sqlbuilder.Raw("now()")).
+		SQL(fmt.Sprintf("ON CONFLICT (key) DO UPDATE SET tokens = CASE WHEN %[1]s >= 1 THEN %[1]s - 1 ELSE %[1]s END, allowed = %[1]s >= 1, updated_at = now()", refill)).
+		SQL("RETURNING tokens, allowed, updated_at").
+		BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	err := database.QueryRow(ctx, r.Db, query, args...).Scan(&res.Tokens, &allowed, &res.UpdatedAt)
+	if err != nil {
+		return model.Bucket{}, false, err
+	}
+
+	return res, allowed, nil
+}
+
+// DeleteStale deletes the buckets left untouched since before, a deleted
+// bucket starts full again which is what it would have refilled to anyway.
+func (r *RateLimitRepositoryImpl) DeleteStale(ctx context.Context, before time.Time) (int64, error) {
+	defer metrics.ObserveQuery("ratelimit", "DeleteStale")()
+
+	q := sqlbuilder.NewDeleteBuilder()
+	query, args := q.DeleteFrom(RATE_LIMIT_BUCKET_TABLE_NAME).
+		Where(q.LessThan("updated_at", before)).
+		BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	res, err := database.Exec(ctx, r.Db, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
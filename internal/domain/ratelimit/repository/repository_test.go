@@ -0,0 +1,103 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/tesarwijaya/ouroboros/internal/domain/ratelimit/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/ratelimit/repository"
+)
+
+type mockFn func(db sqlmock.Sqlmock)
+
+func createRepo(mockFn mockFn) repository.RateLimitRepository {
+	db, mock, _ := sqlmock.New()
+
+	mockFn(mock)
+	repo := repository.NewRateLimitRepository(repository.RateLimitRepositoryImpl{
+		Db: db,
+	})
+
+	return repo
+}
+
+func Test_Take(t *testing.T) {
+	now := time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)
+	limit := model.Limit{Rate: 2, Burst: 5}
+	query := regexp.QuoteMeta("INSERT INTO rate_limit_bucket (key, tokens, allowed, updated_at) VALUES ($1, $2, $3, now()) " +
+		"ON CONFLICT (key) DO UPDATE SET " +
+		"tokens = CASE WHEN LEAST($4, rate_limit_bucket.tokens + EXTRACT(EPOCH FROM now() - rate_limit_bucket.updated_at)::float8 * $5) >= 1 " +
+		"THEN LEAST($6, rate_limit_bucket.tokens + EXTRACT(EPOCH FROM now() - rate_limit_bucket.updated_at)::float8 * $7) - 1 " +
+		"ELSE LEAST($8, rate_limit_bucket.tokens + EXTRACT(EPOCH FROM now() - rate_limit_bucket.updated_at)::float8 * $9) END, " +
+		"allowed = LEAST($10, rate_limit_bucket.tokens + EXTRACT(EPOCH FROM now() - rate_limit_bucket.updated_at)::float8 * $11) >= 1, " +
+		"updated_at = now() RETURNING tokens, allowed, updated_at")
+
+	testCases := []struct {
+		Name          string
+		MockFn        mockFn
+		Expected      model.Bucket
+		ExpectAllowed bool
+		ExpectedErr   error
+	}{
+		{
+			Name: "when_allowed",
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(query).
+					WithArgs("some-key", float64(4), true, float64(5), float64(2), float64(5), float64(2), float64(5), float64(2), float64(5), float64(2)).
+					WillReturnRows(sqlmock.NewRows([]string{"tokens", "allowed", "updated_at"}).AddRow(float64(3), true, now))
+			},
+			Expected:      model.Bucket{Tokens: 3, UpdatedAt: now},
+			ExpectAllowed: true,
+		},
+		{
+			Name: "when_denied",
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(query).
+					WillReturnRows(sqlmock.NewRows([]string{"tokens", "allowed", "updated_at"}).AddRow(float64(0.5), false, now))
+			},
+			Expected: model.Bucket{Tokens: 0.5, UpdatedAt: now},
+		},
+		{
+			Name: "when_query_failed",
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(query).WillReturnError(errors.New("some-error"))
+			},
+			ExpectedErr: errors.New("some-error"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := createRepo(test.MockFn)
+
+			actual, allowed, err := repo.Take(context.Background(), "some-key", limit)
+			if test.ExpectedErr != nil {
+				assert.EqualError(t, err, test.ExpectedErr.Error())
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.Expected, actual)
+				assert.Equal(t, test.ExpectAllowed, allowed)
+			}
+		})
+	}
+}
+
+func Test_DeleteStale(t *testing.T) {
+	before := time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	repo := createRepo(func(db sqlmock.Sqlmock) {
+		db.ExpectExec(regexp.QuoteMeta("DELETE FROM rate_limit_bucket WHERE updated_at < $1")).
+			WithArgs(before).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+	})
+
+	actual, err := repo.DeleteStale(context.Background(), before)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), actual)
+}
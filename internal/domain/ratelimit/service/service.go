@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tesarwijaya/ouroboros/internal/config"
+	"github.com/tesarwijaya/ouroboros/internal/domain/ratelimit/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/ratelimit/repository"
+	"github.com/tesarwijaya/ouroboros/internal/tracing"
+	"go.uber.org/dig"
+)
+
+type RateLimitService interface {
+	Allow(ctx context.Context, key string, limit model.Limit) (model.Result, error)
+	Purge(ctx context.Context) (int64, error)
+}
+
+// RateLimitServiceImpl keeps the buckets in memory by default, each replica
+// then limits on its own. The postgres store shares them between replicas.
+type RateLimitServiceImpl struct {
+	dig.In `ignore-unexported:"true"`
+	Config *config.Config
+	Repo   repository.RateLimitRepository
+
+	mu      *sync.Mutex
+	buckets map[string]model.Bucket
+}
+
+func NewRateLimitService(svc RateLimitServiceImpl) (RateLimitService, error) {
+	if svc.Config.RateLimitStore != model.STORE_MEMORY && svc.Config.RateLimitStore != model.STORE_POSTGRES {
+		return nil, fmt.Errorf("unknown rate limit store %q", svc.Config.RateLimitStore)
+	}
+
+	svc.mu = &sync.Mutex{}
+	svc.buckets = map[string]model.Bucket{}
+
+	return &svc, nil
+}
+
+// Allow takes a token from the bucket of the key.
+func (s *RateLimitServiceImpl) Allow(ctx context.Context, key string, limit model.Limit) (model.Result, error) {
+	ctx, span := tracing.Start(ctx, "RateLimitService.Allow")
+	defer span.End()
+
+	if s.Config.RateLimitStore == model.STORE_POSTGRES {
+		bucket, allowed, err := s.Repo.Take(ctx, key, limit)
+		if err != nil {
+			return model.Result{}, err
+		}
+
+		return model.NewResult(limit, bucket.Tokens, allowed), nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bucket, allowed := s.buckets[key].Take(limit, time.Now())
+	s.buckets[key] = bucket
+
+	return model.NewResult(limit, bucket.Tokens, allowed), nil
+}
+
+// Purge deletes the buckets untouched for a purge interval and reports how
+// many were deleted.
+func (s *RateLimitServiceImpl) Purge(ctx context.Context) (int64, error) {
+	ctx, span := tracing.Start(ctx, "RateLimitService.Purge")
+	defer span.End()
+
+	before := time.Now().Add(-s.Config.RateLimitPurgeInterval)
+	if s.Config.RateLimitStore == model.STORE_POSTGRES {
+		return s.Repo.DeleteStale(ctx, before)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var purged int64
+	for key, bucket := range s.buckets {
+		if bucket.UpdatedAt.Before(before) {
+			delete(s.buckets, key)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
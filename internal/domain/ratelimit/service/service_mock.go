@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service/service.go
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/tesarwijaya/ouroboros/internal/domain/ratelimit/model"
+)
+
+// MockRateLimitService is a mock of RateLimitService interface.
+type MockRateLimitService struct {
+	ctrl     *gomock.Controller
+	recorder *MockRateLimitServiceMockRecorder
+}
+
+// MockRateLimitServiceMockRecorder is the mock recorder for MockRateLimitService.
+type MockRateLimitServiceMockRecorder struct {
+	mock *MockRateLimitService
+}
+
+// NewMockRateLimitService creates a new mock instance.
+func NewMockRateLimitService(ctrl *gomock.Controller) *MockRateLimitService {
+	mock := &MockRateLimitService{ctrl: ctrl}
+	mock.recorder = &MockRateLimitServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRateLimitService) EXPECT() *MockRateLimitServiceMockRecorder {
+	return m.recorder
+}
+
+// Allow mocks base method.
+func (m *MockRateLimitService) Allow(ctx context.Context, key string, limit model.Limit) (model.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Allow", ctx, key, limit)
+	ret0, _ := ret[0].(model.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Allow indicates an expected call of Allow.
+func (mr *MockRateLimitServiceMockRecorder) Allow(ctx, key, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Allow", reflect.TypeOf((*MockRateLimitService)(nil).Allow), ctx, key, limit)
+}
+
+// Purge mocks base method.
+func (m *MockRateLimitService) Purge(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockRateLimitServiceMockRecorder) Purge(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockRateLimitService)(nil).Purge), ctx)
+}
@@ -0,0 +1,120 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/tesarwijaya/ouroboros/internal/config"
+	"github.com/tesarwijaya/ouroboros/internal/domain/ratelimit/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/ratelimit/repository"
+	"github.com/tesarwijaya/ouroboros/internal/domain/ratelimit/service"
+)
+
+type resolverFn func(repo *repository.MockRateLimitRepository)
+
+func createService(t *testing.T, store string, resolver resolverFn) service.RateLimitService {
+	ctrl := gomock.NewController(t)
+
+	repo := repository.NewMockRateLimitRepository(ctrl)
+	resolver(repo)
+
+	svc, err := service.NewRateLimitService(service.RateLimitServiceImpl{
+		Config: &config.Config{RateLimitStore: store, RateLimitPurgeInterval: time.Hour},
+		Repo:   repo,
+	})
+	assert.Nil(t, err)
+
+	return svc
+}
+
+func Test_NewRateLimitService(t *testing.T) {
+	_, err := service.NewRateLimitService(service.RateLimitServiceImpl{
+		Config: &config.Config{RateLimitStore: "redis"},
+	})
+
+	assert.EqualError(t, err, `unknown rate limit store "redis"`)
+}
+
+func Test_Allow(t *testing.T) {
+	limit := model.Limit{Rate: 1, Burst: 2}
+
+	t.Run("when_memory_store", func(t *testing.T) {
+		svc := createService(t, model.STORE_MEMORY, func(repo *repository.MockRateLimitRepository) {})
+
+		var results []model.Result
+		for i := 0; i < 3; i++ {
+			res, err := svc.Allow(context.Background(), "some-key", limit)
+			assert.Nil(t, err)
+			results = append(results, res)
+		}
+
+		assert.True(t, results[0].Allowed)
+		assert.Equal(t, int64(1), results[0].Remaining)
+		assert.True(t, results[1].Allowed)
+		assert.Equal(t, int64(0), results[1].Remaining)
+		assert.False(t, results[2].Allowed)
+		assert.InDelta(t, time.Second, results[2].RetryAfter, float64(100*time.Millisecond))
+
+		res, err := svc.Allow(context.Background(), "other-key", limit)
+		assert.Nil(t, err)
+		assert.True(t, res.Allowed)
+	})
+
+	t.Run("when_postgres_store", func(t *testing.T) {
+		svc := createService(t, model.STORE_POSTGRES, func(repo *repository.MockRateLimitRepository) {
+			repo.EXPECT().Take(gomock.Any(), "some-key", limit).Return(model.Bucket{Tokens: 0.5}, false, nil)
+		})
+
+		res, err := svc.Allow(context.Background(), "some-key", limit)
+
+		assert.Nil(t, err)
+		assert.Equal(t, model.Result{
+			Allowed:    false,
+			Limit:      2,
+			Remaining:  0,
+			Reset:      1500 * time.Millisecond,
+			RetryAfter: 500 * time.Millisecond,
+		}, res)
+	})
+
+	t.Run("when_postgres_store_failed", func(t *testing.T) {
+		svc := createService(t, model.STORE_POSTGRES, func(repo *repository.MockRateLimitRepository) {
+			repo.EXPECT().Take(gomock.Any(), "some-key", limit).Return(model.Bucket{}, false, errors.New("some-error"))
+		})
+
+		_, err := svc.Allow(context.Background(), "some-key", limit)
+
+		assert.EqualError(t, err, "some-error")
+	})
+}
+
+func Test_Purge(t *testing.T) {
+	t.Run("when_memory_store", func(t *testing.T) {
+		svc := createService(t, model.STORE_MEMORY, func(repo *repository.MockRateLimitRepository) {})
+
+		_, err := svc.Allow(context.Background(), "some-key", model.Limit{Rate: 1, Burst: 1})
+		assert.Nil(t, err)
+
+		purged, err := svc.Purge(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, int64(0), purged)
+	})
+
+	t.Run("when_postgres_store", func(t *testing.T) {
+		svc := createService(t, model.STORE_POSTGRES, func(repo *repository.MockRateLimitRepository) {
+			repo.EXPECT().DeleteStale(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, before time.Time) (int64, error) {
+				assert.WithinDuration(t, time.Now().Add(-time.Hour), before, time.Minute)
+
+				return 3, nil
+			})
+		})
+
+		purged, err := svc.Purge(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), purged)
+	})
+}
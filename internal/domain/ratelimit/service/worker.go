@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+
+	"github.com/tesarwijaya/ouroboros/internal/config"
+	"github.com/tesarwijaya/ouroboros/internal/logger"
+	"github.com/tesarwijaya/ouroboros/internal/worker"
+	"go.uber.org/zap"
+)
+
+// NewPurgeWorker periodically deletes the idle rate limit buckets.
+func NewPurgeWorker(cfg *config.Config, svc RateLimitService) worker.Worker {
+	return worker.Worker{
+		Name:     "rate_limit_purge",
+		Interval: cfg.RateLimitPurgeInterval,
+		Run: func(ctx context.Context) error {
+			purged, err := svc.Purge(ctx)
+			if err != nil {
+				return err
+			}
+
+			if purged > 0 {
+				logger.FromContext(ctx).Info("purged idle rate limit buckets", zap.Int64("count", purged))
+			}
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+
+	event_model "github.com/tesarwijaya/ouroboros/internal/domain/event/model"
+)
+
+type SnapshotModel struct {
+	StreamID  string    `db:"stream_id" json:"streamId"`
+	Version   uint64    `db:"version" json:"version"`
+	Data      []byte    `db:"data" json:"data"`
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+}
+
+// Aggregate is a state that can be rebuilt by replaying the events of its
+// stream, its JSON representation is what gets stored as a snapshot.
+type Aggregate interface {
+	StreamID() string
+	Apply(event event_model.Event) error
+}
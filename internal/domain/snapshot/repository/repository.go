@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/tesarwijaya/ouroboros/internal/database"
+	"github.com/tesarwijaya/ouroboros/internal/domain/snapshot/model"
+	"github.com/tesarwijaya/ouroboros/internal/metrics"
+	"go.uber.org/dig"
+)
+
+const (
+	SNAPSHOT_TABLE_NAME = "snapshot"
+)
+
+type SnapshotRepository interface {
+	FindLatest(ctx context.Context, streamID string) (model.SnapshotModel, error)
+	Insert(ctx context.Context, payload model.SnapshotModel) error
+	Delete(ctx context.Context, streamID string) error
+	DeleteAll(ctx context.Context) error
+}
+
+type SnapshotRepositoryImpl struct {
+	dig.In
+	Db *sql.DB
+}
+
+func NewSnapshotRepository(repo SnapshotRepositoryImpl) SnapshotRepository {
+	return &repo
+}
+
+func (r *SnapshotRepositoryImpl) FindLatest(ctx context.Context, streamID string) (model.SnapshotModel, error) {
+	defer metrics.ObserveQuery("snapshot", "FindLatest")()
+
+	var res model.SnapshotModel
+	q := sqlbuilder.NewSelectBuilder()
+	query, args := q.Select("stream_id", "version", "data", "created_at").
+		From(SNAPSHOT_TABLE_NAME).
+		Where(q.Equal("stream_id", streamID)).
+		OrderBy("version").Desc().
+		Limit(1).
+		BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	row := database.QueryRow(ctx, r.Db, query, args...)
+	if err := row.Err(); err != nil {
+		return model.SnapshotModel{}, err
+	}
+
+	if err := row.Scan(
+		&res.StreamID,
+		&res.Version,
+		&res.Data,
+		&res.CreatedAt,
+	); err != nil {
+		return model.SnapshotModel{}, err
+	}
+
+	return res, nil
+}
+
+func (r *SnapshotRepositoryImpl) Insert(ctx context.Context, payload model.SnapshotModel) error {
+	defer metrics.ObserveQuery("snapshot", "Insert")()
+
+	q := sqlbuilder.NewInsertBuilder()
+	query, args := q.InsertInto(SNAPSHOT_TABLE_NAME).
+		Cols("stream_id", "version", "data").
+		Values(payload.StreamID, payload.Version, payload.Data).
+		SQL("ON CONFLICT (stream_id, version) DO UPDATE SET data = EXCLUDED.data").
+		BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	_, err := database.Exec(ctx, r.Db, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *SnapshotRepositoryImpl) Delete(ctx context.Context, streamID string) error {
+	defer metrics.ObserveQuery("snapshot", "Delete")()
+
+	q := sqlbuilder.NewDeleteBuilder()
+	query, args := q.DeleteFrom(SNAPSHOT_TABLE_NAME).
+		Where(q.Equal("stream_id", streamID)).
+		BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	_, err := database.Exec(ctx, r.Db, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *SnapshotRepositoryImpl) DeleteAll(ctx context.Context) error {
+	defer metrics.ObserveQuery("snapshot", "DeleteAll")()
+
+	query, args := sqlbuilder.NewDeleteBuilder().
+		DeleteFrom(SNAPSHOT_TABLE_NAME).
+		BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	_, err := database.Exec(ctx, r.Db, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/tesarwijaya/ouroboros/internal/domain/snapshot/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/snapshot/repository"
+)
+
+type mockFn func(db sqlmock.Sqlmock)
+
+func createRepo(mockFn mockFn) repository.SnapshotRepository {
+	db, mock, _ := sqlmock.New()
+
+	mockFn(mock)
+	repo := repository.NewSnapshotRepository(repository.SnapshotRepositoryImpl{
+		Db: db,
+	})
+
+	return repo
+}
+
+func Test_FindLatest(t *testing.T) {
+	createdAt := time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		Name      string
+		Param     string
+		mockFn    mockFn
+		Expect    model.SnapshotModel
+		ExpectErr error
+	}{
+		{
+			Name:  "when_success",
+			Param: "player-1",
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(regexp.QuoteMeta("SELECT stream_id, version, data, created_at FROM snapshot WHERE stream_id = $1 ORDER BY version DESC LIMIT 1")).
+					WithArgs("player-1").
+					WillReturnRows(
+						sqlmock.NewRows([]string{"stream_id", "version", "data", "created_at"}).
+							AddRow("player-1", uint64(10), []byte(`{"id":1}`), createdAt),
+					)
+			},
+			Expect: model.SnapshotModel{
+				StreamID:  "player-1",
+				Version:   10,
+				Data:      []byte(`{"id":1}`),
+				CreatedAt: createdAt,
+			},
+		},
+		{
+			Name:  "when_not_found",
+			Param: "player-1",
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(regexp.QuoteMeta("SELECT stream_id, version, data, created_at FROM snapshot WHERE stream_id = $1 ORDER BY version DESC LIMIT 1")).
+					WithArgs("player-1").
+					WillReturnRows(sqlmock.NewRows([]string{"stream_id", "version", "data", "created_at"}))
+			},
+			ExpectErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := createRepo(test.mockFn)
+
+			actual, err := repo.FindLatest(context.Background(), test.Param)
+			if test.ExpectErr == nil {
+				assert.Equal(t, test.Expect, actual)
+				assert.Nil(t, err)
+			}
+
+			assert.Equal(t, test.ExpectErr, err)
+		})
+	}
+}
+
+func Test_Insert(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Param     model.SnapshotModel
+		mockFn    mockFn
+		ExpectErr error
+	}{
+		{
+			Name: "when_success",
+			Param: model.SnapshotModel{
+				StreamID: "player-1",
+				Version:  10,
+				Data:     []byte(`{"id":1}`),
+			},
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectExec(regexp.QuoteMeta("INSERT INTO snapshot (stream_id, version, data) VALUES ($1, $2, $3) ON CONFLICT (stream_id, version) DO UPDATE SET data = EXCLUDED.data")).
+					WithArgs("player-1", uint64(10), []byte(`{"id":1}`)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := createRepo(test.mockFn)
+
+			err := repo.Insert(context.Background(), test.Param)
+
+			assert.Equal(t, test.ExpectErr, err)
+		})
+	}
+}
+
+func Test_Delete(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Param     string
+		mockFn    mockFn
+		ExpectErr error
+	}{
+		{
+			Name:  "when_success",
+			Param: "player-1",
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectExec(regexp.QuoteMeta("DELETE FROM snapshot WHERE stream_id = $1")).
+					WithArgs("player-1").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := createRepo(test.mockFn)
+
+			err := repo.Delete(context.Background(), test.Param)
+
+			assert.Equal(t, test.ExpectErr, err)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/tesarwijaya/ouroboros/internal/config"
+	"github.com/tesarwijaya/ouroboros/internal/database"
+	event_repository "github.com/tesarwijaya/ouroboros/internal/domain/event/repository"
+	"github.com/tesarwijaya/ouroboros/internal/domain/snapshot/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/snapshot/repository"
+	"github.com/tesarwijaya/ouroboros/internal/tracing"
+	"go.uber.org/dig"
+)
+
+type SnapshotService interface {
+	Load(ctx context.Context, aggregate model.Aggregate) error
+	Rebuild(ctx context.Context, aggregate model.Aggregate) error
+	Delete(ctx context.Context, streamID string) error
+	DeleteAll(ctx context.Context) error
+}
+
+type SnapshotServiceImpl struct {
+	dig.In
+	Db        *sql.DB
+	Config    *config.Config
+	Repo      repository.SnapshotRepository
+	EventRepo event_repository.EventRepository
+}
+
+func NewSnapshotService(svc SnapshotServiceImpl) SnapshotService {
+	return &svc
+}
+
+// Load restores the aggregate from its latest snapshot and replays only the
+// events appended after it. A new snapshot is taken once the number of
+// replayed events reaches the configured frequency.
+func (s *SnapshotServiceImpl) Load(ctx context.Context, aggregate model.Aggregate) error {
+	ctx, span := tracing.Start(ctx, "SnapshotService.Load")
+	defer span.End()
+
+	var from uint64
+
+	snapshot, err := s.Repo.FindLatest(ctx, aggregate.StreamID())
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	if err == nil {
+		if err := json.Unmarshal(snapshot.Data, aggregate); err != nil {
+			return err
+		}
+
+		from = snapshot.Version + 1
+	}
+
+	events, err := s.EventRepo.ReadStream(ctx, aggregate.StreamID(), from)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		if err := aggregate.Apply(event); err != nil {
+			return err
+		}
+	}
+
+	if len(events) == 0 || s.Config.SnapshotFrequency <= 0 || int64(len(events)) < s.Config.SnapshotFrequency {
+		return nil
+	}
+
+	return s.save(ctx, aggregate, events[len(events)-1].Version)
+}
+
+// Rebuild replays the whole aggregate stream and replaces its snapshots with
+// a fresh one at its last version. The stored snapshots are only dropped once
+// the replay succeeded, so a failed rebuild keeps them.
+func (s *SnapshotServiceImpl) Rebuild(ctx context.Context, aggregate model.Aggregate) error {
+	ctx, span := tracing.Start(ctx, "SnapshotService.Rebuild")
+	defer span.End()
+
+	events, err := s.EventRepo.ReadStream(ctx, aggregate.StreamID(), 0)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		if err := aggregate.Apply(event); err != nil {
+			return err
+		}
+	}
+
+	return database.WithTx(ctx, s.Db, func(ctx context.Context) error {
+		if err := s.Repo.Delete(ctx, aggregate.StreamID()); err != nil {
+			return err
+		}
+
+		if len(events) == 0 {
+			return nil
+		}
+
+		return s.save(ctx, aggregate, events[len(events)-1].Version)
+	})
+}
+
+func (s *SnapshotServiceImpl) Delete(ctx context.Context, streamID string) error {
+	ctx, span := tracing.Start(ctx, "SnapshotService.Delete")
+	defer span.End()
+
+	return s.Repo.Delete(ctx, streamID)
+}
+
+func (s *SnapshotServiceImpl) DeleteAll(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "SnapshotService.DeleteAll")
+	defer span.End()
+
+	return s.Repo.DeleteAll(ctx)
+}
+
+func (s *SnapshotServiceImpl) save(ctx context.Context, aggregate model.Aggregate, version uint64) error {
+	data, err := json.Marshal(aggregate)
+	if err != nil {
+		return err
+	}
+
+	return s.Repo.Insert(ctx, model.SnapshotModel{
+		StreamID: aggregate.StreamID(),
+		Version:  version,
+		Data:     data,
+	})
+}
@@ -0,0 +1,92 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/snapshot/service/service.go
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/tesarwijaya/ouroboros/internal/domain/snapshot/model"
+)
+
+// MockSnapshotService is a mock of SnapshotService interface.
+type MockSnapshotService struct {
+	ctrl     *gomock.Controller
+	recorder *MockSnapshotServiceMockRecorder
+}
+
+// MockSnapshotServiceMockRecorder is the mock recorder for MockSnapshotService.
+type MockSnapshotServiceMockRecorder struct {
+	mock *MockSnapshotService
+}
+
+// NewMockSnapshotService creates a new mock instance.
+func NewMockSnapshotService(ctrl *gomock.Controller) *MockSnapshotService {
+	mock := &MockSnapshotService{ctrl: ctrl}
+	mock.recorder = &MockSnapshotServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSnapshotService) EXPECT() *MockSnapshotServiceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockSnapshotService) Delete(ctx context.Context, streamID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, streamID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockSnapshotServiceMockRecorder) Delete(ctx, streamID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSnapshotService)(nil).Delete), ctx, streamID)
+}
+
+// DeleteAll mocks base method.
+func (m *MockSnapshotService) DeleteAll(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAll", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAll indicates an expected call of DeleteAll.
+func (mr *MockSnapshotServiceMockRecorder) DeleteAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAll", reflect.TypeOf((*MockSnapshotService)(nil).DeleteAll), ctx)
+}
+
+// Load mocks base method.
+func (m *MockSnapshotService) Load(ctx context.Context, aggregate model.Aggregate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Load", ctx, aggregate)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Load indicates an expected call of Load.
+func (mr *MockSnapshotServiceMockRecorder) Load(ctx, aggregate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Load", reflect.TypeOf((*MockSnapshotService)(nil).Load), ctx, aggregate)
+}
+
+// Rebuild mocks base method.
+func (m *MockSnapshotService) Rebuild(ctx context.Context, aggregate model.Aggregate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rebuild", ctx, aggregate)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rebuild indicates an expected call of Rebuild.
+func (mr *MockSnapshotServiceMockRecorder) Rebuild(ctx, aggregate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rebuild", reflect.TypeOf((*MockSnapshotService)(nil).Rebuild), ctx, aggregate)
+}
@@ -0,0 +1,192 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/tesarwijaya/ouroboros/internal/config"
+	event_model "github.com/tesarwijaya/ouroboros/internal/domain/event/model"
+	event_repository "github.com/tesarwijaya/ouroboros/internal/domain/event/repository"
+	player_model "github.com/tesarwijaya/ouroboros/internal/domain/player/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/snapshot/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/snapshot/repository"
+	"github.com/tesarwijaya/ouroboros/internal/domain/snapshot/service"
+)
+
+type resolverFn func(repo *repository.MockSnapshotRepository, eventRepo *event_repository.MockEventRepository)
+
+func createService(t *testing.T, frequency int64, resolver resolverFn) (*service.SnapshotServiceImpl, *gomock.Controller) {
+	ctrl := gomock.NewController(t)
+
+	repo := repository.NewMockSnapshotRepository(ctrl)
+	eventRepo := event_repository.NewMockEventRepository(ctrl)
+	resolver(repo, eventRepo)
+
+	return &service.SnapshotServiceImpl{
+		Config:    &config.Config{SnapshotFrequency: frequency},
+		Repo:      repo,
+		EventRepo: eventRepo,
+	}, ctrl
+}
+
+func transferIn(version uint64, teamID int64) event_model.Event {
+	return event_model.Event{
+		StreamID: "player-1",
+		Version:  version,
+		Type:     player_model.PLAYER_TRANSFER_IN_EVENT,
+		Data:     []byte(fmt.Sprintf(`{"PlayerID":1,"TeamID":%d}`, teamID)),
+	}
+}
+
+func Test_NewSnapshotService(t *testing.T) {
+	svc := service.NewSnapshotService(service.SnapshotServiceImpl{})
+
+	assert.Implements(t, (*service.SnapshotService)(nil), svc)
+}
+
+func Test_Load(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Frequency int64
+		Resolver  resolverFn
+		Expect    player_model.PlayerAggregate
+		ExpectErr error
+	}{
+		{
+			Name:      "when_no_snapshot",
+			Frequency: 100,
+			Resolver: func(repo *repository.MockSnapshotRepository, eventRepo *event_repository.MockEventRepository) {
+				repo.EXPECT().FindLatest(gomock.Any(), "player-1").
+					Return(model.SnapshotModel{}, sql.ErrNoRows)
+				eventRepo.EXPECT().ReadStream(gomock.Any(), "player-1", uint64(0)).
+					Return([]event_model.Event{transferIn(1, 2)}, nil)
+			},
+			Expect: player_model.PlayerAggregate{PlayerModel: player_model.PlayerModel{ID: 1, TeamID: 2}},
+		},
+		{
+			Name:      "when_snapshot_exists",
+			Frequency: 100,
+			Resolver: func(repo *repository.MockSnapshotRepository, eventRepo *event_repository.MockEventRepository) {
+				repo.EXPECT().FindLatest(gomock.Any(), "player-1").
+					Return(model.SnapshotModel{StreamID: "player-1", Version: 9, Data: []byte(`{"id":1,"name":"some-player-name","teamId":3}`)}, nil)
+				eventRepo.EXPECT().ReadStream(gomock.Any(), "player-1", uint64(10)).
+					Return([]event_model.Event{}, nil)
+			},
+			Expect: player_model.PlayerAggregate{PlayerModel: player_model.PlayerModel{ID: 1, Name: "some-player-name", TeamID: 3}},
+		},
+		{
+			Name:      "when_frequency_reached",
+			Frequency: 2,
+			Resolver: func(repo *repository.MockSnapshotRepository, eventRepo *event_repository.MockEventRepository) {
+				repo.EXPECT().FindLatest(gomock.Any(), "player-1").
+					Return(model.SnapshotModel{}, sql.ErrNoRows)
+				eventRepo.EXPECT().ReadStream(gomock.Any(), "player-1", uint64(0)).
+					Return([]event_model.Event{transferIn(0, 2), transferIn(1, 4)}, nil)
+				repo.EXPECT().Insert(gomock.Any(), model.SnapshotModel{
+					StreamID: "player-1",
+					Version:  1,
+					Data:     []byte(`{"id":1,"teamId":4}`),
+				}).Return(nil)
+			},
+			Expect: player_model.PlayerAggregate{PlayerModel: player_model.PlayerModel{ID: 1, TeamID: 4}},
+		},
+		{
+			Name:      "when_snapshot_lookup_fails",
+			Frequency: 100,
+			Resolver: func(repo *repository.MockSnapshotRepository, eventRepo *event_repository.MockEventRepository) {
+				repo.EXPECT().FindLatest(gomock.Any(), "player-1").
+					Return(model.SnapshotModel{}, errors.New("some-error"))
+			},
+			ExpectErr: errors.New("some-error"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			svc, mock := createService(t, test.Frequency, test.Resolver)
+			defer mock.Finish()
+
+			aggregate := player_model.NewPlayerAggregate(1)
+			err := svc.Load(context.Background(), aggregate)
+
+			if test.ExpectErr == nil {
+				assert.Equal(t, test.Expect, *aggregate)
+				assert.Nil(t, err)
+			}
+
+			assert.Equal(t, test.ExpectErr, err)
+		})
+	}
+}
+
+func Test_Rebuild(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Resolver  resolverFn
+		MockFn    func(db sqlmock.Sqlmock)
+		ExpectErr error
+	}{
+		{
+			Name: "when_success",
+			Resolver: func(repo *repository.MockSnapshotRepository, eventRepo *event_repository.MockEventRepository) {
+				eventRepo.EXPECT().ReadStream(gomock.Any(), "player-1", uint64(0)).
+					Return([]event_model.Event{transferIn(0, 2)}, nil)
+				repo.EXPECT().Delete(gomock.Any(), "player-1").Return(nil)
+				repo.EXPECT().Insert(gomock.Any(), model.SnapshotModel{
+					StreamID: "player-1",
+					Version:  0,
+					Data:     []byte(`{"id":1,"teamId":2}`),
+				}).Return(nil)
+			},
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectBegin()
+				db.ExpectCommit()
+			},
+		},
+		{
+			Name: "when_replay_fails",
+			Resolver: func(repo *repository.MockSnapshotRepository, eventRepo *event_repository.MockEventRepository) {
+				eventRepo.EXPECT().ReadStream(gomock.Any(), "player-1", uint64(0)).
+					Return([]event_model.Event{}, errors.New("some-error"))
+			},
+			MockFn:    func(db sqlmock.Sqlmock) {},
+			ExpectErr: errors.New("some-error"),
+		},
+		{
+			Name: "when_insert_fails",
+			Resolver: func(repo *repository.MockSnapshotRepository, eventRepo *event_repository.MockEventRepository) {
+				eventRepo.EXPECT().ReadStream(gomock.Any(), "player-1", uint64(0)).
+					Return([]event_model.Event{transferIn(0, 2)}, nil)
+				repo.EXPECT().Delete(gomock.Any(), "player-1").Return(nil)
+				repo.EXPECT().Insert(gomock.Any(), gomock.Any()).Return(errors.New("some-error"))
+			},
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectBegin()
+				db.ExpectRollback()
+			},
+			ExpectErr: errors.New("some-error"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			svc, mock := createService(t, 100, test.Resolver)
+			defer mock.Finish()
+
+			db, dbMock, _ := sqlmock.New()
+			test.MockFn(dbMock)
+			svc.Db = db
+
+			err := svc.Rebuild(context.Background(), player_model.NewPlayerAggregate(1))
+
+			assert.Equal(t, test.ExpectErr, err)
+			assert.Nil(t, dbMock.ExpectationsWereMet())
+		})
+	}
+}
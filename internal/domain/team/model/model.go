@@ -1,15 +1,91 @@
 package model
 
 import (
+	"errors"
+	"fmt"
+
 	player_model "github.com/tesarwijaya/ouroboros/internal/domain/player/model"
 )
 
+const (
+	TEAM_STREAM_PREFIX = "team-"
+
+	TEAM_UPDATED_EVENT = "team_updated"
+	TEAM_DELETED_EVENT = "team_deleted"
+)
+
+var ErrTeamHasPlayers = errors.New("team still has players, transfer them first")
+
 type TeamModel struct {
 	ID   int64  `json:"id,omitempty"`
 	Name string `json:"name,omitempty"`
 }
 
+// TEAM_COLUMNS maps the JSON fields of a team to their column.
+var TEAM_COLUMNS = map[string]string{
+	"id":   "id",
+	"name": "name",
+}
+
+// TeamLookupRespModel lists the teams found by id, with the requested fields
+// only, and the ids no team has.
+type TeamLookupRespModel struct {
+	Items   []map[string]interface{} `json:"items"`
+	Missing []int64                  `json:"missing"`
+}
+
+// Sparse returns the given JSON fields of the team only, every field when
+// fields is empty.
+func (t TeamModel) Sparse(fields []string) map[string]interface{} {
+	values := map[string]interface{}{
+		"id":   t.ID,
+		"name": t.Name,
+	}
+	if len(fields) == 0 {
+		return values
+	}
+
+	res := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		res[field] = values[field]
+	}
+
+	return res
+}
+
+func TeamStreamID(id int64) string {
+	return fmt.Sprintf("%s%d", TEAM_STREAM_PREFIX, id)
+}
+
+// UpdatedEventData lists the JSON fields of the team a patch changed, along
+// the team once updated.
+type UpdatedEventData struct {
+	TeamID int64
+	Fields []string
+	Team   TeamModel
+}
+
+// DeletedEventData is the team as it was when deleted.
+type DeletedEventData struct {
+	TeamID int64
+	Team   TeamModel
+}
+
 type TeamPlayerRespModel struct {
 	TeamModel
 	Players []player_model.PlayerModel
 }
+
+// TeamWithPlayersRespModel is a team responded by include=players, its players
+// are keyed in lower case like its other fields.
+type TeamWithPlayersRespModel struct {
+	TeamModel
+	Players []player_model.PlayerModel `json:"players"`
+}
+
+// TeamPlayerLookupRespModel lists the teams found by id with their players,
+// and the ids no team has.
+type TeamPlayerLookupRespModel struct {
+	Items   []TeamWithPlayersRespModel `json:"items"`
+	Missing []int64                    `json:"missing"`
+}
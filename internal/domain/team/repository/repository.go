@@ -3,9 +3,13 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"sort"
 
 	"github.com/huandu/go-sqlbuilder"
+	"github.com/tesarwijaya/ouroboros/internal/database"
 	"github.com/tesarwijaya/ouroboros/internal/domain/team/model"
+	"github.com/tesarwijaya/ouroboros/internal/metrics"
 	"go.uber.org/dig"
 )
 
@@ -16,7 +20,16 @@ const (
 type TeamRepository interface {
 	FindAll(ctx context.Context) ([]model.TeamModel, error)
 	FindByID(ctx context.Context, id int64) (model.TeamModel, error)
-	Insert(ctx context.Context, payload model.TeamModel) error
+	FindByIDForUpdate(ctx context.Context, id int64) (model.TeamModel, error)
+	FindByIDs(ctx context.Context, ids []int64) ([]model.TeamModel, error)
+	FindByIDsForShare(ctx context.Context, ids []int64) ([]model.TeamModel, error)
+	FindByNames(ctx context.Context, names []string) ([]model.TeamModel, error)
+	StreamAll(ctx context.Context, fn func(model.TeamModel) error) error
+	FindFields(ctx context.Context, ids []int64, columns []string) ([]model.TeamModel, error)
+	Insert(ctx context.Context, payload model.TeamModel) (model.TeamModel, error)
+	UpdateName(ctx context.Context, id int64, name string) (model.TeamModel, error)
+	Update(ctx context.Context, id int64, values map[string]interface{}) (model.TeamModel, error)
+	Delete(ctx context.Context, id int64) error
 }
 
 type TeamRepositoryImpl struct {
@@ -29,11 +42,13 @@ func NewTeamReposity(repo TeamRepositoryImpl) TeamRepository {
 }
 
 func (r *TeamRepositoryImpl) FindAll(ctx context.Context) ([]model.TeamModel, error) {
+	defer metrics.ObserveQuery("team", "FindAll")()
+
 	var res []model.TeamModel
 	q := sqlbuilder.NewSelectBuilder()
 	query, _ := q.Select("*").From(TEAM_TABLE_NAME).BuildWithFlavor(sqlbuilder.PostgreSQL)
 
-	rows, err := r.Db.Query(query)
+	rows, err := database.Query(ctx, r.Db, query)
 	if err != nil {
 		return []model.TeamModel{}, err
 	}
@@ -60,11 +75,30 @@ func (r *TeamRepositoryImpl) FindAll(ctx context.Context) ([]model.TeamModel, er
 }
 
 func (r *TeamRepositoryImpl) FindByID(ctx context.Context, id int64) (model.TeamModel, error) {
-	var res model.TeamModel
+	defer metrics.ObserveQuery("team", "FindByID")()
+
 	q := sqlbuilder.NewSelectBuilder()
-	query, args := q.Select("*").From(TEAM_TABLE_NAME).Where(q.Equal("id", id)).BuildWithFlavor(sqlbuilder.PostgreSQL)
+	q.Select("*").From(TEAM_TABLE_NAME).Where(q.Equal("id", id))
+
+	return r.findOne(ctx, q)
+}
+
+// FindByIDForUpdate reads the team as FindByID does and locks its row until
+// the transaction ctx is in ends.
+func (r *TeamRepositoryImpl) FindByIDForUpdate(ctx context.Context, id int64) (model.TeamModel, error) {
+	defer metrics.ObserveQuery("team", "FindByIDForUpdate")()
+
+	q := sqlbuilder.NewSelectBuilder()
+	q.Select("*").From(TEAM_TABLE_NAME).Where(q.Equal("id", id)).ForUpdate()
+
+	return r.findOne(ctx, q)
+}
+
+func (r *TeamRepositoryImpl) findOne(ctx context.Context, q *sqlbuilder.SelectBuilder) (model.TeamModel, error) {
+	var res model.TeamModel
+	query, args := q.BuildWithFlavor(sqlbuilder.PostgreSQL)
 
-	row := r.Db.QueryRow(query, args...)
+	row := database.QueryRow(ctx, r.Db, query, args...)
 	if err := row.Err(); err != nil {
 		return model.TeamModel{}, err
 	}
@@ -79,13 +113,268 @@ func (r *TeamRepositoryImpl) FindByID(ctx context.Context, id int64) (model.Team
 	return res, nil
 }
 
-func (r *TeamRepositoryImpl) Insert(ctx context.Context, payload model.TeamModel) error {
+// FindByIDs returns the teams matching ids in a single query, unknown ids are
+// left out of the result.
+func (r *TeamRepositoryImpl) FindByIDs(ctx context.Context, ids []int64) ([]model.TeamModel, error) {
+	defer metrics.ObserveQuery("team", "FindByIDs")()
+
+	q := sqlbuilder.NewSelectBuilder()
+	q.Select("*").From(TEAM_TABLE_NAME).Where(q.In("id", sqlbuilder.Flatten(ids)...))
+
+	return r.findMany(ctx, q, len(ids))
+}
+
+// FindByIDsForShare reads the teams as FindByIDs does and locks their rows
+// against updates and deletes until the transaction ctx is in ends.
+func (r *TeamRepositoryImpl) FindByIDsForShare(ctx context.Context, ids []int64) ([]model.TeamModel, error) {
+	defer metrics.ObserveQuery("team", "FindByIDsForShare")()
+
+	q := sqlbuilder.NewSelectBuilder()
+	q.Select("*").From(TEAM_TABLE_NAME).Where(q.In("id", sqlbuilder.Flatten(ids)...)).ForShare()
+
+	return r.findMany(ctx, q, len(ids))
+}
+
+func (r *TeamRepositoryImpl) findMany(ctx context.Context, q *sqlbuilder.SelectBuilder, ids int) ([]model.TeamModel, error) {
+	res := []model.TeamModel{}
+	if ids == 0 {
+		return res, nil
+	}
+
+	query, args := q.BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	rows, err := database.Query(ctx, r.Db, query, args...)
+	if err != nil {
+		return []model.TeamModel{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item model.TeamModel
+
+		if err = rows.Scan(
+			&item.ID,
+			&item.Name,
+		); err != nil {
+			return []model.TeamModel{}, err
+		}
+
+		res = append(res, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return []model.TeamModel{}, err
+	}
+
+	return res, nil
+}
+
+// FindByNames returns the teams named exactly like one of names in a single
+// query, unknown names are left out of the result.
+func (r *TeamRepositoryImpl) FindByNames(ctx context.Context, names []string) ([]model.TeamModel, error) {
+	defer metrics.ObserveQuery("team", "FindByNames")()
+
+	res := []model.TeamModel{}
+	if len(names) == 0 {
+		return res, nil
+	}
+
+	q := sqlbuilder.NewSelectBuilder()
+	query, args := q.Select("*").From(TEAM_TABLE_NAME).Where(q.In("name", sqlbuilder.Flatten(names)...)).BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	rows, err := database.Query(ctx, r.Db, query, args...)
+	if err != nil {
+		return []model.TeamModel{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item model.TeamModel
+
+		if err = rows.Scan(
+			&item.ID,
+			&item.Name,
+		); err != nil {
+			return []model.TeamModel{}, err
+		}
+
+		res = append(res, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return []model.TeamModel{}, err
+	}
+
+	return res, nil
+}
+
+// StreamAll calls fn with every team ordered by id as they are read from the
+// result set, so that they are never all held in memory. An error of fn stops
+// the iteration and is returned.
+func (r *TeamRepositoryImpl) StreamAll(ctx context.Context, fn func(model.TeamModel) error) error {
+	defer metrics.ObserveQuery("team", "StreamAll")()
+
+	q := sqlbuilder.NewSelectBuilder()
+	query, _ := q.Select("*").From(TEAM_TABLE_NAME).OrderBy("id").BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	rows, err := database.Query(ctx, r.Db, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item model.TeamModel
+
+		if err := rows.Scan(
+			&item.ID,
+			&item.Name,
+		); err != nil {
+			return err
+		}
+
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// FindFields selects only columns of the teams matching ids in a single
+// query, of every team when ids is empty, the other fields are left unset.
+func (r *TeamRepositoryImpl) FindFields(ctx context.Context, ids []int64, columns []string) ([]model.TeamModel, error) {
+	defer metrics.ObserveQuery("team", "FindFields")()
+
+	q := sqlbuilder.NewSelectBuilder()
+	q.Select(columns...).From(TEAM_TABLE_NAME).OrderBy("id")
+	if len(ids) > 0 {
+		q.Where(q.In("id", sqlbuilder.Flatten(ids)...))
+	}
+	query, args := q.BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	rows, err := database.Query(ctx, r.Db, query, args...)
+	if err != nil {
+		return []model.TeamModel{}, err
+	}
+	defer rows.Close()
+
+	res := []model.TeamModel{}
+	for rows.Next() {
+		var item model.TeamModel
+
+		dest := make([]interface{}, 0, len(columns))
+		for _, column := range columns {
+			switch column {
+			case "id":
+				dest = append(dest, &item.ID)
+			case "name":
+				dest = append(dest, &item.Name)
+			default:
+				return []model.TeamModel{}, fmt.Errorf("unknown team column %s", column)
+			}
+		}
+
+		if err := rows.Scan(dest...); err != nil {
+			return []model.TeamModel{}, err
+		}
+
+		res = append(res, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return []model.TeamModel{}, err
+	}
+
+	return res, nil
+}
+
+func (r *TeamRepositoryImpl) Insert(ctx context.Context, payload model.TeamModel) (model.TeamModel, error) {
+	defer metrics.ObserveQuery("team", "Insert")()
+
+	var res model.TeamModel
 	q := sqlbuilder.NewInsertBuilder()
 
 	query, args := q.InsertInto(TEAM_TABLE_NAME).Cols("name").Values(payload.Name).
+		SQL("RETURNING id, name").
+		BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	if err := database.QueryRow(ctx, r.Db, query, args...).Scan(
+		&res.ID,
+		&res.Name,
+	); err != nil {
+		return model.TeamModel{}, err
+	}
+
+	return res, nil
+}
+
+// UpdateName returns sql.ErrNoRows when the team doesn't exist.
+func (r *TeamRepositoryImpl) UpdateName(ctx context.Context, id int64, name string) (model.TeamModel, error) {
+	defer metrics.ObserveQuery("team", "UpdateName")()
+
+	var res model.TeamModel
+	q := sqlbuilder.NewUpdateBuilder()
+	query, args := q.Update(TEAM_TABLE_NAME).
+		Set(q.Assign("name", name)).
+		Where(q.Equal("id", id)).
+		SQL("RETURNING id, name").
+		BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	if err := database.QueryRow(ctx, r.Db, query, args...).Scan(
+		&res.ID,
+		&res.Name,
+	); err != nil {
+		return model.TeamModel{}, err
+	}
+
+	return res, nil
+}
+
+// Update sets only the given columns of the team, it returns sql.ErrNoRows
+// when the team doesn't exist.
+func (r *TeamRepositoryImpl) Update(ctx context.Context, id int64, values map[string]interface{}) (model.TeamModel, error) {
+	defer metrics.ObserveQuery("team", "Update")()
+
+	columns := make([]string, 0, len(values))
+	for column := range values {
+		if column != "name" {
+			return model.TeamModel{}, fmt.Errorf("unknown team column %s", column)
+		}
+
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	q := sqlbuilder.NewUpdateBuilder()
+	q.Update(TEAM_TABLE_NAME)
+	for _, column := range columns {
+		q.SetMore(q.Assign(column, values[column]))
+	}
+	query, args := q.Where(q.Equal("id", id)).
+		SQL("RETURNING id, name").
+		BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	var res model.TeamModel
+	if err := database.QueryRow(ctx, r.Db, query, args...).Scan(
+		&res.ID,
+		&res.Name,
+	); err != nil {
+		return model.TeamModel{}, err
+	}
+
+	return res, nil
+}
+
+func (r *TeamRepositoryImpl) Delete(ctx context.Context, id int64) error {
+	defer metrics.ObserveQuery("team", "Delete")()
+
+	q := sqlbuilder.NewDeleteBuilder()
+	query, args := q.DeleteFrom(TEAM_TABLE_NAME).
+		Where(q.Equal("id", id)).
 		BuildWithFlavor(sqlbuilder.PostgreSQL)
 
-	_, err := r.Db.Exec(query, args...)
+	_, err := database.Exec(ctx, r.Db, query, args...)
 	if err != nil {
 		return err
 	}
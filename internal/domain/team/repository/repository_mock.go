@@ -35,6 +35,20 @@ func (m *MockTeamRepository) EXPECT() *MockTeamRepositoryMockRecorder {
 	return m.recorder
 }
 
+// Delete mocks base method.
+func (m *MockTeamRepository) Delete(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTeamRepositoryMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTeamRepository)(nil).Delete), ctx, id)
+}
+
 // FindAll mocks base method.
 func (m *MockTeamRepository) FindAll(ctx context.Context) ([]model.TeamModel, error) {
 	m.ctrl.T.Helper()
@@ -65,12 +79,88 @@ func (mr *MockTeamRepositoryMockRecorder) FindByID(ctx, id interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockTeamRepository)(nil).FindByID), ctx, id)
 }
 
+// FindByIDForUpdate mocks base method.
+func (m *MockTeamRepository) FindByIDForUpdate(ctx context.Context, id int64) (model.TeamModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByIDForUpdate", ctx, id)
+	ret0, _ := ret[0].(model.TeamModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByIDForUpdate indicates an expected call of FindByIDForUpdate.
+func (mr *MockTeamRepositoryMockRecorder) FindByIDForUpdate(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByIDForUpdate", reflect.TypeOf((*MockTeamRepository)(nil).FindByIDForUpdate), ctx, id)
+}
+
+// FindByIDs mocks base method.
+func (m *MockTeamRepository) FindByIDs(ctx context.Context, ids []int64) ([]model.TeamModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByIDs", ctx, ids)
+	ret0, _ := ret[0].([]model.TeamModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByIDs indicates an expected call of FindByIDs.
+func (mr *MockTeamRepositoryMockRecorder) FindByIDs(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByIDs", reflect.TypeOf((*MockTeamRepository)(nil).FindByIDs), ctx, ids)
+}
+
+// FindByIDsForShare mocks base method.
+func (m *MockTeamRepository) FindByIDsForShare(ctx context.Context, ids []int64) ([]model.TeamModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByIDsForShare", ctx, ids)
+	ret0, _ := ret[0].([]model.TeamModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByIDsForShare indicates an expected call of FindByIDsForShare.
+func (mr *MockTeamRepositoryMockRecorder) FindByIDsForShare(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByIDsForShare", reflect.TypeOf((*MockTeamRepository)(nil).FindByIDsForShare), ctx, ids)
+}
+
+// FindByNames mocks base method.
+func (m *MockTeamRepository) FindByNames(ctx context.Context, names []string) ([]model.TeamModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByNames", ctx, names)
+	ret0, _ := ret[0].([]model.TeamModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByNames indicates an expected call of FindByNames.
+func (mr *MockTeamRepositoryMockRecorder) FindByNames(ctx, names interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByNames", reflect.TypeOf((*MockTeamRepository)(nil).FindByNames), ctx, names)
+}
+
+// FindFields mocks base method.
+func (m *MockTeamRepository) FindFields(ctx context.Context, ids []int64, columns []string) ([]model.TeamModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFields", ctx, ids, columns)
+	ret0, _ := ret[0].([]model.TeamModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindFields indicates an expected call of FindFields.
+func (mr *MockTeamRepositoryMockRecorder) FindFields(ctx, ids, columns interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFields", reflect.TypeOf((*MockTeamRepository)(nil).FindFields), ctx, ids, columns)
+}
+
 // Insert mocks base method.
-func (m *MockTeamRepository) Insert(ctx context.Context, payload model.TeamModel) error {
+func (m *MockTeamRepository) Insert(ctx context.Context, payload model.TeamModel) (model.TeamModel, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Insert", ctx, payload)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(model.TeamModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Insert indicates an expected call of Insert.
@@ -78,3 +168,47 @@ func (mr *MockTeamRepositoryMockRecorder) Insert(ctx, payload interface{}) *gomo
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockTeamRepository)(nil).Insert), ctx, payload)
 }
+
+// StreamAll mocks base method.
+func (m *MockTeamRepository) StreamAll(ctx context.Context, fn func(model.TeamModel) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamAll", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamAll indicates an expected call of StreamAll.
+func (mr *MockTeamRepositoryMockRecorder) StreamAll(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamAll", reflect.TypeOf((*MockTeamRepository)(nil).StreamAll), ctx, fn)
+}
+
+// Update mocks base method.
+func (m *MockTeamRepository) Update(ctx context.Context, id int64, values map[string]interface{}) (model.TeamModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, values)
+	ret0, _ := ret[0].(model.TeamModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTeamRepositoryMockRecorder) Update(ctx, id, values interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTeamRepository)(nil).Update), ctx, id, values)
+}
+
+// UpdateName mocks base method.
+func (m *MockTeamRepository) UpdateName(ctx context.Context, id int64, name string) (model.TeamModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateName", ctx, id, name)
+	ret0, _ := ret[0].(model.TeamModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateName indicates an expected call of UpdateName.
+func (mr *MockTeamRepositoryMockRecorder) UpdateName(ctx, id, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateName", reflect.TypeOf((*MockTeamRepository)(nil).UpdateName), ctx, id, name)
+}
@@ -2,6 +2,7 @@ package repository_test
 
 import (
 	"context"
+	"errors"
 	"regexp"
 	"testing"
 
@@ -98,11 +99,228 @@ func Test_FindByID(t *testing.T) {
 	}
 }
 
+func Test_FindByIDForUpdate(t *testing.T) {
+	repo := createRepo(func(db sqlmock.Sqlmock) {
+		db.ExpectQuery(regexp.QuoteMeta("SELECT * FROM team WHERE id = $1 FOR UPDATE")).WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+				AddRow(int64(1), "some-team-name"),
+			)
+	})
+
+	actual, err := repo.FindByIDForUpdate(context.Background(), 1)
+
+	assert.Nil(t, err)
+	assert.Equal(t, model.TeamModel{ID: 1, Name: "some-team-name"}, actual)
+}
+
+func Test_FindByIDsForShare(t *testing.T) {
+	repo := createRepo(func(db sqlmock.Sqlmock) {
+		db.ExpectQuery(regexp.QuoteMeta("SELECT * FROM team WHERE id IN ($1, $2) FOR SHARE")).WithArgs(int64(1), int64(2)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+				AddRow(int64(1), "some-team-name"),
+			)
+	})
+
+	actual, err := repo.FindByIDsForShare(context.Background(), []int64{1, 2})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []model.TeamModel{{ID: 1, Name: "some-team-name"}}, actual)
+}
+
+func Test_FindByIDs(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		Param       []int64
+		MockFn      mockFn
+		Expected    []model.TeamModel
+		ExpectedErr string
+	}{
+		{
+			Name:  "when_data_present",
+			Param: []int64{1, 2},
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(regexp.QuoteMeta("SELECT * FROM team WHERE id IN ($1, $2)")).WithArgs(int64(1), int64(2)).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+						AddRow(int64(1), "some-team-name"),
+					)
+			},
+			Expected: []model.TeamModel{{ID: 1, Name: "some-team-name"}},
+		},
+		{
+			Name:     "when_ids_empty",
+			Param:    []int64{},
+			MockFn:   func(db sqlmock.Sqlmock) {},
+			Expected: []model.TeamModel{},
+		},
+		{
+			Name:  "when_query_fails",
+			Param: []int64{1},
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(regexp.QuoteMeta("SELECT * FROM team WHERE id IN ($1)")).WithArgs(int64(1)).
+					WillReturnError(errors.New("some-error"))
+			},
+			ExpectedErr: "some-error",
+		},
+	}
+
+	for _, test := range testCases {
+		repo := createRepo(test.MockFn)
+
+		actual, err := repo.FindByIDs(context.Background(), test.Param)
+		if test.ExpectedErr != "" {
+			assert.EqualError(t, err, test.ExpectedErr)
+		} else {
+			assert.Equal(t, test.Expected, actual)
+			assert.Nil(t, err)
+		}
+	}
+}
+
+func Test_FindByNames(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		Param       []string
+		MockFn      mockFn
+		Expected    []model.TeamModel
+		ExpectedErr string
+	}{
+		{
+			Name:  "when_data_present",
+			Param: []string{"some-team-name", "other-team-name"},
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(regexp.QuoteMeta("SELECT * FROM team WHERE name IN ($1, $2)")).WithArgs("some-team-name", "other-team-name").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+						AddRow(int64(1), "some-team-name"),
+					)
+			},
+			Expected: []model.TeamModel{{ID: 1, Name: "some-team-name"}},
+		},
+		{
+			Name:     "when_names_empty",
+			Param:    []string{},
+			MockFn:   func(db sqlmock.Sqlmock) {},
+			Expected: []model.TeamModel{},
+		},
+	}
+
+	for _, test := range testCases {
+		repo := createRepo(test.MockFn)
+
+		actual, err := repo.FindByNames(context.Background(), test.Param)
+		if test.ExpectedErr != "" {
+			assert.EqualError(t, err, test.ExpectedErr)
+		} else {
+			assert.Equal(t, test.Expected, actual)
+			assert.Nil(t, err)
+		}
+	}
+}
+
+func Test_StreamAll(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		MockFn      mockFn
+		Expected    []model.TeamModel
+		ExpectedErr string
+	}{
+		{
+			Name: "when_data_present",
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(regexp.QuoteMeta("SELECT * FROM team ORDER BY id")).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+						AddRow(int64(1), "some-team-name").
+						AddRow(int64(2), "other-team-name"),
+					)
+			},
+			Expected: []model.TeamModel{{ID: 1, Name: "some-team-name"}, {ID: 2, Name: "other-team-name"}},
+		},
+		{
+			Name: "when_query_fails",
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(regexp.QuoteMeta("SELECT * FROM team ORDER BY id")).WillReturnError(errors.New("some-error"))
+			},
+			Expected:    []model.TeamModel{},
+			ExpectedErr: "some-error",
+		},
+	}
+
+	for _, test := range testCases {
+		repo := createRepo(test.MockFn)
+
+		actual := []model.TeamModel{}
+		err := repo.StreamAll(context.Background(), func(team model.TeamModel) error {
+			actual = append(actual, team)
+			return nil
+		})
+		if test.ExpectedErr != "" {
+			assert.EqualError(t, err, test.ExpectedErr)
+		} else {
+			assert.Nil(t, err)
+		}
+		assert.Equal(t, test.Expected, actual)
+	}
+}
+
+func Test_FindFields(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		IDs         []int64
+		Columns     []string
+		MockFn      mockFn
+		Expected    []model.TeamModel
+		ExpectedErr string
+	}{
+		{
+			Name:    "when_ids_given",
+			IDs:     []int64{1, 2},
+			Columns: []string{"id", "name"},
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(regexp.QuoteMeta("SELECT id, name FROM team WHERE id IN ($1, $2) ORDER BY id")).WithArgs(int64(1), int64(2)).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+						AddRow(int64(1), "some-team-name"),
+					)
+			},
+			Expected: []model.TeamModel{{ID: 1, Name: "some-team-name"}},
+		},
+		{
+			Name:    "when_every_team",
+			Columns: []string{"id"},
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(regexp.QuoteMeta("SELECT id FROM team ORDER BY id")).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(1)))
+			},
+			Expected: []model.TeamModel{{ID: 1}},
+		},
+		{
+			Name:    "when_column_unknown",
+			Columns: []string{"coach"},
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(regexp.QuoteMeta("SELECT coach FROM team ORDER BY id")).
+					WillReturnRows(sqlmock.NewRows([]string{"coach"}).AddRow("some-coach"))
+			},
+			ExpectedErr: "unknown team column coach",
+		},
+	}
+
+	for _, test := range testCases {
+		repo := createRepo(test.MockFn)
+
+		actual, err := repo.FindFields(context.Background(), test.IDs, test.Columns)
+		if test.ExpectedErr != "" {
+			assert.EqualError(t, err, test.ExpectedErr)
+		} else {
+			assert.Equal(t, test.Expected, actual)
+			assert.Nil(t, err)
+		}
+	}
+}
+
 func Test_Insert(t *testing.T) {
 	testCases := []struct {
 		Name        string
 		Param       model.TeamModel
 		MockFn      mockFn
+		Expected    model.TeamModel
 		ExpectedErr string
 	}{
 		{
@@ -111,8 +329,12 @@ func Test_Insert(t *testing.T) {
 				Name: "some-team-name",
 			},
 			MockFn: func(db sqlmock.Sqlmock) {
-				db.ExpectExec(regexp.QuoteMeta("INSERT INTO team (name) VALUES ($1)")).WithArgs("some-team-name").
-					WillReturnResult(sqlmock.NewResult(1, 1))
+				db.ExpectQuery(regexp.QuoteMeta("INSERT INTO team (name) VALUES ($1) RETURNING id, name")).WithArgs("some-team-name").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(int64(1), "some-team-name"))
+			},
+			Expected: model.TeamModel{
+				ID:   1,
+				Name: "some-team-name",
 			},
 		},
 	}
@@ -120,13 +342,156 @@ func Test_Insert(t *testing.T) {
 	for _, test := range testCases {
 		repo := createRepo(test.MockFn)
 
-		err := repo.Insert(context.Background(), test.Param)
+		actual, err := repo.Insert(context.Background(), test.Param)
 
 		if test.ExpectedErr != "" {
 			assert.EqualError(t, err, test.ExpectedErr)
 		} else {
+			assert.Equal(t, test.Expected, actual)
 			assert.Nil(t, err)
 		}
 
 	}
 }
+
+func Test_UpdateName(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		ID          int64
+		Param       string
+		MockFn      mockFn
+		Expected    model.TeamModel
+		ExpectedErr string
+	}{
+		{
+			Name:  "when_successful",
+			ID:    1,
+			Param: "some-team-name",
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(regexp.QuoteMeta("UPDATE team SET name = $1 WHERE id = $2 RETURNING id, name")).WithArgs("some-team-name", int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(int64(1), "some-team-name"))
+			},
+			Expected: model.TeamModel{
+				ID:   1,
+				Name: "some-team-name",
+			},
+		},
+		{
+			Name:  "when_not_found",
+			ID:    1,
+			Param: "some-team-name",
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(regexp.QuoteMeta("UPDATE team SET name = $1 WHERE id = $2 RETURNING id, name")).WithArgs("some-team-name", int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+			},
+			ExpectedErr: "sql: no rows in result set",
+		},
+	}
+
+	for _, test := range testCases {
+		repo := createRepo(test.MockFn)
+
+		actual, err := repo.UpdateName(context.Background(), test.ID, test.Param)
+
+		if test.ExpectedErr != "" {
+			assert.EqualError(t, err, test.ExpectedErr)
+		} else {
+			assert.Equal(t, test.Expected, actual)
+			assert.Nil(t, err)
+		}
+	}
+}
+
+func Test_Update(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		ID          int64
+		Values      map[string]interface{}
+		MockFn      mockFn
+		Expected    model.TeamModel
+		ExpectedErr string
+	}{
+		{
+			Name:   "when_successful",
+			ID:     1,
+			Values: map[string]interface{}{"name": "some-team-name"},
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(regexp.QuoteMeta("UPDATE team SET name = $1 WHERE id = $2 RETURNING id, name")).WithArgs("some-team-name", int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(int64(1), "some-team-name"))
+			},
+			Expected: model.TeamModel{
+				ID:   1,
+				Name: "some-team-name",
+			},
+		},
+		{
+			Name:   "when_not_found",
+			ID:     1,
+			Values: map[string]interface{}{"name": "some-team-name"},
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery(regexp.QuoteMeta("UPDATE team SET name = $1 WHERE id = $2 RETURNING id, name")).WithArgs("some-team-name", int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+			},
+			ExpectedErr: "sql: no rows in result set",
+		},
+		{
+			Name:        "when_column_is_unknown",
+			ID:          1,
+			Values:      map[string]interface{}{"id": int64(2)},
+			MockFn:      func(db sqlmock.Sqlmock) {},
+			ExpectedErr: "unknown team column id",
+		},
+	}
+
+	for _, test := range testCases {
+		repo := createRepo(test.MockFn)
+
+		actual, err := repo.Update(context.Background(), test.ID, test.Values)
+
+		if test.ExpectedErr != "" {
+			assert.EqualError(t, err, test.ExpectedErr)
+		} else {
+			assert.Equal(t, test.Expected, actual)
+			assert.Nil(t, err)
+		}
+	}
+}
+
+func Test_Delete(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		ID          int64
+		MockFn      mockFn
+		ExpectedErr string
+	}{
+		{
+			Name: "when_successful",
+			ID:   1,
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectExec(regexp.QuoteMeta("DELETE FROM team WHERE id = $1")).WithArgs(int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			Name: "when_not_successful",
+			ID:   1,
+			MockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectExec(regexp.QuoteMeta("DELETE FROM team WHERE id = $1")).WithArgs(int64(1)).
+					WillReturnError(errors.New("some-error"))
+			},
+			ExpectedErr: "some-error",
+		},
+	}
+
+	for _, test := range testCases {
+		repo := createRepo(test.MockFn)
+
+		err := repo.Delete(context.Background(), test.ID)
+
+		if test.ExpectedErr != "" {
+			assert.EqualError(t, err, test.ExpectedErr)
+		} else {
+			assert.Nil(t, err)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	command_model "github.com/tesarwijaya/ouroboros/internal/domain/command/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/team/model"
+	"github.com/tesarwijaya/ouroboros/internal/patch"
+	"go.uber.org/dig"
+)
+
+type (
+	InsertTeamCommand struct {
+		Payload model.TeamModel
+	}
+
+	RenameTeamCommand struct {
+		ID   int64
+		Name string
+	}
+
+	DeleteTeamCommand struct {
+		ID int64
+	}
+
+	PatchTeamCommand struct {
+		ID    int64
+		Patch patch.Patch
+	}
+
+	CommandHandlers struct {
+		dig.Out
+		Handlers []command_model.Handler `group:"command_handlers,flatten"`
+	}
+)
+
+func (InsertTeamCommand) CommandName() string {
+	return "team.insert"
+}
+
+func (c InsertTeamCommand) Validate() error {
+	if strings.TrimSpace(c.Payload.Name) == "" {
+		return errors.New("name is required")
+	}
+
+	return nil
+}
+
+func (RenameTeamCommand) CommandName() string {
+	return "team.rename"
+}
+
+func (c RenameTeamCommand) Validate() error {
+	if c.ID <= 0 {
+		return errors.New("id is required")
+	}
+
+	if strings.TrimSpace(c.Name) == "" {
+		return errors.New("name is required")
+	}
+
+	return nil
+}
+
+func (DeleteTeamCommand) CommandName() string {
+	return "team.delete"
+}
+
+func (c DeleteTeamCommand) Validate() error {
+	if c.ID <= 0 {
+		return errors.New("id is required")
+	}
+
+	return nil
+}
+
+func (PatchTeamCommand) CommandName() string {
+	return "team.patch"
+}
+
+func (c PatchTeamCommand) Validate() error {
+	if c.ID <= 0 {
+		return errors.New("id is required")
+	}
+
+	return c.Patch.Validate()
+}
+
+// NewCommandHandlers registers the team service methods as the handlers of
+// the team commands.
+func NewCommandHandlers(svc TeamService) CommandHandlers {
+	return CommandHandlers{
+		Handlers: []command_model.Handler{
+			command_model.NewHandler(func(ctx context.Context, cmd InsertTeamCommand) (model.TeamModel, error) {
+				return svc.Insert(ctx, cmd.Payload)
+			}),
+			command_model.NewHandler(func(ctx context.Context, cmd RenameTeamCommand) (model.TeamModel, error) {
+				return svc.Rename(ctx, cmd.ID, cmd.Name)
+			}),
+			command_model.NewHandler(func(ctx context.Context, cmd DeleteTeamCommand) (model.TeamModel, error) {
+				return svc.Delete(ctx, cmd.ID)
+			}),
+			command_model.NewHandler(func(ctx context.Context, cmd PatchTeamCommand) (model.TeamModel, error) {
+				return svc.Patch(ctx, cmd.ID, cmd.Patch)
+			}),
+		},
+	}
+}
@@ -2,24 +2,49 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
 
+	"github.com/EventStore/EventStore-Client-Go/esdb"
+	"github.com/gofrs/uuid"
+	"github.com/tesarwijaya/ouroboros/internal/database"
+	auth_model "github.com/tesarwijaya/ouroboros/internal/domain/auth/model"
+	auth_service "github.com/tesarwijaya/ouroboros/internal/domain/auth/service"
+	command_model "github.com/tesarwijaya/ouroboros/internal/domain/command/model"
+	event_model "github.com/tesarwijaya/ouroboros/internal/domain/event/model"
+	event_service "github.com/tesarwijaya/ouroboros/internal/domain/event/service"
+	player_model "github.com/tesarwijaya/ouroboros/internal/domain/player/model"
 	player_repository "github.com/tesarwijaya/ouroboros/internal/domain/player/repository"
 	"github.com/tesarwijaya/ouroboros/internal/domain/team/model"
 	"github.com/tesarwijaya/ouroboros/internal/domain/team/repository"
+	"github.com/tesarwijaya/ouroboros/internal/patch"
+	"github.com/tesarwijaya/ouroboros/internal/tracing"
 	"go.uber.org/dig"
 )
 
 type TeamService interface {
 	FindAll(ctx context.Context) ([]model.TeamModel, error)
 	FindByID(ctx context.Context, id int64) (model.TeamModel, error)
+	FindByIDs(ctx context.Context, ids []int64) ([]model.TeamModel, error)
+	FindByNames(ctx context.Context, names []string) ([]model.TeamModel, error)
+	FindFields(ctx context.Context, ids []int64, fields []string) ([]model.TeamModel, []int64, error)
 	FindTeamPlayer(ctx context.Context, id int64) (model.TeamPlayerRespModel, error)
+	FindTeamPlayers(ctx context.Context, ids []int64) ([]model.TeamWithPlayersRespModel, []int64, error)
 	Insert(ctx context.Context, payload model.TeamModel) (model.TeamModel, error)
+	Rename(ctx context.Context, id int64, name string) (model.TeamModel, error)
+	Delete(ctx context.Context, id int64) (model.TeamModel, error)
+	Patch(ctx context.Context, id int64, p patch.Patch) (model.TeamModel, error)
 }
 
 type TeamServiceImpl struct {
 	dig.In
+	Db         *sql.DB
 	Repo       repository.TeamRepository
 	PlayerRepo player_repository.PlayerRepository
+	EventBus   event_service.EventBus
+	Authz      auth_service.Authorizer
 }
 
 func NewTeamService(svc TeamServiceImpl) TeamService {
@@ -27,30 +52,405 @@ func NewTeamService(svc TeamServiceImpl) TeamService {
 }
 
 func (s *TeamServiceImpl) FindAll(ctx context.Context) ([]model.TeamModel, error) {
+	ctx, span := tracing.Start(ctx, "TeamService.FindAll")
+	defer span.End()
+
+	if err := s.Authz.Authorize(ctx, auth_model.ACTION_TEAM_READ, auth_model.Resource{}); err != nil {
+		return []model.TeamModel{}, err
+	}
+
 	return s.Repo.FindAll(ctx)
 }
 
 func (s *TeamServiceImpl) FindByID(ctx context.Context, id int64) (model.TeamModel, error) {
+	ctx, span := tracing.Start(ctx, "TeamService.FindByID")
+	defer span.End()
+
+	if err := s.Authz.Authorize(ctx, auth_model.ACTION_TEAM_READ, auth_model.Resource{TeamID: id}); err != nil {
+		return model.TeamModel{}, err
+	}
+
 	return s.Repo.FindByID(ctx, id)
 }
 
+// FindByIDs returns the given teams at once, the caller must be allowed to
+// read each of them.
+func (s *TeamServiceImpl) FindByIDs(ctx context.Context, ids []int64) ([]model.TeamModel, error) {
+	ctx, span := tracing.Start(ctx, "TeamService.FindByIDs")
+	defer span.End()
+
+	for _, id := range ids {
+		if err := s.Authz.Authorize(ctx, auth_model.ACTION_TEAM_READ, auth_model.Resource{TeamID: id}); err != nil {
+			return []model.TeamModel{}, err
+		}
+	}
+
+	return s.Repo.FindByIDs(ctx, ids)
+}
+
+// FindByNames returns the teams named like one of names, the caller must be
+// allowed to read each of them.
+func (s *TeamServiceImpl) FindByNames(ctx context.Context, names []string) ([]model.TeamModel, error) {
+	ctx, span := tracing.Start(ctx, "TeamService.FindByNames")
+	defer span.End()
+
+	teams, err := s.Repo.FindByNames(ctx, names)
+	if err != nil {
+		return []model.TeamModel{}, err
+	}
+
+	for _, team := range teams {
+		if err := s.Authz.Authorize(ctx, auth_model.ACTION_TEAM_READ, auth_model.Resource{TeamID: team.ID}); err != nil {
+			return []model.TeamModel{}, err
+		}
+	}
+
+	return teams, nil
+}
+
+// FindFields selects only the given JSON fields of the teams matching ids,
+// of every team when ids is empty, and returns the ids no team has apart.
+// Every field is selected when fields is empty.
+func (s *TeamServiceImpl) FindFields(ctx context.Context, ids []int64, fields []string) ([]model.TeamModel, []int64, error) {
+	ctx, span := tracing.Start(ctx, "TeamService.FindFields")
+	defer span.End()
+
+	// the id tells which teams are missing
+	columns := []string{"id"}
+	for _, field := range fields {
+		column, ok := model.TEAM_COLUMNS[field]
+		if !ok {
+			return []model.TeamModel{}, []int64{}, command_model.ValidationError{Err: fmt.Errorf("unknown field %q", field)}
+		}
+
+		if column != "id" {
+			columns = append(columns, column)
+		}
+	}
+	if len(fields) == 0 {
+		columns = append(columns, "name")
+	}
+
+	resources := []auth_model.Resource{{}}
+	if len(ids) > 0 {
+		resources = make([]auth_model.Resource, 0, len(ids))
+		for _, id := range ids {
+			resources = append(resources, auth_model.Resource{TeamID: id})
+		}
+	}
+
+	for _, resource := range resources {
+		if err := s.Authz.Authorize(ctx, auth_model.ACTION_TEAM_READ, resource); err != nil {
+			return []model.TeamModel{}, []int64{}, err
+		}
+	}
+
+	teams, err := s.Repo.FindFields(ctx, ids, columns)
+	if err != nil {
+		return []model.TeamModel{}, []int64{}, err
+	}
+
+	found := make(map[int64]bool, len(teams))
+	for _, team := range teams {
+		found[team.ID] = true
+	}
+
+	missing := []int64{}
+	for _, id := range ids {
+		if !found[id] {
+			missing = append(missing, id)
+			found[id] = true
+		}
+	}
+
+	return teams, missing, nil
+}
+
 func (s *TeamServiceImpl) Insert(ctx context.Context, payload model.TeamModel) (model.TeamModel, error) {
-	if err := s.Repo.Insert(ctx, payload); err != nil {
+	ctx, span := tracing.Start(ctx, "TeamService.Insert")
+	defer span.End()
+
+	if err := s.Authz.Authorize(ctx, auth_model.ACTION_TEAM_CREATE, auth_model.Resource{}); err != nil {
+		return model.TeamModel{}, err
+	}
+
+	if command_model.IsDryRun(ctx) {
+		return payload, nil
+	}
+
+	return s.Repo.Insert(ctx, payload)
+}
+
+// Rename changes the name of the team, a team_updated event lists the name
+// as changed. Renaming a team to its current name writes nothing.
+func (s *TeamServiceImpl) Rename(ctx context.Context, id int64, name string) (model.TeamModel, error) {
+	ctx, span := tracing.Start(ctx, "TeamService.Rename")
+	defer span.End()
+
+	if err := s.Authz.Authorize(ctx, auth_model.ACTION_TEAM_UPDATE, auth_model.Resource{TeamID: id}); err != nil {
+		return model.TeamModel{}, err
+	}
+
+	var res model.TeamModel
+	err := database.WithTx(ctx, s.Db, func(ctx context.Context) error {
+		team, err := s.Repo.FindByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if team.Name == name || command_model.IsDryRun(ctx) {
+			team.Name = name
+			res = team
+			return nil
+		}
+
+		res, err = s.Repo.UpdateName(ctx, id, name)
+		if err != nil {
+			return err
+		}
+
+		return s.publish(ctx, id, model.TEAM_UPDATED_EVENT, model.UpdatedEventData{
+			TeamID: id,
+			Fields: []string{"name"},
+			Team:   res,
+		})
+	})
+	if err != nil {
 		return model.TeamModel{}, err
 	}
 
-	return payload, nil
+	return res, nil
+}
+
+// Patch applies p to the team and writes only the changed columns, a
+// team_updated event lists the changed fields. The team is locked from the
+// read to the write so that concurrent patches apply one after the other.
+func (s *TeamServiceImpl) Patch(ctx context.Context, id int64, p patch.Patch) (model.TeamModel, error) {
+	ctx, span := tracing.Start(ctx, "TeamService.Patch")
+	defer span.End()
+
+	if err := s.Authz.Authorize(ctx, auth_model.ACTION_TEAM_UPDATE, auth_model.Resource{TeamID: id}); err != nil {
+		return model.TeamModel{}, err
+	}
+
+	var res model.TeamModel
+	err := database.WithTx(ctx, s.Db, func(ctx context.Context) error {
+		currTeam, err := s.Repo.FindByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		var team model.TeamModel
+		if err := p.Apply(currTeam, &team); err != nil {
+			return command_model.ValidationError{Err: err}
+		}
+
+		after := team.Sparse(nil)
+		fields := patch.Changed(currTeam.Sparse(nil), after)
+
+		values := make(map[string]interface{}, len(fields))
+		for _, field := range fields {
+			if field == "id" {
+				return command_model.ValidationError{Err: errors.New("id can't be changed")}
+			}
+
+			values[model.TEAM_COLUMNS[field]] = after[field]
+		}
+
+		if err := (InsertTeamCommand{Payload: team}).Validate(); err != nil {
+			return command_model.ValidationError{Err: err}
+		}
+
+		if len(fields) == 0 || command_model.IsDryRun(ctx) {
+			res = team
+			return nil
+		}
+
+		res, err = s.Repo.Update(ctx, id, values)
+		if err != nil {
+			return err
+		}
+
+		return s.publish(ctx, id, model.TEAM_UPDATED_EVENT, model.UpdatedEventData{
+			TeamID: id,
+			Fields: fields,
+			Team:   res,
+		})
+	})
+	if err != nil {
+		return model.TeamModel{}, err
+	}
+
+	return res, nil
+}
+
+// Delete refuses to delete a team that still has players, it returns the
+// deleted team and emits a team_deleted event. The team is locked from the
+// check to the delete, the players joining it wait for the delete and then
+// find no team.
+func (s *TeamServiceImpl) Delete(ctx context.Context, id int64) (model.TeamModel, error) {
+	ctx, span := tracing.Start(ctx, "TeamService.Delete")
+	defer span.End()
+
+	if err := s.Authz.Authorize(ctx, auth_model.ACTION_TEAM_DELETE, auth_model.Resource{TeamID: id}); err != nil {
+		return model.TeamModel{}, err
+	}
+
+	var team model.TeamModel
+	err := database.WithTx(ctx, s.Db, func(ctx context.Context) error {
+		var err error
+
+		team, err = s.Repo.FindByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		players, err := s.PlayerRepo.FindByTeamID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if len(players) > 0 {
+			return command_model.ValidationError{Err: model.ErrTeamHasPlayers}
+		}
+
+		if command_model.IsDryRun(ctx) {
+			return nil
+		}
+
+		if err := s.Repo.Delete(ctx, id); err != nil {
+			return err
+		}
+
+		return s.publish(ctx, id, model.TEAM_DELETED_EVENT, model.DeletedEventData{
+			TeamID: id,
+			Team:   team,
+		})
+	})
+	if err != nil {
+		return model.TeamModel{}, err
+	}
+
+	return team, nil
+}
+
+// publish records an event of the team.
+func (s *TeamServiceImpl) publish(ctx context.Context, id int64, eventType string, payload interface{}) error {
+	data, _ := json.Marshal(payload)
+	eventID, _ := uuid.NewGen().NewV4()
+
+	return s.EventBus.Publish(ctx, event_model.Event{
+		ID:          eventID,
+		StreamID:    model.TeamStreamID(id),
+		Type:        eventType,
+		ContentType: esdb.JsonContentType,
+		Data:        data,
+	})
 }
 
 func (s *TeamServiceImpl) FindTeamPlayer(ctx context.Context, id int64) (model.TeamPlayerRespModel, error) {
+	ctx, span := tracing.Start(ctx, "TeamService.FindTeamPlayer")
+	defer span.End()
+
 	team, err := s.FindByID(ctx, id)
 	if err != nil {
 		return model.TeamPlayerRespModel{}, err
 	}
 
-	players, _ := s.PlayerRepo.FindByTeamID(ctx, id)
+	res, err := s.withPlayers(ctx, []model.TeamModel{team})
+	if err != nil {
+		return model.TeamPlayerRespModel{}, err
+	}
+
 	return model.TeamPlayerRespModel{
-		TeamModel: team,
-		Players:   players,
+		TeamModel: res[0].TeamModel,
+		Players:   res[0].Players,
 	}, nil
 }
+
+// FindTeamPlayers returns the teams matching ids, every team when ids is
+// empty, with their players and the ids no team has apart. The players of all
+// the teams are read in a single query, which doesn't list the team ids when
+// every team is read so that it stays within the bind parameter limit.
+func (s *TeamServiceImpl) FindTeamPlayers(ctx context.Context, ids []int64) ([]model.TeamWithPlayersRespModel, []int64, error) {
+	ctx, span := tracing.Start(ctx, "TeamService.FindTeamPlayers")
+	defer span.End()
+
+	if len(ids) == 0 {
+		teams, err := s.FindAll(ctx)
+		if err != nil {
+			return []model.TeamWithPlayersRespModel{}, []int64{}, err
+		}
+
+		players, err := s.PlayerRepo.FindAll(ctx)
+		if err != nil {
+			return []model.TeamWithPlayersRespModel{}, []int64{}, err
+		}
+
+		return groupPlayers(teams, players), []int64{}, nil
+	}
+
+	teams, err := s.FindByIDs(ctx, ids)
+	if err != nil {
+		return []model.TeamWithPlayersRespModel{}, []int64{}, err
+	}
+
+	res, err := s.withPlayers(ctx, teams)
+	if err != nil {
+		return []model.TeamWithPlayersRespModel{}, []int64{}, err
+	}
+
+	found := make(map[int64]bool, len(teams))
+	for _, team := range teams {
+		found[team.ID] = true
+	}
+
+	missing := []int64{}
+	for _, id := range ids {
+		if !found[id] {
+			missing = append(missing, id)
+			found[id] = true
+		}
+	}
+
+	return res, missing, nil
+}
+
+// withPlayers reads the players of the given teams at once and groups them by
+// team.
+func (s *TeamServiceImpl) withPlayers(ctx context.Context, teams []model.TeamModel) ([]model.TeamWithPlayersRespModel, error) {
+	ids := make([]int64, 0, len(teams))
+	for _, team := range teams {
+		ids = append(ids, team.ID)
+	}
+
+	players, err := s.PlayerRepo.FindByTeamIDs(ctx, ids)
+	if err != nil {
+		return []model.TeamWithPlayersRespModel{}, err
+	}
+
+	return groupPlayers(teams, players), nil
+}
+
+// groupPlayers pairs every team with its players, in the order of teams.
+func groupPlayers(teams []model.TeamModel, players []player_model.PlayerModel) []model.TeamWithPlayersRespModel {
+	byTeam := make(map[int64][]player_model.PlayerModel, len(teams))
+	for _, player := range players {
+		byTeam[player.TeamID] = append(byTeam[player.TeamID], player)
+	}
+
+	res := make([]model.TeamWithPlayersRespModel, 0, len(teams))
+	for _, team := range teams {
+		teamPlayers := byTeam[team.ID]
+		if teamPlayers == nil {
+			teamPlayers = []player_model.PlayerModel{}
+		}
+
+		res = append(res, model.TeamWithPlayersRespModel{
+			TeamModel: team,
+			Players:   teamPlayers,
+		})
+	}
+
+	return res
+}
@@ -10,6 +10,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	model "github.com/tesarwijaya/ouroboros/internal/domain/team/model"
+	patch "github.com/tesarwijaya/ouroboros/internal/patch"
 )
 
 // MockTeamService is a mock of TeamService interface.
@@ -35,6 +36,21 @@ func (m *MockTeamService) EXPECT() *MockTeamServiceMockRecorder {
 	return m.recorder
 }
 
+// Delete mocks base method.
+func (m *MockTeamService) Delete(ctx context.Context, id int64) (model.TeamModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(model.TeamModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTeamServiceMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTeamService)(nil).Delete), ctx, id)
+}
+
 // FindAll mocks base method.
 func (m *MockTeamService) FindAll(ctx context.Context) ([]model.TeamModel, error) {
 	m.ctrl.T.Helper()
@@ -65,6 +81,52 @@ func (mr *MockTeamServiceMockRecorder) FindByID(ctx, id interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockTeamService)(nil).FindByID), ctx, id)
 }
 
+// FindByIDs mocks base method.
+func (m *MockTeamService) FindByIDs(ctx context.Context, ids []int64) ([]model.TeamModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByIDs", ctx, ids)
+	ret0, _ := ret[0].([]model.TeamModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByIDs indicates an expected call of FindByIDs.
+func (mr *MockTeamServiceMockRecorder) FindByIDs(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByIDs", reflect.TypeOf((*MockTeamService)(nil).FindByIDs), ctx, ids)
+}
+
+// FindByNames mocks base method.
+func (m *MockTeamService) FindByNames(ctx context.Context, names []string) ([]model.TeamModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByNames", ctx, names)
+	ret0, _ := ret[0].([]model.TeamModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByNames indicates an expected call of FindByNames.
+func (mr *MockTeamServiceMockRecorder) FindByNames(ctx, names interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByNames", reflect.TypeOf((*MockTeamService)(nil).FindByNames), ctx, names)
+}
+
+// FindFields mocks base method.
+func (m *MockTeamService) FindFields(ctx context.Context, ids []int64, fields []string) ([]model.TeamModel, []int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFields", ctx, ids, fields)
+	ret0, _ := ret[0].([]model.TeamModel)
+	ret1, _ := ret[1].([]int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindFields indicates an expected call of FindFields.
+func (mr *MockTeamServiceMockRecorder) FindFields(ctx, ids, fields interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFields", reflect.TypeOf((*MockTeamService)(nil).FindFields), ctx, ids, fields)
+}
+
 // FindTeamPlayer mocks base method.
 func (m *MockTeamService) FindTeamPlayer(ctx context.Context, id int64) (model.TeamPlayerRespModel, error) {
 	m.ctrl.T.Helper()
@@ -80,6 +142,22 @@ func (mr *MockTeamServiceMockRecorder) FindTeamPlayer(ctx, id interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTeamPlayer", reflect.TypeOf((*MockTeamService)(nil).FindTeamPlayer), ctx, id)
 }
 
+// FindTeamPlayers mocks base method.
+func (m *MockTeamService) FindTeamPlayers(ctx context.Context, ids []int64) ([]model.TeamWithPlayersRespModel, []int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTeamPlayers", ctx, ids)
+	ret0, _ := ret[0].([]model.TeamWithPlayersRespModel)
+	ret1, _ := ret[1].([]int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindTeamPlayers indicates an expected call of FindTeamPlayers.
+func (mr *MockTeamServiceMockRecorder) FindTeamPlayers(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTeamPlayers", reflect.TypeOf((*MockTeamService)(nil).FindTeamPlayers), ctx, ids)
+}
+
 // Insert mocks base method.
 func (m *MockTeamService) Insert(ctx context.Context, payload model.TeamModel) (model.TeamModel, error) {
 	m.ctrl.T.Helper()
@@ -94,3 +172,33 @@ func (mr *MockTeamServiceMockRecorder) Insert(ctx, payload interface{}) *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockTeamService)(nil).Insert), ctx, payload)
 }
+
+// Patch mocks base method.
+func (m *MockTeamService) Patch(ctx context.Context, id int64, p patch.Patch) (model.TeamModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Patch", ctx, id, p)
+	ret0, _ := ret[0].(model.TeamModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Patch indicates an expected call of Patch.
+func (mr *MockTeamServiceMockRecorder) Patch(ctx, id, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockTeamService)(nil).Patch), ctx, id, p)
+}
+
+// Rename mocks base method.
+func (m *MockTeamService) Rename(ctx context.Context, id int64, name string) (model.TeamModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rename", ctx, id, name)
+	ret0, _ := ret[0].(model.TeamModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rename indicates an expected call of Rename.
+func (mr *MockTeamServiceMockRecorder) Rename(ctx, id, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rename", reflect.TypeOf((*MockTeamService)(nil).Rename), ctx, id, name)
+}
@@ -2,31 +2,44 @@ package service_test
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"errors"
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	auth_model "github.com/tesarwijaya/ouroboros/internal/domain/auth/model"
+	auth_service "github.com/tesarwijaya/ouroboros/internal/domain/auth/service"
+	command_model "github.com/tesarwijaya/ouroboros/internal/domain/command/model"
+	event_model "github.com/tesarwijaya/ouroboros/internal/domain/event/model"
+	event_service "github.com/tesarwijaya/ouroboros/internal/domain/event/service"
 	player_model "github.com/tesarwijaya/ouroboros/internal/domain/player/model"
 	player_repository "github.com/tesarwijaya/ouroboros/internal/domain/player/repository"
 	"github.com/tesarwijaya/ouroboros/internal/domain/team/model"
 	"github.com/tesarwijaya/ouroboros/internal/domain/team/repository"
 	"github.com/tesarwijaya/ouroboros/internal/domain/team/service"
+	"github.com/tesarwijaya/ouroboros/internal/patch"
 )
 
 type resolverFn func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository)
 
-func createService(t *testing.T, resolver resolverFn) (*service.TeamServiceImpl, gomock.Controller) {
+func createService(t *testing.T, resolver resolverFn) (*service.TeamServiceImpl, *gomock.Controller) {
 	ctrl := gomock.NewController(t)
 
 	repo := repository.NewMockTeamRepository(ctrl)
 	playerRepo := player_repository.NewMockPlayerRepository(ctrl)
 	resolver(repo, playerRepo)
 
+	authz := auth_service.NewMockAuthorizer(ctrl)
+	authz.EXPECT().Authorize(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
 	return &service.TeamServiceImpl{
 		Repo:       repo,
 		PlayerRepo: playerRepo,
-	}, *ctrl
+		Authz:      authz,
+	}, ctrl
 }
 
 func Test_NewTeamService(t *testing.T) {
@@ -117,6 +130,157 @@ func Test_FindByID(t *testing.T) {
 	}
 }
 
+func Test_FindByIDs(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Param     []int64
+		Resolver  resolverFn
+		Expect    []model.TeamModel
+		ExpectErr error
+	}{
+		{
+			Name:  "when_success",
+			Param: []int64{1, 2},
+			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
+				repo.EXPECT().FindByIDs(gomock.Any(), []int64{1, 2}).
+					Return([]model.TeamModel{{ID: 1, Name: "some-team-name"}}, nil)
+			},
+			Expect: []model.TeamModel{{ID: 1, Name: "some-team-name"}},
+		},
+		{
+			Name:  "when_not_success",
+			Param: []int64{1},
+			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
+				repo.EXPECT().FindByIDs(gomock.Any(), []int64{1}).
+					Return([]model.TeamModel{}, errors.New("some-error"))
+			},
+			ExpectErr: errors.New("some-error"),
+		},
+	}
+
+	for _, test := range testCases {
+		svc, mock := createService(t, test.Resolver)
+		defer mock.Finish()
+
+		actual, err := svc.FindByIDs(context.Background(), test.Param)
+
+		if test.ExpectErr == nil {
+			assert.Equal(t, test.Expect, actual)
+			assert.Nil(t, err)
+		}
+
+		assert.Equal(t, test.ExpectErr, err)
+	}
+}
+
+func Test_FindFields(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		IDs           []int64
+		Fields        []string
+		Resolver      resolverFn
+		Expect        []model.TeamModel
+		ExpectMissing []int64
+		ExpectErr     error
+	}{
+		{
+			Name:   "when_ids_given",
+			IDs:    []int64{1, 2, 3},
+			Fields: []string{"name"},
+			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
+				repo.EXPECT().FindFields(gomock.Any(), []int64{1, 2, 3}, []string{"id", "name"}).
+					Return([]model.TeamModel{{ID: 2, Name: "some-team-name"}}, nil)
+			},
+			Expect:        []model.TeamModel{{ID: 2, Name: "some-team-name"}},
+			ExpectMissing: []int64{1, 3},
+		},
+		{
+			Name:   "when_every_team",
+			Fields: []string{"id"},
+			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
+				repo.EXPECT().FindFields(gomock.Any(), nil, []string{"id"}).
+					Return([]model.TeamModel{{ID: 1}}, nil)
+			},
+			Expect:        []model.TeamModel{{ID: 1}},
+			ExpectMissing: []int64{},
+		},
+		{
+			Name:   "when_field_unknown",
+			Fields: []string{"coach"},
+			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
+			},
+			ExpectErr: command_model.ValidationError{Err: errors.New(`unknown field "coach"`)},
+		},
+		{
+			Name: "when_not_success",
+			IDs:  []int64{1},
+			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
+				repo.EXPECT().FindFields(gomock.Any(), []int64{1}, []string{"id", "name"}).
+					Return([]model.TeamModel{}, errors.New("some-error"))
+			},
+			ExpectErr: errors.New("some-error"),
+		},
+	}
+
+	for _, test := range testCases {
+		svc, mock := createService(t, test.Resolver)
+		defer mock.Finish()
+
+		actual, missing, err := svc.FindFields(context.Background(), test.IDs, test.Fields)
+
+		if test.ExpectErr == nil {
+			assert.Equal(t, test.Expect, actual)
+			assert.Equal(t, test.ExpectMissing, missing)
+			assert.Nil(t, err)
+		}
+
+		assert.Equal(t, test.ExpectErr, err)
+	}
+}
+
+func Test_FindByNames(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Param     []string
+		Resolver  resolverFn
+		Expect    []model.TeamModel
+		ExpectErr error
+	}{
+		{
+			Name:  "when_success",
+			Param: []string{"some-team-name"},
+			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
+				repo.EXPECT().FindByNames(gomock.Any(), []string{"some-team-name"}).
+					Return([]model.TeamModel{{ID: 1, Name: "some-team-name"}}, nil)
+			},
+			Expect: []model.TeamModel{{ID: 1, Name: "some-team-name"}},
+		},
+		{
+			Name:  "when_not_success",
+			Param: []string{"some-team-name"},
+			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
+				repo.EXPECT().FindByNames(gomock.Any(), []string{"some-team-name"}).
+					Return([]model.TeamModel{}, errors.New("some-error"))
+			},
+			ExpectErr: errors.New("some-error"),
+		},
+	}
+
+	for _, test := range testCases {
+		svc, mock := createService(t, test.Resolver)
+		defer mock.Finish()
+
+		actual, err := svc.FindByNames(context.Background(), test.Param)
+
+		if test.ExpectErr == nil {
+			assert.Equal(t, test.Expect, actual)
+			assert.Nil(t, err)
+		}
+
+		assert.Equal(t, test.ExpectErr, err)
+	}
+}
+
 func Test_Insert(t *testing.T) {
 	testCases := []struct {
 		Name      string
@@ -130,16 +294,16 @@ func Test_Insert(t *testing.T) {
 			Param: model.TeamModel{Name: "some-team-name"},
 			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
 				repo.EXPECT().Insert(gomock.Any(), model.TeamModel{Name: "some-team-name"}).
-					Return(nil)
+					Return(model.TeamModel{ID: 1, Name: "some-team-name"}, nil)
 			},
-			Expect: model.TeamModel{Name: "some-team-name"},
+			Expect: model.TeamModel{ID: 1, Name: "some-team-name"},
 		},
 		{
 			Name:  "when_not_success",
 			Param: model.TeamModel{Name: "some-team-name"},
 			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
 				repo.EXPECT().Insert(gomock.Any(), model.TeamModel{Name: "some-team-name"}).
-					Return(errors.New("some-error"))
+					Return(model.TeamModel{}, errors.New("some-error"))
 			},
 			ExpectErr: errors.New("some-error"),
 		},
@@ -160,6 +324,264 @@ func Test_Insert(t *testing.T) {
 	}
 }
 
+func Test_InsertDryRun(t *testing.T) {
+	svc, mock := createService(t, func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {})
+	defer mock.Finish()
+
+	actual, err := svc.Insert(command_model.WithDryRun(context.Background()), model.TeamModel{Name: "some-team-name"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, model.TeamModel{Name: "some-team-name"}, actual)
+}
+
+func Test_Rename(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		DryRun      bool
+		Resolver    resolverFn
+		Publish     error
+		Expect      model.TeamModel
+		ExpectEvent model.UpdatedEventData
+		ExpectErr   error
+	}{
+		{
+			Name: "when_success",
+			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
+				repo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(1)).
+					Return(model.TeamModel{ID: 1, Name: "some-team-name"}, nil)
+				repo.EXPECT().UpdateName(gomock.Any(), int64(1), "new-team-name").
+					Return(model.TeamModel{ID: 1, Name: "new-team-name"}, nil)
+			},
+			Expect: model.TeamModel{ID: 1, Name: "new-team-name"},
+			ExpectEvent: model.UpdatedEventData{
+				TeamID: 1,
+				Fields: []string{"name"},
+				Team:   model.TeamModel{ID: 1, Name: "new-team-name"},
+			},
+		},
+		{
+			Name: "when_name_unchanged",
+			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
+				repo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(1)).
+					Return(model.TeamModel{ID: 1, Name: "new-team-name"}, nil)
+			},
+			Expect: model.TeamModel{ID: 1, Name: "new-team-name"},
+		},
+		{
+			Name: "when_not_found",
+			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
+				repo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(1)).
+					Return(model.TeamModel{}, sql.ErrNoRows)
+			},
+			ExpectErr: sql.ErrNoRows,
+		},
+		{
+			Name: "when_not_success",
+			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
+				repo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(1)).
+					Return(model.TeamModel{ID: 1, Name: "some-team-name"}, nil)
+				repo.EXPECT().UpdateName(gomock.Any(), int64(1), "new-team-name").
+					Return(model.TeamModel{}, errors.New("some-error"))
+			},
+			ExpectErr: errors.New("some-error"),
+		},
+		{
+			Name: "when_publish_fails",
+			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
+				repo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(1)).
+					Return(model.TeamModel{ID: 1, Name: "some-team-name"}, nil)
+				repo.EXPECT().UpdateName(gomock.Any(), int64(1), "new-team-name").
+					Return(model.TeamModel{ID: 1, Name: "new-team-name"}, nil)
+			},
+			Publish:   errors.New("some-error"),
+			ExpectErr: errors.New("some-error"),
+		},
+		{
+			Name:   "when_dry_run",
+			DryRun: true,
+			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
+				repo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(1)).
+					Return(model.TeamModel{ID: 1, Name: "some-team-name"}, nil)
+			},
+			Expect: model.TeamModel{ID: 1, Name: "new-team-name"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			svc, mock := createService(t, test.Resolver)
+			defer mock.Finish()
+
+			db, dbMock, _ := sqlmock.New()
+			defer db.Close()
+			dbMock.ExpectBegin()
+			if test.ExpectErr != nil {
+				dbMock.ExpectRollback()
+			} else {
+				dbMock.ExpectCommit()
+			}
+			svc.Db = db
+
+			var published []event_model.Event
+			bus := event_service.NewMockEventBus(mock)
+			bus.EXPECT().Publish(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, events ...event_model.Event) error {
+				if test.Publish == nil {
+					published = append(published, events...)
+				}
+				return test.Publish
+			}).AnyTimes()
+			svc.EventBus = bus
+
+			ctx := context.Background()
+			if test.DryRun {
+				ctx = command_model.WithDryRun(ctx)
+			}
+
+			actual, err := svc.Rename(ctx, 1, "new-team-name")
+			assert.Nil(t, dbMock.ExpectationsWereMet())
+			assert.Equal(t, test.ExpectErr, err)
+
+			if test.ExpectErr != nil {
+				return
+			}
+
+			assert.Equal(t, test.Expect, actual)
+
+			if test.ExpectEvent.TeamID == 0 {
+				assert.Empty(t, published)
+				return
+			}
+
+			assert.Len(t, published, 1)
+			assert.Equal(t, model.TEAM_UPDATED_EVENT, published[0].Type)
+			assert.Equal(t, model.TeamStreamID(1), published[0].StreamID)
+
+			var data model.UpdatedEventData
+			assert.Nil(t, json.Unmarshal(published[0].Data, &data))
+			assert.Equal(t, test.ExpectEvent, data)
+		})
+	}
+}
+
+func Test_Delete(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		DryRun    bool
+		Resolver  resolverFn
+		Publish   error
+		Expect    model.TeamModel
+		ExpectErr error
+	}{
+		{
+			Name: "when_success",
+			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
+				repo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(1)).
+					Return(model.TeamModel{ID: 1, Name: "some-team-name"}, nil)
+				playerRepo.EXPECT().FindByTeamID(gomock.Any(), int64(1)).
+					Return([]player_model.PlayerModel{}, nil)
+				repo.EXPECT().Delete(gomock.Any(), int64(1)).Return(nil)
+			},
+			Expect: model.TeamModel{ID: 1, Name: "some-team-name"},
+		},
+		{
+			Name: "when_team_has_players",
+			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
+				repo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(1)).
+					Return(model.TeamModel{ID: 1, Name: "some-team-name"}, nil)
+				playerRepo.EXPECT().FindByTeamID(gomock.Any(), int64(1)).
+					Return([]player_model.PlayerModel{{ID: 2, TeamID: 1}}, nil)
+			},
+			ExpectErr: command_model.ValidationError{Err: model.ErrTeamHasPlayers},
+		},
+		{
+			Name: "when_not_found",
+			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
+				repo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(1)).
+					Return(model.TeamModel{}, sql.ErrNoRows)
+			},
+			ExpectErr: sql.ErrNoRows,
+		},
+		{
+			Name: "when_publish_fails",
+			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
+				repo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(1)).
+					Return(model.TeamModel{ID: 1, Name: "some-team-name"}, nil)
+				playerRepo.EXPECT().FindByTeamID(gomock.Any(), int64(1)).
+					Return([]player_model.PlayerModel{}, nil)
+				repo.EXPECT().Delete(gomock.Any(), int64(1)).Return(nil)
+			},
+			Publish:   errors.New("some-error"),
+			ExpectErr: errors.New("some-error"),
+		},
+		{
+			Name:   "when_dry_run",
+			DryRun: true,
+			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
+				repo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(1)).
+					Return(model.TeamModel{ID: 1, Name: "some-team-name"}, nil)
+				playerRepo.EXPECT().FindByTeamID(gomock.Any(), int64(1)).
+					Return([]player_model.PlayerModel{}, nil)
+			},
+			Expect: model.TeamModel{ID: 1, Name: "some-team-name"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			svc, mock := createService(t, test.Resolver)
+			defer mock.Finish()
+
+			db, dbMock, _ := sqlmock.New()
+			defer db.Close()
+			dbMock.ExpectBegin()
+			if test.ExpectErr != nil {
+				dbMock.ExpectRollback()
+			} else {
+				dbMock.ExpectCommit()
+			}
+			svc.Db = db
+
+			var published []event_model.Event
+			bus := event_service.NewMockEventBus(mock)
+			bus.EXPECT().Publish(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, events ...event_model.Event) error {
+				if test.Publish == nil {
+					published = append(published, events...)
+				}
+				return test.Publish
+			}).AnyTimes()
+			svc.EventBus = bus
+
+			ctx := context.Background()
+			if test.DryRun {
+				ctx = command_model.WithDryRun(ctx)
+			}
+
+			actual, err := svc.Delete(ctx, 1)
+			assert.Nil(t, dbMock.ExpectationsWereMet())
+			assert.Equal(t, test.ExpectErr, err)
+
+			if test.ExpectErr != nil {
+				return
+			}
+
+			assert.Equal(t, test.Expect, actual)
+
+			if test.DryRun {
+				assert.Empty(t, published)
+				return
+			}
+
+			assert.Len(t, published, 1)
+			assert.Equal(t, model.TEAM_DELETED_EVENT, published[0].Type)
+			assert.Equal(t, model.TeamStreamID(1), published[0].StreamID)
+
+			var data model.DeletedEventData
+			assert.Nil(t, json.Unmarshal(published[0].Data, &data))
+			assert.Equal(t, model.DeletedEventData{TeamID: 1, Team: test.Expect}, data)
+		})
+	}
+}
+
 func Test_FindTeamPlayer(t *testing.T) {
 	testCases := []struct {
 		Name      string
@@ -173,16 +595,41 @@ func Test_FindTeamPlayer(t *testing.T) {
 			Param: 1,
 			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
 				repo.EXPECT().FindByID(gomock.Any(), int64(1)).
-					Return(model.TeamModel{Name: "some-team-name"}, nil)
-				playerRepo.EXPECT().FindByTeamID(gomock.Any(), int64(1)).
+					Return(model.TeamModel{ID: 1, Name: "some-team-name"}, nil)
+				playerRepo.EXPECT().FindByTeamIDs(gomock.Any(), []int64{1}).
 					Return([]player_model.PlayerModel{{Name: "some-player", TeamID: 1}}, nil)
 
 			},
 			Expect: model.TeamPlayerRespModel{
-				TeamModel: model.TeamModel{Name: "some-team-name"},
+				TeamModel: model.TeamModel{ID: 1, Name: "some-team-name"},
 				Players:   []player_model.PlayerModel{{Name: "some-player", TeamID: 1}},
 			},
 		},
+		{
+			Name:  "when_team_has_no_players",
+			Param: 1,
+			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
+				repo.EXPECT().FindByID(gomock.Any(), int64(1)).
+					Return(model.TeamModel{ID: 1, Name: "some-team-name"}, nil)
+				playerRepo.EXPECT().FindByTeamIDs(gomock.Any(), []int64{1}).
+					Return([]player_model.PlayerModel{}, nil)
+			},
+			Expect: model.TeamPlayerRespModel{
+				TeamModel: model.TeamModel{ID: 1, Name: "some-team-name"},
+				Players:   []player_model.PlayerModel{},
+			},
+		},
+		{
+			Name:  "when_players_not_found",
+			Param: 1,
+			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
+				repo.EXPECT().FindByID(gomock.Any(), int64(1)).
+					Return(model.TeamModel{ID: 1, Name: "some-team-name"}, nil)
+				playerRepo.EXPECT().FindByTeamIDs(gomock.Any(), []int64{1}).
+					Return(nil, errors.New("some-error"))
+			},
+			ExpectErr: errors.New("some-error"),
+		},
 		{
 			Name:  "when_not_success",
 			Param: 1,
@@ -208,3 +655,351 @@ func Test_FindTeamPlayer(t *testing.T) {
 		assert.Equal(t, test.ExpectErr, err)
 	}
 }
+
+func Test_FindTeamPlayers(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Param         []int64
+		Resolver      resolverFn
+		Expect        []model.TeamWithPlayersRespModel
+		ExpectMissing []int64
+		ExpectErr     error
+	}{
+		{
+			Name: "when_all_teams",
+			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
+				repo.EXPECT().FindAll(gomock.Any()).
+					Return([]model.TeamModel{{ID: 1, Name: "owls"}, {ID: 2, Name: "larks"}}, nil)
+				playerRepo.EXPECT().FindAll(gomock.Any()).
+					Return([]player_model.PlayerModel{
+						{ID: 10, Name: "jane", TeamID: 1},
+						{ID: 11, Name: "john", TeamID: 1},
+						{ID: 13, Name: "jim", TeamID: 3},
+					}, nil)
+			},
+			Expect: []model.TeamWithPlayersRespModel{
+				{
+					TeamModel: model.TeamModel{ID: 1, Name: "owls"},
+					Players: []player_model.PlayerModel{
+						{ID: 10, Name: "jane", TeamID: 1},
+						{ID: 11, Name: "john", TeamID: 1},
+					},
+				},
+				{
+					TeamModel: model.TeamModel{ID: 2, Name: "larks"},
+					Players:   []player_model.PlayerModel{},
+				},
+			},
+			ExpectMissing: []int64{},
+		},
+		{
+			Name:  "when_ids",
+			Param: []int64{2, 3},
+			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
+				repo.EXPECT().FindByIDs(gomock.Any(), []int64{2, 3}).
+					Return([]model.TeamModel{{ID: 2, Name: "larks"}}, nil)
+				playerRepo.EXPECT().FindByTeamIDs(gomock.Any(), []int64{2}).
+					Return([]player_model.PlayerModel{{ID: 12, Name: "joe", TeamID: 2}}, nil)
+			},
+			Expect: []model.TeamWithPlayersRespModel{
+				{
+					TeamModel: model.TeamModel{ID: 2, Name: "larks"},
+					Players:   []player_model.PlayerModel{{ID: 12, Name: "joe", TeamID: 2}},
+				},
+			},
+			ExpectMissing: []int64{3},
+		},
+		{
+			Name: "when_teams_not_found",
+			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
+				repo.EXPECT().FindAll(gomock.Any()).Return(nil, errors.New("some-error"))
+			},
+			ExpectErr: errors.New("some-error"),
+		},
+		{
+			Name: "when_players_not_found",
+			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
+				repo.EXPECT().FindAll(gomock.Any()).
+					Return([]model.TeamModel{{ID: 1, Name: "owls"}}, nil)
+				playerRepo.EXPECT().FindAll(gomock.Any()).
+					Return(nil, errors.New("some-error"))
+			},
+			ExpectErr: errors.New("some-error"),
+		},
+		{
+			Name:  "when_players_of_ids_not_found",
+			Param: []int64{1},
+			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
+				repo.EXPECT().FindByIDs(gomock.Any(), []int64{1}).
+					Return([]model.TeamModel{{ID: 1, Name: "owls"}}, nil)
+				playerRepo.EXPECT().FindByTeamIDs(gomock.Any(), []int64{1}).
+					Return(nil, errors.New("some-error"))
+			},
+			ExpectErr: errors.New("some-error"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			svc, mock := createService(t, test.Resolver)
+			defer mock.Finish()
+
+			actual, missing, err := svc.FindTeamPlayers(context.Background(), test.Param)
+
+			if test.ExpectErr == nil {
+				assert.Equal(t, test.Expect, actual)
+				assert.Equal(t, test.ExpectMissing, missing)
+				assert.Nil(t, err)
+			}
+
+			assert.Equal(t, test.ExpectErr, err)
+		})
+	}
+}
+
+func Test_Patch(t *testing.T) {
+	mergePatch := func(body string) patch.Patch {
+		return patch.Patch{Type: patch.MIME_MERGE_PATCH, Body: []byte(body)}
+	}
+
+	testCases := []struct {
+		Name        string
+		Patch       patch.Patch
+		Resolver    resolverFn
+		Publish     error
+		Expect      model.TeamModel
+		ExpectEvent model.UpdatedEventData
+		ExpectErr   string
+	}{
+		{
+			Name:  "when_merge_patch",
+			Patch: mergePatch(`{"name": "larks"}`),
+			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
+				repo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(1)).Return(model.TeamModel{ID: 1, Name: "owls"}, nil)
+				repo.EXPECT().Update(gomock.Any(), int64(1), map[string]interface{}{"name": "larks"}).
+					Return(model.TeamModel{ID: 1, Name: "larks"}, nil)
+			},
+			Expect:      model.TeamModel{ID: 1, Name: "larks"},
+			ExpectEvent: model.UpdatedEventData{TeamID: 1, Fields: []string{"name"}, Team: model.TeamModel{ID: 1, Name: "larks"}},
+		},
+		{
+			Name:  "when_json_patch",
+			Patch: patch.Patch{Type: patch.MIME_JSON_PATCH, Body: []byte(`[{"op": "test", "path": "/name", "value": "owls"}, {"op": "replace", "path": "/name", "value": "larks"}]`)},
+			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
+				repo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(1)).Return(model.TeamModel{ID: 1, Name: "owls"}, nil)
+				repo.EXPECT().Update(gomock.Any(), int64(1), map[string]interface{}{"name": "larks"}).
+					Return(model.TeamModel{ID: 1, Name: "larks"}, nil)
+			},
+			Expect:      model.TeamModel{ID: 1, Name: "larks"},
+			ExpectEvent: model.UpdatedEventData{TeamID: 1, Fields: []string{"name"}, Team: model.TeamModel{ID: 1, Name: "larks"}},
+		},
+		{
+			Name:  "when_nothing_changes",
+			Patch: mergePatch(`{}`),
+			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
+				repo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(1)).Return(model.TeamModel{ID: 1, Name: "owls"}, nil)
+			},
+			Expect: model.TeamModel{ID: 1, Name: "owls"},
+		},
+		{
+			Name:  "when_json_patch_test_fails",
+			Patch: patch.Patch{Type: patch.MIME_JSON_PATCH, Body: []byte(`[{"op": "test", "path": "/name", "value": "larks"}]`)},
+			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
+				repo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(1)).Return(model.TeamModel{ID: 1, Name: "owls"}, nil)
+			},
+			ExpectErr: "invalid patch: testing value /name failed: test failed",
+		},
+		{
+			Name:  "when_id_changes",
+			Patch: mergePatch(`{"id": 2}`),
+			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
+				repo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(1)).Return(model.TeamModel{ID: 1, Name: "owls"}, nil)
+			},
+			ExpectErr: "id can't be changed",
+		},
+		{
+			Name:  "when_name_is_blank",
+			Patch: mergePatch(`{"name": " "}`),
+			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
+				repo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(1)).Return(model.TeamModel{ID: 1, Name: "owls"}, nil)
+			},
+			ExpectErr: "name is required",
+		},
+		{
+			Name:  "when_update_fails",
+			Patch: mergePatch(`{"name": "larks"}`),
+			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
+				repo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(1)).Return(model.TeamModel{ID: 1, Name: "owls"}, nil)
+				repo.EXPECT().Update(gomock.Any(), int64(1), gomock.Any()).Return(model.TeamModel{}, errors.New("some-error"))
+			},
+			ExpectErr: "some-error",
+		},
+		{
+			Name:  "when_publish_fails",
+			Patch: mergePatch(`{"name": "larks"}`),
+			Resolver: func(repo *repository.MockTeamRepository, playerRepo *player_repository.MockPlayerRepository) {
+				repo.EXPECT().FindByIDForUpdate(gomock.Any(), int64(1)).Return(model.TeamModel{ID: 1, Name: "owls"}, nil)
+				repo.EXPECT().Update(gomock.Any(), int64(1), gomock.Any()).Return(model.TeamModel{ID: 1, Name: "larks"}, nil)
+			},
+			Publish:   errors.New("some-error"),
+			ExpectErr: "some-error",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			svc, mock := createService(t, test.Resolver)
+			defer mock.Finish()
+
+			db, dbMock, _ := sqlmock.New()
+			defer db.Close()
+			dbMock.ExpectBegin()
+			if test.ExpectErr != "" {
+				dbMock.ExpectRollback()
+			} else {
+				dbMock.ExpectCommit()
+			}
+			svc.Db = db
+
+			var published []event_model.Event
+			bus := event_service.NewMockEventBus(mock)
+			bus.EXPECT().Publish(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, events ...event_model.Event) error {
+				if test.Publish == nil {
+					published = append(published, events...)
+				}
+				return test.Publish
+			}).AnyTimes()
+			svc.EventBus = bus
+
+			actual, err := svc.Patch(context.Background(), 1, test.Patch)
+			assert.Nil(t, dbMock.ExpectationsWereMet())
+
+			if test.ExpectErr != "" {
+				assert.EqualError(t, err, test.ExpectErr)
+				assert.Empty(t, published)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, test.Expect, actual)
+
+			if test.ExpectEvent.TeamID == 0 {
+				assert.Empty(t, published)
+				return
+			}
+
+			assert.Len(t, published, 1)
+			assert.Equal(t, model.TEAM_UPDATED_EVENT, published[0].Type)
+			assert.Equal(t, model.TeamStreamID(1), published[0].StreamID)
+
+			var data model.UpdatedEventData
+			assert.Nil(t, json.Unmarshal(published[0].Data, &data))
+			assert.Equal(t, test.ExpectEvent, data)
+		})
+	}
+}
+
+func Test_Authorize(t *testing.T) {
+	forbidden := func(action string) error {
+		return auth_model.ForbiddenError{Subject: "some-user", Action: action}
+	}
+
+	testCases := []struct {
+		Name     string
+		Action   string
+		Resource auth_model.Resource
+		Call     func(svc *service.TeamServiceImpl) error
+	}{
+		{
+			Name:     "when_find_all",
+			Action:   auth_model.ACTION_TEAM_READ,
+			Resource: auth_model.Resource{},
+			Call: func(svc *service.TeamServiceImpl) error {
+				_, err := svc.FindAll(context.Background())
+				return err
+			},
+		},
+		{
+			Name:     "when_find_by_id",
+			Action:   auth_model.ACTION_TEAM_READ,
+			Resource: auth_model.Resource{TeamID: 1},
+			Call: func(svc *service.TeamServiceImpl) error {
+				_, err := svc.FindByID(context.Background(), 1)
+				return err
+			},
+		},
+		{
+			Name:     "when_find_by_ids",
+			Action:   auth_model.ACTION_TEAM_READ,
+			Resource: auth_model.Resource{TeamID: 1},
+			Call: func(svc *service.TeamServiceImpl) error {
+				_, err := svc.FindByIDs(context.Background(), []int64{1, 2})
+				return err
+			},
+		},
+		{
+			Name:     "when_find_team_players",
+			Action:   auth_model.ACTION_TEAM_READ,
+			Resource: auth_model.Resource{TeamID: 1},
+			Call: func(svc *service.TeamServiceImpl) error {
+				_, _, err := svc.FindTeamPlayers(context.Background(), []int64{1, 2})
+				return err
+			},
+		},
+		{
+			Name:     "when_rename",
+			Action:   auth_model.ACTION_TEAM_UPDATE,
+			Resource: auth_model.Resource{TeamID: 1},
+			Call: func(svc *service.TeamServiceImpl) error {
+				_, err := svc.Rename(context.Background(), 1, "new-team-name")
+				return err
+			},
+		},
+		{
+			Name:     "when_patch",
+			Action:   auth_model.ACTION_TEAM_UPDATE,
+			Resource: auth_model.Resource{TeamID: 1},
+			Call: func(svc *service.TeamServiceImpl) error {
+				_, err := svc.Patch(context.Background(), 1, patch.Patch{Type: patch.MIME_MERGE_PATCH, Body: []byte(`{"name": "larks"}`)})
+				return err
+			},
+		},
+		{
+			Name:     "when_delete",
+			Action:   auth_model.ACTION_TEAM_DELETE,
+			Resource: auth_model.Resource{TeamID: 1},
+			Call: func(svc *service.TeamServiceImpl) error {
+				_, err := svc.Delete(context.Background(), 1)
+				return err
+			},
+		},
+		{
+			Name:     "when_insert",
+			Action:   auth_model.ACTION_TEAM_CREATE,
+			Resource: auth_model.Resource{},
+			Call: func(svc *service.TeamServiceImpl) error {
+				_, err := svc.Insert(context.Background(), model.TeamModel{Name: "some-team"})
+				return err
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			authz := auth_service.NewMockAuthorizer(ctrl)
+			authz.EXPECT().Authorize(gomock.Any(), test.Action, test.Resource).Return(forbidden(test.Action))
+
+			svc := &service.TeamServiceImpl{
+				Repo:       repository.NewMockTeamRepository(ctrl),
+				PlayerRepo: player_repository.NewMockPlayerRepository(ctrl),
+				Authz:      authz,
+			}
+
+			err := test.Call(svc)
+			assert.Equal(t, forbidden(test.Action), err)
+		})
+	}
+}
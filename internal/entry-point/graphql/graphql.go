@@ -0,0 +1,112 @@
+package graphql
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/labstack/echo/v4"
+	command_service "github.com/tesarwijaya/ouroboros/internal/domain/command/service"
+	player_service "github.com/tesarwijaya/ouroboros/internal/domain/player/service"
+	team_service "github.com/tesarwijaya/ouroboros/internal/domain/team/service"
+	"github.com/tesarwijaya/ouroboros/internal/logger"
+	"go.uber.org/zap"
+)
+
+// MAX_QUERY_DEPTH bounds how deep teams, players and transfers can be nested
+// in a single query.
+const MAX_QUERY_DEPTH = 8
+
+//go:embed schema.graphql
+var schema string
+
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+type GraphqlHandler struct {
+	Schema *graphql.Schema
+	Team   team_service.TeamService
+	Player player_service.PlayerService
+}
+
+func NewGraphqlHandler(team team_service.TeamService, player player_service.PlayerService, bus command_service.CommandBus) (GraphqlHandler, error) {
+	s, err := graphql.ParseSchema(schema,
+		&Resolver{
+			TeamService:   team,
+			PlayerService: player,
+			Bus:           bus,
+		},
+		graphql.MaxDepth(MAX_QUERY_DEPTH),
+		graphql.Logger(recoverer{}),
+		graphql.PanicHandler(recoverer{}),
+	)
+	if err != nil {
+		return GraphqlHandler{}, fmt.Errorf("parse graphql schema: %w", err)
+	}
+
+	return GraphqlHandler{
+		Schema: s,
+		Team:   team,
+		Player: player,
+	}, nil
+}
+
+func (h *GraphqlHandler) SetRouter(ec *echo.Echo) {
+	ec.POST("/graphql", h.Serve)
+}
+
+// Serve godoc
+// @Summary      GraphQL endpoint
+// @Description  run a GraphQL query or mutation on teams, players and their transfers, errors are reported in the errors of the response with their code in the extensions
+// @Tags         GraphQL
+// @Accept       json
+// @Produce      json
+// @param        Idempotency-Key header string false "key to safely retry the request"
+// @param        request body Request true "body"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  echo.HTTPError
+// @Failure      401  {object}  echo.HTTPError
+// @Failure      429  {object}  echo.HTTPError
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Router       /graphql [post]
+func (h *GraphqlHandler) Serve(ec echo.Context) error {
+	var req Request
+
+	if err := ec.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if strings.TrimSpace(req.Query) == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "query is required")
+	}
+
+	ctx := WithLoaders(ec.Request().Context(), NewLoaders(h.Team, h.Player))
+
+	return ec.JSON(http.StatusOK, h.Schema.Exec(ctx, req.Query, req.OperationName, req.Variables))
+}
+
+// recoverer logs a panicking resolver like the REST recover middleware does,
+// the client is only told about an internal error.
+type recoverer struct{}
+
+func (recoverer) LogPanic(ctx context.Context, value interface{}) {
+	logger.FromContext(ctx).Error("resolver panicked",
+		zap.String("panic", fmt.Sprint(value)),
+		zap.Stack("stack"),
+	)
+}
+
+func (recoverer) MakePanicError(ctx context.Context, value interface{}) *gqlerrors.QueryError {
+	return &gqlerrors.QueryError{
+		Message:    http.StatusText(http.StatusInternalServerError),
+		Extensions: map[string]interface{}{"code": CODE_INTERNAL_SERVER_ERROR},
+	}
+}
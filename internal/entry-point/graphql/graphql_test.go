@@ -0,0 +1,286 @@
+package graphql_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	auth_model "github.com/tesarwijaya/ouroboros/internal/domain/auth/model"
+	command_model "github.com/tesarwijaya/ouroboros/internal/domain/command/model"
+	command_service "github.com/tesarwijaya/ouroboros/internal/domain/command/service"
+	player_model "github.com/tesarwijaya/ouroboros/internal/domain/player/model"
+	player_service "github.com/tesarwijaya/ouroboros/internal/domain/player/service"
+	team_model "github.com/tesarwijaya/ouroboros/internal/domain/team/model"
+	team_service "github.com/tesarwijaya/ouroboros/internal/domain/team/service"
+	"github.com/tesarwijaya/ouroboros/internal/entry-point/graphql"
+)
+
+type ResolverFn func(teamSvc *team_service.MockTeamService, playerSvc *player_service.MockPlayerService)
+
+func createServer(t *testing.T, resolver ResolverFn) (*echo.Echo, *gomock.Controller) {
+	ctrl := gomock.NewController(t)
+
+	teamSvc := team_service.NewMockTeamService(ctrl)
+	playerSvc := player_service.NewMockPlayerService(ctrl)
+	resolver(teamSvc, playerSvc)
+
+	bus, _ := command_service.NewCommandBus(command_service.CommandBusImpl{
+		Handlers:    append(team_service.NewCommandHandlers(teamSvc).Handlers, player_service.NewCommandHandlers(playerSvc).Handlers...),
+		Middlewares: []command_model.Middleware{command_service.NewValidationMiddleware()},
+	})
+
+	h, err := graphql.NewGraphqlHandler(teamSvc, playerSvc, bus)
+	assert.Nil(t, err)
+
+	e := echo.New()
+	h.SetRouter(e)
+
+	return e, ctrl
+}
+
+func do(e *echo.Echo, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func query(q string) string {
+	body, _ := json.Marshal(graphql.Request{Query: q})
+	return string(body)
+}
+
+// findTeamPlayers returns the players of the requested teams, like the
+// repository would in a single query.
+func findTeamPlayers(players ...player_model.PlayerModel) func(ctx context.Context, teamIDs []int64) ([]player_model.PlayerModel, error) {
+	return func(ctx context.Context, teamIDs []int64) ([]player_model.PlayerModel, error) {
+		res := []player_model.PlayerModel{}
+		for _, player := range players {
+			for _, teamID := range teamIDs {
+				if player.TeamID == teamID {
+					res = append(res, player)
+				}
+			}
+		}
+
+		return res, nil
+	}
+}
+
+func findTeams(teams ...team_model.TeamModel) func(ctx context.Context, ids []int64) ([]team_model.TeamModel, error) {
+	return func(ctx context.Context, ids []int64) ([]team_model.TeamModel, error) {
+		res := []team_model.TeamModel{}
+		for _, team := range teams {
+			for _, id := range ids {
+				if team.ID == id {
+					res = append(res, team)
+				}
+			}
+		}
+
+		return res, nil
+	}
+}
+
+func Test_NewGraphqlHandler(t *testing.T) {
+	_, err := graphql.NewGraphqlHandler(nil, nil, nil)
+
+	assert.Nil(t, err)
+}
+
+func Test_Serve(t *testing.T) {
+	at := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	testCases := []struct {
+		Name         string
+		Body         string
+		Resolver     ResolverFn
+		ExpectStatus int
+		ExpectBody   string
+	}{
+		{
+			Name: "when_teams_with_players_loaded_in_one_batch",
+			Body: query(`{ teams { id name players { id name } } }`),
+			Resolver: func(teamSvc *team_service.MockTeamService, playerSvc *player_service.MockPlayerService) {
+				teamSvc.EXPECT().FindAll(gomock.Any()).
+					Return([]team_model.TeamModel{{ID: 1, Name: "some-team"}, {ID: 2, Name: "other-team"}}, nil)
+				playerSvc.EXPECT().FindByTeamIDs(gomock.Any(), gomock.Len(2)).
+					DoAndReturn(findTeamPlayers(
+						player_model.PlayerModel{ID: 10, Name: "some-player", TeamID: 1},
+						player_model.PlayerModel{ID: 11, Name: "other-player", TeamID: 1},
+					)).
+					Times(1)
+			},
+			ExpectStatus: http.StatusOK,
+			ExpectBody: `{"data":{"teams":[
+				{"id":"1","name":"some-team","players":[{"id":"10","name":"some-player"},{"id":"11","name":"other-player"}]},
+				{"id":"2","name":"other-team","players":[]}
+			]}}`,
+		},
+		{
+			Name: "when_player_with_team_and_transfers",
+			Body: query(`{ player(id: "10") { name team { name } transfers { from { name } to { name } transferredAt } } }`),
+			Resolver: func(teamSvc *team_service.MockTeamService, playerSvc *player_service.MockPlayerService) {
+				playerSvc.EXPECT().FindByID(gomock.Any(), int64(10)).
+					Return(player_model.PlayerModel{ID: 10, Name: "some-player", TeamID: 2}, nil)
+				playerSvc.EXPECT().FindTransfersOf(gomock.Any(), []player_model.PlayerModel{{ID: 10, Name: "some-player", TeamID: 2}}).
+					Return(map[int64][]player_model.TransferModel{
+						10: {{PlayerID: 10, FromTeamID: 1, ToTeamID: 2, TransferredAt: at}},
+					}, nil)
+				teamSvc.EXPECT().FindByIDs(gomock.Any(), gomock.Any()).
+					DoAndReturn(findTeams(
+						team_model.TeamModel{ID: 1, Name: "some-team"},
+						team_model.TeamModel{ID: 2, Name: "other-team"},
+					)).
+					MaxTimes(2)
+			},
+			ExpectStatus: http.StatusOK,
+			ExpectBody: `{"data":{"player":{"name":"some-player","team":{"name":"other-team"},"transfers":[
+				{"from":{"name":"some-team"},"to":{"name":"other-team"},"transferredAt":"2023-01-02T03:04:05Z"}
+			]}}}`,
+		},
+		{
+			Name: "when_team_players_transfers_loaded_in_one_batch",
+			Body: query(`{ teams { players { id transfers { transferredAt } } } }`),
+			Resolver: func(teamSvc *team_service.MockTeamService, playerSvc *player_service.MockPlayerService) {
+				teamSvc.EXPECT().FindAll(gomock.Any()).
+					Return([]team_model.TeamModel{{ID: 1, Name: "some-team"}}, nil)
+				playerSvc.EXPECT().FindByTeamIDs(gomock.Any(), gomock.Len(1)).
+					DoAndReturn(findTeamPlayers(
+						player_model.PlayerModel{ID: 10, Name: "some-player", TeamID: 1},
+						player_model.PlayerModel{ID: 11, Name: "other-player", TeamID: 1},
+					))
+				playerSvc.EXPECT().FindTransfersOf(gomock.Any(), gomock.Len(2)).
+					Return(map[int64][]player_model.TransferModel{
+						10: {{PlayerID: 10, FromTeamID: 2, ToTeamID: 1, TransferredAt: at}},
+					}, nil).
+					Times(1)
+			},
+			ExpectStatus: http.StatusOK,
+			ExpectBody: `{"data":{"teams":[{"players":[
+				{"id":"10","transfers":[{"transferredAt":"2023-01-02T03:04:05Z"}]},
+				{"id":"11","transfers":[]}
+			]}]}}`,
+		},
+		{
+			Name: "when_player_not_found",
+			Body: query(`{ player(id: "10") { name } }`),
+			Resolver: func(teamSvc *team_service.MockTeamService, playerSvc *player_service.MockPlayerService) {
+				playerSvc.EXPECT().FindByID(gomock.Any(), int64(10)).
+					Return(player_model.PlayerModel{}, sql.ErrNoRows)
+			},
+			ExpectStatus: http.StatusOK,
+			ExpectBody:   `{"data":{"player":null}}`,
+		},
+		{
+			Name:         "when_id_invalid",
+			Body:         query(`{ team(id: "abc") { name } }`),
+			Resolver:     func(teamSvc *team_service.MockTeamService, playerSvc *player_service.MockPlayerService) {},
+			ExpectStatus: http.StatusOK,
+			ExpectBody: `{"data":{"team":null},"errors":[
+				{"message":"invalid id \"abc\"","path":["team"],"extensions":{"code":"BAD_USER_INPUT"}}
+			]}`,
+		},
+		{
+			Name: "when_forbidden",
+			Body: query(`{ teams { name } }`),
+			Resolver: func(teamSvc *team_service.MockTeamService, playerSvc *player_service.MockPlayerService) {
+				teamSvc.EXPECT().FindAll(gomock.Any()).
+					Return([]team_model.TeamModel{}, auth_model.ForbiddenError{Subject: "some-user", Action: auth_model.ACTION_TEAM_READ})
+			},
+			ExpectStatus: http.StatusOK,
+			ExpectBody: `{"data":null,"errors":[
+				{"message":"some-user is not allowed to team:read","path":["teams"],"extensions":{"code":"FORBIDDEN"}}
+			]}`,
+		},
+		{
+			Name: "when_insert_team",
+			Body: query(`mutation { insertTeam(input: {name: "some-team"}) { id name } }`),
+			Resolver: func(teamSvc *team_service.MockTeamService, playerSvc *player_service.MockPlayerService) {
+				teamSvc.EXPECT().Insert(gomock.Any(), team_model.TeamModel{Name: "some-team"}).
+					Return(team_model.TeamModel{ID: 1, Name: "some-team"}, nil)
+			},
+			ExpectStatus: http.StatusOK,
+			ExpectBody:   `{"data":{"insertTeam":{"id":"1","name":"some-team"}}}`,
+		},
+		{
+			Name:         "when_insert_player_invalid",
+			Body:         query(`mutation { insertPlayer(input: {name: " ", teamId: "1"}) { id } }`),
+			Resolver:     func(teamSvc *team_service.MockTeamService, playerSvc *player_service.MockPlayerService) {},
+			ExpectStatus: http.StatusOK,
+			ExpectBody: `{"data":null,"errors":[
+				{"message":"name is required","path":["insertPlayer"],"extensions":{"code":"BAD_USER_INPUT"}}
+			]}`,
+		},
+		{
+			Name: "when_transfer_player",
+			Body: query(`mutation { transferPlayer(input: {playerId: "10", teamId: "2"}) { id team { id } } }`),
+			Resolver: func(teamSvc *team_service.MockTeamService, playerSvc *player_service.MockPlayerService) {
+				playerSvc.EXPECT().Transfer(gomock.Any(), player_service.TransferPayload{PlayerID: 10, TeamID: 2}).
+					Return(nil)
+				playerSvc.EXPECT().FindByID(gomock.Any(), int64(10)).
+					Return(player_model.PlayerModel{ID: 10, Name: "some-player", TeamID: 2}, nil)
+				teamSvc.EXPECT().FindByIDs(gomock.Any(), []int64{2}).
+					Return([]team_model.TeamModel{{ID: 2, Name: "other-team"}}, nil)
+			},
+			ExpectStatus: http.StatusOK,
+			ExpectBody:   `{"data":{"transferPlayer":{"id":"10","team":{"id":"2"}}}}`,
+		},
+		{
+			Name: "when_internal_error",
+			Body: query(`{ teams { name } }`),
+			Resolver: func(teamSvc *team_service.MockTeamService, playerSvc *player_service.MockPlayerService) {
+				teamSvc.EXPECT().FindAll(gomock.Any()).
+					Return([]team_model.TeamModel{}, errors.New("some-error"))
+			},
+			ExpectStatus: http.StatusOK,
+			ExpectBody: `{"data":null,"errors":[
+				{"message":"Internal Server Error","path":["teams"],"extensions":{"code":"INTERNAL_SERVER_ERROR"}}
+			]}`,
+		},
+		{
+			Name: "when_resolver_panics",
+			Body: query(`{ teams { name } }`),
+			Resolver: func(teamSvc *team_service.MockTeamService, playerSvc *player_service.MockPlayerService) {
+				teamSvc.EXPECT().FindAll(gomock.Any()).
+					DoAndReturn(func(ctx context.Context) ([]team_model.TeamModel, error) {
+						panic("some-panic")
+					})
+			},
+			ExpectStatus: http.StatusOK,
+			ExpectBody: `{"data":null,"errors":[
+				{"message":"Internal Server Error","path":["teams"],"extensions":{"code":"INTERNAL_SERVER_ERROR"}}
+			]}`,
+		},
+		{
+			Name:         "when_query_missing",
+			Body:         `{}`,
+			Resolver:     func(teamSvc *team_service.MockTeamService, playerSvc *player_service.MockPlayerService) {},
+			ExpectStatus: http.StatusBadRequest,
+			ExpectBody:   `{"message":"query is required"}`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			e, mock := createServer(t, test.Resolver)
+			defer mock.Finish()
+
+			rec := do(e, test.Body)
+
+			assert.Equal(t, test.ExpectStatus, rec.Code)
+			assert.JSONEq(t, test.ExpectBody, rec.Body.String())
+		})
+	}
+}
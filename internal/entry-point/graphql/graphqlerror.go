@@ -0,0 +1,78 @@
+package graphql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	apikey_model "github.com/tesarwijaya/ouroboros/internal/domain/apikey/model"
+	auth_model "github.com/tesarwijaya/ouroboros/internal/domain/auth/model"
+	command_model "github.com/tesarwijaya/ouroboros/internal/domain/command/model"
+	offer_model "github.com/tesarwijaya/ouroboros/internal/domain/offer/model"
+	"github.com/tesarwijaya/ouroboros/internal/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	CODE_BAD_USER_INPUT        = "BAD_USER_INPUT"
+	CODE_FORBIDDEN             = "FORBIDDEN"
+	CODE_UNAUTHENTICATED       = "UNAUTHENTICATED"
+	CODE_NOT_FOUND             = "NOT_FOUND"
+	CODE_INTERNAL_SERVER_ERROR = "INTERNAL_SERVER_ERROR"
+)
+
+// Error is reported in the errors of the response with its code in the
+// extensions, so that clients can tell errors apart without parsing messages.
+type Error struct {
+	Err  error
+	Code string
+}
+
+func (e Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e Error) Unwrap() error {
+	return e.Err
+}
+
+func (e Error) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.Code}
+}
+
+// FromError maps a domain error to the matching error code the same way
+// httperror does for HTTP, anything unknown is reported as an internal error.
+// The cause of an internal error is logged but never sent to the client.
+func FromError(ctx context.Context, err error) error {
+	var gqlErr Error
+	if errors.As(err, &gqlErr) {
+		return err
+	}
+
+	var validationErr command_model.ValidationError
+	if errors.As(err, &validationErr) {
+		return Error{Err: err, Code: CODE_BAD_USER_INPUT}
+	}
+
+	var forbiddenErr auth_model.ForbiddenError
+	if errors.As(err, &forbiddenErr) {
+		return Error{Err: err, Code: CODE_FORBIDDEN}
+	}
+
+	if errors.Is(err, auth_model.ErrMissingToken) || errors.Is(err, auth_model.ErrInvalidToken) || errors.Is(err, apikey_model.ErrInvalidAPIKey) {
+		return Error{Err: err, Code: CODE_UNAUTHENTICATED}
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return Error{Err: errors.New(http.StatusText(http.StatusNotFound)), Code: CODE_NOT_FOUND}
+	}
+
+	if errors.Is(err, offer_model.ErrOfferNotFound) || errors.Is(err, apikey_model.ErrAPIKeyNotFound) {
+		return Error{Err: err, Code: CODE_NOT_FOUND}
+	}
+
+	logger.FromContext(ctx).Error("internal error", zap.Error(err))
+
+	return Error{Err: errors.New(http.StatusText(http.StatusInternalServerError)), Code: CODE_INTERNAL_SERVER_ERROR}
+}
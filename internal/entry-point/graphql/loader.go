@@ -0,0 +1,187 @@
+package graphql
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/graph-gophers/dataloader"
+	player_model "github.com/tesarwijaya/ouroboros/internal/domain/player/model"
+	player_service "github.com/tesarwijaya/ouroboros/internal/domain/player/service"
+	team_model "github.com/tesarwijaya/ouroboros/internal/domain/team/model"
+	team_service "github.com/tesarwijaya/ouroboros/internal/domain/team/service"
+)
+
+type loadersKey struct{}
+
+// Loaders batch the team and player lookups made while resolving a single
+// request into one query per level of the query. They are created for every
+// request, so that nothing is cached across callers.
+type Loaders struct {
+	Team        *dataloader.Loader
+	TeamPlayers *dataloader.Loader
+	Transfers   *dataloader.Loader
+}
+
+func NewLoaders(teams team_service.TeamService, players player_service.PlayerService) *Loaders {
+	return &Loaders{
+		Team:        dataloader.NewBatchedLoader(batchTeams(teams)),
+		TeamPlayers: dataloader.NewBatchedLoader(batchTeamPlayers(players)),
+		Transfers:   dataloader.NewBatchedLoader(batchTransfers(players)),
+	}
+}
+
+func WithLoaders(ctx context.Context, loaders *Loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, loaders)
+}
+
+func LoadersFromContext(ctx context.Context) *Loaders {
+	return ctx.Value(loadersKey{}).(*Loaders)
+}
+
+// LoadTeam returns nil when the team doesn't exist.
+func (l *Loaders) LoadTeam(ctx context.Context, id int64) (*team_model.TeamModel, error) {
+	res, err := l.Team.Load(ctx, toKey(id))()
+	if err != nil {
+		return nil, err
+	}
+
+	team, _ := res.(*team_model.TeamModel)
+
+	return team, nil
+}
+
+func (l *Loaders) LoadTeamPlayers(ctx context.Context, teamID int64) ([]player_model.PlayerModel, error) {
+	res, err := l.TeamPlayers.Load(ctx, toKey(teamID))()
+	if err != nil {
+		return nil, err
+	}
+
+	return res.([]player_model.PlayerModel), nil
+}
+
+// LoadTransfers returns the transfer history of a player that was already
+// loaded, so that it isn't looked up again.
+func (l *Loaders) LoadTransfers(ctx context.Context, player player_model.PlayerModel) ([]player_model.TransferModel, error) {
+	res, err := l.Transfers.Load(ctx, playerKey{player: player})()
+	if err != nil {
+		return nil, err
+	}
+
+	return res.([]player_model.TransferModel), nil
+}
+
+func batchTeams(teams team_service.TeamService) dataloader.BatchFunc {
+	return func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		ids, err := fromKeys(keys)
+		if err != nil {
+			return failBatch(keys, err)
+		}
+
+		res, err := teams.FindByIDs(ctx, ids)
+		if err != nil {
+			return failBatch(keys, err)
+		}
+
+		byID := make(map[int64]*team_model.TeamModel, len(res))
+		for i := range res {
+			byID[res[i].ID] = &res[i]
+		}
+
+		results := make([]*dataloader.Result, 0, len(ids))
+		for _, id := range ids {
+			results = append(results, &dataloader.Result{Data: byID[id]})
+		}
+
+		return results
+	}
+}
+
+func batchTeamPlayers(players player_service.PlayerService) dataloader.BatchFunc {
+	return func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		teamIDs, err := fromKeys(keys)
+		if err != nil {
+			return failBatch(keys, err)
+		}
+
+		res, err := players.FindByTeamIDs(ctx, teamIDs)
+		if err != nil {
+			return failBatch(keys, err)
+		}
+
+		byTeam := make(map[int64][]player_model.PlayerModel, len(teamIDs))
+		for _, player := range res {
+			byTeam[player.TeamID] = append(byTeam[player.TeamID], player)
+		}
+
+		results := make([]*dataloader.Result, 0, len(teamIDs))
+		for _, teamID := range teamIDs {
+			results = append(results, &dataloader.Result{Data: append([]player_model.PlayerModel{}, byTeam[teamID]...)})
+		}
+
+		return results
+	}
+}
+
+func batchTransfers(players player_service.PlayerService) dataloader.BatchFunc {
+	return func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		batch := make([]player_model.PlayerModel, 0, len(keys))
+		for _, key := range keys {
+			batch = append(batch, key.Raw().(player_model.PlayerModel))
+		}
+
+		res, err := players.FindTransfersOf(ctx, batch)
+		if err != nil {
+			return failBatch(keys, err)
+		}
+
+		results := make([]*dataloader.Result, 0, len(batch))
+		for _, player := range batch {
+			results = append(results, &dataloader.Result{Data: append([]player_model.TransferModel{}, res[player.ID]...)})
+		}
+
+		return results
+	}
+}
+
+// playerKey is keyed by the player id and carries the player itself, so
+// that the batch doesn't have to look the players up again.
+type playerKey struct {
+	player player_model.PlayerModel
+}
+
+func (k playerKey) String() string {
+	return strconv.FormatInt(k.player.ID, 10)
+}
+
+func (k playerKey) Raw() interface{} {
+	return k.player
+}
+
+func toKey(id int64) dataloader.Key {
+	return dataloader.StringKey(strconv.FormatInt(id, 10))
+}
+
+func fromKeys(keys dataloader.Keys) ([]int64, error) {
+	ids := make([]int64, 0, len(keys))
+	for _, key := range keys {
+		id, err := strconv.ParseInt(key.String(), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// failBatch fails every key of the batch with err, the loader requires a
+// result per key.
+func failBatch(keys dataloader.Keys, err error) []*dataloader.Result {
+	results := make([]*dataloader.Result, 0, len(keys))
+	for range keys {
+		results = append(results, &dataloader.Result{Error: err})
+	}
+
+	return results
+}
@@ -0,0 +1,265 @@
+package graphql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+
+	"github.com/graph-gophers/graphql-go"
+	command_model "github.com/tesarwijaya/ouroboros/internal/domain/command/model"
+	command_service "github.com/tesarwijaya/ouroboros/internal/domain/command/service"
+	player_model "github.com/tesarwijaya/ouroboros/internal/domain/player/model"
+	player_service "github.com/tesarwijaya/ouroboros/internal/domain/player/service"
+	team_model "github.com/tesarwijaya/ouroboros/internal/domain/team/model"
+	team_service "github.com/tesarwijaya/ouroboros/internal/domain/team/service"
+)
+
+type (
+	InsertTeamInput struct {
+		Name string
+	}
+
+	InsertPlayerInput struct {
+		Name   string
+		TeamID graphql.ID
+	}
+
+	TransferPlayerInput struct {
+		PlayerID graphql.ID
+		TeamID   graphql.ID
+	}
+)
+
+// Resolver is the root resolver of the schema, reads go through the services
+// while inserts and transfers are dispatched on the command bus like the REST
+// ones.
+type Resolver struct {
+	TeamService   team_service.TeamService
+	PlayerService player_service.PlayerService
+	Bus           command_service.CommandBus
+}
+
+func (r *Resolver) Teams(ctx context.Context) ([]*TeamResolver, error) {
+	res, err := r.TeamService.FindAll(ctx)
+	if err != nil {
+		return nil, FromError(ctx, err)
+	}
+
+	teams := make([]*TeamResolver, 0, len(res))
+	for _, team := range res {
+		teams = append(teams, &TeamResolver{team: team, root: r})
+	}
+
+	return teams, nil
+}
+
+func (r *Resolver) Team(ctx context.Context, args struct{ ID graphql.ID }) (*TeamResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, FromError(ctx, err)
+	}
+
+	res, err := r.TeamService.FindByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, FromError(ctx, err)
+	}
+
+	return &TeamResolver{team: res, root: r}, nil
+}
+
+func (r *Resolver) Players(ctx context.Context) ([]*PlayerResolver, error) {
+	res, err := r.PlayerService.FindAll(ctx)
+	if err != nil {
+		return nil, FromError(ctx, err)
+	}
+
+	players := make([]*PlayerResolver, 0, len(res))
+	for _, player := range res {
+		players = append(players, &PlayerResolver{player: player, root: r})
+	}
+
+	return players, nil
+}
+
+func (r *Resolver) Player(ctx context.Context, args struct{ ID graphql.ID }) (*PlayerResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, FromError(ctx, err)
+	}
+
+	res, err := r.PlayerService.FindByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, FromError(ctx, err)
+	}
+
+	return &PlayerResolver{player: res, root: r}, nil
+}
+
+func (r *Resolver) InsertTeam(ctx context.Context, args struct{ Input InsertTeamInput }) (*TeamResolver, error) {
+	res, err := command_service.Dispatch[team_model.TeamModel](ctx, r.Bus, team_service.InsertTeamCommand{
+		Payload: team_model.TeamModel{Name: args.Input.Name},
+	})
+	if err != nil {
+		return nil, FromError(ctx, err)
+	}
+
+	return &TeamResolver{team: res, root: r}, nil
+}
+
+func (r *Resolver) InsertPlayer(ctx context.Context, args struct{ Input InsertPlayerInput }) (*PlayerResolver, error) {
+	teamID, err := parseID(args.Input.TeamID)
+	if err != nil {
+		return nil, FromError(ctx, err)
+	}
+
+	res, err := command_service.Dispatch[player_model.PlayerModel](ctx, r.Bus, player_service.InsertPlayerCommand{
+		Payload: player_model.PlayerModel{Name: args.Input.Name, TeamID: teamID},
+	})
+	if err != nil {
+		return nil, FromError(ctx, err)
+	}
+
+	return &PlayerResolver{player: res, root: r}, nil
+}
+
+// TransferPlayer responds the player read once the transfer is committed, its
+// team is changed in the same transaction the transfer events are written in.
+func (r *Resolver) TransferPlayer(ctx context.Context, args struct{ Input TransferPlayerInput }) (*PlayerResolver, error) {
+	playerID, err := parseID(args.Input.PlayerID)
+	if err != nil {
+		return nil, FromError(ctx, err)
+	}
+
+	teamID, err := parseID(args.Input.TeamID)
+	if err != nil {
+		return nil, FromError(ctx, err)
+	}
+
+	if _, err := r.Bus.Dispatch(ctx, player_service.TransferPlayerCommand{
+		Payload: player_service.TransferPayload{PlayerID: playerID, TeamID: teamID},
+	}); err != nil {
+		return nil, FromError(ctx, err)
+	}
+
+	res, err := r.PlayerService.FindByID(ctx, playerID)
+	if err != nil {
+		return nil, FromError(ctx, err)
+	}
+
+	return &PlayerResolver{player: res, root: r}, nil
+}
+
+type TeamResolver struct {
+	team team_model.TeamModel
+	root *Resolver
+}
+
+func (r *TeamResolver) ID() graphql.ID {
+	return toID(r.team.ID)
+}
+
+func (r *TeamResolver) Name() string {
+	return r.team.Name
+}
+
+func (r *TeamResolver) Players(ctx context.Context) ([]*PlayerResolver, error) {
+	res, err := LoadersFromContext(ctx).LoadTeamPlayers(ctx, r.team.ID)
+	if err != nil {
+		return nil, FromError(ctx, err)
+	}
+
+	players := make([]*PlayerResolver, 0, len(res))
+	for _, player := range res {
+		players = append(players, &PlayerResolver{player: player, root: r.root})
+	}
+
+	return players, nil
+}
+
+type PlayerResolver struct {
+	player player_model.PlayerModel
+	root   *Resolver
+}
+
+func (r *PlayerResolver) ID() graphql.ID {
+	return toID(r.player.ID)
+}
+
+func (r *PlayerResolver) Name() string {
+	return r.player.Name
+}
+
+func (r *PlayerResolver) Team(ctx context.Context) (*TeamResolver, error) {
+	return r.root.loadTeam(ctx, r.player.TeamID)
+}
+
+// Transfers reads the event stream of the player, it is only read for the
+// players whose transfers are selected. The streams of the players of a level
+// are read in one batch.
+func (r *PlayerResolver) Transfers(ctx context.Context) ([]*TransferResolver, error) {
+	res, err := LoadersFromContext(ctx).LoadTransfers(ctx, r.player)
+	if err != nil {
+		return nil, FromError(ctx, err)
+	}
+
+	transfers := make([]*TransferResolver, 0, len(res))
+	for _, transfer := range res {
+		transfers = append(transfers, &TransferResolver{transfer: transfer, root: r.root})
+	}
+
+	return transfers, nil
+}
+
+type TransferResolver struct {
+	transfer player_model.TransferModel
+	root     *Resolver
+}
+
+func (r *TransferResolver) From(ctx context.Context) (*TeamResolver, error) {
+	return r.root.loadTeam(ctx, r.transfer.FromTeamID)
+}
+
+func (r *TransferResolver) To(ctx context.Context) (*TeamResolver, error) {
+	return r.root.loadTeam(ctx, r.transfer.ToTeamID)
+}
+
+func (r *TransferResolver) TransferredAt() graphql.Time {
+	return graphql.Time{Time: r.transfer.TransferredAt}
+}
+
+// loadTeam resolves null for a player without team or a team that is gone.
+func (r *Resolver) loadTeam(ctx context.Context, id int64) (*TeamResolver, error) {
+	if id == 0 {
+		return nil, nil
+	}
+
+	res, err := LoadersFromContext(ctx).LoadTeam(ctx, id)
+	if err != nil {
+		return nil, FromError(ctx, err)
+	}
+	if res == nil {
+		return nil, nil
+	}
+
+	return &TeamResolver{team: *res, root: r}, nil
+}
+
+func toID(id int64) graphql.ID {
+	return graphql.ID(strconv.FormatInt(id, 10))
+}
+
+func parseID(id graphql.ID) (int64, error) {
+	res, err := strconv.ParseInt(string(id), 10, 64)
+	if err != nil {
+		return 0, command_model.ValidationError{Err: errors.New("invalid id " + strconv.Quote(string(id)))}
+	}
+
+	return res, nil
+}
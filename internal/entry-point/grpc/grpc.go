@@ -0,0 +1,137 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/tesarwijaya/ouroboros/internal/config"
+	apikey_service "github.com/tesarwijaya/ouroboros/internal/domain/apikey/service"
+	auth_service "github.com/tesarwijaya/ouroboros/internal/domain/auth/service"
+	idempotency_service "github.com/tesarwijaya/ouroboros/internal/domain/idempotency/service"
+	ratelimit_model "github.com/tesarwijaya/ouroboros/internal/domain/ratelimit/model"
+	ratelimit_service "github.com/tesarwijaya/ouroboros/internal/domain/ratelimit/service"
+	player_handler "github.com/tesarwijaya/ouroboros/internal/entry-point/grpc/handler/player"
+	team_handler "github.com/tesarwijaya/ouroboros/internal/entry-point/grpc/handler/team"
+	"github.com/tesarwijaya/ouroboros/internal/entry-point/grpc/interceptor"
+	"go.uber.org/dig"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/reflection"
+)
+
+type GrpcHandler struct {
+	dig.In
+	PlayerHandler player_handler.PlayerHandler
+	TeamHandler   team_handler.TeamHandler
+}
+
+// publicServices don't require authentication, so that tools like grpcurl
+// can discover the API.
+var publicServices = []string{
+	"grpc.reflection.v1alpha.ServerReflection",
+}
+
+type GrpcServer struct {
+	Server *grpc.Server
+	Config *config.Config
+}
+
+// NewGrpcServer serves the team and player services next to the REST server,
+// over TLS with the same certificate when one is configured. Calls are rate
+// limited and made idempotent the same way as REST requests.
+func NewGrpcServer(c *config.Config, l *zap.Logger, handlers GrpcHandler, auth auth_service.AuthService, apiKeys apikey_service.APIKeyService, rateLimit ratelimit_service.RateLimitService, idempotency idempotency_service.IdempotencyService) (GrpcServer, error) {
+	unary := []grpc.UnaryServerInterceptor{
+		interceptor.Tracing(),
+		interceptor.RequestLogger(l),
+		interceptor.Recover(),
+	}
+	var stream []grpc.StreamServerInterceptor
+	if c.RateLimitEnabled {
+		unary = append(unary, interceptor.RateLimitByIP(
+			rateLimit,
+			ratelimit_model.Limit{Rate: c.RateLimitIPRate, Burst: c.RateLimitIPBurst},
+			publicServices...,
+		))
+	}
+	if c.AuthEnabled {
+		unary = append(unary, interceptor.Authentication(auth, apiKeys, publicServices...))
+		stream = append(stream, interceptor.StreamAuthentication(auth, apiKeys, publicServices...))
+	}
+	if c.RateLimitEnabled {
+		unary = append(unary, interceptor.RateLimit(
+			rateLimit,
+			ratelimit_model.Limit{Rate: c.RateLimitReadRate, Burst: c.RateLimitReadBurst},
+			ratelimit_model.Limit{Rate: c.RateLimitWriteRate, Burst: c.RateLimitWriteBurst},
+			publicServices...,
+		))
+	}
+	unary = append(unary, interceptor.Idempotency(idempotency))
+
+	var opts []grpc.ServerOption
+	if c.TLSCertFile != "" || c.TLSKeyFile != "" {
+		if c.TLSCertFile == "" || c.TLSKeyFile == "" {
+			return GrpcServer{}, errors.New("both a tls certificate and key file are required")
+		}
+
+		cert, err := tls.LoadX509KeyPair(c.TLSCertFile, c.TLSKeyFile)
+		if err != nil {
+			return GrpcServer{}, fmt.Errorf("failed to load tls certificate: %w", err)
+		}
+
+		opts = append(opts, grpc.Creds(credentials.NewTLS(&tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			Certificates: []tls.Certificate{cert},
+		})))
+	}
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	)
+
+	s := grpc.NewServer(opts...)
+	handlers.PlayerHandler.Register(s)
+	handlers.TeamHandler.Register(s)
+	reflection.Register(s)
+
+	return GrpcServer{
+		Server: s,
+		Config: c,
+	}, nil
+}
+
+// Listen binds the configured gRPC port, so that a taken port fails the app
+// start instead of a background goroutine.
+func (s *GrpcServer) Listen() (net.Listener, error) {
+	return net.Listen("tcp", fmt.Sprintf(":%s", s.Config.GrpcPort))
+}
+
+// Serve blocks until the server stops, it returns nil once Shutdown is called.
+func (s *GrpcServer) Serve(ln net.Listener) error {
+	if err := s.Server.Serve(ln); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		return err
+	}
+
+	return nil
+}
+
+// Shutdown stops accepting calls and waits for in-flight ones until ctx is
+// done, the remaining ones are then cancelled.
+func (s *GrpcServer) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.Server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.Server.Stop()
+		return ctx.Err()
+	}
+}
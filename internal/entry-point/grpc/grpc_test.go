@@ -0,0 +1,128 @@
+package grpc_test
+
+import (
+	"context"
+	"net"
+	"sort"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/tesarwijaya/ouroboros/internal/config"
+	apikey_service "github.com/tesarwijaya/ouroboros/internal/domain/apikey/service"
+	auth_model "github.com/tesarwijaya/ouroboros/internal/domain/auth/model"
+	auth_service "github.com/tesarwijaya/ouroboros/internal/domain/auth/service"
+	idempotency_service "github.com/tesarwijaya/ouroboros/internal/domain/idempotency/service"
+	ratelimit_service "github.com/tesarwijaya/ouroboros/internal/domain/ratelimit/service"
+	team_model "github.com/tesarwijaya/ouroboros/internal/domain/team/model"
+	team_service "github.com/tesarwijaya/ouroboros/internal/domain/team/service"
+	grpc_server "github.com/tesarwijaya/ouroboros/internal/entry-point/grpc"
+	team_handler "github.com/tesarwijaya/ouroboros/internal/entry-point/grpc/handler/team"
+	"github.com/tesarwijaya/ouroboros/internal/entry-point/grpc/pb"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func createServer(t *testing.T, resolver func(auth *auth_service.MockAuthService, teams *team_service.MockTeamService)) *grpc.ClientConn {
+	ctrl := gomock.NewController(t)
+
+	auth := auth_service.NewMockAuthService(ctrl)
+	teams := team_service.NewMockTeamService(ctrl)
+	resolver(auth, teams)
+
+	server, err := grpc_server.NewGrpcServer(
+		&config.Config{AuthEnabled: true},
+		zap.NewNop(),
+		grpc_server.GrpcHandler{TeamHandler: team_handler.NewTeamHandler(teams, nil)},
+		auth,
+		apikey_service.NewMockAPIKeyService(ctrl),
+		ratelimit_service.NewMockRateLimitService(ctrl),
+		idempotency_service.NewMockIdempotencyService(ctrl),
+	)
+	assert.Nil(t, err)
+
+	ln := bufconn.Listen(1024 * 1024)
+	go func() {
+		_ = server.Serve(ln)
+	}()
+	t.Cleanup(server.Server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return ln.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.Nil(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func Test_GrpcServer(t *testing.T) {
+	t.Run("when_listing_services", func(t *testing.T) {
+		conn := createServer(t, func(auth *auth_service.MockAuthService, teams *team_service.MockTeamService) {})
+
+		stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+		assert.Nil(t, err)
+		assert.Nil(t, stream.Send(&reflectionpb.ServerReflectionRequest{
+			MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+		}))
+
+		res, err := stream.Recv()
+		assert.Nil(t, err)
+
+		var services []string
+		for _, svc := range res.GetListServicesResponse().GetService() {
+			services = append(services, svc.GetName())
+		}
+		sort.Strings(services)
+		assert.Equal(t, []string{"grpc.reflection.v1alpha.ServerReflection", "ouroboros.v1.PlayerService", "ouroboros.v1.TeamService"}, services)
+	})
+
+	t.Run("when_unauthenticated", func(t *testing.T) {
+		conn := createServer(t, func(auth *auth_service.MockAuthService, teams *team_service.MockTeamService) {})
+
+		_, err := pb.NewTeamServiceClient(conn).ListTeams(context.Background(), &pb.ListTeamsRequest{})
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("when_authenticated", func(t *testing.T) {
+		conn := createServer(t, func(auth *auth_service.MockAuthService, teams *team_service.MockTeamService) {
+			auth.EXPECT().Authenticate(gomock.Any(), "some-token").Return(auth_model.Claims{
+				RegisteredClaims: jwt.RegisteredClaims{Subject: "some-user"},
+			}, nil)
+			teams.EXPECT().FindAll(gomock.Any()).Return([]team_model.TeamModel{{ID: 1, Name: "some-team-name"}}, nil)
+		})
+
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer some-token")
+		var header metadata.MD
+		res, err := pb.NewTeamServiceClient(conn).ListTeams(ctx, &pb.ListTeamsRequest{}, grpc.Header(&header))
+
+		assert.Nil(t, err)
+		assert.Len(t, res.GetTeams(), 1)
+		assert.NotEmpty(t, header.Get("x-request-id"))
+	})
+
+	t.Run("when_handler_panics", func(t *testing.T) {
+		conn := createServer(t, func(auth *auth_service.MockAuthService, teams *team_service.MockTeamService) {
+			auth.EXPECT().Authenticate(gomock.Any(), "some-token").Return(auth_model.Claims{}, nil)
+			teams.EXPECT().FindByID(gomock.Any(), int64(1)).DoAndReturn(func(ctx context.Context, id int64) (team_model.TeamModel, error) {
+				panic("db password is hunter2")
+			})
+		})
+
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer some-token")
+		_, err := pb.NewTeamServiceClient(conn).GetTeam(ctx, &pb.GetTeamRequest{Id: 1})
+
+		assert.Equal(t, status.Error(codes.Internal, "Internal").Error(), err.Error())
+	})
+}
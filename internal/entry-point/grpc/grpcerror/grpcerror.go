@@ -0,0 +1,51 @@
+package grpcerror
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	apikey_model "github.com/tesarwijaya/ouroboros/internal/domain/apikey/model"
+	auth_model "github.com/tesarwijaya/ouroboros/internal/domain/auth/model"
+	command_model "github.com/tesarwijaya/ouroboros/internal/domain/command/model"
+	offer_model "github.com/tesarwijaya/ouroboros/internal/domain/offer/model"
+	"github.com/tesarwijaya/ouroboros/internal/logger"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// FromError maps a domain error to the matching gRPC status the same way
+// httperror does for HTTP, anything unknown is reported as an internal error.
+// The cause of an internal error is logged but never sent to the client.
+func FromError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var validationErr command_model.ValidationError
+	if errors.As(err, &validationErr) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var forbiddenErr auth_model.ForbiddenError
+	if errors.As(err, &forbiddenErr) {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	if errors.Is(err, auth_model.ErrMissingToken) || errors.Is(err, auth_model.ErrInvalidToken) || errors.Is(err, apikey_model.ErrInvalidAPIKey) {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	if errors.Is(err, sql.ErrNoRows) || errors.Is(err, offer_model.ErrOfferNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+
+	logger.FromContext(ctx).Error("internal error", zap.Error(err))
+
+	return status.Error(codes.Internal, codes.Internal.String())
+}
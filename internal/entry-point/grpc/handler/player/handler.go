@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"context"
+
+	command_service "github.com/tesarwijaya/ouroboros/internal/domain/command/service"
+	"github.com/tesarwijaya/ouroboros/internal/domain/player/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/player/service"
+	"github.com/tesarwijaya/ouroboros/internal/entry-point/grpc/grpcerror"
+	"github.com/tesarwijaya/ouroboros/internal/entry-point/grpc/pb"
+	"google.golang.org/grpc"
+)
+
+type PlayerHandler struct {
+	pb.UnimplementedPlayerServiceServer
+	Service service.PlayerService
+	Bus     command_service.CommandBus
+}
+
+func NewPlayerHandler(service service.PlayerService, bus command_service.CommandBus) PlayerHandler {
+	return PlayerHandler{
+		Service: service,
+		Bus:     bus,
+	}
+}
+
+func (h *PlayerHandler) Register(s *grpc.Server) {
+	pb.RegisterPlayerServiceServer(s, h)
+}
+
+func (h *PlayerHandler) ListPlayers(ctx context.Context, req *pb.ListPlayersRequest) (*pb.ListPlayersResponse, error) {
+	res, err := h.Service.FindAll(ctx)
+	if err != nil {
+		return nil, grpcerror.FromError(ctx, err)
+	}
+
+	players := make([]*pb.Player, 0, len(res))
+	for _, player := range res {
+		players = append(players, toPlayer(player))
+	}
+
+	return &pb.ListPlayersResponse{Players: players}, nil
+}
+
+func (h *PlayerHandler) GetPlayer(ctx context.Context, req *pb.GetPlayerRequest) (*pb.Player, error) {
+	res, err := h.Service.FindByID(ctx, req.GetId())
+	if err != nil {
+		return nil, grpcerror.FromError(ctx, err)
+	}
+
+	return toPlayer(res), nil
+}
+
+func (h *PlayerHandler) InsertPlayer(ctx context.Context, req *pb.InsertPlayerRequest) (*pb.Player, error) {
+	res, err := command_service.Dispatch[model.PlayerModel](ctx, h.Bus, service.InsertPlayerCommand{
+		Payload: model.PlayerModel{
+			Name:   req.GetName(),
+			TeamID: req.GetTeamId(),
+		},
+	})
+	if err != nil {
+		return nil, grpcerror.FromError(ctx, err)
+	}
+
+	return toPlayer(res), nil
+}
+
+func (h *PlayerHandler) TransferPlayer(ctx context.Context, req *pb.TransferPlayerRequest) (*pb.TransferPlayerResponse, error) {
+	_, err := h.Bus.Dispatch(ctx, service.TransferPlayerCommand{
+		Payload: service.TransferPayload{
+			PlayerID: req.GetPlayerId(),
+			TeamID:   req.GetTeamId(),
+		},
+	})
+	if err != nil {
+		return nil, grpcerror.FromError(ctx, err)
+	}
+
+	return &pb.TransferPlayerResponse{}, nil
+}
+
+func toPlayer(player model.PlayerModel) *pb.Player {
+	return &pb.Player{
+		Id:     player.ID,
+		Name:   player.Name,
+		TeamId: player.TeamID,
+	}
+}
@@ -0,0 +1,212 @@
+package handler_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	auth_model "github.com/tesarwijaya/ouroboros/internal/domain/auth/model"
+	command_model "github.com/tesarwijaya/ouroboros/internal/domain/command/model"
+	command_service "github.com/tesarwijaya/ouroboros/internal/domain/command/service"
+	"github.com/tesarwijaya/ouroboros/internal/domain/player/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/player/service"
+	handler "github.com/tesarwijaya/ouroboros/internal/entry-point/grpc/handler/player"
+	"github.com/tesarwijaya/ouroboros/internal/entry-point/grpc/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+type ResolverFn func(svc *service.MockPlayerService)
+
+func createHandler(t *testing.T, resolver ResolverFn) (handler.PlayerHandler, *gomock.Controller) {
+	ctrl := gomock.NewController(t)
+
+	svc := service.NewMockPlayerService(ctrl)
+	resolver(svc)
+
+	bus, _ := command_service.NewCommandBus(command_service.CommandBusImpl{
+		Handlers:    service.NewCommandHandlers(svc).Handlers,
+		Middlewares: []command_model.Middleware{command_service.NewValidationMiddleware()},
+	})
+
+	return handler.PlayerHandler{
+		Service: svc,
+		Bus:     bus,
+	}, ctrl
+}
+
+func Test_ListPlayers(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Resolver  ResolverFn
+		Expect    *pb.ListPlayersResponse
+		ExpectErr error
+	}{
+		{
+			Name: "when_success",
+			Resolver: func(svc *service.MockPlayerService) {
+				svc.EXPECT().FindAll(gomock.Any()).
+					Return([]model.PlayerModel{{ID: 1, Name: "some-player-name", TeamID: 2}}, nil)
+			},
+			Expect: &pb.ListPlayersResponse{Players: []*pb.Player{{Id: 1, Name: "some-player-name", TeamId: 2}}},
+		},
+		{
+			Name: "when_forbidden",
+			Resolver: func(svc *service.MockPlayerService) {
+				svc.EXPECT().FindAll(gomock.Any()).
+					Return([]model.PlayerModel{}, auth_model.ForbiddenError{Subject: "some-user", Action: auth_model.ACTION_PLAYER_READ})
+			},
+			ExpectErr: status.Error(codes.PermissionDenied, auth_model.ForbiddenError{Subject: "some-user", Action: auth_model.ACTION_PLAYER_READ}.Error()),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			h, mock := createHandler(t, test.Resolver)
+			defer mock.Finish()
+
+			res, err := h.ListPlayers(context.Background(), &pb.ListPlayersRequest{})
+			if test.ExpectErr != nil {
+				assert.Equal(t, test.ExpectErr, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.True(t, proto.Equal(test.Expect, res), res.String())
+		})
+	}
+}
+
+func Test_GetPlayer(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Resolver  ResolverFn
+		Expect    *pb.Player
+		ExpectErr error
+	}{
+		{
+			Name: "when_success",
+			Resolver: func(svc *service.MockPlayerService) {
+				svc.EXPECT().FindByID(gomock.Any(), int64(1)).
+					Return(model.PlayerModel{ID: 1, Name: "some-player-name"}, nil)
+			},
+			Expect: &pb.Player{Id: 1, Name: "some-player-name"},
+		},
+		{
+			Name: "when_not_found",
+			Resolver: func(svc *service.MockPlayerService) {
+				svc.EXPECT().FindByID(gomock.Any(), int64(1)).
+					Return(model.PlayerModel{}, sql.ErrNoRows)
+			},
+			ExpectErr: status.Error(codes.NotFound, sql.ErrNoRows.Error()),
+		},
+		{
+			Name: "when_not_success",
+			Resolver: func(svc *service.MockPlayerService) {
+				svc.EXPECT().FindByID(gomock.Any(), int64(1)).
+					Return(model.PlayerModel{}, errors.New("some-error"))
+			},
+			ExpectErr: status.Error(codes.Internal, codes.Internal.String()),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			h, mock := createHandler(t, test.Resolver)
+			defer mock.Finish()
+
+			res, err := h.GetPlayer(context.Background(), &pb.GetPlayerRequest{Id: 1})
+			if test.ExpectErr != nil {
+				assert.Equal(t, test.ExpectErr, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.True(t, proto.Equal(test.Expect, res), res.String())
+		})
+	}
+}
+
+func Test_InsertPlayer(t *testing.T) {
+	testCases := []struct {
+		Name       string
+		Request    *pb.InsertPlayerRequest
+		Resolver   ResolverFn
+		Expect     *pb.Player
+		ExpectCode codes.Code
+	}{
+		{
+			Name:    "when_success",
+			Request: &pb.InsertPlayerRequest{Name: "some-player-name", TeamId: 2},
+			Resolver: func(svc *service.MockPlayerService) {
+				svc.EXPECT().Insert(gomock.Any(), model.PlayerModel{Name: "some-player-name", TeamID: 2}).
+					Return(model.PlayerModel{ID: 1, Name: "some-player-name", TeamID: 2}, nil)
+			},
+			Expect: &pb.Player{Id: 1, Name: "some-player-name", TeamId: 2},
+		},
+		{
+			Name:       "when_team_missing",
+			Request:    &pb.InsertPlayerRequest{Name: "some-player-name"},
+			Resolver:   func(svc *service.MockPlayerService) {},
+			ExpectCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			h, mock := createHandler(t, test.Resolver)
+			defer mock.Finish()
+
+			res, err := h.InsertPlayer(context.Background(), test.Request)
+			if test.ExpectCode != codes.OK {
+				assert.Equal(t, test.ExpectCode, status.Code(err))
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.True(t, proto.Equal(test.Expect, res), res.String())
+		})
+	}
+}
+
+func Test_TransferPlayer(t *testing.T) {
+	testCases := []struct {
+		Name       string
+		Request    *pb.TransferPlayerRequest
+		Resolver   ResolverFn
+		ExpectCode codes.Code
+	}{
+		{
+			Name:    "when_success",
+			Request: &pb.TransferPlayerRequest{PlayerId: 1, TeamId: 2},
+			Resolver: func(svc *service.MockPlayerService) {
+				svc.EXPECT().Transfer(gomock.Any(), service.TransferPayload{PlayerID: 1, TeamID: 2}).
+					Return(nil)
+			},
+		},
+		{
+			Name:    "when_not_success",
+			Request: &pb.TransferPlayerRequest{PlayerId: 1, TeamId: 2},
+			Resolver: func(svc *service.MockPlayerService) {
+				svc.EXPECT().Transfer(gomock.Any(), gomock.Any()).
+					Return(errors.New("some-error"))
+			},
+			ExpectCode: codes.Internal,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			h, mock := createHandler(t, test.Resolver)
+			defer mock.Finish()
+
+			_, err := h.TransferPlayer(context.Background(), test.Request)
+
+			assert.Equal(t, test.ExpectCode, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"context"
+
+	command_service "github.com/tesarwijaya/ouroboros/internal/domain/command/service"
+	"github.com/tesarwijaya/ouroboros/internal/domain/team/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/team/service"
+	"github.com/tesarwijaya/ouroboros/internal/entry-point/grpc/grpcerror"
+	"github.com/tesarwijaya/ouroboros/internal/entry-point/grpc/pb"
+	"google.golang.org/grpc"
+)
+
+type TeamHandler struct {
+	pb.UnimplementedTeamServiceServer
+	Service service.TeamService
+	Bus     command_service.CommandBus
+}
+
+func NewTeamHandler(service service.TeamService, bus command_service.CommandBus) TeamHandler {
+	return TeamHandler{
+		Service: service,
+		Bus:     bus,
+	}
+}
+
+func (h *TeamHandler) Register(s *grpc.Server) {
+	pb.RegisterTeamServiceServer(s, h)
+}
+
+func (h *TeamHandler) ListTeams(ctx context.Context, req *pb.ListTeamsRequest) (*pb.ListTeamsResponse, error) {
+	res, err := h.Service.FindAll(ctx)
+	if err != nil {
+		return nil, grpcerror.FromError(ctx, err)
+	}
+
+	teams := make([]*pb.Team, 0, len(res))
+	for _, team := range res {
+		teams = append(teams, toTeam(team))
+	}
+
+	return &pb.ListTeamsResponse{Teams: teams}, nil
+}
+
+func (h *TeamHandler) GetTeam(ctx context.Context, req *pb.GetTeamRequest) (*pb.Team, error) {
+	res, err := h.Service.FindByID(ctx, req.GetId())
+	if err != nil {
+		return nil, grpcerror.FromError(ctx, err)
+	}
+
+	return toTeam(res), nil
+}
+
+func (h *TeamHandler) InsertTeam(ctx context.Context, req *pb.InsertTeamRequest) (*pb.Team, error) {
+	res, err := command_service.Dispatch[model.TeamModel](ctx, h.Bus, service.InsertTeamCommand{
+		Payload: model.TeamModel{Name: req.GetName()},
+	})
+	if err != nil {
+		return nil, grpcerror.FromError(ctx, err)
+	}
+
+	return toTeam(res), nil
+}
+
+func toTeam(team model.TeamModel) *pb.Team {
+	return &pb.Team{
+		Id:   team.ID,
+		Name: team.Name,
+	}
+}
@@ -0,0 +1,173 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	command_model "github.com/tesarwijaya/ouroboros/internal/domain/command/model"
+	command_service "github.com/tesarwijaya/ouroboros/internal/domain/command/service"
+	"github.com/tesarwijaya/ouroboros/internal/domain/team/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/team/service"
+	handler "github.com/tesarwijaya/ouroboros/internal/entry-point/grpc/handler/team"
+	"github.com/tesarwijaya/ouroboros/internal/entry-point/grpc/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+type ResolverFn func(svc *service.MockTeamService)
+
+func createHandler(t *testing.T, resolver ResolverFn) (handler.TeamHandler, *gomock.Controller) {
+	ctrl := gomock.NewController(t)
+
+	svc := service.NewMockTeamService(ctrl)
+	resolver(svc)
+
+	bus, _ := command_service.NewCommandBus(command_service.CommandBusImpl{
+		Handlers:    service.NewCommandHandlers(svc).Handlers,
+		Middlewares: []command_model.Middleware{command_service.NewValidationMiddleware()},
+	})
+
+	return handler.TeamHandler{
+		Service: svc,
+		Bus:     bus,
+	}, ctrl
+}
+
+func Test_ListTeams(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Resolver  ResolverFn
+		Expect    *pb.ListTeamsResponse
+		ExpectErr error
+	}{
+		{
+			Name: "when_success",
+			Resolver: func(svc *service.MockTeamService) {
+				svc.EXPECT().FindAll(gomock.Any()).
+					Return([]model.TeamModel{{ID: 1, Name: "some-team-name"}}, nil)
+			},
+			Expect: &pb.ListTeamsResponse{Teams: []*pb.Team{{Id: 1, Name: "some-team-name"}}},
+		},
+		{
+			Name: "when_not_success",
+			Resolver: func(svc *service.MockTeamService) {
+				svc.EXPECT().FindAll(gomock.Any()).
+					Return([]model.TeamModel{}, errors.New("some-error"))
+			},
+			ExpectErr: status.Error(codes.Internal, codes.Internal.String()),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			h, mock := createHandler(t, test.Resolver)
+			defer mock.Finish()
+
+			res, err := h.ListTeams(context.Background(), &pb.ListTeamsRequest{})
+			if test.ExpectErr != nil {
+				assert.Equal(t, test.ExpectErr, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.True(t, proto.Equal(test.Expect, res), res.String())
+		})
+	}
+}
+
+func Test_GetTeam(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Resolver  ResolverFn
+		Expect    *pb.Team
+		ExpectErr error
+	}{
+		{
+			Name: "when_success",
+			Resolver: func(svc *service.MockTeamService) {
+				svc.EXPECT().FindByID(gomock.Any(), int64(1)).
+					Return(model.TeamModel{ID: 1, Name: "some-team-name"}, nil)
+			},
+			Expect: &pb.Team{Id: 1, Name: "some-team-name"},
+		},
+		{
+			Name: "when_not_success",
+			Resolver: func(svc *service.MockTeamService) {
+				svc.EXPECT().FindByID(gomock.Any(), int64(1)).
+					Return(model.TeamModel{}, errors.New("some-error"))
+			},
+			ExpectErr: status.Error(codes.Internal, codes.Internal.String()),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			h, mock := createHandler(t, test.Resolver)
+			defer mock.Finish()
+
+			res, err := h.GetTeam(context.Background(), &pb.GetTeamRequest{Id: 1})
+			if test.ExpectErr != nil {
+				assert.Equal(t, test.ExpectErr, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.True(t, proto.Equal(test.Expect, res), res.String())
+		})
+	}
+}
+
+func Test_InsertTeam(t *testing.T) {
+	testCases := []struct {
+		Name       string
+		Request    *pb.InsertTeamRequest
+		Resolver   ResolverFn
+		Expect     *pb.Team
+		ExpectCode codes.Code
+	}{
+		{
+			Name:    "when_success",
+			Request: &pb.InsertTeamRequest{Name: "some-team-name"},
+			Resolver: func(svc *service.MockTeamService) {
+				svc.EXPECT().Insert(gomock.Any(), model.TeamModel{Name: "some-team-name"}).
+					Return(model.TeamModel{ID: 1, Name: "some-team-name"}, nil)
+			},
+			Expect: &pb.Team{Id: 1, Name: "some-team-name"},
+		},
+		{
+			Name:       "when_name_missing",
+			Request:    &pb.InsertTeamRequest{},
+			Resolver:   func(svc *service.MockTeamService) {},
+			ExpectCode: codes.InvalidArgument,
+		},
+		{
+			Name:    "when_not_success",
+			Request: &pb.InsertTeamRequest{Name: "some-team-name"},
+			Resolver: func(svc *service.MockTeamService) {
+				svc.EXPECT().Insert(gomock.Any(), gomock.Any()).
+					Return(model.TeamModel{}, errors.New("some-error"))
+			},
+			ExpectCode: codes.Internal,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			h, mock := createHandler(t, test.Resolver)
+			defer mock.Finish()
+
+			res, err := h.InsertTeam(context.Background(), test.Request)
+			if test.ExpectCode != codes.OK {
+				assert.Equal(t, test.ExpectCode, status.Code(err))
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.True(t, proto.Equal(test.Expect, res), res.String())
+		})
+	}
+}
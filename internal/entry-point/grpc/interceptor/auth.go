@@ -0,0 +1,107 @@
+package interceptor
+
+import (
+	"context"
+	"strings"
+
+	apikey_service "github.com/tesarwijaya/ouroboros/internal/domain/apikey/service"
+	"github.com/tesarwijaya/ouroboros/internal/domain/auth/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/auth/service"
+	"github.com/tesarwijaya/ouroboros/internal/entry-point/grpc/grpcerror"
+	"github.com/tesarwijaya/ouroboros/internal/logger"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	METADATA_AUTHORIZATION = "authorization"
+	METADATA_API_KEY       = "x-api-key"
+)
+
+// Authentication rejects calls without a valid bearer token or x-api-key in
+// their metadata, except for the methods of the given public services, and
+// puts the verified claims in the context.
+func Authentication(tokens service.AuthService, apiKeys apikey_service.APIKeyService, public ...string) grpc.UnaryServerInterceptor {
+	a := authenticator{tokens: tokens, apiKeys: apiKeys, public: public}
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := a.authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuthentication is Authentication for streaming calls.
+func StreamAuthentication(tokens service.AuthService, apiKeys apikey_service.APIKeyService, public ...string) grpc.StreamServerInterceptor {
+	a := authenticator{tokens: tokens, apiKeys: apiKeys, public: public}
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authenticate(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+type authenticator struct {
+	tokens  service.AuthService
+	apiKeys apikey_service.APIKeyService
+	public  []string
+}
+
+func (a authenticator) authenticate(ctx context.Context, fullMethod string) (context.Context, error) {
+	if isPublic(fullMethod, a.public) {
+		return ctx, nil
+	}
+
+	claims, err := a.claims(ctx)
+	if err != nil {
+		return ctx, grpcerror.FromError(ctx, err)
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("enduser.id", claims.Subject))
+	ctx = logger.WithContext(ctx, logger.FromContext(ctx).With(zap.String("subject", claims.Subject)))
+
+	return model.WithClaims(ctx, claims), nil
+}
+
+func (a authenticator) claims(ctx context.Context) (model.Claims, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if key := first(md, METADATA_API_KEY); key != "" {
+		return a.apiKeys.Authenticate(ctx, key)
+	}
+
+	scheme, token, ok := strings.Cut(first(md, METADATA_AUTHORIZATION), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return model.Claims{}, model.ErrMissingToken
+	}
+
+	return a.tokens.Authenticate(ctx, token)
+}
+
+func first(md metadata.MD, key string) string {
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
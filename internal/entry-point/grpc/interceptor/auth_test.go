@@ -0,0 +1,128 @@
+package interceptor_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	apikey_model "github.com/tesarwijaya/ouroboros/internal/domain/apikey/model"
+	apikey_service "github.com/tesarwijaya/ouroboros/internal/domain/apikey/service"
+	"github.com/tesarwijaya/ouroboros/internal/domain/auth/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/auth/service"
+	"github.com/tesarwijaya/ouroboros/internal/entry-point/grpc/interceptor"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func Test_Authentication(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Method        string
+		Metadata      metadata.MD
+		Resolver      func(svc *service.MockAuthService, apiKeys *apikey_service.MockAPIKeyService)
+		ExpectSubject string
+		ExpectErr     error
+	}{
+		{
+			Name:     "when_public_service",
+			Method:   "/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo",
+			Resolver: func(svc *service.MockAuthService, apiKeys *apikey_service.MockAPIKeyService) {},
+		},
+		{
+			Name:      "when_no_token",
+			Method:    "/ouroboros.v1.TeamService/ListTeams",
+			Resolver:  func(svc *service.MockAuthService, apiKeys *apikey_service.MockAPIKeyService) {},
+			ExpectErr: status.Error(codes.Unauthenticated, model.ErrMissingToken.Error()),
+		},
+		{
+			Name:      "when_not_bearer",
+			Method:    "/ouroboros.v1.TeamService/ListTeams",
+			Metadata:  metadata.Pairs("authorization", "Basic some-token"),
+			Resolver:  func(svc *service.MockAuthService, apiKeys *apikey_service.MockAPIKeyService) {},
+			ExpectErr: status.Error(codes.Unauthenticated, model.ErrMissingToken.Error()),
+		},
+		{
+			Name:     "when_invalid_token",
+			Method:   "/ouroboros.v1.TeamService/ListTeams",
+			Metadata: metadata.Pairs("authorization", "Bearer some-token"),
+			Resolver: func(svc *service.MockAuthService, apiKeys *apikey_service.MockAPIKeyService) {
+				svc.EXPECT().Authenticate(gomock.Any(), "some-token").Return(model.Claims{}, model.ErrInvalidToken)
+			},
+			ExpectErr: status.Error(codes.Unauthenticated, model.ErrInvalidToken.Error()),
+		},
+		{
+			Name:     "when_valid_token",
+			Method:   "/ouroboros.v1.TeamService/ListTeams",
+			Metadata: metadata.Pairs("authorization", "Bearer some-token"),
+			Resolver: func(svc *service.MockAuthService, apiKeys *apikey_service.MockAPIKeyService) {
+				svc.EXPECT().Authenticate(gomock.Any(), "some-token").Return(model.Claims{
+					RegisteredClaims: jwt.RegisteredClaims{Subject: "some-user"},
+				}, nil)
+			},
+			ExpectSubject: "some-user",
+		},
+		{
+			Name:     "when_invalid_api_key",
+			Method:   "/ouroboros.v1.TeamService/ListTeams",
+			Metadata: metadata.Pairs("x-api-key", "some-key"),
+			Resolver: func(svc *service.MockAuthService, apiKeys *apikey_service.MockAPIKeyService) {
+				apiKeys.EXPECT().Authenticate(gomock.Any(), "some-key").Return(model.Claims{}, apikey_model.ErrInvalidAPIKey)
+			},
+			ExpectErr: status.Error(codes.Unauthenticated, apikey_model.ErrInvalidAPIKey.Error()),
+		},
+		{
+			Name:     "when_api_key_store_failed",
+			Method:   "/ouroboros.v1.TeamService/ListTeams",
+			Metadata: metadata.Pairs("x-api-key", "some-key"),
+			Resolver: func(svc *service.MockAuthService, apiKeys *apikey_service.MockAPIKeyService) {
+				apiKeys.EXPECT().Authenticate(gomock.Any(), "some-key").Return(model.Claims{}, errors.New("some-error"))
+			},
+			ExpectErr: status.Error(codes.Internal, codes.Internal.String()),
+		},
+		{
+			Name:     "when_valid_api_key",
+			Method:   "/ouroboros.v1.TeamService/ListTeams",
+			Metadata: metadata.Pairs("x-api-key", "some-key", "authorization", "Bearer some-token"),
+			Resolver: func(svc *service.MockAuthService, apiKeys *apikey_service.MockAPIKeyService) {
+				apiKeys.EXPECT().Authenticate(gomock.Any(), "some-key").Return(model.Claims{
+					RegisteredClaims: jwt.RegisteredClaims{Subject: "apikey:1"},
+				}, nil)
+			},
+			ExpectSubject: "apikey:1",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			svc := service.NewMockAuthService(ctrl)
+			apiKeys := apikey_service.NewMockAPIKeyService(ctrl)
+			test.Resolver(svc, apiKeys)
+
+			ctx := metadata.NewIncomingContext(context.Background(), test.Metadata)
+			called := false
+			_, err := interceptor.Authentication(svc, apiKeys, "grpc.reflection.v1alpha.ServerReflection")(
+				ctx,
+				nil,
+				&grpc.UnaryServerInfo{FullMethod: test.Method},
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					called = true
+					claims, _ := model.ClaimsFromContext(ctx)
+					assert.Equal(t, test.ExpectSubject, claims.Subject)
+
+					return nil, nil
+				},
+			)
+
+			assert.Equal(t, test.ExpectErr, err)
+			assert.Equal(t, test.ExpectErr == nil, called)
+		})
+	}
+}
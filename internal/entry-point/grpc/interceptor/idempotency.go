@@ -0,0 +1,144 @@
+package interceptor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/tesarwijaya/ouroboros/internal/domain/idempotency/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/idempotency/service"
+	"github.com/tesarwijaya/ouroboros/internal/entry-point/grpc/grpcerror"
+	"github.com/tesarwijaya/ouroboros/internal/logger"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+const (
+	METADATA_IDEMPOTENCY_KEY     = "idempotency-key"
+	METADATA_IDEMPOTENT_REPLAYED = "idempotent-replayed"
+
+	contentTypeProto = "application/protobuf"
+)
+
+// Idempotency stores the response of the write calls carrying an
+// idempotency-key in their metadata, a retry with the same key and request
+// gets the stored response back instead of running the handler again. Keys
+// are scoped by caller, the same way the REST middleware does.
+func Idempotency(svc service.IdempotencyService) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		header := first(md, METADATA_IDEMPOTENCY_KEY)
+		msg, ok := req.(proto.Message)
+		if header == "" || isRead(info.FullMethod) || !ok {
+			return handler(ctx, req)
+		}
+		key := clientKey(ctx) + ":" + header
+
+		body, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		stored, replay, err := svc.Begin(ctx, key, fingerprint(info.FullMethod, body))
+		if errors.Is(err, model.ErrFingerprintMismatch) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+
+		if errors.Is(err, model.ErrInProgress) {
+			return nil, status.Error(codes.Aborted, err.Error())
+		}
+
+		if err != nil {
+			return nil, grpcerror.FromError(ctx, err)
+		}
+
+		if replay {
+			res, err := newResponse(info.FullMethod)
+			if err != nil {
+				return nil, grpcerror.FromError(ctx, err)
+			}
+
+			if err := proto.Unmarshal(stored.Body, res); err != nil {
+				return nil, grpcerror.FromError(ctx, err)
+			}
+			_ = grpc.SetHeader(ctx, metadata.Pairs(METADATA_IDEMPOTENT_REPLAYED, "true"))
+
+			return res, nil
+		}
+
+		res, err := handler(ctx, req)
+		if err != nil {
+			if releaseErr := svc.Release(ctx, key); releaseErr != nil {
+				logger.FromContext(ctx).Error("failed to release idempotency key", zap.String("idempotency_key", key), zap.Error(releaseErr))
+			}
+
+			return nil, err
+		}
+
+		resMsg, ok := res.(proto.Message)
+		if !ok {
+			return res, nil
+		}
+
+		resBody, err := proto.Marshal(resMsg)
+		if err == nil {
+			err = svc.Complete(ctx, model.IdempotencyKeyModel{
+				Key:         key,
+				StatusCode:  http.StatusOK,
+				ContentType: contentTypeProto,
+				Body:        resBody,
+			})
+		}
+		if err != nil {
+			logger.FromContext(ctx).Error("failed to store idempotent response", zap.String("idempotency_key", key), zap.Error(err))
+		}
+
+		return res, nil
+	}
+}
+
+// newResponse returns an empty response message of the method, looked up in
+// the registered proto descriptors.
+func newResponse(fullMethod string) (proto.Message, error) {
+	svc, method := splitMethod(fullMethod)
+
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(svc))
+	if err != nil {
+		return nil, err
+	}
+
+	svcDesc, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a service", svc)
+	}
+
+	methodDesc := svcDesc.Methods().ByName(protoreflect.Name(method))
+	if methodDesc == nil {
+		return nil, fmt.Errorf("unknown method %s", fullMethod)
+	}
+
+	msgType, err := protoregistry.GlobalTypes.FindMessageByName(methodDesc.Output().FullName())
+	if err != nil {
+		return nil, err
+	}
+
+	return msgType.New().Interface(), nil
+}
+
+func fingerprint(fullMethod string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(fullMethod))
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,140 @@
+package interceptor_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/tesarwijaya/ouroboros/internal/domain/idempotency/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/idempotency/service"
+	"github.com/tesarwijaya/ouroboros/internal/entry-point/grpc/interceptor"
+	"github.com/tesarwijaya/ouroboros/internal/entry-point/grpc/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func Test_Idempotency(t *testing.T) {
+	team := &pb.Team{Id: 1, Name: "some-team-name"}
+	body, _ := proto.Marshal(team)
+
+	testCases := []struct {
+		Name         string
+		Method       string
+		Metadata     metadata.MD
+		Resolver     func(svc *service.MockIdempotencyService)
+		Handler      func() (interface{}, error)
+		ExpectCalled bool
+		Expect       interface{}
+		ExpectErr    error
+	}{
+		{
+			Name:         "when_no_key",
+			Method:       "/ouroboros.v1.TeamService/InsertTeam",
+			Resolver:     func(svc *service.MockIdempotencyService) {},
+			ExpectCalled: true,
+			Expect:       team,
+		},
+		{
+			Name:         "when_read",
+			Method:       "/ouroboros.v1.TeamService/GetTeam",
+			Metadata:     metadata.Pairs("idempotency-key", "some-key"),
+			Resolver:     func(svc *service.MockIdempotencyService) {},
+			ExpectCalled: true,
+			Expect:       team,
+		},
+		{
+			Name:     "when_first_call",
+			Method:   "/ouroboros.v1.TeamService/InsertTeam",
+			Metadata: metadata.Pairs("idempotency-key", "some-key"),
+			Resolver: func(svc *service.MockIdempotencyService) {
+				svc.EXPECT().Begin(gomock.Any(), "ip:192.0.2.1:some-key", gomock.Any()).
+					Return(model.IdempotencyKeyModel{}, false, nil)
+				svc.EXPECT().Complete(gomock.Any(), model.IdempotencyKeyModel{
+					Key:         "ip:192.0.2.1:some-key",
+					StatusCode:  http.StatusOK,
+					ContentType: "application/protobuf",
+					Body:        body,
+				}).Return(nil)
+			},
+			ExpectCalled: true,
+			Expect:       team,
+		},
+		{
+			Name:     "when_retried",
+			Method:   "/ouroboros.v1.TeamService/InsertTeam",
+			Metadata: metadata.Pairs("idempotency-key", "some-key"),
+			Resolver: func(svc *service.MockIdempotencyService) {
+				svc.EXPECT().Begin(gomock.Any(), "ip:192.0.2.1:some-key", gomock.Any()).
+					Return(model.IdempotencyKeyModel{StatusCode: http.StatusOK, Body: body}, true, nil)
+			},
+			Expect: team,
+		},
+		{
+			Name:     "when_request_differs",
+			Method:   "/ouroboros.v1.TeamService/InsertTeam",
+			Metadata: metadata.Pairs("idempotency-key", "some-key"),
+			Resolver: func(svc *service.MockIdempotencyService) {
+				svc.EXPECT().Begin(gomock.Any(), "ip:192.0.2.1:some-key", gomock.Any()).
+					Return(model.IdempotencyKeyModel{}, false, model.ErrFingerprintMismatch)
+			},
+			ExpectErr: status.Error(codes.FailedPrecondition, model.ErrFingerprintMismatch.Error()),
+		},
+		{
+			Name:     "when_handler_failed",
+			Method:   "/ouroboros.v1.TeamService/InsertTeam",
+			Metadata: metadata.Pairs("idempotency-key", "some-key"),
+			Resolver: func(svc *service.MockIdempotencyService) {
+				svc.EXPECT().Begin(gomock.Any(), "ip:192.0.2.1:some-key", gomock.Any()).
+					Return(model.IdempotencyKeyModel{}, false, nil)
+				svc.EXPECT().Release(gomock.Any(), "ip:192.0.2.1:some-key").Return(nil)
+			},
+			Handler: func() (interface{}, error) {
+				return nil, errors.New("some-error")
+			},
+			ExpectCalled: true,
+			ExpectErr:    errors.New("some-error"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			svc := service.NewMockIdempotencyService(ctrl)
+			test.Resolver(svc)
+
+			ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 1234}})
+			ctx = metadata.NewIncomingContext(ctx, test.Metadata)
+
+			called := false
+			res, err := interceptor.Idempotency(svc)(
+				ctx,
+				&pb.InsertTeamRequest{Name: "some-team-name"},
+				&grpc.UnaryServerInfo{FullMethod: test.Method},
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					called = true
+					if test.Handler != nil {
+						return test.Handler()
+					}
+
+					return team, nil
+				},
+			)
+
+			assert.Equal(t, test.ExpectErr, err)
+			assert.Equal(t, test.ExpectCalled, called)
+			if test.Expect != nil {
+				assert.True(t, proto.Equal(test.Expect.(proto.Message), res.(proto.Message)))
+			}
+		})
+	}
+}
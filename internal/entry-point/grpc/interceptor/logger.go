@@ -0,0 +1,65 @@
+package interceptor
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/tesarwijaya/ouroboros/internal/logger"
+	"github.com/tesarwijaya/ouroboros/internal/tracing"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const METADATA_REQUEST_ID = "x-request-id"
+
+// RequestLogger puts a logger carrying the request ID and method in the
+// context and logs every call once it is handled. The request ID is taken
+// from the metadata when the caller sent one, and sent back in the header.
+func RequestLogger(l *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+
+		requestID := ""
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(METADATA_REQUEST_ID); len(values) > 0 {
+				requestID = values[0]
+			}
+		}
+		if requestID == "" {
+			requestID = uuid.Must(uuid.NewV4()).String()
+		}
+		_ = grpc.SetHeader(ctx, metadata.Pairs(METADATA_REQUEST_ID, requestID))
+
+		reqLogger := l.With(
+			zap.String("request_id", requestID),
+			zap.String("method", info.FullMethod),
+		)
+		if traceID := tracing.TraceID(ctx); traceID != "" {
+			reqLogger = reqLogger.With(zap.String("trace_id", traceID))
+		}
+		ctx = logger.WithContext(ctx, reqLogger)
+
+		res, err := handler(ctx, req)
+
+		code := status.Code(err)
+		fields := []zap.Field{
+			zap.String("code", code.String()),
+			zap.Duration("latency", time.Since(start)),
+		}
+
+		switch code {
+		case codes.OK:
+			reqLogger.Info("rpc handled", fields...)
+		case codes.Unknown, codes.Internal, codes.Unavailable, codes.DataLoss, codes.DeadlineExceeded, codes.Unimplemented:
+			reqLogger.Error("rpc failed", append(fields, zap.Error(err))...)
+		default:
+			reqLogger.Warn("rpc rejected", append(fields, zap.Error(err))...)
+		}
+
+		return res, err
+	}
+}
@@ -0,0 +1,155 @@
+package interceptor
+
+import (
+	"context"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	auth_model "github.com/tesarwijaya/ouroboros/internal/domain/auth/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/ratelimit/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/ratelimit/service"
+	"github.com/tesarwijaya/ouroboros/internal/logger"
+	"github.com/tesarwijaya/ouroboros/internal/metrics"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	METADATA_RATE_LIMIT_LIMIT     = "ratelimit-limit"
+	METADATA_RATE_LIMIT_REMAINING = "ratelimit-remaining"
+	METADATA_RATE_LIMIT_RESET     = "ratelimit-reset"
+	METADATA_RETRY_AFTER          = "retry-after"
+)
+
+// RateLimit gives every client a bucket for reads and another one for
+// writes, except for the methods of the given public services, the same way
+// the REST middleware does. Get and List methods are reads.
+func RateLimit(svc service.RateLimitService, read model.Limit, write model.Limit, public ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if isPublic(info.FullMethod, public) {
+			return handler(ctx, req)
+		}
+
+		class, limit := model.CLASS_WRITE, write
+		if isRead(info.FullMethod) {
+			class, limit = model.CLASS_READ, read
+		}
+
+		if err := take(ctx, svc, class, clientKey(ctx), limit); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// RateLimitByIP gives every IP a bucket for all of its calls, except for the
+// methods of the given public services. It runs before the authentication,
+// so that failed logins and guessed API keys are limited too.
+func RateLimitByIP(svc service.RateLimitService, limit model.Limit, public ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if isPublic(info.FullMethod, public) {
+			return handler(ctx, req)
+		}
+
+		if err := take(ctx, svc, model.CLASS_IP, peerIP(ctx), limit); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// take takes a token out of the client bucket of the class, the returned
+// error rejects the call. A failing store lets calls through.
+func take(ctx context.Context, svc service.RateLimitService, class string, client string, limit model.Limit) error {
+	if limit.Unlimited() {
+		return nil
+	}
+
+	key := class + ":" + client
+
+	res, err := svc.Allow(ctx, key, limit)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to check rate limit", zap.String("rate_limit_key", key), zap.Error(err))
+
+		return nil
+	}
+
+	md := metadata.Pairs(
+		METADATA_RATE_LIMIT_LIMIT, strconv.FormatInt(res.Limit, 10),
+		METADATA_RATE_LIMIT_REMAINING, strconv.FormatInt(res.Remaining, 10),
+		METADATA_RATE_LIMIT_RESET, ceilSeconds(res.Reset),
+	)
+
+	if !res.Allowed {
+		metrics.RateLimitedRequests.WithLabelValues(class).Inc()
+		md.Set(METADATA_RETRY_AFTER, ceilSeconds(res.RetryAfter))
+		_ = grpc.SetHeader(ctx, md)
+
+		return status.Error(codes.ResourceExhausted, "rate limit exceeded")
+	}
+	_ = grpc.SetHeader(ctx, md)
+
+	return nil
+}
+
+// clientKey identifies the caller, API key subjects already carry their
+// "apikey:" prefix.
+func clientKey(ctx context.Context) string {
+	claims, ok := auth_model.ClaimsFromContext(ctx)
+	if ok && claims.Subject != "" {
+		if strings.HasPrefix(claims.Subject, "apikey:") {
+			return claims.Subject
+		}
+
+		return "user:" + claims.Subject
+	}
+
+	return "ip:" + peerIP(ctx)
+}
+
+// peerIP is the address of the connected peer, metadata sent by the client
+// is never trusted for it.
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+
+	return host
+}
+
+// isRead reports whether the method only reads, going by its name.
+func isRead(fullMethod string) bool {
+	_, method := splitMethod(fullMethod)
+
+	return strings.HasPrefix(method, "Get") || strings.HasPrefix(method, "List")
+}
+
+func isPublic(fullMethod string, public []string) bool {
+	svc, _ := splitMethod(fullMethod)
+	for _, p := range public {
+		if svc == p {
+			return true
+		}
+	}
+
+	return false
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
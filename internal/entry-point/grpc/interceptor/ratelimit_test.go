@@ -0,0 +1,123 @@
+package interceptor_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	auth_model "github.com/tesarwijaya/ouroboros/internal/domain/auth/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/ratelimit/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/ratelimit/service"
+	"github.com/tesarwijaya/ouroboros/internal/entry-point/grpc/interceptor"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func Test_RateLimit(t *testing.T) {
+	read := model.Limit{Rate: 10, Burst: 20}
+	write := model.Limit{Rate: 1, Burst: 5}
+
+	testCases := []struct {
+		Name         string
+		Method       string
+		Subject      string
+		Resolver     func(svc *service.MockRateLimitService)
+		ExpectCalled bool
+		ExpectErr    error
+	}{
+		{
+			Name:         "when_public_service",
+			Method:       "/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo",
+			Resolver:     func(svc *service.MockRateLimitService) {},
+			ExpectCalled: true,
+		},
+		{
+			Name:   "when_anonymous_read",
+			Method: "/ouroboros.v1.TeamService/ListTeams",
+			Resolver: func(svc *service.MockRateLimitService) {
+				svc.EXPECT().Allow(gomock.Any(), "read:ip:192.0.2.1", read).
+					Return(model.Result{Allowed: true, Limit: 20, Remaining: 19}, nil)
+			},
+			ExpectCalled: true,
+		},
+		{
+			Name:    "when_user_write",
+			Method:  "/ouroboros.v1.TeamService/InsertTeam",
+			Subject: "some-user",
+			Resolver: func(svc *service.MockRateLimitService) {
+				svc.EXPECT().Allow(gomock.Any(), "write:user:some-user", write).
+					Return(model.Result{Allowed: true, Limit: 5, Remaining: 4}, nil)
+			},
+			ExpectCalled: true,
+		},
+		{
+			Name:    "when_api_key_exhausted",
+			Method:  "/ouroboros.v1.PlayerService/TransferPlayer",
+			Subject: "apikey:1",
+			Resolver: func(svc *service.MockRateLimitService) {
+				svc.EXPECT().Allow(gomock.Any(), "write:apikey:1", write).
+					Return(model.Result{Limit: 5}, nil)
+			},
+			ExpectErr: status.Error(codes.ResourceExhausted, "rate limit exceeded"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			svc := service.NewMockRateLimitService(ctrl)
+			test.Resolver(svc)
+
+			ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 1234}})
+			if test.Subject != "" {
+				ctx = auth_model.WithClaims(ctx, auth_model.Claims{
+					RegisteredClaims: jwt.RegisteredClaims{Subject: test.Subject},
+				})
+			}
+
+			called := false
+			_, err := interceptor.RateLimit(svc, read, write, "grpc.reflection.v1alpha.ServerReflection")(
+				ctx,
+				nil,
+				&grpc.UnaryServerInfo{FullMethod: test.Method},
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					called = true
+					return nil, nil
+				},
+			)
+
+			assert.Equal(t, test.ExpectErr, err)
+			assert.Equal(t, test.ExpectCalled, called)
+		})
+	}
+}
+
+func Test_RateLimitByIP(t *testing.T) {
+	limit := model.Limit{Rate: 50, Burst: 100}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := service.NewMockRateLimitService(ctrl)
+	svc.EXPECT().Allow(gomock.Any(), "ip:192.0.2.1", limit).Return(model.Result{Limit: 100}, nil)
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 1234}})
+	_, err := interceptor.RateLimitByIP(svc, limit)(
+		ctx,
+		nil,
+		&grpc.UnaryServerInfo{FullMethod: "/ouroboros.v1.TeamService/ListTeams"},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			t.Fatal("handler called")
+			return nil, nil
+		},
+	)
+
+	assert.Equal(t, status.Error(codes.ResourceExhausted, "rate limit exceeded"), err)
+}
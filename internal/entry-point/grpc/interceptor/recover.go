@@ -0,0 +1,33 @@
+package interceptor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tesarwijaya/ouroboros/internal/logger"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Recover turns a panicking handler into a bare internal error, the panic
+// value and the stack trace are logged but never sent to the client.
+func Recover() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (res interface{}, err error) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+
+			logger.FromContext(ctx).Error("handler panicked",
+				zap.String("panic", fmt.Sprint(r)),
+				zap.Stack("stack"),
+			)
+			err = status.Error(codes.Internal, codes.Internal.String())
+		}()
+
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,220 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: ouroboros/v1/player.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	PlayerService_ListPlayers_FullMethodName    = "/ouroboros.v1.PlayerService/ListPlayers"
+	PlayerService_GetPlayer_FullMethodName      = "/ouroboros.v1.PlayerService/GetPlayer"
+	PlayerService_InsertPlayer_FullMethodName   = "/ouroboros.v1.PlayerService/InsertPlayer"
+	PlayerService_TransferPlayer_FullMethodName = "/ouroboros.v1.PlayerService/TransferPlayer"
+)
+
+// PlayerServiceClient is the client API for PlayerService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PlayerServiceClient interface {
+	ListPlayers(ctx context.Context, in *ListPlayersRequest, opts ...grpc.CallOption) (*ListPlayersResponse, error)
+	GetPlayer(ctx context.Context, in *GetPlayerRequest, opts ...grpc.CallOption) (*Player, error)
+	InsertPlayer(ctx context.Context, in *InsertPlayerRequest, opts ...grpc.CallOption) (*Player, error)
+	TransferPlayer(ctx context.Context, in *TransferPlayerRequest, opts ...grpc.CallOption) (*TransferPlayerResponse, error)
+}
+
+type playerServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPlayerServiceClient(cc grpc.ClientConnInterface) PlayerServiceClient {
+	return &playerServiceClient{cc}
+}
+
+func (c *playerServiceClient) ListPlayers(ctx context.Context, in *ListPlayersRequest, opts ...grpc.CallOption) (*ListPlayersResponse, error) {
+	out := new(ListPlayersResponse)
+	err := c.cc.Invoke(ctx, PlayerService_ListPlayers_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *playerServiceClient) GetPlayer(ctx context.Context, in *GetPlayerRequest, opts ...grpc.CallOption) (*Player, error) {
+	out := new(Player)
+	err := c.cc.Invoke(ctx, PlayerService_GetPlayer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *playerServiceClient) InsertPlayer(ctx context.Context, in *InsertPlayerRequest, opts ...grpc.CallOption) (*Player, error) {
+	out := new(Player)
+	err := c.cc.Invoke(ctx, PlayerService_InsertPlayer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *playerServiceClient) TransferPlayer(ctx context.Context, in *TransferPlayerRequest, opts ...grpc.CallOption) (*TransferPlayerResponse, error) {
+	out := new(TransferPlayerResponse)
+	err := c.cc.Invoke(ctx, PlayerService_TransferPlayer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PlayerServiceServer is the server API for PlayerService service.
+// All implementations must embed UnimplementedPlayerServiceServer
+// for forward compatibility
+type PlayerServiceServer interface {
+	ListPlayers(context.Context, *ListPlayersRequest) (*ListPlayersResponse, error)
+	GetPlayer(context.Context, *GetPlayerRequest) (*Player, error)
+	InsertPlayer(context.Context, *InsertPlayerRequest) (*Player, error)
+	TransferPlayer(context.Context, *TransferPlayerRequest) (*TransferPlayerResponse, error)
+	mustEmbedUnimplementedPlayerServiceServer()
+}
+
+// UnimplementedPlayerServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedPlayerServiceServer struct {
+}
+
+func (UnimplementedPlayerServiceServer) ListPlayers(context.Context, *ListPlayersRequest) (*ListPlayersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPlayers not implemented")
+}
+func (UnimplementedPlayerServiceServer) GetPlayer(context.Context, *GetPlayerRequest) (*Player, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPlayer not implemented")
+}
+func (UnimplementedPlayerServiceServer) InsertPlayer(context.Context, *InsertPlayerRequest) (*Player, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InsertPlayer not implemented")
+}
+func (UnimplementedPlayerServiceServer) TransferPlayer(context.Context, *TransferPlayerRequest) (*TransferPlayerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TransferPlayer not implemented")
+}
+func (UnimplementedPlayerServiceServer) mustEmbedUnimplementedPlayerServiceServer() {}
+
+// UnsafePlayerServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PlayerServiceServer will
+// result in compilation errors.
+type UnsafePlayerServiceServer interface {
+	mustEmbedUnimplementedPlayerServiceServer()
+}
+
+func RegisterPlayerServiceServer(s grpc.ServiceRegistrar, srv PlayerServiceServer) {
+	s.RegisterService(&PlayerService_ServiceDesc, srv)
+}
+
+func _PlayerService_ListPlayers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPlayersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlayerServiceServer).ListPlayers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlayerService_ListPlayers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlayerServiceServer).ListPlayers(ctx, req.(*ListPlayersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlayerService_GetPlayer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPlayerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlayerServiceServer).GetPlayer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlayerService_GetPlayer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlayerServiceServer).GetPlayer(ctx, req.(*GetPlayerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlayerService_InsertPlayer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InsertPlayerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlayerServiceServer).InsertPlayer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlayerService_InsertPlayer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlayerServiceServer).InsertPlayer(ctx, req.(*InsertPlayerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlayerService_TransferPlayer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferPlayerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlayerServiceServer).TransferPlayer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlayerService_TransferPlayer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlayerServiceServer).TransferPlayer(ctx, req.(*TransferPlayerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PlayerService_ServiceDesc is the grpc.ServiceDesc for PlayerService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PlayerService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "ouroboros.v1.PlayerService",
+	HandlerType: (*PlayerServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListPlayers",
+			Handler:    _PlayerService_ListPlayers_Handler,
+		},
+		{
+			MethodName: "GetPlayer",
+			Handler:    _PlayerService_GetPlayer_Handler,
+		},
+		{
+			MethodName: "InsertPlayer",
+			Handler:    _PlayerService_InsertPlayer_Handler,
+		},
+		{
+			MethodName: "TransferPlayer",
+			Handler:    _PlayerService_TransferPlayer_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "ouroboros/v1/player.proto",
+}
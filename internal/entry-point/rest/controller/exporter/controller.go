@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tesarwijaya/ouroboros/internal/domain/exporter/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/exporter/service"
+	"github.com/tesarwijaya/ouroboros/internal/entry-point/rest/httperror"
+	"github.com/tesarwijaya/ouroboros/internal/logger"
+	"go.uber.org/zap"
+)
+
+var contentTypes = map[string]string{
+	model.FORMAT_CSV:    "text/csv; charset=utf-8",
+	model.FORMAT_NDJSON: "application/x-ndjson",
+}
+
+type ExportController struct {
+	Service service.ExportService
+}
+
+func NewExportController(service service.ExportService) ExportController {
+	return ExportController{
+		Service: service,
+	}
+}
+
+func (c *ExportController) SetRouter(ec *echo.Echo) {
+	ec.GET("/export/:kind", c.Export)
+}
+
+// Export godoc
+// @Summary      Export teams, players or transfers
+// @Description  stream every team, player or transfer as CSV or NDJSON, rows are written as they are read so the export may be cut short by an error once started
+// @Tags         Export
+// @Produce      text/csv,application/x-ndjson
+// @param        kind path string true "what to export" Enums(teams, players, transfers)
+// @param        format query string false "format of the export, csv by default" Enums(csv, ndjson)
+// @param        team_id query int false "only the players of the team, or the transfers from or to it"
+// @param        player_id query int false "only the transfers of the player"
+// @Success      200  {string}  string
+// @Failure      400  {object}  echo.HTTPError
+// @Failure      401  {object}  echo.HTTPError
+// @Failure      403  {object}  echo.HTTPError
+// @Failure      404  {object}  echo.HTTPError
+// @Failure      429  {object}  echo.HTTPError
+// @Failure      500  {object}  echo.HTTPError
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Router       /export/{kind} [get]
+func (c *ExportController) Export(ec echo.Context) error {
+	kind := ec.Param("kind")
+
+	format := strings.ToLower(ec.QueryParam("format"))
+	if format == "" {
+		format = model.FORMAT_CSV
+	}
+
+	contentType, ok := contentTypes[format]
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, model.ErrUnknownFormat.Error())
+	}
+
+	var (
+		filter model.Filter
+		err    error
+	)
+	if teamID := ec.QueryParam("team_id"); teamID != "" {
+		if filter.TeamID, err = strconv.ParseInt(teamID, 10, 64); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "team_id must be a number")
+		}
+	}
+
+	if playerID := ec.QueryParam("player_id"); playerID != "" {
+		if filter.PlayerID, err = strconv.ParseInt(playerID, 10, 64); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "player_id must be a number")
+		}
+	}
+
+	res := ec.Response()
+	res.Header().Set(echo.HeaderContentType, contentType)
+	res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", kind+"."+format))
+
+	err = c.Service.Export(ec.Request().Context(), kind, format, filter, res)
+	if err == nil {
+		return nil
+	}
+
+	if !res.Committed {
+		res.Header().Del(echo.HeaderContentType)
+		res.Header().Del(echo.HeaderContentDisposition)
+		return httperror.FromError(ec.Request().Context(), err)
+	}
+
+	// the status is sent already, the client only sees a truncated export
+	logger.FromContext(ec.Request().Context()).Error("export failed", zap.String("kind", kind), zap.Error(err))
+
+	return nil
+}
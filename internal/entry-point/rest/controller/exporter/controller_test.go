@@ -0,0 +1,135 @@
+package controller_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	auth_model "github.com/tesarwijaya/ouroboros/internal/domain/auth/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/exporter/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/exporter/service"
+	controller "github.com/tesarwijaya/ouroboros/internal/entry-point/rest/controller/exporter"
+)
+
+type ResolverFn func(svc *service.MockExportService)
+
+func createController(t *testing.T, resolver ResolverFn) (controller.ExportController, *gomock.Controller) {
+	ctrl := gomock.NewController(t)
+
+	svc := service.NewMockExportService(ctrl)
+	resolver(svc)
+
+	return controller.ExportController{
+		Service: svc,
+	}, ctrl
+}
+
+// write mocks an export writing body then failing with err.
+func write(body string, err error) func(ctx context.Context, kind string, format string, filter model.Filter, w io.Writer) error {
+	return func(ctx context.Context, kind string, format string, filter model.Filter, w io.Writer) error {
+		if body != "" {
+			_, _ = io.WriteString(w, body)
+		}
+
+		return err
+	}
+}
+
+func Test_Export(t *testing.T) {
+	testCases := []struct {
+		Name              string
+		Kind              string
+		Query             string
+		Resolver          ResolverFn
+		ExpectBody        string
+		ExpectContentType string
+		ExpectErr         error
+	}{
+		{
+			Name:  "when_csv",
+			Kind:  "players",
+			Query: "?team_id=1",
+			Resolver: func(svc *service.MockExportService) {
+				svc.EXPECT().Export(gomock.Any(), "players", model.FORMAT_CSV, model.Filter{TeamID: 1}, gomock.Any()).
+					DoAndReturn(write("id,name,team_id\n1,some-player-name,1\n", nil))
+			},
+			ExpectBody:        "id,name,team_id\n1,some-player-name,1\n",
+			ExpectContentType: "text/csv; charset=utf-8",
+		},
+		{
+			Name:  "when_ndjson",
+			Kind:  "transfers",
+			Query: "?format=ndjson&player_id=2",
+			Resolver: func(svc *service.MockExportService) {
+				svc.EXPECT().Export(gomock.Any(), "transfers", model.FORMAT_NDJSON, model.Filter{PlayerID: 2}, gomock.Any()).
+					DoAndReturn(write("{\"playerId\":2,\"toTeamId\":1}\n", nil))
+			},
+			ExpectBody:        "{\"playerId\":2,\"toTeamId\":1}\n",
+			ExpectContentType: "application/x-ndjson",
+		},
+		{
+			Name: "when_fails_midway",
+			Kind: "teams",
+			Resolver: func(svc *service.MockExportService) {
+				svc.EXPECT().Export(gomock.Any(), "teams", model.FORMAT_CSV, model.Filter{}, gomock.Any()).
+					DoAndReturn(write("id,name\n1,some-team-name\n", errors.New("some-error")))
+			},
+			ExpectBody:        "id,name\n1,some-team-name\n",
+			ExpectContentType: "text/csv; charset=utf-8",
+		},
+		{
+			Name: "when_forbidden",
+			Kind: "teams",
+			Resolver: func(svc *service.MockExportService) {
+				svc.EXPECT().Export(gomock.Any(), "teams", model.FORMAT_CSV, model.Filter{}, gomock.Any()).
+					Return(auth_model.ForbiddenError{Subject: "some-user", Action: auth_model.ACTION_TEAM_READ})
+			},
+			ExpectErr: echo.NewHTTPError(http.StatusForbidden, auth_model.ForbiddenError{Subject: "some-user", Action: auth_model.ACTION_TEAM_READ}.Error()),
+		},
+		{
+			Name:      "when_format_unknown",
+			Kind:      "teams",
+			Query:     "?format=xml",
+			Resolver:  func(svc *service.MockExportService) {},
+			ExpectErr: echo.NewHTTPError(http.StatusBadRequest, model.ErrUnknownFormat.Error()),
+		},
+		{
+			Name:      "when_team_id_invalid",
+			Kind:      "players",
+			Query:     "?team_id=x",
+			Resolver:  func(svc *service.MockExportService) {},
+			ExpectErr: echo.NewHTTPError(http.StatusBadRequest, "team_id must be a number"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/export/"+test.Kind+test.Query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("kind")
+			c.SetParamValues(test.Kind)
+
+			controller, mock := createController(t, test.Resolver)
+			defer mock.Finish()
+
+			err := controller.Export(c)
+			if test.ExpectErr == nil {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, rec.Code)
+				assert.Equal(t, test.ExpectBody, rec.Body.String())
+				assert.Equal(t, test.ExpectContentType, rec.Header().Get(echo.HeaderContentType))
+			} else {
+				assert.Equal(t, test.ExpectErr, err)
+				assert.Empty(t, rec.Header().Get(echo.HeaderContentDisposition))
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/labstack/echo/v4"
+	"github.com/tesarwijaya/ouroboros/internal/domain/healthz/model"
 	"github.com/tesarwijaya/ouroboros/internal/domain/healthz/service"
 )
 
@@ -18,13 +19,35 @@ func NewHealthzController(service service.HealthzService) HealthzController {
 }
 
 func (c *HealthzController) SetRouter(ec *echo.Echo) {
-	ec.GET("/healthz", c.Healthz)
+	ec.GET("/livez", c.Livez)
+	ec.GET("/readyz", c.Readyz)
+	// kept for probes configured before /readyz existed
+	ec.GET("/healthz", c.Readyz)
 }
 
-func (c *HealthzController) Healthz(ec echo.Context) error {
-	res, err := c.Service.Healthz(ec.Request().Context())
-	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+// Livez godoc
+// @Summary      Liveness probe
+// @Description  tells whether the process is alive, it doesn't check any dependency
+// @Tags         Healthz
+// @Produce      json
+// @Success      200  {object}  model.Report
+// @Router       /livez [get]
+func (c *HealthzController) Livez(ec echo.Context) error {
+	return ec.JSON(http.StatusOK, c.Service.Livez(ec.Request().Context()))
+}
+
+// Readyz godoc
+// @Summary      Readiness probe
+// @Description  checks every dependency, responds 503 when a required one is down
+// @Tags         Healthz
+// @Produce      json
+// @Success      200  {object}  model.Report
+// @Failure      503  {object}  model.Report
+// @Router       /readyz [get]
+func (c *HealthzController) Readyz(ec echo.Context) error {
+	res := c.Service.Readyz(ec.Request().Context())
+	if res.Status != model.STATUS_UP {
+		return ec.JSON(http.StatusServiceUnavailable, res)
 	}
 
 	return ec.JSON(http.StatusOK, res)
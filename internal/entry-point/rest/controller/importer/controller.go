@@ -0,0 +1,136 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tesarwijaya/ouroboros/internal/domain/importer/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/importer/service"
+	"github.com/tesarwijaya/ouroboros/internal/entry-point/rest/httperror"
+)
+
+type ImportController struct {
+	Service service.ImportService
+}
+
+func NewImportController(service service.ImportService) ImportController {
+	return ImportController{
+		Service: service,
+	}
+}
+
+func (c *ImportController) SetRouter(ec *echo.Echo) {
+	ec.POST("/import", c.Import)
+}
+
+// Import godoc
+// @Summary      Import teams and players
+// @Description  import teams, matched by name, and their players from a CSV, JSON or NDJSON file sent as body or as the file field of a multipart form. CSV files have a team column and optional player and player_id columns, JSON rows have team, player and playerId fields. Nothing is applied when a row is invalid, the rows of the report tell which of them were applied.
+// @Tags         Import
+// @Accept       text/csv,application/json,application/x-ndjson,multipart/form-data
+// @Produce      json
+// @param        format query string false "format of the file, guessed from the file name or content type by default" Enums(csv, json, ndjson)
+// @param        dry_run query bool false "validate the file and report what would be done without applying it"
+// @param        chunk_size query int false "rows imported per transaction, the whole file is imported in one transaction by default"
+// @param        file formData file false "file to import, when sent as multipart form"
+// @Success      200  {object}  model.ImportReport "dry run report"
+// @Success      201  {object}  model.ImportReport
+// @Failure      400  {object}  echo.HTTPError
+// @Failure      401  {object}  echo.HTTPError
+// @Failure      403  {object}  echo.HTTPError
+// @Failure      413  {object}  echo.HTTPError
+// @Failure      422  {object}  model.ImportReport "some rows failed"
+// @Failure      429  {object}  echo.HTTPError
+// @Failure      500  {object}  echo.HTTPError
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Router       /import [post]
+func (c *ImportController) Import(ec echo.Context) error {
+	opts, err := options(ec)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	body, format, err := file(ec)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	defer body.Close()
+
+	if f := ec.QueryParam("format"); f != "" {
+		format = strings.ToLower(f)
+	}
+
+	rows, err := model.Parse(body, format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	res, err := c.Service.Import(ec.Request().Context(), rows, opts)
+	if err != nil {
+		return httperror.FromError(ec.Request().Context(), err)
+	}
+
+	switch {
+	case res.Summary.Failed > 0:
+		return ec.JSON(http.StatusUnprocessableEntity, res)
+	case res.DryRun:
+		return ec.JSON(http.StatusOK, res)
+	}
+
+	return ec.JSON(http.StatusCreated, res)
+}
+
+func options(ec echo.Context) (model.Options, error) {
+	var (
+		opts model.Options
+		err  error
+	)
+
+	if dryRun := ec.QueryParam("dry_run"); dryRun != "" {
+		opts.DryRun, err = strconv.ParseBool(dryRun)
+		if err != nil {
+			return opts, errors.New("dry_run must be a boolean")
+		}
+	}
+
+	if chunkSize := ec.QueryParam("chunk_size"); chunkSize != "" {
+		opts.ChunkSize, err = strconv.Atoi(chunkSize)
+		if err != nil || opts.ChunkSize < 0 {
+			return opts, errors.New("chunk_size must be a positive number")
+		}
+	}
+
+	return opts, nil
+}
+
+// file returns the uploaded file of a multipart form or else the body, with
+// the format guessed from its name or content type.
+func file(ec echo.Context) (io.ReadCloser, string, error) {
+	req := ec.Request()
+
+	if !strings.HasPrefix(req.Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		return req.Body, model.FormatFromContentType(req.Header.Get(echo.HeaderContentType)), nil
+	}
+
+	header, err := ec.FormFile("file")
+	if err != nil {
+		return nil, "", errors.New("file is required")
+	}
+
+	f, err := header.Open()
+	if err != nil {
+		return nil, "", err
+	}
+
+	format := model.FormatFromFilename(header.Filename)
+	if format == "" {
+		format = model.FormatFromContentType(header.Header.Get(echo.HeaderContentType))
+	}
+
+	return f, format, nil
+}
@@ -0,0 +1,176 @@
+package controller_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/tesarwijaya/ouroboros/internal/domain/importer/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/importer/service"
+	controller "github.com/tesarwijaya/ouroboros/internal/entry-point/rest/controller/importer"
+)
+
+type ResolverFn func(svc *service.MockImportService)
+
+func createController(t *testing.T, resolver ResolverFn) (controller.ImportController, *gomock.Controller) {
+	ctrl := gomock.NewController(t)
+
+	svc := service.NewMockImportService(ctrl)
+	resolver(svc)
+
+	return controller.ImportController{
+		Service: svc,
+	}, ctrl
+}
+
+func multipartBody(filename, content string) (io.Reader, string) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, _ := w.CreateFormFile("file", filename)
+	_, _ = part.Write([]byte(content))
+	_ = w.Close()
+
+	return &body, w.FormDataContentType()
+}
+
+func Test_Import(t *testing.T) {
+	report := model.ImportReport{
+		Applied: true,
+		Summary: model.Summary{Created: 1},
+		Rows:    []model.RowResult{{Row: 1, Team: "owls", Player: "jane", Action: model.ACTION_CREATED, Applied: true}},
+	}
+
+	multipartJSON, multipartType := multipartBody("rows.json", `[{"team": "owls", "player": "jane"}]`)
+
+	testCases := []struct {
+		Name             string
+		Query            string
+		ContentType      string
+		Body             io.Reader
+		Resolver         ResolverFn
+		ExpectBody       string
+		ExpectStatusCode int
+		ExpectErr        error
+	}{
+		{
+			Name:        "when_csv",
+			ContentType: "text/csv",
+			Body:        strings.NewReader("team,player,player_id\nowls,jane,\n larks ,,10\nrobins,,x\n"),
+			Resolver: func(svc *service.MockImportService) {
+				svc.EXPECT().Import(gomock.Any(), []model.Row{
+					{Number: 1, Team: "owls", Player: "jane"},
+					{Number: 2, Team: "larks", PlayerID: 10},
+					{Number: 3, Team: "robins", Err: errors.New("invalid player_id \"x\"")},
+				}, model.Options{}).Return(report, nil)
+			},
+			ExpectStatusCode: http.StatusCreated,
+			ExpectBody:       "{\"dryRun\":false,\"applied\":true,\"summary\":{\"created\":1,\"updated\":0,\"skipped\":0,\"failed\":0},\"rows\":[{\"row\":1,\"team\":\"owls\",\"player\":\"jane\",\"action\":\"created\",\"applied\":true}]}\n",
+		},
+		{
+			Name:  "when_ndjson_dry_run",
+			Query: "?format=ndjson&dry_run=true&chunk_size=100",
+			Body:  strings.NewReader("{\"team\": \"owls\", \"player\": \"jane\"}\n\n{\"team\": \"larks\", \"playerId\": 10}\n"),
+			Resolver: func(svc *service.MockImportService) {
+				svc.EXPECT().Import(gomock.Any(), []model.Row{
+					{Number: 1, Team: "owls", Player: "jane"},
+					{Number: 2, Team: "larks", PlayerID: 10},
+				}, model.Options{DryRun: true, ChunkSize: 100}).Return(model.ImportReport{DryRun: true, Rows: []model.RowResult{}}, nil)
+			},
+			ExpectStatusCode: http.StatusOK,
+			ExpectBody:       "{\"dryRun\":true,\"applied\":false,\"summary\":{\"created\":0,\"updated\":0,\"skipped\":0,\"failed\":0},\"rows\":[]}\n",
+		},
+		{
+			Name:        "when_multipart",
+			ContentType: multipartType,
+			Body:        multipartJSON,
+			Resolver: func(svc *service.MockImportService) {
+				svc.EXPECT().Import(gomock.Any(), []model.Row{{Number: 1, Team: "owls", Player: "jane"}}, model.Options{}).
+					Return(report, nil)
+			},
+			ExpectStatusCode: http.StatusCreated,
+			ExpectBody:       "{\"dryRun\":false,\"applied\":true,\"summary\":{\"created\":1,\"updated\":0,\"skipped\":0,\"failed\":0},\"rows\":[{\"row\":1,\"team\":\"owls\",\"player\":\"jane\",\"action\":\"created\",\"applied\":true}]}\n",
+		},
+		{
+			Name:        "when_rows_fail",
+			ContentType: echo.MIMEApplicationJSON,
+			Body:        strings.NewReader(`[{"team": ""}]`),
+			Resolver: func(svc *service.MockImportService) {
+				svc.EXPECT().Import(gomock.Any(), gomock.Any(), gomock.Any()).Return(model.ImportReport{
+					Summary: model.Summary{Failed: 1},
+					Rows:    []model.RowResult{{Row: 1, Action: model.ACTION_FAILED, Error: "team name is required"}},
+				}, nil)
+			},
+			ExpectStatusCode: http.StatusUnprocessableEntity,
+			ExpectBody:       "{\"dryRun\":false,\"applied\":false,\"summary\":{\"created\":0,\"updated\":0,\"skipped\":0,\"failed\":1},\"rows\":[{\"row\":1,\"team\":\"\",\"action\":\"failed\",\"applied\":false,\"error\":\"team name is required\"}]}\n",
+		},
+		{
+			Name:      "when_format_is_unknown",
+			Body:      strings.NewReader("team\nowls\n"),
+			Resolver:  func(svc *service.MockImportService) {},
+			ExpectErr: echo.NewHTTPError(http.StatusBadRequest, model.ErrUnknownFormat.Error()),
+		},
+		{
+			Name:        "when_csv_has_no_team_column",
+			ContentType: "text/csv",
+			Body:        strings.NewReader("name\nowls\n"),
+			Resolver:    func(svc *service.MockImportService) {},
+			ExpectErr:   echo.NewHTTPError(http.StatusBadRequest, "csv header has no team column"),
+		},
+		{
+			Name:        "when_json_is_not_an_array",
+			ContentType: echo.MIMEApplicationJSON,
+			Body:        strings.NewReader(`{"team": "owls"}`),
+			Resolver:    func(svc *service.MockImportService) {},
+			ExpectErr:   echo.NewHTTPError(http.StatusBadRequest, "json must be an array of rows"),
+		},
+		{
+			Name:        "when_chunk_size_is_invalid",
+			Query:       "?chunk_size=-1",
+			ContentType: "text/csv",
+			Body:        strings.NewReader("team\nowls\n"),
+			Resolver:    func(svc *service.MockImportService) {},
+			ExpectErr:   echo.NewHTTPError(http.StatusBadRequest, "chunk_size must be a positive number"),
+		},
+		{
+			Name:        "when_not_success",
+			ContentType: "text/csv",
+			Body:        strings.NewReader("team\nowls\n"),
+			Resolver: func(svc *service.MockImportService) {
+				svc.EXPECT().Import(gomock.Any(), gomock.Any(), gomock.Any()).Return(model.ImportReport{}, errors.New("some-error"))
+			},
+			ExpectErr: echo.NewHTTPError(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError)),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/import"+test.Query, test.Body)
+			if test.ContentType != "" {
+				req.Header.Set(echo.HeaderContentType, test.ContentType)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			controller, mock := createController(t, test.Resolver)
+			defer mock.Finish()
+
+			err := controller.Import(c)
+			if test.ExpectErr == nil {
+				assert.Nil(t, err)
+				assert.Equal(t, test.ExpectStatusCode, rec.Code)
+				assert.Equal(t, test.ExpectBody, rec.Body.String())
+			} else {
+				assert.Equal(t, test.ExpectErr, err)
+			}
+		})
+	}
+}
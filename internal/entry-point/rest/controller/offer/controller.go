@@ -0,0 +1,212 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	command_service "github.com/tesarwijaya/ouroboros/internal/domain/command/service"
+	"github.com/tesarwijaya/ouroboros/internal/domain/offer/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/offer/service"
+	"github.com/tesarwijaya/ouroboros/internal/entry-point/rest/httperror"
+)
+
+type OfferController struct {
+	Service service.OfferService
+	Bus     command_service.CommandBus
+}
+
+func NewOfferController(service service.OfferService, bus command_service.CommandBus) OfferController {
+	return OfferController{
+		Service: service,
+		Bus:     bus,
+	}
+}
+
+func (c *OfferController) SetRouter(ec *echo.Echo) {
+	ec.GET("/team/:id/offers", c.FindByTeamID)
+	ec.POST("/offers", c.Make)
+	ec.POST("/offers/:id/accept", c.Accept)
+	ec.POST("/offers/:id/reject", c.Reject)
+	ec.POST("/offers/:id/counter", c.Counter)
+}
+
+// FindByTeamID godoc
+// @Summary      List team offers
+// @Description  list the transfer offers the team makes or receives, the pending ones unless another status is given.
+// @Tags         Offer
+// @Accept       json
+// @Produce      json
+// @param        id path int true "team id"
+// @param        status query string false "pending, accepted, rejected, countered, expired or withdrawn"
+// @Success      200  {object}  []model.OfferModel
+// @Failure      400  {object}  echo.HTTPError
+// @Failure      401  {object}  echo.HTTPError
+// @Failure      403  {object}  echo.HTTPError
+// @Failure      429  {object}  echo.HTTPError
+// @Failure      500  {object}  echo.HTTPError
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Router       /team/{id}/offers [get]
+func (c *OfferController) FindByTeamID(ec echo.Context) error {
+	id, err := strconv.ParseInt(ec.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	status := ec.QueryParam("status")
+	if status == "" {
+		status = model.STATUS_PENDING
+	}
+
+	res, err := c.Service.FindByTeamID(ec.Request().Context(), id, status)
+	if err != nil {
+		return httperror.FromError(ec.Request().Context(), err)
+	}
+
+	return ec.JSON(http.StatusOK, res)
+}
+
+// Make godoc
+// @Summary      Make offer
+// @Description  make a transfer offer for a player of another team, the selling team accepts, rejects or counters it before it expires. The player is only transferred once the offer is accepted. A team can't make an offer for a player it already has one pending for, and expiresAt can't be further than APP_OFFER_MAX_TTL.
+// @Tags         Offer
+// @Accept       json
+// @Produce      json
+// @param        Idempotency-Key header string false "key to safely retry the request"
+// @param        offer body service.MakeOfferPayload true "body"
+// @Success      201  {object}  model.OfferModel
+// @Failure      400  {object}  echo.HTTPError
+// @Failure      401  {object}  echo.HTTPError
+// @Failure      403  {object}  echo.HTTPError
+// @Failure      429  {object}  echo.HTTPError
+// @Failure      500  {object}  echo.HTTPError
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Router       /offers [post]
+func (c *OfferController) Make(ec echo.Context) error {
+	var payload service.MakeOfferPayload
+
+	if err := ec.Bind(&payload); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	res, err := command_service.Dispatch[model.OfferModel](ec.Request().Context(), c.Bus, service.MakeOfferCommand{
+		Payload: payload,
+	})
+	if err != nil {
+		return httperror.FromError(ec.Request().Context(), err)
+	}
+
+	return ec.JSON(http.StatusCreated, res)
+}
+
+// Accept godoc
+// @Summary      Accept offer
+// @Description  accept a pending offer made to the caller's team, the player is transferred to the buying team along.
+// @Tags         Offer
+// @Accept       json
+// @Produce      json
+// @param        Idempotency-Key header string false "key to safely retry the request"
+// @param        id path int true "offer id"
+// @Success      200  {object}  model.OfferModel
+// @Failure      400  {object}  echo.HTTPError
+// @Failure      401  {object}  echo.HTTPError
+// @Failure      403  {object}  echo.HTTPError
+// @Failure      404  {object}  echo.HTTPError
+// @Failure      429  {object}  echo.HTTPError
+// @Failure      500  {object}  echo.HTTPError
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Router       /offers/{id}/accept [post]
+func (c *OfferController) Accept(ec echo.Context) error {
+	id, err := strconv.ParseInt(ec.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	res, err := command_service.Dispatch[model.OfferModel](ec.Request().Context(), c.Bus, service.AcceptOfferCommand{
+		ID: id,
+	})
+	if err != nil {
+		return httperror.FromError(ec.Request().Context(), err)
+	}
+
+	return ec.JSON(http.StatusOK, res)
+}
+
+// Reject godoc
+// @Summary      Reject offer
+// @Description  reject a pending offer made to the caller's team.
+// @Tags         Offer
+// @Accept       json
+// @Produce      json
+// @param        Idempotency-Key header string false "key to safely retry the request"
+// @param        id path int true "offer id"
+// @Success      200  {object}  model.OfferModel
+// @Failure      400  {object}  echo.HTTPError
+// @Failure      401  {object}  echo.HTTPError
+// @Failure      403  {object}  echo.HTTPError
+// @Failure      404  {object}  echo.HTTPError
+// @Failure      429  {object}  echo.HTTPError
+// @Failure      500  {object}  echo.HTTPError
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Router       /offers/{id}/reject [post]
+func (c *OfferController) Reject(ec echo.Context) error {
+	id, err := strconv.ParseInt(ec.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	res, err := command_service.Dispatch[model.OfferModel](ec.Request().Context(), c.Bus, service.RejectOfferCommand{
+		ID: id,
+	})
+	if err != nil {
+		return httperror.FromError(ec.Request().Context(), err)
+	}
+
+	return ec.JSON(http.StatusOK, res)
+}
+
+// Counter godoc
+// @Summary      Counter offer
+// @Description  counter a pending offer made to the caller's team with another fee, the offer is closed and the counter offer is made to the other team in return.
+// @Tags         Offer
+// @Accept       json
+// @Produce      json
+// @param        Idempotency-Key header string false "key to safely retry the request"
+// @param        id path int true "offer id"
+// @param        counter body service.CounterOfferPayload true "body"
+// @Success      201  {object}  model.OfferModel
+// @Failure      400  {object}  echo.HTTPError
+// @Failure      401  {object}  echo.HTTPError
+// @Failure      403  {object}  echo.HTTPError
+// @Failure      404  {object}  echo.HTTPError
+// @Failure      429  {object}  echo.HTTPError
+// @Failure      500  {object}  echo.HTTPError
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Router       /offers/{id}/counter [post]
+func (c *OfferController) Counter(ec echo.Context) error {
+	id, err := strconv.ParseInt(ec.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	var payload service.CounterOfferPayload
+
+	if err := ec.Bind(&payload); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	res, err := command_service.Dispatch[model.OfferModel](ec.Request().Context(), c.Bus, service.CounterOfferCommand{
+		ID:      id,
+		Payload: payload,
+	})
+	if err != nil {
+		return httperror.FromError(ec.Request().Context(), err)
+	}
+
+	return ec.JSON(http.StatusCreated, res)
+}
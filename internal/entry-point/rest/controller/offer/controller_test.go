@@ -0,0 +1,304 @@
+package controller_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	command_model "github.com/tesarwijaya/ouroboros/internal/domain/command/model"
+	command_service "github.com/tesarwijaya/ouroboros/internal/domain/command/service"
+	"github.com/tesarwijaya/ouroboros/internal/domain/offer/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/offer/service"
+	controller "github.com/tesarwijaya/ouroboros/internal/entry-point/rest/controller/offer"
+)
+
+type ResolverFn func(svc *service.MockOfferService)
+
+var (
+	expiresAt = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	offer     = model.OfferModel{
+		ID:               1,
+		PlayerID:         2,
+		FromTeamID:       3,
+		ToTeamID:         4,
+		ProposedByTeamID: 4,
+		Fee:              100,
+		Status:           model.STATUS_PENDING,
+		ExpiresAt:        expiresAt,
+		CreatedAt:        expiresAt,
+		UpdatedAt:        expiresAt,
+	}
+	offerBody = "{\"id\":1,\"playerId\":2,\"fromTeamId\":3,\"toTeamId\":4,\"proposedByTeamId\":4,\"fee\":100,\"status\":\"pending\",\"expiresAt\":\"2024-01-02T03:04:05Z\",\"createdAt\":\"2024-01-02T03:04:05Z\",\"updatedAt\":\"2024-01-02T03:04:05Z\"}\n"
+)
+
+func createController(t *testing.T, resolver ResolverFn) (controller.OfferController, *gomock.Controller) {
+	ctrl := gomock.NewController(t)
+
+	svc := service.NewMockOfferService(ctrl)
+	resolver(svc)
+
+	bus, _ := command_service.NewCommandBus(command_service.CommandBusImpl{
+		Handlers:    service.NewCommandHandlers(svc).Handlers,
+		Middlewares: []command_model.Middleware{command_service.NewValidationMiddleware()},
+	})
+
+	return controller.OfferController{
+		Service: svc,
+		Bus:     bus,
+	}, ctrl
+}
+
+func Test_FindByTeamID(t *testing.T) {
+	testCases := []struct {
+		Name       string
+		Query      string
+		Resolver   ResolverFn
+		ExpectBody string
+		ExpectErr  error
+	}{
+		{
+			Name: "when_success",
+			Resolver: func(svc *service.MockOfferService) {
+				svc.EXPECT().FindByTeamID(gomock.Any(), int64(3), model.STATUS_PENDING).
+					Return([]model.OfferModel{offer}, nil)
+			},
+			ExpectBody: "[" + strings.TrimSuffix(offerBody, "\n") + "]\n",
+		},
+		{
+			Name:  "when_status_given",
+			Query: "?status=expired",
+			Resolver: func(svc *service.MockOfferService) {
+				svc.EXPECT().FindByTeamID(gomock.Any(), int64(3), model.STATUS_EXPIRED).
+					Return([]model.OfferModel{}, nil)
+			},
+			ExpectBody: "[]\n",
+		},
+		{
+			Name:  "when_not_success",
+			Query: "?status=some-status",
+			Resolver: func(svc *service.MockOfferService) {
+				svc.EXPECT().FindByTeamID(gomock.Any(), int64(3), "some-status").
+					Return([]model.OfferModel{}, command_model.ValidationError{Err: errors.New("unknown status \"some-status\"")})
+			},
+			ExpectErr: echo.NewHTTPError(http.StatusBadRequest, "unknown status \"some-status\""),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/team/3/offers"+test.Query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues("3")
+
+			controller, mock := createController(t, test.Resolver)
+			defer mock.Finish()
+
+			err := controller.FindByTeamID(c)
+			if test.ExpectErr == nil {
+				assert.Nil(t, err)
+				assert.Equal(t, test.ExpectBody, rec.Body.String())
+				assert.Equal(t, http.StatusOK, rec.Code)
+			} else {
+				assert.Equal(t, test.ExpectErr, err)
+			}
+		})
+	}
+}
+
+func Test_Make(t *testing.T) {
+	testCases := []struct {
+		Name       string
+		Body       string
+		Resolver   ResolverFn
+		ExpectBody string
+		ExpectErr  error
+	}{
+		{
+			Name: "when_success",
+			Body: `{"playerId": 2, "toTeamId": 4, "fee": 100}`,
+			Resolver: func(svc *service.MockOfferService) {
+				svc.EXPECT().Make(gomock.Any(), service.MakeOfferPayload{PlayerID: 2, ToTeamID: 4, Fee: 100}).
+					Return(offer, nil)
+			},
+			ExpectBody: offerBody,
+		},
+		{
+			Name:      "when_fee_negative",
+			Body:      `{"playerId": 2, "toTeamId": 4, "fee": -1}`,
+			Resolver:  func(svc *service.MockOfferService) {},
+			ExpectErr: echo.NewHTTPError(http.StatusBadRequest, "fee can't be negative"),
+		},
+		{
+			Name:      "when_team_missing",
+			Body:      `{"playerId": 2}`,
+			Resolver:  func(svc *service.MockOfferService) {},
+			ExpectErr: echo.NewHTTPError(http.StatusBadRequest, "toTeamId is required"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/offers", strings.NewReader(test.Body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			controller, mock := createController(t, test.Resolver)
+			defer mock.Finish()
+
+			err := controller.Make(c)
+			if test.ExpectErr == nil {
+				assert.Nil(t, err)
+				assert.Equal(t, test.ExpectBody, rec.Body.String())
+				assert.Equal(t, http.StatusCreated, rec.Code)
+			} else {
+				assert.Equal(t, test.ExpectErr, err)
+			}
+		})
+	}
+}
+
+func Test_Accept(t *testing.T) {
+	testCases := []struct {
+		Name       string
+		ID         string
+		Resolver   ResolverFn
+		ExpectBody string
+		ExpectErr  error
+	}{
+		{
+			Name: "when_success",
+			ID:   "1",
+			Resolver: func(svc *service.MockOfferService) {
+				svc.EXPECT().Accept(gomock.Any(), int64(1)).Return(offer, nil)
+			},
+			ExpectBody: offerBody,
+		},
+		{
+			Name: "when_not_found",
+			ID:   "1",
+			Resolver: func(svc *service.MockOfferService) {
+				svc.EXPECT().Accept(gomock.Any(), int64(1)).Return(model.OfferModel{}, model.ErrOfferNotFound)
+			},
+			ExpectErr: echo.NewHTTPError(http.StatusNotFound, "offer not found"),
+		},
+		{
+			Name: "when_not_pending",
+			ID:   "1",
+			Resolver: func(svc *service.MockOfferService) {
+				svc.EXPECT().Accept(gomock.Any(), int64(1)).
+					Return(model.OfferModel{}, command_model.ValidationError{Err: errors.New("offer 1 is rejected")})
+			},
+			ExpectErr: echo.NewHTTPError(http.StatusBadRequest, "offer 1 is rejected"),
+		},
+		{
+			Name:      "when_id_invalid",
+			ID:        "some-id",
+			Resolver:  func(svc *service.MockOfferService) {},
+			ExpectErr: echo.NewHTTPError(http.StatusBadRequest, "strconv.ParseInt: parsing \"some-id\": invalid syntax"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/offers/"+test.ID+"/accept", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues(test.ID)
+
+			controller, mock := createController(t, test.Resolver)
+			defer mock.Finish()
+
+			err := controller.Accept(c)
+			if test.ExpectErr == nil {
+				assert.Nil(t, err)
+				assert.Equal(t, test.ExpectBody, rec.Body.String())
+				assert.Equal(t, http.StatusOK, rec.Code)
+			} else {
+				assert.Equal(t, test.ExpectErr, err)
+			}
+		})
+	}
+}
+
+func Test_Reject(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/offers/1/reject", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	controller, mock := createController(t, func(svc *service.MockOfferService) {
+		svc.EXPECT().Reject(gomock.Any(), int64(1)).Return(offer, nil)
+	})
+	defer mock.Finish()
+
+	err := controller.Reject(c)
+
+	assert.Nil(t, err)
+	assert.Equal(t, offerBody, rec.Body.String())
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func Test_Counter(t *testing.T) {
+	testCases := []struct {
+		Name       string
+		Body       string
+		Resolver   ResolverFn
+		ExpectBody string
+		ExpectErr  error
+	}{
+		{
+			Name: "when_success",
+			Body: `{"fee": 150, "expiresAt": "2024-01-02T03:04:05Z"}`,
+			Resolver: func(svc *service.MockOfferService) {
+				svc.EXPECT().Counter(gomock.Any(), int64(1), service.CounterOfferPayload{Fee: 150, ExpiresAt: expiresAt}).
+					Return(offer, nil)
+			},
+			ExpectBody: offerBody,
+		},
+		{
+			Name:      "when_fee_negative",
+			Body:      `{"fee": -1}`,
+			Resolver:  func(svc *service.MockOfferService) {},
+			ExpectErr: echo.NewHTTPError(http.StatusBadRequest, "fee can't be negative"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/offers/1/counter", strings.NewReader(test.Body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues("1")
+
+			controller, mock := createController(t, test.Resolver)
+			defer mock.Finish()
+
+			err := controller.Counter(c)
+			if test.ExpectErr == nil {
+				assert.Nil(t, err)
+				assert.Equal(t, test.ExpectBody, rec.Body.String())
+				assert.Equal(t, http.StatusCreated, rec.Code)
+			} else {
+				assert.Equal(t, test.ExpectErr, err)
+			}
+		})
+	}
+}
@@ -1,21 +1,30 @@
 package controller
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 
 	"github.com/labstack/echo/v4"
+	command_service "github.com/tesarwijaya/ouroboros/internal/domain/command/service"
 	"github.com/tesarwijaya/ouroboros/internal/domain/player/model"
 	"github.com/tesarwijaya/ouroboros/internal/domain/player/service"
+	"github.com/tesarwijaya/ouroboros/internal/entry-point/rest/httperror"
+	"github.com/tesarwijaya/ouroboros/internal/entry-point/rest/query"
+	"github.com/tesarwijaya/ouroboros/internal/patch"
 )
 
 type PlayerController struct {
 	Service service.PlayerService
+	Bus     command_service.CommandBus
 }
 
-func NewPlayerController(service service.PlayerService) PlayerController {
+func NewPlayerController(service service.PlayerService, bus command_service.CommandBus) PlayerController {
 	return PlayerController{
 		Service: service,
+		Bus:     bus,
 	}
 }
 
@@ -24,26 +33,63 @@ func (c *PlayerController) SetRouter(ec *echo.Echo) {
 	ec.GET("/player/:id", c.FindByID)
 	ec.POST("/player", c.Insert)
 	ec.PATCH("/player/transfer", c.Transfer)
+	ec.PATCH("/player/:id", c.Patch)
+	ec.POST("/transfers/batch", c.TransferBatch)
+	ec.POST("/transfers/swap", c.Swap)
 }
 
 // FindAll godoc
 // @Summary      Show all player
-// @Description  get all player
+// @Description  get all player, or the players with the given ids in a single query along the ids no player has. fields only selects and responds the given fields.
 // @Tags         Player
 // @Accept       json
 // @Produce      json
+// @param        ids query string false "comma separated player ids, at most 100, the response then lists the found items and the missing ids"
+// @param        fields query string false "comma separated fields to respond, e.g. id,name,teamId"
 // @Success      200  {object}  []model.PlayerModel
 // @Failure      400  {object}  echo.HTTPError
+// @Failure      401  {object}  echo.HTTPError
+// @Failure      403  {object}  echo.HTTPError
 // @Failure      404  {object}  echo.HTTPError
+// @Failure      429  {object}  echo.HTTPError
 // @Failure      500  {object}  echo.HTTPError
+// @Security     BearerAuth
+// @Security     APIKeyAuth
 // @Router       /player [get]
 func (c *PlayerController) FindAll(ec echo.Context) error {
-	res, err := c.Service.FindAll(ec.Request().Context())
+	ids, err := query.IDs(ec.QueryParam("ids"))
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	return ec.JSON(http.StatusOK, res)
+	fields := query.Fields(ec.QueryParam("fields"))
+	if ids == nil && fields == nil {
+		res, err := c.Service.FindAll(ec.Request().Context())
+		if err != nil {
+			return httperror.FromError(ec.Request().Context(), err)
+		}
+
+		return ec.JSON(http.StatusOK, res)
+	}
+
+	players, missing, err := c.Service.FindFields(ec.Request().Context(), ids, fields)
+	if err != nil {
+		return httperror.FromError(ec.Request().Context(), err)
+	}
+
+	items := make([]map[string]interface{}, 0, len(players))
+	for _, player := range players {
+		items = append(items, player.Sparse(fields))
+	}
+
+	if ids == nil {
+		return ec.JSON(http.StatusOK, items)
+	}
+
+	return ec.JSON(http.StatusOK, model.PlayerLookupRespModel{
+		Items:   items,
+		Missing: missing,
+	})
 }
 
 // FindByID godoc
@@ -55,8 +101,13 @@ func (c *PlayerController) FindAll(ec echo.Context) error {
 // @param        id path int true "player id"
 // @Success      200  {object}  model.PlayerModel
 // @Failure      400  {object}  echo.HTTPError
+// @Failure      401  {object}  echo.HTTPError
+// @Failure      403  {object}  echo.HTTPError
 // @Failure      404  {object}  echo.HTTPError
+// @Failure      429  {object}  echo.HTTPError
 // @Failure      500  {object}  echo.HTTPError
+// @Security     BearerAuth
+// @Security     APIKeyAuth
 // @Router       /player/{id} [get]
 func (c *PlayerController) FindByID(ec echo.Context) error {
 	idParam := ec.Param("id")
@@ -67,7 +118,7 @@ func (c *PlayerController) FindByID(ec echo.Context) error {
 
 	res, err := c.Service.FindByID(ec.Request().Context(), id)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return httperror.FromError(ec.Request().Context(), err)
 	}
 
 	return ec.JSON(http.StatusOK, res)
@@ -79,11 +130,17 @@ func (c *PlayerController) FindByID(ec echo.Context) error {
 // @Tags         Player
 // @Accept       json
 // @Produce      json
+// @param        Idempotency-Key header string false "key to safely retry the request"
 // @param        id body model.PlayerModel true "body"
-// @Success      200  {object}  model.PlayerModel
+// @Success      201  {object}  model.PlayerModel
 // @Failure      400  {object}  echo.HTTPError
+// @Failure      401  {object}  echo.HTTPError
+// @Failure      403  {object}  echo.HTTPError
 // @Failure      404  {object}  echo.HTTPError
+// @Failure      429  {object}  echo.HTTPError
 // @Failure      500  {object}  echo.HTTPError
+// @Security     BearerAuth
+// @Security     APIKeyAuth
 // @Router       /player [post]
 func (c *PlayerController) Insert(ec echo.Context) error {
 	var payload model.PlayerModel
@@ -92,12 +149,16 @@ func (c *PlayerController) Insert(ec echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	res, err := c.Service.Insert(ec.Request().Context(), payload)
+	res, err := command_service.Dispatch[model.PlayerModel](ec.Request().Context(), c.Bus, service.InsertPlayerCommand{
+		Payload: payload,
+	})
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return httperror.FromError(ec.Request().Context(), err)
 	}
 
-	return ec.JSON(http.StatusOK, res)
+	ec.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("/player/%d", res.ID))
+
+	return ec.JSON(http.StatusCreated, res)
 }
 
 // Transfer godoc
@@ -106,10 +167,16 @@ func (c *PlayerController) Insert(ec echo.Context) error {
 // @Tags         Player
 // @Accept       json
 // @Produce      json
+// @param        Idempotency-Key header string false "key to safely retry the request"
 // @param        id body service.TransferPayload true "body"
 // @Failure      400  {object}  echo.HTTPError
+// @Failure      401  {object}  echo.HTTPError
+// @Failure      403  {object}  echo.HTTPError
 // @Failure      404  {object}  echo.HTTPError
+// @Failure      429  {object}  echo.HTTPError
 // @Failure      500  {object}  echo.HTTPError
+// @Security     BearerAuth
+// @Security     APIKeyAuth
 // @Router       /player/transfer [post]
 func (c *PlayerController) Transfer(ec echo.Context) error {
 	var payload service.TransferPayload
@@ -118,10 +185,130 @@ func (c *PlayerController) Transfer(ec echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	err := c.Service.Transfer(ec.Request().Context(), payload)
+	_, err := c.Bus.Dispatch(ec.Request().Context(), service.TransferPlayerCommand{
+		Payload: payload,
+	})
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return httperror.FromError(ec.Request().Context(), err)
 	}
 
 	return ec.JSON(http.StatusNoContent, nil)
 }
+
+// TransferBatch godoc
+// @Summary      Transfer players together
+// @Description  transfer several players at once, every transfer is applied or none of them. The transfers are validated together and their events share a correlation id.
+// @Tags         Player
+// @Accept       json
+// @Produce      json
+// @param        Idempotency-Key header string false "key to safely retry the request"
+// @param        transfers body service.TransferBatchPayload true "body"
+// @Success      200  {object}  model.TransferBatchModel
+// @Failure      400  {object}  echo.HTTPError
+// @Failure      401  {object}  echo.HTTPError
+// @Failure      403  {object}  echo.HTTPError
+// @Failure      429  {object}  echo.HTTPError
+// @Failure      500  {object}  echo.HTTPError
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Router       /transfers/batch [post]
+func (c *PlayerController) TransferBatch(ec echo.Context) error {
+	var payload service.TransferBatchPayload
+
+	if err := ec.Bind(&payload); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	res, err := command_service.Dispatch[model.TransferBatchModel](ec.Request().Context(), c.Bus, service.TransferBatchCommand{
+		Payload: payload,
+	})
+	if err != nil {
+		return httperror.FromError(ec.Request().Context(), err)
+	}
+
+	return ec.JSON(http.StatusOK, res)
+}
+
+// Swap godoc
+// @Summary      Swap players
+// @Description  swap two players of different teams, both transfers are applied or none of them and their events share a correlation id.
+// @Tags         Player
+// @Accept       json
+// @Produce      json
+// @param        Idempotency-Key header string false "key to safely retry the request"
+// @param        swap body service.SwapPayload true "body"
+// @Success      200  {object}  model.TransferBatchModel
+// @Failure      400  {object}  echo.HTTPError
+// @Failure      401  {object}  echo.HTTPError
+// @Failure      403  {object}  echo.HTTPError
+// @Failure      429  {object}  echo.HTTPError
+// @Failure      500  {object}  echo.HTTPError
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Router       /transfers/swap [post]
+func (c *PlayerController) Swap(ec echo.Context) error {
+	var payload service.SwapPayload
+
+	if err := ec.Bind(&payload); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	res, err := command_service.Dispatch[model.TransferBatchModel](ec.Request().Context(), c.Bus, service.SwapPlayersCommand{
+		Payload: payload,
+	})
+	if err != nil {
+		return httperror.FromError(ec.Request().Context(), err)
+	}
+
+	return ec.JSON(http.StatusOK, res)
+}
+
+// Patch godoc
+// @Summary      Patch player
+// @Description  partially update a player with a JSON Merge Patch or a JSON Patch, only the changed fields are written and a player_updated event lists them. Only the name can be changed, the team is changed by a transfer.
+// @Tags         Player
+// @Accept       application/merge-patch+json,application/json-patch+json
+// @Produce      json
+// @param        Idempotency-Key header string false "key to safely retry the request"
+// @param        id path int true "player id"
+// @param        patch body object true "merge patch object or array of patch operations"
+// @Success      200  {object}  model.PlayerModel
+// @Failure      400  {object}  echo.HTTPError
+// @Failure      401  {object}  echo.HTTPError
+// @Failure      403  {object}  echo.HTTPError
+// @Failure      404  {object}  echo.HTTPError
+// @Failure      415  {object}  echo.HTTPError
+// @Failure      429  {object}  echo.HTTPError
+// @Failure      500  {object}  echo.HTTPError
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Router       /player/{id} [patch]
+func (c *PlayerController) Patch(ec echo.Context) error {
+	id, err := strconv.ParseInt(ec.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	body, err := io.ReadAll(ec.Request().Body)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	p, err := patch.New(ec.Request().Header.Get(echo.HeaderContentType), body)
+	if errors.Is(err, patch.ErrUnsupportedType) {
+		return echo.NewHTTPError(http.StatusUnsupportedMediaType, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	res, err := command_service.Dispatch[model.PlayerModel](ec.Request().Context(), c.Bus, service.PatchPlayerCommand{
+		ID:    id,
+		Patch: p,
+	})
+	if err != nil {
+		return httperror.FromError(ec.Request().Context(), err)
+	}
+
+	return ec.JSON(http.StatusOK, res)
+}
@@ -2,36 +2,49 @@ package controller_test
 
 import (
 	"bytes"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
+	command_model "github.com/tesarwijaya/ouroboros/internal/domain/command/model"
+	command_service "github.com/tesarwijaya/ouroboros/internal/domain/command/service"
 	"github.com/tesarwijaya/ouroboros/internal/domain/player/model"
 	"github.com/tesarwijaya/ouroboros/internal/domain/player/service"
 	controller "github.com/tesarwijaya/ouroboros/internal/entry-point/rest/controller/player"
+	"github.com/tesarwijaya/ouroboros/internal/patch"
 )
 
 type ResolverFn func(svc *service.MockPlayerService)
 
-func createController(t *testing.T, resolver ResolverFn) (controller.PlayerController, gomock.Controller) {
+func createController(t *testing.T, resolver ResolverFn) (controller.PlayerController, *gomock.Controller) {
 	ctrl := gomock.NewController(t)
 
 	svc := service.NewMockPlayerService(ctrl)
 	resolver(svc)
 
+	bus, _ := command_service.NewCommandBus(command_service.CommandBusImpl{
+		Handlers:    service.NewCommandHandlers(svc).Handlers,
+		Middlewares: []command_model.Middleware{command_service.NewValidationMiddleware()},
+	})
+
 	return controller.PlayerController{
 		Service: svc,
-	}, *ctrl
+		Bus:     bus,
+	}, ctrl
 }
 
 func Test_FindAll(t *testing.T) {
 	testCases := []struct {
 		Name             string
+		Query            string
 		Resolver         ResolverFn
 		ExpectBody       string
 		ExpectStatusCode int64
@@ -53,13 +66,50 @@ func Test_FindAll(t *testing.T) {
 					Return([]model.PlayerModel{}, errors.New("some-error"))
 			},
 			ExpectStatusCode: 500,
-			ExpectErr:        echo.NewHTTPError(http.StatusInternalServerError, "some-error"),
+			ExpectErr:        echo.NewHTTPError(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError)),
+		},
+		{
+			Name:  "when_ids_given",
+			Query: "?ids=1,2,1&fields=name",
+			Resolver: func(svc *service.MockPlayerService) {
+				svc.EXPECT().FindFields(gomock.Any(), []int64{1, 2}, []string{"name"}).
+					Return([]model.PlayerModel{{ID: 1, Name: "some-player-name", TeamID: 3}}, []int64{2}, nil)
+			},
+			ExpectStatusCode: 200,
+			ExpectBody:       "{\"items\":[{\"name\":\"some-player-name\"}],\"missing\":[2]}\n",
+		},
+		{
+			Name:  "when_fields_given",
+			Query: "?fields=id,teamId",
+			Resolver: func(svc *service.MockPlayerService) {
+				svc.EXPECT().FindFields(gomock.Any(), nil, []string{"id", "teamId"}).
+					Return([]model.PlayerModel{{ID: 1, TeamID: 3}}, []int64{}, nil)
+			},
+			ExpectStatusCode: 200,
+			ExpectBody:       "[{\"id\":1,\"teamId\":3}]\n",
+		},
+		{
+			Name:             "when_ids_invalid",
+			Query:            "?ids=1,x",
+			Resolver:         func(svc *service.MockPlayerService) {},
+			ExpectStatusCode: 400,
+			ExpectErr:        echo.NewHTTPError(http.StatusBadRequest, "invalid id \"x\""),
+		},
+		{
+			Name:  "when_field_unknown",
+			Query: "?fields=age",
+			Resolver: func(svc *service.MockPlayerService) {
+				svc.EXPECT().FindFields(gomock.Any(), nil, []string{"age"}).
+					Return([]model.PlayerModel{}, []int64{}, command_model.ValidationError{Err: errors.New("unknown field \"age\"")})
+			},
+			ExpectStatusCode: 400,
+			ExpectErr:        echo.NewHTTPError(http.StatusBadRequest, "unknown field \"age\""),
 		},
 	}
 
 	for _, test := range testCases {
 		e := echo.New()
-		req := httptest.NewRequest(http.MethodGet, "/player", nil)
+		req := httptest.NewRequest(http.MethodGet, "/player"+test.Query, nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
 		c := e.NewContext(req, rec)
@@ -96,6 +146,16 @@ func Test_FindByID(t *testing.T) {
 			ExpectStatusCode: 200,
 			ExpectBody:       "{\"id\":0,\"name\":\"some-player-name\"}\n",
 		},
+		{
+			Name:        "when_not_found",
+			QueryString: "1",
+			Resolver: func(svc *service.MockPlayerService) {
+				svc.EXPECT().FindByID(gomock.Any(), int64(1)).
+					Return(model.PlayerModel{}, sql.ErrNoRows)
+			},
+			ExpectStatusCode: 404,
+			ExpectErr:        echo.NewHTTPError(http.StatusNotFound, http.StatusText(http.StatusNotFound)),
+		},
 	}
 
 	for _, test := range testCases {
@@ -127,17 +187,26 @@ func Test_Insert(t *testing.T) {
 		Resolver         ResolverFn
 		ExpectBody       string
 		ExpectStatusCode int64
+		ExpectLocation   string
 		ExpectErr        error
 	}{
 		{
 			Name: "when_success",
-			Body: model.PlayerModel{Name: "some-player-name"},
+			Body: model.PlayerModel{Name: "some-player-name", TeamID: 1},
 			Resolver: func(svc *service.MockPlayerService) {
-				svc.EXPECT().Insert(gomock.Any(), model.PlayerModel{Name: "some-player-name"}).
-					Return(model.PlayerModel{Name: "some-player-name"}, nil)
+				svc.EXPECT().Insert(gomock.Any(), model.PlayerModel{Name: "some-player-name", TeamID: 1}).
+					Return(model.PlayerModel{ID: 1, Name: "some-player-name", TeamID: 1}, nil)
 			},
-			ExpectStatusCode: 200,
-			ExpectBody:       "{\"id\":0,\"name\":\"some-player-name\"}\n",
+			ExpectStatusCode: 201,
+			ExpectLocation:   "/player/1",
+			ExpectBody:       "{\"id\":1,\"name\":\"some-player-name\",\"teamId\":1}\n",
+		},
+		{
+			Name:             "when_invalid",
+			Body:             model.PlayerModel{Name: "some-player-name"},
+			Resolver:         func(svc *service.MockPlayerService) {},
+			ExpectStatusCode: 400,
+			ExpectErr:        echo.NewHTTPError(http.StatusBadRequest, "teamId is required"),
 		},
 	}
 
@@ -157,9 +226,235 @@ func Test_Insert(t *testing.T) {
 		err := controller.Insert(c)
 		if test.ExpectErr == nil {
 			assert.Equal(t, test.ExpectBody, rec.Body.String())
-			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, http.StatusCreated, rec.Code)
+			assert.Equal(t, test.ExpectLocation, rec.Header().Get(echo.HeaderLocation))
 		} else {
 			assert.Equal(t, test.ExpectErr, err)
 		}
 	}
 }
+
+func Test_Patch(t *testing.T) {
+	testCases := []struct {
+		Name             string
+		ID               string
+		ContentType      string
+		Body             string
+		Resolver         ResolverFn
+		ExpectBody       string
+		ExpectStatusCode int64
+		ExpectErr        error
+	}{
+		{
+			Name:        "when_merge_patch",
+			ID:          "1",
+			ContentType: patch.MIME_MERGE_PATCH,
+			Body:        `{"name": "jane"}`,
+			Resolver: func(svc *service.MockPlayerService) {
+				svc.EXPECT().Patch(gomock.Any(), int64(1), patch.Patch{Type: patch.MIME_MERGE_PATCH, Body: []byte(`{"name": "jane"}`)}).
+					Return(model.PlayerModel{ID: 1, Name: "jane", TeamID: 2}, nil)
+			},
+			ExpectStatusCode: 200,
+			ExpectBody:       "{\"id\":1,\"name\":\"jane\",\"teamId\":2}\n",
+		},
+		{
+			Name:        "when_json_patch",
+			ID:          "1",
+			ContentType: patch.MIME_JSON_PATCH,
+			Body:        `[{"op": "replace", "path": "/name", "value": "jane"}]`,
+			Resolver: func(svc *service.MockPlayerService) {
+				svc.EXPECT().Patch(gomock.Any(), int64(1), patch.Patch{Type: patch.MIME_JSON_PATCH, Body: []byte(`[{"op": "replace", "path": "/name", "value": "jane"}]`)}).
+					Return(model.PlayerModel{ID: 1, Name: "jane", TeamID: 2}, nil)
+			},
+			ExpectStatusCode: 200,
+			ExpectBody:       "{\"id\":1,\"name\":\"jane\",\"teamId\":2}\n",
+		},
+		{
+			Name:             "when_content_type_is_unsupported",
+			ID:               "1",
+			ContentType:      echo.MIMEApplicationJSON,
+			Body:             `{"name": "jane"}`,
+			Resolver:         func(svc *service.MockPlayerService) {},
+			ExpectStatusCode: 415,
+			ExpectErr:        echo.NewHTTPError(http.StatusUnsupportedMediaType, patch.ErrUnsupportedType.Error()),
+		},
+		{
+			Name:             "when_body_is_empty",
+			ID:               "1",
+			ContentType:      patch.MIME_MERGE_PATCH,
+			Resolver:         func(svc *service.MockPlayerService) {},
+			ExpectStatusCode: 400,
+			ExpectErr:        echo.NewHTTPError(http.StatusBadRequest, "patch is required"),
+		},
+		{
+			Name:             "when_id_is_invalid",
+			ID:               "x",
+			ContentType:      patch.MIME_MERGE_PATCH,
+			Body:             `{"name": "jane"}`,
+			Resolver:         func(svc *service.MockPlayerService) {},
+			ExpectStatusCode: 400,
+			ExpectErr:        echo.NewHTTPError(http.StatusBadRequest, "strconv.ParseInt: parsing \"x\": invalid syntax"),
+		},
+		{
+			Name:        "when_patch_is_invalid",
+			ID:          "1",
+			ContentType: patch.MIME_MERGE_PATCH,
+			Body:        `{"id": 2}`,
+			Resolver: func(svc *service.MockPlayerService) {
+				svc.EXPECT().Patch(gomock.Any(), int64(1), gomock.Any()).
+					Return(model.PlayerModel{}, command_model.ValidationError{Err: errors.New("id can't be changed")})
+			},
+			ExpectStatusCode: 400,
+			ExpectErr:        echo.NewHTTPError(http.StatusBadRequest, "id can't be changed"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPatch, "/player/"+test.ID, strings.NewReader(test.Body))
+			req.Header.Set(echo.HeaderContentType, test.ContentType)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues(test.ID)
+
+			controller, mock := createController(t, test.Resolver)
+			defer mock.Finish()
+
+			err := controller.Patch(c)
+			if test.ExpectErr == nil {
+				assert.Nil(t, err)
+				assert.Equal(t, test.ExpectBody, rec.Body.String())
+				assert.Equal(t, http.StatusOK, rec.Code)
+			} else {
+				assert.Equal(t, test.ExpectErr, err)
+			}
+		})
+	}
+}
+
+func Test_TransferBatch(t *testing.T) {
+	transferredAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	testCases := []struct {
+		Name       string
+		Body       string
+		Resolver   ResolverFn
+		ExpectBody string
+		ExpectErr  error
+	}{
+		{
+			Name: "when_success",
+			Body: `{"transfers": [{"playerId": 1, "teamId": 3}, {"playerId": 2, "teamId": 3}]}`,
+			Resolver: func(svc *service.MockPlayerService) {
+				svc.EXPECT().TransferBatch(gomock.Any(), []service.TransferPayload{{PlayerID: 1, TeamID: 3}, {PlayerID: 2, TeamID: 3}}).
+					Return(model.TransferBatchModel{
+						CorrelationID: "some-id",
+						Transfers: []model.TransferModel{
+							{PlayerID: 1, FromTeamID: 2, ToTeamID: 3, TransferredAt: transferredAt, CorrelationID: "some-id"},
+							{PlayerID: 2, FromTeamID: 4, ToTeamID: 3, TransferredAt: transferredAt, CorrelationID: "some-id"},
+						},
+					}, nil)
+			},
+			ExpectBody: "{\"correlationId\":\"some-id\",\"transfers\":[{\"playerId\":1,\"fromTeamId\":2,\"toTeamId\":3,\"transferredAt\":\"2024-01-02T03:04:05Z\",\"correlationId\":\"some-id\"},{\"playerId\":2,\"fromTeamId\":4,\"toTeamId\":3,\"transferredAt\":\"2024-01-02T03:04:05Z\",\"correlationId\":\"some-id\"}]}\n",
+		},
+		{
+			Name:      "when_empty",
+			Body:      `{"transfers": []}`,
+			Resolver:  func(svc *service.MockPlayerService) {},
+			ExpectErr: echo.NewHTTPError(http.StatusBadRequest, "transfers are required"),
+		},
+		{
+			Name:      "when_transfer_invalid",
+			Body:      `{"transfers": [{"playerId": 1, "teamId": 3}, {"playerId": 2}]}`,
+			Resolver:  func(svc *service.MockPlayerService) {},
+			ExpectErr: echo.NewHTTPError(http.StatusBadRequest, "transfers[1]: teamID is required"),
+		},
+		{
+			Name: "when_not_success",
+			Body: `{"transfers": [{"playerId": 1, "teamId": 3}]}`,
+			Resolver: func(svc *service.MockPlayerService) {
+				svc.EXPECT().TransferBatch(gomock.Any(), gomock.Any()).
+					Return(model.TransferBatchModel{}, command_model.ValidationError{Err: errors.New("team 3 not found")})
+			},
+			ExpectErr: echo.NewHTTPError(http.StatusBadRequest, "team 3 not found"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/transfers/batch", strings.NewReader(test.Body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			controller, mock := createController(t, test.Resolver)
+			defer mock.Finish()
+
+			err := controller.TransferBatch(c)
+			if test.ExpectErr == nil {
+				assert.Nil(t, err)
+				assert.Equal(t, test.ExpectBody, rec.Body.String())
+				assert.Equal(t, http.StatusOK, rec.Code)
+			} else {
+				assert.Equal(t, test.ExpectErr, err)
+			}
+		})
+	}
+}
+
+func Test_Swap(t *testing.T) {
+	testCases := []struct {
+		Name       string
+		Body       string
+		Resolver   ResolverFn
+		ExpectBody string
+		ExpectErr  error
+	}{
+		{
+			Name: "when_success",
+			Body: `{"playerId": 1, "otherPlayerId": 2}`,
+			Resolver: func(svc *service.MockPlayerService) {
+				svc.EXPECT().Swap(gomock.Any(), service.SwapPayload{PlayerID: 1, OtherPlayerID: 2}).
+					Return(model.TransferBatchModel{CorrelationID: "some-id", Transfers: []model.TransferModel{}}, nil)
+			},
+			ExpectBody: "{\"correlationId\":\"some-id\",\"transfers\":[]}\n",
+		},
+		{
+			Name:      "when_same_player",
+			Body:      `{"playerId": 1, "otherPlayerId": 1}`,
+			Resolver:  func(svc *service.MockPlayerService) {},
+			ExpectErr: echo.NewHTTPError(http.StatusBadRequest, "a player can't be swapped with itself"),
+		},
+		{
+			Name:      "when_other_player_missing",
+			Body:      `{"playerId": 1}`,
+			Resolver:  func(svc *service.MockPlayerService) {},
+			ExpectErr: echo.NewHTTPError(http.StatusBadRequest, "otherPlayerId is required"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/transfers/swap", strings.NewReader(test.Body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			controller, mock := createController(t, test.Resolver)
+			defer mock.Finish()
+
+			err := controller.Swap(c)
+			if test.ExpectErr == nil {
+				assert.Nil(t, err)
+				assert.Equal(t, test.ExpectBody, rec.Body.String())
+				assert.Equal(t, http.StatusOK, rec.Code)
+			} else {
+				assert.Equal(t, test.ExpectErr, err)
+			}
+		})
+	}
+}
@@ -1,21 +1,33 @@
 package controller
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 
 	"github.com/labstack/echo/v4"
+	command_service "github.com/tesarwijaya/ouroboros/internal/domain/command/service"
 	"github.com/tesarwijaya/ouroboros/internal/domain/team/model"
 	"github.com/tesarwijaya/ouroboros/internal/domain/team/service"
+	"github.com/tesarwijaya/ouroboros/internal/entry-point/rest/httperror"
+	"github.com/tesarwijaya/ouroboros/internal/entry-point/rest/query"
+	"github.com/tesarwijaya/ouroboros/internal/patch"
 )
 
+// INCLUDE_PLAYERS responds the players of each team along it.
+const INCLUDE_PLAYERS = "players"
+
 type TeamController struct {
 	Service service.TeamService
+	Bus     command_service.CommandBus
 }
 
-func NewTeamController(service service.TeamService) TeamController {
+func NewTeamController(service service.TeamService, bus command_service.CommandBus) TeamController {
 	return TeamController{
 		Service: service,
+		Bus:     bus,
 	}
 }
 
@@ -24,26 +36,91 @@ func (c *TeamController) SetRouter(ec *echo.Echo) {
 	ec.GET("/team/:id", c.FindByID)
 	ec.GET("/team/:id/player", c.FindTeamPlayer)
 	ec.POST("/team", c.Insert)
+	ec.PATCH("/team/:id", c.Patch)
 }
 
 // FindAll godoc
 // @Summary      Show all team
-// @Description  get all team
+// @Description  get all team, or the teams with the given ids in a single query along the ids no team has. fields only selects and responds the given fields. include=players responds every team with its players keyed players, or the teams with the given ids along the missing ids, the players are read in a single query for all the teams.
 // @Tags         Team
 // @Accept       json
 // @Produce      json
-// @Success      200  {object}  []model.TeamModel
+// @param        ids query string false "comma separated team ids, at most 100, the response then lists the found items and the missing ids"
+// @param        fields query string false "comma separated fields to respond, e.g. id,name"
+// @param        include query string false "related resources to respond along each team as []model.TeamWithPlayersRespModel, or model.TeamPlayerLookupRespModel with ids, can't be combined with fields" Enums(players)
+// @Success      200  {object}  []model.TeamModel "[]model.TeamModel, model.TeamLookupRespModel with ids, []model.TeamWithPlayersRespModel with include=players, model.TeamPlayerLookupRespModel with ids and include=players"
 // @Failure      400  {object}  echo.HTTPError
+// @Failure      401  {object}  echo.HTTPError
+// @Failure      403  {object}  echo.HTTPError
 // @Failure      404  {object}  echo.HTTPError
+// @Failure      429  {object}  echo.HTTPError
 // @Failure      500  {object}  echo.HTTPError
+// @Security     BearerAuth
+// @Security     APIKeyAuth
 // @Router       /team [get]
 func (c *TeamController) FindAll(ec echo.Context) error {
-	res, err := c.Service.FindAll(ec.Request().Context())
+	ids, err := query.IDs(ec.QueryParam("ids"))
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	return ec.JSON(http.StatusOK, res)
+	fields := query.Fields(ec.QueryParam("fields"))
+
+	switch include := ec.QueryParam("include"); include {
+	case "":
+	case INCLUDE_PLAYERS:
+		if fields != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "include can't be combined with fields")
+		}
+
+		return c.findAllWithPlayers(ec, ids)
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("unknown include %q", include))
+	}
+
+	if ids == nil && fields == nil {
+		res, err := c.Service.FindAll(ec.Request().Context())
+		if err != nil {
+			return httperror.FromError(ec.Request().Context(), err)
+		}
+
+		return ec.JSON(http.StatusOK, res)
+	}
+
+	teams, missing, err := c.Service.FindFields(ec.Request().Context(), ids, fields)
+	if err != nil {
+		return httperror.FromError(ec.Request().Context(), err)
+	}
+
+	items := make([]map[string]interface{}, 0, len(teams))
+	for _, team := range teams {
+		items = append(items, team.Sparse(fields))
+	}
+
+	if ids == nil {
+		return ec.JSON(http.StatusOK, items)
+	}
+
+	return ec.JSON(http.StatusOK, model.TeamLookupRespModel{
+		Items:   items,
+		Missing: missing,
+	})
+}
+
+func (c *TeamController) findAllWithPlayers(ec echo.Context, ids []int64) error {
+	teams, missing, err := c.Service.FindTeamPlayers(ec.Request().Context(), ids)
+	if err != nil {
+		return httperror.FromError(ec.Request().Context(), err)
+	}
+
+	if ids == nil {
+		return ec.JSON(http.StatusOK, teams)
+	}
+
+	return ec.JSON(http.StatusOK, model.TeamPlayerLookupRespModel{
+		Items:   teams,
+		Missing: missing,
+	})
 }
 
 // FindByID godoc
@@ -55,8 +132,13 @@ func (c *TeamController) FindAll(ec echo.Context) error {
 // @param        id path int true "team id"
 // @Success      200  {object}  model.TeamModel
 // @Failure      400  {object}  echo.HTTPError
+// @Failure      401  {object}  echo.HTTPError
+// @Failure      403  {object}  echo.HTTPError
 // @Failure      404  {object}  echo.HTTPError
+// @Failure      429  {object}  echo.HTTPError
 // @Failure      500  {object}  echo.HTTPError
+// @Security     BearerAuth
+// @Security     APIKeyAuth
 // @Router       /team/{id} [get]
 func (c *TeamController) FindByID(ec echo.Context) error {
 	idParam := ec.Param("id")
@@ -67,7 +149,7 @@ func (c *TeamController) FindByID(ec echo.Context) error {
 
 	res, err := c.Service.FindByID(ec.Request().Context(), id)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return httperror.FromError(ec.Request().Context(), err)
 	}
 
 	return ec.JSON(http.StatusOK, res)
@@ -79,11 +161,17 @@ func (c *TeamController) FindByID(ec echo.Context) error {
 // @Tags         Team
 // @Accept       json
 // @Produce      json
+// @param        Idempotency-Key header string false "key to safely retry the request"
 // @param        id body model.TeamModel true "body"
-// @Success      200  {object}  model.TeamModel
+// @Success      201  {object}  model.TeamModel
 // @Failure      400  {object}  echo.HTTPError
+// @Failure      401  {object}  echo.HTTPError
+// @Failure      403  {object}  echo.HTTPError
 // @Failure      404  {object}  echo.HTTPError
+// @Failure      429  {object}  echo.HTTPError
 // @Failure      500  {object}  echo.HTTPError
+// @Security     BearerAuth
+// @Security     APIKeyAuth
 // @Router       /team [post]
 func (c *TeamController) Insert(ec echo.Context) error {
 	var payload model.TeamModel
@@ -92,12 +180,16 @@ func (c *TeamController) Insert(ec echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	res, err := c.Service.Insert(ec.Request().Context(), payload)
+	res, err := command_service.Dispatch[model.TeamModel](ec.Request().Context(), c.Bus, service.InsertTeamCommand{
+		Payload: payload,
+	})
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return httperror.FromError(ec.Request().Context(), err)
 	}
 
-	return ec.JSON(http.StatusOK, res)
+	ec.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("/team/%d", res.ID))
+
+	return ec.JSON(http.StatusCreated, res)
 }
 
 func (c *TeamController) FindTeamPlayer(ec echo.Context) error {
@@ -109,7 +201,57 @@ func (c *TeamController) FindTeamPlayer(ec echo.Context) error {
 
 	res, err := c.Service.FindTeamPlayer(ec.Request().Context(), id)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return httperror.FromError(ec.Request().Context(), err)
+	}
+
+	return ec.JSON(http.StatusOK, res)
+}
+
+// Patch godoc
+// @Summary      Patch team
+// @Description  partially update a team with a JSON Merge Patch or a JSON Patch, only the changed fields are written and a team_updated event lists them. Only the name can be changed.
+// @Tags         Team
+// @Accept       application/merge-patch+json,application/json-patch+json
+// @Produce      json
+// @param        Idempotency-Key header string false "key to safely retry the request"
+// @param        id path int true "team id"
+// @param        patch body object true "merge patch object or array of patch operations"
+// @Success      200  {object}  model.TeamModel
+// @Failure      400  {object}  echo.HTTPError
+// @Failure      401  {object}  echo.HTTPError
+// @Failure      403  {object}  echo.HTTPError
+// @Failure      404  {object}  echo.HTTPError
+// @Failure      415  {object}  echo.HTTPError
+// @Failure      429  {object}  echo.HTTPError
+// @Failure      500  {object}  echo.HTTPError
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Router       /team/{id} [patch]
+func (c *TeamController) Patch(ec echo.Context) error {
+	id, err := strconv.ParseInt(ec.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	body, err := io.ReadAll(ec.Request().Body)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	p, err := patch.New(ec.Request().Header.Get(echo.HeaderContentType), body)
+	if errors.Is(err, patch.ErrUnsupportedType) {
+		return echo.NewHTTPError(http.StatusUnsupportedMediaType, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	res, err := command_service.Dispatch[model.TeamModel](ec.Request().Context(), c.Bus, service.PatchTeamCommand{
+		ID:    id,
+		Patch: p,
+	})
+	if err != nil {
+		return httperror.FromError(ec.Request().Context(), err)
 	}
 
 	return ec.JSON(http.StatusOK, res)
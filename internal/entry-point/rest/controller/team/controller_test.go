@@ -2,36 +2,49 @@ package controller_test
 
 import (
 	"bytes"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/golang/mock/gomock"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
+	command_model "github.com/tesarwijaya/ouroboros/internal/domain/command/model"
+	command_service "github.com/tesarwijaya/ouroboros/internal/domain/command/service"
+	player_model "github.com/tesarwijaya/ouroboros/internal/domain/player/model"
 	"github.com/tesarwijaya/ouroboros/internal/domain/team/model"
 	"github.com/tesarwijaya/ouroboros/internal/domain/team/service"
 	controller "github.com/tesarwijaya/ouroboros/internal/entry-point/rest/controller/team"
+	"github.com/tesarwijaya/ouroboros/internal/patch"
 )
 
 type ResolverFn func(svc *service.MockTeamService)
 
-func createController(t *testing.T, resolver ResolverFn) (controller.TeamController, gomock.Controller) {
+func createController(t *testing.T, resolver ResolverFn) (controller.TeamController, *gomock.Controller) {
 	ctrl := gomock.NewController(t)
 
 	svc := service.NewMockTeamService(ctrl)
 	resolver(svc)
 
+	bus, _ := command_service.NewCommandBus(command_service.CommandBusImpl{
+		Handlers:    service.NewCommandHandlers(svc).Handlers,
+		Middlewares: []command_model.Middleware{command_service.NewValidationMiddleware()},
+	})
+
 	return controller.TeamController{
 		Service: svc,
-	}, *ctrl
+		Bus:     bus,
+	}, ctrl
 }
 
 func Test_FindAll(t *testing.T) {
 	testCases := []struct {
 		Name             string
+		Query            string
 		Resolver         ResolverFn
 		ExpectBody       string
 		ExpectStatusCode int64
@@ -53,13 +66,95 @@ func Test_FindAll(t *testing.T) {
 					Return([]model.TeamModel{}, errors.New("some-error"))
 			},
 			ExpectStatusCode: 500,
-			ExpectErr:        echo.NewHTTPError(http.StatusInternalServerError, "some-error"),
+			ExpectErr:        echo.NewHTTPError(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError)),
+		},
+		{
+			Name:  "when_ids_given",
+			Query: "?ids=1,2",
+			Resolver: func(svc *service.MockTeamService) {
+				svc.EXPECT().FindFields(gomock.Any(), []int64{1, 2}, nil).
+					Return([]model.TeamModel{{ID: 1, Name: "some-team-name"}}, []int64{2}, nil)
+			},
+			ExpectStatusCode: 200,
+			ExpectBody:       "{\"items\":[{\"id\":1,\"name\":\"some-team-name\"}],\"missing\":[2]}\n",
+		},
+		{
+			Name:  "when_fields_given",
+			Query: "?fields=name",
+			Resolver: func(svc *service.MockTeamService) {
+				svc.EXPECT().FindFields(gomock.Any(), nil, []string{"name"}).
+					Return([]model.TeamModel{{ID: 1, Name: "some-team-name"}}, []int64{}, nil)
+			},
+			ExpectStatusCode: 200,
+			ExpectBody:       "[{\"name\":\"some-team-name\"}]\n",
+		},
+		{
+			Name:             "when_ids_invalid",
+			Query:            "?ids=0",
+			Resolver:         func(svc *service.MockTeamService) {},
+			ExpectStatusCode: 400,
+			ExpectErr:        echo.NewHTTPError(http.StatusBadRequest, "invalid id \"0\""),
+		},
+		{
+			Name:  "when_include_players",
+			Query: "?include=players",
+			Resolver: func(svc *service.MockTeamService) {
+				svc.EXPECT().FindTeamPlayers(gomock.Any(), nil).
+					Return([]model.TeamWithPlayersRespModel{
+						{
+							TeamModel: model.TeamModel{ID: 1, Name: "owls"},
+							Players:   []player_model.PlayerModel{{ID: 10, Name: "jane", TeamID: 1}},
+						},
+						{
+							TeamModel: model.TeamModel{ID: 2, Name: "larks"},
+							Players:   []player_model.PlayerModel{},
+						},
+					}, []int64{}, nil)
+			},
+			ExpectStatusCode: 200,
+			ExpectBody:       "[{\"id\":1,\"name\":\"owls\",\"players\":[{\"id\":10,\"name\":\"jane\",\"teamId\":1}]},{\"id\":2,\"name\":\"larks\",\"players\":[]}]\n",
+		},
+		{
+			Name:  "when_include_players_with_ids",
+			Query: "?ids=2,3&include=players",
+			Resolver: func(svc *service.MockTeamService) {
+				svc.EXPECT().FindTeamPlayers(gomock.Any(), []int64{2, 3}).
+					Return([]model.TeamWithPlayersRespModel{
+						{
+							TeamModel: model.TeamModel{ID: 2, Name: "larks"},
+							Players:   []player_model.PlayerModel{},
+						},
+					}, []int64{3}, nil)
+			},
+			ExpectStatusCode: 200,
+			ExpectBody:       "{\"items\":[{\"id\":2,\"name\":\"larks\",\"players\":[]}],\"missing\":[3]}\n",
+		},
+		{
+			Name:  "when_include_players_not_success",
+			Query: "?include=players",
+			Resolver: func(svc *service.MockTeamService) {
+				svc.EXPECT().FindTeamPlayers(gomock.Any(), nil).
+					Return(nil, nil, errors.New("some-error"))
+			},
+			ExpectErr: echo.NewHTTPError(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError)),
+		},
+		{
+			Name:      "when_include_unknown",
+			Query:     "?include=coaches",
+			Resolver:  func(svc *service.MockTeamService) {},
+			ExpectErr: echo.NewHTTPError(http.StatusBadRequest, "unknown include \"coaches\""),
+		},
+		{
+			Name:      "when_include_with_fields",
+			Query:     "?include=players&fields=name",
+			Resolver:  func(svc *service.MockTeamService) {},
+			ExpectErr: echo.NewHTTPError(http.StatusBadRequest, "include can't be combined with fields"),
 		},
 	}
 
 	for _, test := range testCases {
 		e := echo.New()
-		req := httptest.NewRequest(http.MethodGet, "/team", nil)
+		req := httptest.NewRequest(http.MethodGet, "/team"+test.Query, nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
 		c := e.NewContext(req, rec)
@@ -120,6 +215,59 @@ func Test_FindByID(t *testing.T) {
 	}
 }
 
+func Test_FindTeamPlayer(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		QueryString string
+		Resolver    ResolverFn
+		ExpectBody  string
+		ExpectErr   error
+	}{
+		{
+			Name:        "when_success",
+			QueryString: "1",
+			Resolver: func(svc *service.MockTeamService) {
+				svc.EXPECT().FindTeamPlayer(gomock.Any(), int64(1)).
+					Return(model.TeamPlayerRespModel{
+						TeamModel: model.TeamModel{ID: 1, Name: "some-team-name"},
+						Players:   []player_model.PlayerModel{{ID: 10, Name: "jane", TeamID: 1}},
+					}, nil)
+			},
+			ExpectBody: "{\"id\":1,\"name\":\"some-team-name\",\"Players\":[{\"id\":10,\"name\":\"jane\",\"teamId\":1}]}\n",
+		},
+		{
+			Name:        "when_not_found",
+			QueryString: "1",
+			Resolver: func(svc *service.MockTeamService) {
+				svc.EXPECT().FindTeamPlayer(gomock.Any(), int64(1)).
+					Return(model.TeamPlayerRespModel{}, sql.ErrNoRows)
+			},
+			ExpectErr: echo.NewHTTPError(http.StatusNotFound, http.StatusText(http.StatusNotFound)),
+		},
+	}
+
+	for _, test := range testCases {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/team/:id/player", nil)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(test.QueryString)
+
+		controller, mock := createController(t, test.Resolver)
+		defer mock.Finish()
+
+		err := controller.FindTeamPlayer(c)
+		if test.ExpectErr == nil {
+			assert.Equal(t, test.ExpectBody, rec.Body.String())
+			assert.Equal(t, http.StatusOK, rec.Code)
+		} else {
+			assert.Equal(t, test.ExpectErr, err)
+		}
+	}
+}
+
 func Test_Insert(t *testing.T) {
 	testCases := []struct {
 		Name             string
@@ -127,6 +275,7 @@ func Test_Insert(t *testing.T) {
 		Resolver         ResolverFn
 		ExpectBody       string
 		ExpectStatusCode int64
+		ExpectLocation   string
 		ExpectErr        error
 	}{
 		{
@@ -134,10 +283,18 @@ func Test_Insert(t *testing.T) {
 			Body: model.TeamModel{Name: "some-team-name"},
 			Resolver: func(svc *service.MockTeamService) {
 				svc.EXPECT().Insert(gomock.Any(), model.TeamModel{Name: "some-team-name"}).
-					Return(model.TeamModel{Name: "some-team-name"}, nil)
+					Return(model.TeamModel{ID: 1, Name: "some-team-name"}, nil)
 			},
-			ExpectStatusCode: 200,
-			ExpectBody:       "{\"name\":\"some-team-name\"}\n",
+			ExpectStatusCode: 201,
+			ExpectLocation:   "/team/1",
+			ExpectBody:       "{\"id\":1,\"name\":\"some-team-name\"}\n",
+		},
+		{
+			Name:             "when_invalid",
+			Body:             model.TeamModel{},
+			Resolver:         func(svc *service.MockTeamService) {},
+			ExpectStatusCode: 400,
+			ExpectErr:        echo.NewHTTPError(http.StatusBadRequest, "name is required"),
 		},
 	}
 
@@ -157,9 +314,110 @@ func Test_Insert(t *testing.T) {
 		err := controller.Insert(c)
 		if test.ExpectErr == nil {
 			assert.Equal(t, test.ExpectBody, rec.Body.String())
-			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, http.StatusCreated, rec.Code)
+			assert.Equal(t, test.ExpectLocation, rec.Header().Get(echo.HeaderLocation))
 		} else {
 			assert.Equal(t, test.ExpectErr, err)
 		}
 	}
 }
+
+func Test_Patch(t *testing.T) {
+	testCases := []struct {
+		Name             string
+		ID               string
+		ContentType      string
+		Body             string
+		Resolver         ResolverFn
+		ExpectBody       string
+		ExpectStatusCode int64
+		ExpectErr        error
+	}{
+		{
+			Name:        "when_merge_patch",
+			ID:          "1",
+			ContentType: patch.MIME_MERGE_PATCH,
+			Body:        `{"name": "larks"}`,
+			Resolver: func(svc *service.MockTeamService) {
+				svc.EXPECT().Patch(gomock.Any(), int64(1), patch.Patch{Type: patch.MIME_MERGE_PATCH, Body: []byte(`{"name": "larks"}`)}).
+					Return(model.TeamModel{ID: 1, Name: "larks"}, nil)
+			},
+			ExpectStatusCode: 200,
+			ExpectBody:       "{\"id\":1,\"name\":\"larks\"}\n",
+		},
+		{
+			Name:        "when_json_patch",
+			ID:          "1",
+			ContentType: patch.MIME_JSON_PATCH,
+			Body:        `[{"op": "replace", "path": "/name", "value": "larks"}]`,
+			Resolver: func(svc *service.MockTeamService) {
+				svc.EXPECT().Patch(gomock.Any(), int64(1), patch.Patch{Type: patch.MIME_JSON_PATCH, Body: []byte(`[{"op": "replace", "path": "/name", "value": "larks"}]`)}).
+					Return(model.TeamModel{ID: 1, Name: "larks"}, nil)
+			},
+			ExpectStatusCode: 200,
+			ExpectBody:       "{\"id\":1,\"name\":\"larks\"}\n",
+		},
+		{
+			Name:             "when_content_type_is_unsupported",
+			ID:               "1",
+			ContentType:      echo.MIMEApplicationJSON,
+			Body:             `{"name": "larks"}`,
+			Resolver:         func(svc *service.MockTeamService) {},
+			ExpectStatusCode: 415,
+			ExpectErr:        echo.NewHTTPError(http.StatusUnsupportedMediaType, patch.ErrUnsupportedType.Error()),
+		},
+		{
+			Name:             "when_body_is_empty",
+			ID:               "1",
+			ContentType:      patch.MIME_MERGE_PATCH,
+			Resolver:         func(svc *service.MockTeamService) {},
+			ExpectStatusCode: 400,
+			ExpectErr:        echo.NewHTTPError(http.StatusBadRequest, "patch is required"),
+		},
+		{
+			Name:             "when_id_is_invalid",
+			ID:               "x",
+			ContentType:      patch.MIME_MERGE_PATCH,
+			Body:             `{"name": "larks"}`,
+			Resolver:         func(svc *service.MockTeamService) {},
+			ExpectStatusCode: 400,
+			ExpectErr:        echo.NewHTTPError(http.StatusBadRequest, "strconv.ParseInt: parsing \"x\": invalid syntax"),
+		},
+		{
+			Name:        "when_patch_is_invalid",
+			ID:          "1",
+			ContentType: patch.MIME_MERGE_PATCH,
+			Body:        `{"id": 2}`,
+			Resolver: func(svc *service.MockTeamService) {
+				svc.EXPECT().Patch(gomock.Any(), int64(1), gomock.Any()).
+					Return(model.TeamModel{}, command_model.ValidationError{Err: errors.New("id can't be changed")})
+			},
+			ExpectStatusCode: 400,
+			ExpectErr:        echo.NewHTTPError(http.StatusBadRequest, "id can't be changed"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPatch, "/team/"+test.ID, strings.NewReader(test.Body))
+			req.Header.Set(echo.HeaderContentType, test.ContentType)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues(test.ID)
+
+			controller, mock := createController(t, test.Resolver)
+			defer mock.Finish()
+
+			err := controller.Patch(c)
+			if test.ExpectErr == nil {
+				assert.Nil(t, err)
+				assert.Equal(t, test.ExpectBody, rec.Body.String())
+				assert.Equal(t, http.StatusOK, rec.Code)
+			} else {
+				assert.Equal(t, test.ExpectErr, err)
+			}
+		})
+	}
+}
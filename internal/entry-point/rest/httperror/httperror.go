@@ -0,0 +1,47 @@
+package httperror
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	apikey_model "github.com/tesarwijaya/ouroboros/internal/domain/apikey/model"
+	auth_model "github.com/tesarwijaya/ouroboros/internal/domain/auth/model"
+	command_model "github.com/tesarwijaya/ouroboros/internal/domain/command/model"
+	offer_model "github.com/tesarwijaya/ouroboros/internal/domain/offer/model"
+	"github.com/tesarwijaya/ouroboros/internal/logger"
+	"go.uber.org/zap"
+)
+
+// FromError maps a domain error to the matching HTTP error, anything unknown
+// is reported as an internal server error. The cause of an internal error is
+// logged but never sent to the client.
+func FromError(ctx context.Context, err error) *echo.HTTPError {
+	var validationErr command_model.ValidationError
+	if errors.As(err, &validationErr) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	var forbiddenErr auth_model.ForbiddenError
+	if errors.As(err, &forbiddenErr) {
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	}
+
+	if errors.Is(err, auth_model.ErrMissingToken) || errors.Is(err, auth_model.ErrInvalidToken) || errors.Is(err, apikey_model.ErrInvalidAPIKey) {
+		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return echo.NewHTTPError(http.StatusNotFound, http.StatusText(http.StatusNotFound))
+	}
+
+	if errors.Is(err, offer_model.ErrOfferNotFound) || errors.Is(err, apikey_model.ErrAPIKeyNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+
+	logger.FromContext(ctx).Error("internal error", zap.Error(err))
+
+	return echo.NewHTTPError(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	apikey_model "github.com/tesarwijaya/ouroboros/internal/domain/apikey/model"
+	apikey_service "github.com/tesarwijaya/ouroboros/internal/domain/apikey/service"
+	"github.com/tesarwijaya/ouroboros/internal/domain/auth/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/auth/service"
+	"github.com/tesarwijaya/ouroboros/internal/logger"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+const HEADER_API_KEY = "X-API-Key"
+
+// Authentication rejects requests without a valid bearer token or X-API-Key,
+// except on the given public routes, and puts the verified claims in the
+// request context.
+func Authentication(tokens service.AuthService, apiKeys apikey_service.APIKeyService, public ...string) echo.MiddlewareFunc {
+	publicRoutes := make(map[string]bool, len(public))
+	for _, route := range public {
+		publicRoutes[route] = true
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if publicRoutes[c.Path()] {
+				return next(c)
+			}
+
+			ctx := c.Request().Context()
+			claims, err := authenticate(ctx, c.Request(), tokens, apiKeys)
+			if err != nil {
+				return unauthorized(c, err)
+			}
+
+			trace.SpanFromContext(ctx).SetAttributes(attribute.String("enduser.id", claims.Subject))
+			ctx = logger.WithContext(ctx, logger.FromContext(ctx).With(zap.String("subject", claims.Subject)))
+			c.SetRequest(c.Request().WithContext(model.WithClaims(ctx, claims)))
+
+			return next(c)
+		}
+	}
+}
+
+func authenticate(ctx context.Context, req *http.Request, tokens service.AuthService, apiKeys apikey_service.APIKeyService) (model.Claims, error) {
+	if key := req.Header.Get(HEADER_API_KEY); key != "" {
+		return apiKeys.Authenticate(ctx, key)
+	}
+
+	token, err := bearerToken(req)
+	if err != nil {
+		return model.Claims{}, err
+	}
+
+	return tokens.Authenticate(ctx, token)
+}
+
+func bearerToken(req *http.Request) (string, error) {
+	header := req.Header.Get(echo.HeaderAuthorization)
+	if header == "" {
+		return "", model.ErrMissingToken
+	}
+
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", model.ErrMissingToken
+	}
+
+	return token, nil
+}
+
+func unauthorized(c echo.Context, err error) error {
+	var challenge string
+	switch {
+	case errors.Is(err, model.ErrInvalidToken):
+		challenge = `Bearer error="invalid_token"`
+	case errors.Is(err, model.ErrMissingToken), errors.Is(err, apikey_model.ErrInvalidAPIKey):
+		challenge = `Bearer`
+	default:
+		// the key store failing isn't the caller's fault
+		return err
+	}
+	c.Response().Header().Set(echo.HeaderWWWAuthenticate, challenge)
+
+	return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+}
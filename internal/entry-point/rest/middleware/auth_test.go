@@ -0,0 +1,156 @@
+package middleware_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	apikey_model "github.com/tesarwijaya/ouroboros/internal/domain/apikey/model"
+	apikey_service "github.com/tesarwijaya/ouroboros/internal/domain/apikey/service"
+	"github.com/tesarwijaya/ouroboros/internal/domain/auth/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/auth/service"
+	"github.com/tesarwijaya/ouroboros/internal/entry-point/rest/middleware"
+)
+
+func Test_Authentication(t *testing.T) {
+	claims := model.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: "some-user"},
+	}
+
+	testCases := []struct {
+		Name            string
+		Path            string
+		Authorization   string
+		APIKey          string
+		Resolver        func(svc *service.MockAuthService, apiKeys *apikey_service.MockAPIKeyService)
+		ExpectStatus    int
+		ExpectBody      string
+		ExpectChallenge string
+	}{
+		{
+			Name:         "when_public_route",
+			Path:         "/livez",
+			Resolver:     func(svc *service.MockAuthService, apiKeys *apikey_service.MockAPIKeyService) {},
+			ExpectStatus: http.StatusOK,
+			ExpectBody:   "anonymous",
+		},
+		{
+			Name:            "when_no_token",
+			Path:            "/player",
+			Resolver:        func(svc *service.MockAuthService, apiKeys *apikey_service.MockAPIKeyService) {},
+			ExpectStatus:    http.StatusUnauthorized,
+			ExpectBody:      `{"message":"missing bearer token"}`,
+			ExpectChallenge: "Bearer",
+		},
+		{
+			Name:            "when_not_bearer",
+			Path:            "/player",
+			Authorization:   "Basic dXNlcjpwYXNz",
+			Resolver:        func(svc *service.MockAuthService, apiKeys *apikey_service.MockAPIKeyService) {},
+			ExpectStatus:    http.StatusUnauthorized,
+			ExpectBody:      `{"message":"missing bearer token"}`,
+			ExpectChallenge: "Bearer",
+		},
+		{
+			Name:          "when_invalid_token",
+			Path:          "/player",
+			Authorization: "Bearer some-token",
+			Resolver: func(svc *service.MockAuthService, apiKeys *apikey_service.MockAPIKeyService) {
+				svc.EXPECT().Authenticate(gomock.Any(), "some-token").
+					Return(model.Claims{}, fmt.Errorf("%w: token is expired", model.ErrInvalidToken))
+			},
+			ExpectStatus:    http.StatusUnauthorized,
+			ExpectBody:      `{"message":"invalid token: token is expired"}`,
+			ExpectChallenge: `Bearer error="invalid_token"`,
+		},
+		{
+			Name:   "when_invalid_api_key",
+			Path:   "/player",
+			APIKey: "some-key",
+			Resolver: func(svc *service.MockAuthService, apiKeys *apikey_service.MockAPIKeyService) {
+				apiKeys.EXPECT().Authenticate(gomock.Any(), "some-key").Return(model.Claims{}, apikey_model.ErrInvalidAPIKey)
+			},
+			ExpectStatus:    http.StatusUnauthorized,
+			ExpectBody:      `{"message":"invalid api key"}`,
+			ExpectChallenge: "Bearer",
+		},
+		{
+			Name:   "when_api_key_store_failed",
+			Path:   "/player",
+			APIKey: "some-key",
+			Resolver: func(svc *service.MockAuthService, apiKeys *apikey_service.MockAPIKeyService) {
+				apiKeys.EXPECT().Authenticate(gomock.Any(), "some-key").Return(model.Claims{}, errors.New("some-error"))
+			},
+			ExpectStatus: http.StatusInternalServerError,
+			ExpectBody:   `{"message":"Internal Server Error"}`,
+		},
+		{
+			Name:   "when_valid_api_key",
+			Path:   "/player",
+			APIKey: "some-key",
+			Resolver: func(svc *service.MockAuthService, apiKeys *apikey_service.MockAPIKeyService) {
+				apiKeys.EXPECT().Authenticate(gomock.Any(), "some-key").Return(model.Claims{
+					RegisteredClaims: jwt.RegisteredClaims{Subject: "apikey:1"},
+				}, nil)
+			},
+			ExpectStatus: http.StatusOK,
+			ExpectBody:   "apikey:1",
+		},
+		{
+			Name:          "when_valid_token",
+			Path:          "/player",
+			Authorization: "Bearer some-token",
+			Resolver: func(svc *service.MockAuthService, apiKeys *apikey_service.MockAPIKeyService) {
+				svc.EXPECT().Authenticate(gomock.Any(), "some-token").Return(claims, nil)
+			},
+			ExpectStatus: http.StatusOK,
+			ExpectBody:   "some-user",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			svc := service.NewMockAuthService(ctrl)
+			apiKeys := apikey_service.NewMockAPIKeyService(ctrl)
+			test.Resolver(svc, apiKeys)
+
+			handler := func(c echo.Context) error {
+				claims, ok := model.ClaimsFromContext(c.Request().Context())
+				if !ok {
+					return c.String(http.StatusOK, "anonymous")
+				}
+
+				return c.String(http.StatusOK, claims.Subject)
+			}
+
+			e := echo.New()
+			e.Use(middleware.Authentication(svc, apiKeys, "/livez"))
+			e.GET("/livez", handler)
+			e.GET("/player", handler)
+
+			req := httptest.NewRequest(http.MethodGet, test.Path, nil)
+			if test.Authorization != "" {
+				req.Header.Set(echo.HeaderAuthorization, test.Authorization)
+			}
+			if test.APIKey != "" {
+				req.Header.Set(middleware.HEADER_API_KEY, test.APIKey)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.ExpectStatus, rec.Code)
+			assert.Equal(t, test.ExpectBody, strings.TrimSpace(rec.Body.String()))
+			assert.Equal(t, test.ExpectChallenge, rec.Header().Get(echo.HeaderWWWAuthenticate))
+		})
+	}
+}
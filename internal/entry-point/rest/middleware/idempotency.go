@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tesarwijaya/ouroboros/internal/domain/idempotency/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/idempotency/service"
+	"github.com/tesarwijaya/ouroboros/internal/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+)
+
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+
+	return r.ResponseWriter.Write(b)
+}
+
+// Idempotency stores the response of POST and PATCH requests carrying an
+// Idempotency-Key header, a retry with the same key and body gets the stored
+// response back instead of running the handler again. Keys are scoped by
+// caller, so a client can't replay the response stored for another one.
+func Idempotency(svc service.IdempotencyService) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+
+			header := req.Header.Get(HeaderIdempotencyKey)
+			if header == "" || (req.Method != http.MethodPost && req.Method != http.MethodPatch) {
+				return next(c)
+			}
+			key := clientKey(c) + ":" + header
+
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+
+			ctx := req.Context()
+
+			stored, replay, err := svc.Begin(ctx, key, fingerprint(req, body))
+			if errors.Is(err, model.ErrFingerprintMismatch) {
+				return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+			}
+
+			if errors.Is(err, model.ErrInProgress) {
+				return echo.NewHTTPError(http.StatusConflict, err.Error())
+			}
+
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+			}
+
+			if replay {
+				c.Response().Header().Set(HeaderIdempotentReplayed, "true")
+				if stored.Location != "" {
+					c.Response().Header().Set(echo.HeaderLocation, stored.Location)
+				}
+
+				return c.Blob(stored.StatusCode, stored.ContentType, stored.Body)
+			}
+
+			recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = recorder
+
+			if err := next(c); err != nil || c.Response().Status >= http.StatusInternalServerError {
+				if releaseErr := svc.Release(ctx, key); releaseErr != nil {
+					logger.FromContext(ctx).Error("failed to release idempotency key", zap.String("idempotency_key", key), zap.Error(releaseErr))
+				}
+
+				return err
+			}
+
+			res := c.Response()
+			if err := svc.Complete(ctx, model.IdempotencyKeyModel{
+				Key:         key,
+				StatusCode:  res.Status,
+				ContentType: res.Header().Get(echo.HeaderContentType),
+				Location:    res.Header().Get(echo.HeaderLocation),
+				Body:        recorder.body.Bytes(),
+			}); err != nil {
+				logger.FromContext(ctx).Error("failed to store idempotent response", zap.String("idempotency_key", key), zap.Error(err))
+			}
+
+			return nil
+		}
+	}
+}
+
+func fingerprint(req *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(req.Method))
+	hash.Write([]byte(req.URL.RequestURI()))
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
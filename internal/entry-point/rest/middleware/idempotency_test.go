@@ -0,0 +1,149 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	auth_model "github.com/tesarwijaya/ouroboros/internal/domain/auth/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/idempotency/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/idempotency/service"
+	"github.com/tesarwijaya/ouroboros/internal/entry-point/rest/middleware"
+)
+
+func Test_Idempotency(t *testing.T) {
+	testCases := []struct {
+		Name         string
+		Method       string
+		Key          string
+		Claims       *auth_model.Claims
+		Resolver     func(svc *service.MockIdempotencyService)
+		ExpectCalled bool
+		ExpectStatus int
+		ExpectBody   string
+		ExpectErr    error
+	}{
+		{
+			Name:         "when_no_key",
+			Method:       http.MethodPost,
+			Resolver:     func(svc *service.MockIdempotencyService) {},
+			ExpectCalled: true,
+			ExpectStatus: http.StatusCreated,
+			ExpectBody:   `{"id":1}`,
+		},
+		{
+			Name:         "when_not_mutating",
+			Method:       http.MethodGet,
+			Key:          "some-key",
+			Resolver:     func(svc *service.MockIdempotencyService) {},
+			ExpectCalled: true,
+			ExpectStatus: http.StatusCreated,
+			ExpectBody:   `{"id":1}`,
+		},
+		{
+			Name:   "when_first_request",
+			Method: http.MethodPost,
+			Key:    "some-key",
+			Resolver: func(svc *service.MockIdempotencyService) {
+				svc.EXPECT().Begin(gomock.Any(), "ip:192.0.2.1:some-key", gomock.Any()).
+					Return(model.IdempotencyKeyModel{}, false, nil)
+				svc.EXPECT().Complete(gomock.Any(), model.IdempotencyKeyModel{
+					Key:         "ip:192.0.2.1:some-key",
+					StatusCode:  http.StatusCreated,
+					ContentType: echo.MIMEApplicationJSONCharsetUTF8,
+					Location:    "/player/1",
+					Body:        []byte(`{"id":1}`),
+				}).Return(nil)
+			},
+			ExpectCalled: true,
+			ExpectStatus: http.StatusCreated,
+			ExpectBody:   `{"id":1}`,
+		},
+		{
+			Name:   "when_authenticated",
+			Method: http.MethodPost,
+			Key:    "some-key",
+			Claims: &auth_model.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "apikey:1"}},
+			Resolver: func(svc *service.MockIdempotencyService) {
+				svc.EXPECT().Begin(gomock.Any(), "apikey:1:some-key", gomock.Any()).
+					Return(model.IdempotencyKeyModel{}, false, nil)
+				svc.EXPECT().Complete(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			ExpectCalled: true,
+			ExpectStatus: http.StatusCreated,
+			ExpectBody:   `{"id":1}`,
+		},
+		{
+			Name:   "when_retried",
+			Method: http.MethodPost,
+			Key:    "some-key",
+			Resolver: func(svc *service.MockIdempotencyService) {
+				svc.EXPECT().Begin(gomock.Any(), "ip:192.0.2.1:some-key", gomock.Any()).
+					Return(model.IdempotencyKeyModel{
+						StatusCode:  http.StatusCreated,
+						ContentType: echo.MIMEApplicationJSONCharsetUTF8,
+						Location:    "/player/1",
+						Body:        []byte(`{"id":1}`),
+					}, true, nil)
+			},
+			ExpectStatus: http.StatusCreated,
+			ExpectBody:   `{"id":1}`,
+		},
+		{
+			Name:   "when_body_differs",
+			Method: http.MethodPost,
+			Key:    "some-key",
+			Resolver: func(svc *service.MockIdempotencyService) {
+				svc.EXPECT().Begin(gomock.Any(), "ip:192.0.2.1:some-key", gomock.Any()).
+					Return(model.IdempotencyKeyModel{}, false, model.ErrFingerprintMismatch)
+			},
+			ExpectErr: echo.NewHTTPError(http.StatusUnprocessableEntity, model.ErrFingerprintMismatch.Error()),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			svc := service.NewMockIdempotencyService(ctrl)
+			test.Resolver(svc)
+
+			e := echo.New()
+			req := httptest.NewRequest(test.Method, "/player", strings.NewReader(`{"name":"some-player-name"}`))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			if test.Key != "" {
+				req.Header.Set(middleware.HeaderIdempotencyKey, test.Key)
+			}
+			if test.Claims != nil {
+				req = req.WithContext(auth_model.WithClaims(req.Context(), *test.Claims))
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			called := false
+			handler := middleware.Idempotency(svc)(func(c echo.Context) error {
+				called = true
+				c.Response().Header().Set(echo.HeaderLocation, "/player/1")
+				return c.Blob(http.StatusCreated, echo.MIMEApplicationJSONCharsetUTF8, []byte(`{"id":1}`))
+			})
+
+			err := handler(c)
+			if test.ExpectErr != nil {
+				assert.Equal(t, test.ExpectErr, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, test.ExpectCalled, called)
+			assert.Equal(t, test.ExpectStatus, rec.Code)
+			assert.Equal(t, test.ExpectBody, rec.Body.String())
+			assert.Equal(t, "/player/1", rec.Header().Get(echo.HeaderLocation))
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tesarwijaya/ouroboros/internal/logger"
+	"github.com/tesarwijaya/ouroboros/internal/tracing"
+	"go.uber.org/zap"
+)
+
+// RequestLogger puts a logger carrying the request ID, method and path in the
+// request context and logs every request once it is handled. It expects the
+// request ID to be set by the RequestID middleware beforehand.
+func RequestLogger(l *zap.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			req := c.Request()
+
+			reqLogger := l.With(
+				zap.String("request_id", c.Response().Header().Get(echo.HeaderXRequestID)),
+				zap.String("method", req.Method),
+				zap.String("path", req.URL.Path),
+			)
+			if traceID := tracing.TraceID(req.Context()); traceID != "" {
+				reqLogger = reqLogger.With(zap.String("trace_id", traceID))
+			}
+			c.SetRequest(req.WithContext(logger.WithContext(req.Context(), reqLogger)))
+
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+
+			fields := []zap.Field{
+				zap.String("route", c.Path()),
+				zap.Int("status", c.Response().Status),
+				zap.Duration("latency", time.Since(start)),
+			}
+
+			switch status := c.Response().Status; {
+			case status >= 500:
+				reqLogger.Error("request failed", append(fields, zap.Error(err))...)
+			case status >= 400:
+				reqLogger.Warn("request rejected", append(fields, zap.Error(err))...)
+			default:
+				reqLogger.Info("request handled", fields...)
+			}
+
+			return err
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package middleware_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	echo_middleware "github.com/labstack/echo/v4/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/tesarwijaya/ouroboros/internal/entry-point/rest/middleware"
+	"github.com/tesarwijaya/ouroboros/internal/logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func Test_RequestLogger(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Handler       echo.HandlerFunc
+		ExpectStatus  int
+		ExpectLevel   zapcore.Level
+		ExpectMessage string
+	}{
+		{
+			Name: "when_handled",
+			Handler: func(c echo.Context) error {
+				logger.FromContext(c.Request().Context()).Info("inside handler")
+				return c.NoContent(http.StatusOK)
+			},
+			ExpectStatus:  http.StatusOK,
+			ExpectLevel:   zapcore.InfoLevel,
+			ExpectMessage: "request handled",
+		},
+		{
+			Name: "when_rejected",
+			Handler: func(c echo.Context) error {
+				logger.FromContext(c.Request().Context()).Info("inside handler")
+				return echo.NewHTTPError(http.StatusBadRequest, "bad")
+			},
+			ExpectStatus:  http.StatusBadRequest,
+			ExpectLevel:   zapcore.WarnLevel,
+			ExpectMessage: "request rejected",
+		},
+		{
+			Name: "when_failed",
+			Handler: func(c echo.Context) error {
+				logger.FromContext(c.Request().Context()).Info("inside handler")
+				return errors.New("some-error")
+			},
+			ExpectStatus:  http.StatusInternalServerError,
+			ExpectLevel:   zapcore.ErrorLevel,
+			ExpectMessage: "request failed",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.DebugLevel)
+
+			e := echo.New()
+			e.Use(echo_middleware.RequestID())
+			e.Use(middleware.RequestLogger(zap.New(core)))
+			e.GET("/player/:id", test.Handler)
+
+			req := httptest.NewRequest(http.MethodGet, "/player/1", nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.ExpectStatus, rec.Code)
+
+			entries := logs.AllUntimed()
+			if assert.Len(t, entries, 2) {
+				requestID := rec.Header().Get(echo.HeaderXRequestID)
+				assert.NotEmpty(t, requestID)
+
+				// the handler logs through the request scoped logger
+				assert.Equal(t, "inside handler", entries[0].Message)
+				assert.Equal(t, requestID, entries[0].ContextMap()["request_id"])
+
+				assert.Equal(t, test.ExpectLevel, entries[1].Level)
+				assert.Equal(t, test.ExpectMessage, entries[1].Message)
+				assert.Equal(t, requestID, entries[1].ContextMap()["request_id"])
+				assert.Equal(t, "/player/:id", entries[1].ContextMap()["route"])
+				assert.Equal(t, int64(test.ExpectStatus), entries[1].ContextMap()["status"])
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tesarwijaya/ouroboros/internal/metrics"
+)
+
+// Metrics counts and times every request by its route template rather than
+// its path, so that IDs in the path don't blow up the label cardinality.
+func Metrics() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+
+			// echo falls back to the raw path when no route matches
+			route := c.Path()
+			if errors.Is(err, echo.ErrNotFound) && route == c.Request().URL.Path {
+				route = "unmatched"
+			}
+			method := c.Request().Method
+
+			metrics.HTTPRequests.WithLabelValues(method, route, strconv.Itoa(c.Response().Status)).Inc()
+			metrics.HTTPRequestDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+
+			return err
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package middleware_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/tesarwijaya/ouroboros/internal/entry-point/rest/middleware"
+	"github.com/tesarwijaya/ouroboros/internal/metrics"
+)
+
+func Test_Metrics(t *testing.T) {
+	testCases := []struct {
+		Name         string
+		Path         string
+		Handler      echo.HandlerFunc
+		ExpectRoute  string
+		ExpectStatus string
+	}{
+		{
+			Name: "when_handled",
+			Path: "/metrics-test/1",
+			Handler: func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			},
+			ExpectRoute:  "/metrics-test/:id",
+			ExpectStatus: "200",
+		},
+		{
+			Name: "when_failed",
+			Path: "/metrics-test/2",
+			Handler: func(c echo.Context) error {
+				return errors.New("some-error")
+			},
+			ExpectRoute:  "/metrics-test/:id",
+			ExpectStatus: "500",
+		},
+		{
+			Name:         "when_unmatched",
+			Path:         "/unknown",
+			Handler:      func(c echo.Context) error { return nil },
+			ExpectRoute:  "unmatched",
+			ExpectStatus: "404",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			e := echo.New()
+			e.Use(middleware.Metrics())
+			e.GET("/metrics-test/:id", test.Handler)
+
+			counter := metrics.HTTPRequests.WithLabelValues(http.MethodGet, test.ExpectRoute, test.ExpectStatus)
+			before := testutil.ToFloat64(counter)
+
+			req := httptest.NewRequest(http.MethodGet, test.Path, nil)
+			e.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, before+1, testutil.ToFloat64(counter))
+		})
+	}
+}
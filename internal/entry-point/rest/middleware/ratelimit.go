@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	auth_model "github.com/tesarwijaya/ouroboros/internal/domain/auth/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/ratelimit/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/ratelimit/service"
+	"github.com/tesarwijaya/ouroboros/internal/logger"
+	"github.com/tesarwijaya/ouroboros/internal/metrics"
+	"go.uber.org/zap"
+)
+
+const (
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+)
+
+// RateLimit gives every client a bucket for reads and another one for
+// writes, except on the given public routes. Clients are told apart by API
+// key, then by user, then by IP. A failing store lets requests through
+// rather than taking the API down with it.
+func RateLimit(svc service.RateLimitService, read model.Limit, write model.Limit, public ...string) echo.MiddlewareFunc {
+	publicRoutes := make(map[string]bool, len(public))
+	for _, route := range public {
+		publicRoutes[route] = true
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if publicRoutes[c.Path()] {
+				return next(c)
+			}
+
+			class, limit := model.CLASS_WRITE, write
+			switch c.Request().Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				class, limit = model.CLASS_READ, read
+			}
+
+			if err := take(c, svc, class, clientKey(c), limit); err != nil {
+				return err
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// RateLimitByIP gives every IP a bucket for all of its requests, except on
+// the given public routes. It runs before the authentication, so that failed
+// logins and guessed API keys are limited too.
+func RateLimitByIP(svc service.RateLimitService, limit model.Limit, public ...string) echo.MiddlewareFunc {
+	publicRoutes := make(map[string]bool, len(public))
+	for _, route := range public {
+		publicRoutes[route] = true
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if publicRoutes[c.Path()] {
+				return next(c)
+			}
+
+			if err := take(c, svc, model.CLASS_IP, c.RealIP(), limit); err != nil {
+				return err
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// take takes a token out of the client bucket of the class, the returned
+// error rejects the request.
+func take(c echo.Context, svc service.RateLimitService, class string, client string, limit model.Limit) error {
+	if limit.Unlimited() {
+		return nil
+	}
+
+	ctx := c.Request().Context()
+	key := class + ":" + client
+
+	res, err := svc.Allow(ctx, key, limit)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to check rate limit", zap.String("rate_limit_key", key), zap.Error(err))
+
+		return nil
+	}
+
+	header := c.Response().Header()
+	header.Set(HeaderRateLimitLimit, strconv.FormatInt(res.Limit, 10))
+	header.Set(HeaderRateLimitRemaining, strconv.FormatInt(res.Remaining, 10))
+	header.Set(HeaderRateLimitReset, ceilSeconds(res.Reset))
+
+	if !res.Allowed {
+		metrics.RateLimitedRequests.WithLabelValues(class).Inc()
+		header.Set(echo.HeaderRetryAfter, ceilSeconds(res.RetryAfter))
+
+		return echo.NewHTTPError(http.StatusTooManyRequests, "rate limit exceeded")
+	}
+
+	return nil
+}
+
+// clientKey identifies the caller, API key subjects already carry their
+// "apikey:" prefix. The IP is the one of the peer unless it is a trusted
+// proxy, see the IP extractor of the server.
+func clientKey(c echo.Context) string {
+	claims, ok := auth_model.ClaimsFromContext(c.Request().Context())
+	if ok && claims.Subject != "" {
+		if strings.HasPrefix(claims.Subject, "apikey:") {
+			return claims.Subject
+		}
+
+		return "user:" + claims.Subject
+	}
+
+	return "ip:" + c.RealIP()
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
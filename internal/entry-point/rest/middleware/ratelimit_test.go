@@ -0,0 +1,207 @@
+package middleware_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	auth_model "github.com/tesarwijaya/ouroboros/internal/domain/auth/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/ratelimit/model"
+	"github.com/tesarwijaya/ouroboros/internal/domain/ratelimit/service"
+	"github.com/tesarwijaya/ouroboros/internal/entry-point/rest/middleware"
+)
+
+func Test_RateLimit(t *testing.T) {
+	read := model.Limit{Rate: 10, Burst: 20}
+	write := model.Limit{Rate: 1, Burst: 5}
+
+	testCases := []struct {
+		Name          string
+		Method        string
+		Path          string
+		Subject       string
+		Write         model.Limit
+		Resolver      func(svc *service.MockRateLimitService)
+		ExpectCalled  bool
+		ExpectHeaders map[string]string
+		ExpectErr     error
+	}{
+		{
+			Name:         "when_public_route",
+			Method:       http.MethodGet,
+			Path:         "/livez",
+			Write:        write,
+			Resolver:     func(svc *service.MockRateLimitService) {},
+			ExpectCalled: true,
+		},
+		{
+			Name:         "when_unlimited",
+			Method:       http.MethodPost,
+			Path:         "/player",
+			Resolver:     func(svc *service.MockRateLimitService) {},
+			ExpectCalled: true,
+		},
+		{
+			Name:   "when_anonymous_read",
+			Method: http.MethodGet,
+			Path:   "/player",
+			Write:  write,
+			Resolver: func(svc *service.MockRateLimitService) {
+				svc.EXPECT().Allow(gomock.Any(), "read:ip:192.0.2.1", read).
+					Return(model.Result{Allowed: true, Limit: 20, Remaining: 19, Reset: 100 * time.Millisecond}, nil)
+			},
+			ExpectCalled: true,
+			ExpectHeaders: map[string]string{
+				middleware.HeaderRateLimitLimit:     "20",
+				middleware.HeaderRateLimitRemaining: "19",
+				middleware.HeaderRateLimitReset:     "1",
+			},
+		},
+		{
+			Name:    "when_user_write",
+			Method:  http.MethodPost,
+			Path:    "/player",
+			Subject: "some-user",
+			Write:   write,
+			Resolver: func(svc *service.MockRateLimitService) {
+				svc.EXPECT().Allow(gomock.Any(), "write:user:some-user", write).
+					Return(model.Result{Allowed: true, Limit: 5, Remaining: 4, Reset: time.Second}, nil)
+			},
+			ExpectCalled: true,
+		},
+		{
+			Name:    "when_api_key_exhausted",
+			Method:  http.MethodPost,
+			Path:    "/player",
+			Subject: "apikey:1",
+			Write:   write,
+			Resolver: func(svc *service.MockRateLimitService) {
+				svc.EXPECT().Allow(gomock.Any(), "write:apikey:1", write).
+					Return(model.Result{Limit: 5, Remaining: 0, Reset: 4500 * time.Millisecond, RetryAfter: 500 * time.Millisecond}, nil)
+			},
+			ExpectHeaders: map[string]string{
+				middleware.HeaderRateLimitLimit:     "5",
+				middleware.HeaderRateLimitRemaining: "0",
+				middleware.HeaderRateLimitReset:     "5",
+				echo.HeaderRetryAfter:               "1",
+			},
+			ExpectErr: echo.NewHTTPError(http.StatusTooManyRequests, "rate limit exceeded"),
+		},
+		{
+			Name:   "when_store_failed",
+			Method: http.MethodPost,
+			Path:   "/player",
+			Write:  write,
+			Resolver: func(svc *service.MockRateLimitService) {
+				svc.EXPECT().Allow(gomock.Any(), "write:ip:192.0.2.1", write).
+					Return(model.Result{}, errors.New("some-error"))
+			},
+			ExpectCalled: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			svc := service.NewMockRateLimitService(ctrl)
+			test.Resolver(svc)
+
+			e := echo.New()
+			req := httptest.NewRequest(test.Method, test.Path, nil)
+			if test.Subject != "" {
+				req = req.WithContext(auth_model.WithClaims(req.Context(), auth_model.Claims{
+					RegisteredClaims: jwt.RegisteredClaims{Subject: test.Subject},
+				}))
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath(test.Path)
+
+			called := false
+			handler := middleware.RateLimit(svc, read, test.Write, "/livez")(func(c echo.Context) error {
+				called = true
+				return c.NoContent(http.StatusOK)
+			})
+
+			err := handler(c)
+			assert.Equal(t, test.ExpectErr, err)
+			assert.Equal(t, test.ExpectCalled, called)
+			for header, value := range test.ExpectHeaders {
+				assert.Equal(t, value, rec.Header().Get(header), header)
+			}
+		})
+	}
+}
+
+func Test_RateLimitByIP(t *testing.T) {
+	limit := model.Limit{Rate: 50, Burst: 100}
+
+	testCases := []struct {
+		Name         string
+		Path         string
+		Resolver     func(svc *service.MockRateLimitService)
+		ExpectCalled bool
+		ExpectErr    error
+	}{
+		{
+			Name:         "when_public_route",
+			Path:         "/livez",
+			Resolver:     func(svc *service.MockRateLimitService) {},
+			ExpectCalled: true,
+		},
+		{
+			Name: "when_allowed",
+			Path: "/player",
+			Resolver: func(svc *service.MockRateLimitService) {
+				svc.EXPECT().Allow(gomock.Any(), "ip:192.0.2.1", limit).
+					Return(model.Result{Allowed: true, Limit: 100, Remaining: 99}, nil)
+			},
+			ExpectCalled: true,
+		},
+		{
+			Name: "when_exhausted",
+			Path: "/player",
+			Resolver: func(svc *service.MockRateLimitService) {
+				svc.EXPECT().Allow(gomock.Any(), "ip:192.0.2.1", limit).
+					Return(model.Result{Limit: 100, RetryAfter: 20 * time.Millisecond}, nil)
+			},
+			ExpectErr: echo.NewHTTPError(http.StatusTooManyRequests, "rate limit exceeded"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			svc := service.NewMockRateLimitService(ctrl)
+			test.Resolver(svc)
+
+			e := echo.New()
+			e.IPExtractor = echo.ExtractIPDirect()
+			req := httptest.NewRequest(http.MethodPost, test.Path, nil)
+			req.Header.Set(echo.HeaderXForwardedFor, "203.0.113.7")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath(test.Path)
+
+			called := false
+			handler := middleware.RateLimitByIP(svc, limit, "/livez")(func(c echo.Context) error {
+				called = true
+				return c.NoContent(http.StatusOK)
+			})
+
+			err := handler(c)
+			assert.Equal(t, test.ExpectErr, err)
+			assert.Equal(t, test.ExpectCalled, called)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tesarwijaya/ouroboros/internal/logger"
+	"go.uber.org/zap"
+)
+
+// Recover turns a panicking handler into a bare 500, the panic value and the
+// stack trace are logged but never sent to the client.
+func Recover() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (err error) {
+			defer func() {
+				r := recover()
+				if r == nil {
+					return
+				}
+
+				// net/http aborts the response on purpose with this one
+				if r == http.ErrAbortHandler {
+					panic(r)
+				}
+
+				logger.FromContext(c.Request().Context()).Error("handler panicked",
+					zap.String("panic", fmt.Sprint(r)),
+					zap.Stack("stack"),
+				)
+				err = echo.NewHTTPError(http.StatusInternalServerError)
+			}()
+
+			return next(c)
+		}
+	}
+}
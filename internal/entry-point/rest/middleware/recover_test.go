@@ -0,0 +1,81 @@
+package middleware_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/tesarwijaya/ouroboros/internal/entry-point/rest/middleware"
+	"github.com/tesarwijaya/ouroboros/internal/logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func Test_Recover(t *testing.T) {
+	testCases := []struct {
+		Name       string
+		Handler    echo.HandlerFunc
+		ExpectErr  error
+		ExpectLogs int
+	}{
+		{
+			Name: "when_handler_succeeds",
+			Handler: func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			},
+		},
+		{
+			Name: "when_handler_fails",
+			Handler: func(c echo.Context) error {
+				return errors.New("some-error")
+			},
+			ExpectErr: errors.New("some-error"),
+		},
+		{
+			Name: "when_handler_panics",
+			Handler: func(c echo.Context) error {
+				panic("db password is hunter2")
+			},
+			ExpectErr:  echo.NewHTTPError(http.StatusInternalServerError),
+			ExpectLogs: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			core, logs := observer.New(zap.DebugLevel)
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/player", nil)
+			req = req.WithContext(logger.WithContext(req.Context(), zap.New(core)))
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := middleware.Recover()(test.Handler)(c)
+
+			assert.Equal(t, test.ExpectErr, err)
+			assert.Equal(t, test.ExpectLogs, logs.Len())
+			if test.ExpectLogs > 0 {
+				entry := logs.All()[0]
+				assert.Equal(t, "db password is hunter2", entry.ContextMap()["panic"])
+			}
+		})
+	}
+}
+
+func Test_Recover_Response(t *testing.T) {
+	e := echo.New()
+	e.Use(middleware.Recover())
+	e.GET("/player", func(c echo.Context) error {
+		panic("db password is hunter2")
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/player", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.JSONEq(t, `{"message":"Internal Server Error"}`, rec.Body.String())
+}
@@ -0,0 +1,87 @@
+package middleware_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/tesarwijaya/ouroboros/internal/entry-point/rest/middleware"
+	"github.com/tesarwijaya/ouroboros/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func Test_Tracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	testCases := []struct {
+		Name          string
+		TraceParent   string
+		Handler       echo.HandlerFunc
+		ExpectTraceID string
+		ExpectStatus  codes.Code
+	}{
+		{
+			Name: "when_new_trace",
+			Handler: func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			},
+			ExpectStatus: codes.Unset,
+		},
+		{
+			Name:        "when_incoming_trace",
+			TraceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			Handler: func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			},
+			ExpectTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+			ExpectStatus:  codes.Unset,
+		},
+		{
+			Name: "when_failed",
+			Handler: func(c echo.Context) error {
+				return errors.New("some-error")
+			},
+			ExpectStatus: codes.Error,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			var handlerTraceID string
+
+			e := echo.New()
+			e.Use(middleware.Tracing())
+			e.GET("/player/:id", func(c echo.Context) error {
+				handlerTraceID = tracing.TraceID(c.Request().Context())
+				return test.Handler(c)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/player/1", nil)
+			if test.TraceParent != "" {
+				req.Header.Set("traceparent", test.TraceParent)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			spans := recorder.Ended()
+			span := spans[len(spans)-1]
+
+			assert.Equal(t, "GET /player/:id", span.Name())
+			assert.Equal(t, test.ExpectStatus, span.Status().Code)
+			assert.Equal(t, span.SpanContext().TraceID().String(), handlerTraceID)
+			assert.Contains(t, rec.Header().Get("traceparent"), handlerTraceID)
+			if test.ExpectTraceID != "" {
+				assert.Equal(t, test.ExpectTraceID, handlerTraceID)
+			}
+		})
+	}
+}
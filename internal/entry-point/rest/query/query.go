@@ -0,0 +1,63 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MAX_IDS bounds the ids looked up at once, so that the IN list of the query
+// stays reasonable.
+const MAX_IDS = 100
+
+// IDs parses a comma separated list of ids, repeated ids are only kept once.
+func IDs(value string) ([]int64, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(value, ",")
+	seen := make(map[int64]bool, len(parts))
+	ids := make([]int64, 0, len(parts))
+	for _, part := range parts {
+		id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid id %q", part)
+		}
+
+		if seen[id] {
+			continue
+		}
+
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	if len(ids) > MAX_IDS {
+		return nil, fmt.Errorf("at most %d ids can be looked up at once", MAX_IDS)
+	}
+
+	return ids, nil
+}
+
+// Fields parses a comma separated list of fields, blanks and repeated fields
+// are left out.
+func Fields(value string) []string {
+	if value == "" {
+		return nil
+	}
+
+	seen := map[string]bool{}
+	fields := []string{}
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" || seen[field] {
+			continue
+		}
+
+		seen[field] = true
+		fields = append(fields, field)
+	}
+
+	return fields
+}
@@ -1,18 +1,34 @@
 package rest
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	echoSwagger "github.com/swaggo/echo-swagger"
 	_ "github.com/tesarwijaya/ouroboros/docs"
 	"github.com/tesarwijaya/ouroboros/internal/config"
+	apikey_service "github.com/tesarwijaya/ouroboros/internal/domain/apikey/service"
+	auth_service "github.com/tesarwijaya/ouroboros/internal/domain/auth/service"
+	idempotency_service "github.com/tesarwijaya/ouroboros/internal/domain/idempotency/service"
+	ratelimit_model "github.com/tesarwijaya/ouroboros/internal/domain/ratelimit/model"
+	ratelimit_service "github.com/tesarwijaya/ouroboros/internal/domain/ratelimit/service"
+	"github.com/tesarwijaya/ouroboros/internal/entry-point/graphql"
+	exporter_controller "github.com/tesarwijaya/ouroboros/internal/entry-point/rest/controller/exporter"
 	healthz_controller "github.com/tesarwijaya/ouroboros/internal/entry-point/rest/controller/healthz"
+	importer_controller "github.com/tesarwijaya/ouroboros/internal/entry-point/rest/controller/importer"
+	offer_controller "github.com/tesarwijaya/ouroboros/internal/entry-point/rest/controller/offer"
 	player_controller "github.com/tesarwijaya/ouroboros/internal/entry-point/rest/controller/player"
 	team_controller "github.com/tesarwijaya/ouroboros/internal/entry-point/rest/controller/team"
+	rest_middleware "github.com/tesarwijaya/ouroboros/internal/entry-point/rest/middleware"
 	"go.uber.org/dig"
+	"go.uber.org/zap"
 )
 
 type RestController struct {
@@ -20,6 +36,21 @@ type RestController struct {
 	HealthzController healthz_controller.HealthzController
 	PlayerController  player_controller.PlayerController
 	TeamController    team_controller.TeamController
+	ImportController  importer_controller.ImportController
+	ExportController  exporter_controller.ExportController
+	OfferController   offer_controller.OfferController
+	GraphqlHandler    graphql.GraphqlHandler
+}
+
+// publicRoutes don't require authentication, probes and scrapers can't
+// hold a token.
+var publicRoutes = []string{
+	"/",
+	"/livez",
+	"/readyz",
+	"/healthz",
+	"/metrics",
+	"/swagger/*",
 }
 
 type RestServer struct {
@@ -41,30 +72,167 @@ type RestServer struct {
 
 // @host     localhost:8000
 // @BasePath /
-func NewRestServer(c *config.Config, controllers RestController) RestServer {
+
+// @securityDefinitions.apikey BearerAuth
+// @in                         header
+// @name                       Authorization
+// @description                JWT signed with HS256 or RS256, prefixed with "Bearer "
+
+// @securityDefinitions.apikey APIKeyAuth
+// @in                         header
+// @name                       X-API-Key
+// @description                API key of a machine client, created with the apikey CLI
+func NewRestServer(c *config.Config, l *zap.Logger, controllers RestController, auth auth_service.AuthService, apiKeys apikey_service.APIKeyService, rateLimit ratelimit_service.RateLimitService, idempotency idempotency_service.IdempotencyService) (RestServer, error) {
+	ipExtractor, err := IPExtractor(c.TrustedProxies)
+	if err != nil {
+		return RestServer{}, err
+	}
+
 	e := echo.New()
-	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
-		AllowOrigins: []string{"*"},
-		AllowHeaders: []string{"*"},
-		AllowMethods: []string{http.MethodGet, http.MethodPut, http.MethodPost, http.MethodDelete},
+	e.HideBanner = true
+	e.HidePort = true
+	e.IPExtractor = ipExtractor
+	e.Use(middleware.RequestID())
+	e.Use(rest_middleware.Tracing())
+	e.Use(rest_middleware.Metrics())
+	e.Use(rest_middleware.RequestLogger(l))
+	e.Use(rest_middleware.Recover())
+	e.Use(middleware.SecureWithConfig(middleware.SecureConfig{
+		ContentTypeNosniff: "nosniff",
+		XFrameOptions:      "DENY",
+		HSTSMaxAge:         c.HSTSMaxAge,
+		ReferrerPolicy:     "no-referrer",
 	}))
+	// echo allows every origin when none is given, so cross-origin requests
+	// stay disabled until origins are configured
+	if len(c.CORSAllowOrigins) > 0 {
+		e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
+			AllowOrigins: c.CORSAllowOrigins,
+			AllowHeaders: c.CORSAllowHeaders,
+			AllowMethods: c.CORSAllowMethods,
+			ExposeHeaders: []string{
+				"traceparent",
+				rest_middleware.HeaderRateLimitLimit,
+				rest_middleware.HeaderRateLimitRemaining,
+				rest_middleware.HeaderRateLimitReset,
+				echo.HeaderRetryAfter,
+			},
+		}))
+	}
+	e.Use(middleware.BodyLimit(c.BodyLimit))
+	if c.RateLimitEnabled {
+		e.Use(rest_middleware.RateLimitByIP(
+			rateLimit,
+			ratelimit_model.Limit{Rate: c.RateLimitIPRate, Burst: c.RateLimitIPBurst},
+			publicRoutes...,
+		))
+	}
+	if c.AuthEnabled {
+		e.Use(rest_middleware.Authentication(auth, apiKeys, publicRoutes...))
+	}
+	if c.RateLimitEnabled {
+		e.Use(rest_middleware.RateLimit(
+			rateLimit,
+			ratelimit_model.Limit{Rate: c.RateLimitReadRate, Burst: c.RateLimitReadBurst},
+			ratelimit_model.Limit{Rate: c.RateLimitWriteRate, Burst: c.RateLimitWriteBurst},
+			publicRoutes...,
+		))
+	}
+	e.Use(rest_middleware.Idempotency(idempotency))
 
 	e.GET("/", func(c echo.Context) error {
 		return c.String(http.StatusOK, "Hello, World!")
 	})
 
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
+	e.GET("/metrics", echo.WrapHandler(promhttp.Handler()))
 
 	controllers.HealthzController.SetRouter(e)
 	controllers.PlayerController.SetRouter(e)
 	controllers.TeamController.SetRouter(e)
+	controllers.ImportController.SetRouter(e)
+	controllers.ExportController.SetRouter(e)
+	controllers.OfferController.SetRouter(e)
+	controllers.GraphqlHandler.SetRouter(e)
 
 	return RestServer{
 		Server: e,
 		Config: c,
+	}, nil
+}
+
+// IPExtractor takes the client IP from the peer address, or from the
+// X-Forwarded-For header when the peer is one of the trusted proxy ranges.
+// Client supplied headers are never trusted otherwise.
+func IPExtractor(trustedProxies []string) (echo.IPExtractor, error) {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	opts := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, proxy := range trustedProxies {
+		_, ipNet, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+
+		opts = append(opts, echo.TrustIPRange(ipNet))
 	}
+
+	return echo.ExtractIPFromXFFHeader(opts...), nil
+}
+
+// Listen binds the configured port, so that a taken port or an unreadable
+// certificate fails the app start instead of a background goroutine.
+func (s *RestServer) Listen() (net.Listener, error) {
+	if s.Config.TLSCertFile != "" || s.Config.TLSKeyFile != "" {
+		if s.Config.TLSCertFile == "" || s.Config.TLSKeyFile == "" {
+			return nil, errors.New("both a tls certificate and key file are required")
+		}
+
+		cert, err := tls.LoadX509KeyPair(s.Config.TLSCertFile, s.Config.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load tls certificate: %w", err)
+		}
+
+		s.Server.Server.TLSConfig = &tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			Certificates: []tls.Certificate{cert},
+		}
+	}
+
+	ln, err := net.Listen("tcp", fmt.Sprintf(":%s", s.Config.Port))
+	if err != nil {
+		return nil, err
+	}
+	s.Server.Listener = ln
+
+	return ln, nil
+}
+
+// Serve blocks until the server stops, it returns nil once Shutdown is called.
+// The connections are served over TLS when Listen loaded a certificate.
+func (s *RestServer) Serve(ln net.Listener) error {
+	var err error
+	if s.Server.Server.TLSConfig != nil {
+		err = s.Server.Server.ServeTLS(ln, "", "")
+	} else {
+		err = s.Server.Server.Serve(ln)
+	}
+
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
 }
 
-func (s *RestServer) Start() error {
-	return s.Server.Start(fmt.Sprintf(":%s", s.Config.Port))
+// Shutdown stops accepting connections and waits for in-flight requests
+// until ctx is done.
+func (s *RestServer) Shutdown(ctx context.Context) error {
+	return s.Server.Shutdown(ctx)
 }
@@ -0,0 +1,175 @@
+package rest_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/tesarwijaya/ouroboros/internal/config"
+	"github.com/tesarwijaya/ouroboros/internal/entry-point/rest"
+)
+
+func writeCertificate(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.Nil(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.Nil(t, err)
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	assert.Nil(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}), 0o600))
+	assert.Nil(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	return certFile, keyFile
+}
+
+func Test_Listen(t *testing.T) {
+	certFile, keyFile := writeCertificate(t)
+
+	testCases := []struct {
+		Name      string
+		Config    config.Config
+		ExpectErr string
+	}{
+		{
+			Name:   "when_plain",
+			Config: config.Config{Port: "0"},
+		},
+		{
+			Name:   "when_tls",
+			Config: config.Config{Port: "0", TLSCertFile: certFile, TLSKeyFile: keyFile},
+		},
+		{
+			Name:      "when_tls_key_missing",
+			Config:    config.Config{Port: "0", TLSCertFile: certFile},
+			ExpectErr: "both a tls certificate and key file are required",
+		},
+		{
+			Name:      "when_tls_files_mismatch",
+			Config:    config.Config{Port: "0", TLSCertFile: keyFile, TLSKeyFile: certFile},
+			ExpectErr: "failed to load tls certificate",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			server := rest.RestServer{Server: echo.New(), Config: &test.Config}
+
+			ln, err := server.Listen()
+			if test.ExpectErr != "" {
+				assert.ErrorContains(t, err, test.ExpectErr)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Nil(t, ln.Close())
+		})
+	}
+}
+
+func Test_Serve_TLS(t *testing.T) {
+	certFile, keyFile := writeCertificate(t)
+
+	e := echo.New()
+	e.GET("/livez", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})
+	server := rest.RestServer{Server: e, Config: &config.Config{Port: "0", TLSCertFile: certFile, TLSKeyFile: keyFile}}
+
+	ln, err := server.Listen()
+	assert.Nil(t, err)
+
+	done := make(chan error)
+	go func() {
+		done <- server.Serve(ln)
+	}()
+
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}}
+	res, err := client.Get("https://" + ln.Addr().String() + "/livez")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+	assert.NotNil(t, res.TLS)
+	res.Body.Close()
+
+	assert.Nil(t, server.Shutdown(context.Background()))
+	assert.Nil(t, <-done)
+}
+
+func Test_IPExtractor(t *testing.T) {
+	testCases := []struct {
+		Name           string
+		TrustedProxies []string
+		RemoteAddr     string
+		Expected       string
+		ExpectErr      string
+	}{
+		{
+			Name:       "when_no_trusted_proxy",
+			RemoteAddr: "10.0.0.1:1234",
+			Expected:   "10.0.0.1",
+		},
+		{
+			Name:           "when_trusted_proxy",
+			TrustedProxies: []string{"10.0.0.0/8"},
+			RemoteAddr:     "10.0.0.1:1234",
+			Expected:       "203.0.113.7",
+		},
+		{
+			Name:           "when_untrusted_peer",
+			TrustedProxies: []string{"10.0.0.0/8"},
+			RemoteAddr:     "192.0.2.1:1234",
+			Expected:       "192.0.2.1",
+		},
+		{
+			Name:           "when_proxy_invalid",
+			TrustedProxies: []string{"some-proxy"},
+			ExpectErr:      "invalid trusted proxy \"some-proxy\"",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			extractor, err := rest.IPExtractor(test.TrustedProxies)
+			if test.ExpectErr != "" {
+				assert.ErrorContains(t, err, test.ExpectErr)
+				return
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = test.RemoteAddr
+			req.Header.Set(echo.HeaderXForwardedFor, "203.0.113.7")
+			req.Header.Set(echo.HeaderXRealIP, "203.0.113.8")
+
+			assert.Nil(t, err)
+			assert.Equal(t, test.Expected, extractor(req))
+		})
+	}
+}
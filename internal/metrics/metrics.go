@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "ouroboros"
+
+var (
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Number of handled HTTP requests.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of handled HTTP requests.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	DBQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Latency of database queries by repository method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"repository", "method"})
+
+	EventAppends = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "event_appends_total",
+		Help:      "Number of events appended to the event store.",
+	}, []string{"event_type", "result"})
+
+	EventAppendDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "event_append_duration_seconds",
+		Help:      "Latency of event store appends.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"event_type"})
+
+	ProjectionLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "projection_lag_seconds",
+		Help:      "Time between appending the last event and a handler finishing with it.",
+	}, []string{"handler"})
+
+	EventDeadLetters = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "event_dead_letters_total",
+		Help:      "Number of events moved out of the outbox after failing a sync handler too many times.",
+	}, []string{"handler"})
+
+	RateLimitedRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limited_requests_total",
+		Help:      "Number of requests rejected by the rate limiter.",
+	}, []string{"class"})
+)
+
+// ObserveQuery starts timing a repository query, the returned func records it
+// and is meant to be deferred.
+//
+//	defer metrics.ObserveQuery("player", "FindAll")()
+func ObserveQuery(repository, method string) func() {
+	start := time.Now()
+
+	return func() {
+		DBQueryDuration.WithLabelValues(repository, method).Observe(time.Since(start).Seconds())
+	}
+}
+
+// ObserveAppend records an event store append of the given event type.
+func ObserveAppend(eventType string, start time.Time, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+
+	EventAppends.WithLabelValues(eventType, result).Inc()
+	EventAppendDuration.WithLabelValues(eventType).Observe(time.Since(start).Seconds())
+}
+
+// RegisterDB exposes the connection pool stats of db. Registering the pool a
+// second time is a no-op.
+func RegisterDB(db *sql.DB, name string) error {
+	err := prometheus.Register(collectors.NewDBStatsCollector(db, name))
+
+	var alreadyRegistered prometheus.AlreadyRegisteredError
+	if errors.As(err, &alreadyRegistered) {
+		return nil
+	}
+
+	return err
+}
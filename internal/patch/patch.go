@@ -0,0 +1,109 @@
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"reflect"
+	"sort"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+)
+
+const (
+	MIME_MERGE_PATCH = "application/merge-patch+json"
+	MIME_JSON_PATCH  = "application/json-patch+json"
+)
+
+var ErrUnsupportedType = fmt.Errorf("content type must be %s or %s", MIME_MERGE_PATCH, MIME_JSON_PATCH)
+
+// Patch is a JSON Merge Patch (RFC 7396) or JSON Patch (RFC 6902) document
+// to apply to a resource, Type tells them apart.
+type Patch struct {
+	Type string
+	Body []byte
+}
+
+// New reads the type of the patch from the Content-Type header value.
+func New(contentType string, body []byte) (Patch, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return Patch{}, ErrUnsupportedType
+	}
+
+	p := Patch{Type: mediaType, Body: body}
+	if err := p.Validate(); err != nil {
+		return Patch{}, err
+	}
+
+	return p, nil
+}
+
+func (p Patch) Validate() error {
+	if p.Type != MIME_MERGE_PATCH && p.Type != MIME_JSON_PATCH {
+		return ErrUnsupportedType
+	}
+
+	if len(bytes.TrimSpace(p.Body)) == 0 {
+		return errors.New("patch is required")
+	}
+
+	return nil
+}
+
+// Apply patches v, a struct marshalled to a JSON object, into res. The patched
+// document is decoded strictly so a patch adding an unknown field fails.
+func (p Patch) Apply(v interface{}, res interface{}) error {
+	doc, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	var patched []byte
+	switch p.Type {
+	case MIME_MERGE_PATCH:
+		patched, err = jsonpatch.MergePatch(doc, p.Body)
+	case MIME_JSON_PATCH:
+		var ops jsonpatch.Patch
+		ops, err = jsonpatch.DecodePatch(p.Body)
+		if err == nil {
+			patched, err = ops.Apply(doc)
+		}
+	default:
+		return ErrUnsupportedType
+	}
+	if err != nil {
+		return fmt.Errorf("invalid patch: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(patched))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(res); err != nil {
+		return fmt.Errorf("invalid patched document: %w", err)
+	}
+
+	return nil
+}
+
+// Changed returns the fields whose value differs between before and after,
+// sorted by name.
+func Changed(before, after map[string]interface{}) []string {
+	res := []string{}
+	for field, value := range after {
+		if !reflect.DeepEqual(before[field], value) {
+			res = append(res, field)
+		}
+	}
+
+	for field := range before {
+		if _, ok := after[field]; !ok {
+			res = append(res, field)
+		}
+	}
+
+	sort.Strings(res)
+
+	return res
+}
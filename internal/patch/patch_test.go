@@ -0,0 +1,124 @@
+package patch_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tesarwijaya/ouroboros/internal/patch"
+)
+
+type doc struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+func Test_New(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		ContentType string
+		Body        string
+		Expect      patch.Patch
+		ExpectErr   error
+	}{
+		{
+			Name:        "when_merge_patch",
+			ContentType: "application/merge-patch+json; charset=utf-8",
+			Body:        `{"name": "owls"}`,
+			Expect:      patch.Patch{Type: patch.MIME_MERGE_PATCH, Body: []byte(`{"name": "owls"}`)},
+		},
+		{
+			Name:        "when_json_patch",
+			ContentType: "application/json-patch+json",
+			Body:        `[{"op": "remove", "path": "/name"}]`,
+			Expect:      patch.Patch{Type: patch.MIME_JSON_PATCH, Body: []byte(`[{"op": "remove", "path": "/name"}]`)},
+		},
+		{
+			Name:        "when_content_type_is_unsupported",
+			ContentType: "application/json",
+			Body:        `{"name": "owls"}`,
+			ExpectErr:   patch.ErrUnsupportedType,
+		},
+		{
+			Name:      "when_content_type_is_missing",
+			Body:      `{"name": "owls"}`,
+			ExpectErr: patch.ErrUnsupportedType,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			actual, err := patch.New(test.ContentType, []byte(test.Body))
+
+			assert.Equal(t, test.ExpectErr, err)
+			assert.Equal(t, test.Expect, actual)
+		})
+	}
+}
+
+func Test_Apply(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Patch     patch.Patch
+		Expect    doc
+		ExpectErr string
+	}{
+		{
+			Name:   "when_merge_patch",
+			Patch:  patch.Patch{Type: patch.MIME_MERGE_PATCH, Body: []byte(`{"name": "larks"}`)},
+			Expect: doc{ID: 1, Name: "larks"},
+		},
+		{
+			Name:   "when_merge_patch_removes",
+			Patch:  patch.Patch{Type: patch.MIME_MERGE_PATCH, Body: []byte(`{"name": null}`)},
+			Expect: doc{ID: 1},
+		},
+		{
+			Name: "when_json_patch",
+			Patch: patch.Patch{Type: patch.MIME_JSON_PATCH, Body: []byte(`[
+				{"op": "test", "path": "/name", "value": "owls"},
+				{"op": "replace", "path": "/name", "value": "larks"}
+			]`)},
+			Expect: doc{ID: 1, Name: "larks"},
+		},
+		{
+			Name:      "when_json_patch_test_fails",
+			Patch:     patch.Patch{Type: patch.MIME_JSON_PATCH, Body: []byte(`[{"op": "test", "path": "/name", "value": "larks"}]`)},
+			ExpectErr: "invalid patch: testing value /name failed: test failed",
+		},
+		{
+			Name:      "when_field_is_unknown",
+			Patch:     patch.Patch{Type: patch.MIME_MERGE_PATCH, Body: []byte(`{"age": 3}`)},
+			ExpectErr: "invalid patched document: json: unknown field \"age\"",
+		},
+		{
+			Name:      "when_field_has_wrong_type",
+			Patch:     patch.Patch{Type: patch.MIME_MERGE_PATCH, Body: []byte(`{"name": 3}`)},
+			ExpectErr: "invalid patched document: json: cannot unmarshal number into Go struct field doc.name of type string",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			var actual doc
+			err := test.Patch.Apply(doc{ID: 1, Name: "owls"}, &actual)
+
+			if test.ExpectErr != "" {
+				assert.EqualError(t, err, test.ExpectErr)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, test.Expect, actual)
+		})
+	}
+}
+
+func Test_Changed(t *testing.T) {
+	actual := patch.Changed(
+		map[string]interface{}{"id": int64(1), "name": "owls", "teamId": int64(2)},
+		map[string]interface{}{"id": int64(1), "name": "larks", "teamId": int64(3)},
+	)
+
+	assert.Equal(t, []string{"name", "teamId"}, actual)
+	assert.Equal(t, []string{}, patch.Changed(map[string]interface{}{"id": int64(1)}, map[string]interface{}{"id": int64(1)}))
+}
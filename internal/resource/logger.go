@@ -0,0 +1,32 @@
+package resource
+
+import (
+	"github.com/tesarwijaya/ouroboros/internal/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func NewLogger(c *config.Config) (*zap.Logger, error) {
+	var level zapcore.Level
+	if err := level.UnmarshalText([]byte(c.LogLevel)); err != nil {
+		return nil, err
+	}
+
+	conf := zap.NewProductionConfig()
+	conf.Level = zap.NewAtomicLevelAt(level)
+	conf.Encoding = c.LogFormat
+	conf.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	if c.LogFormat == "console" {
+		conf.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	}
+
+	l, err := conf.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	// code running outside of a request, like workers, logs with the global logger
+	zap.ReplaceGlobals(l)
+
+	return l, nil
+}
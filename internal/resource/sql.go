@@ -6,9 +6,11 @@ import (
 
 	_ "github.com/lib/pq"
 	"github.com/tesarwijaya/ouroboros/internal/config"
+	"github.com/tesarwijaya/ouroboros/internal/metrics"
+	"go.uber.org/zap"
 )
 
-func NewSQLConnection(c *config.Config) (*sql.DB, error) {
+func NewSQLConnection(c *config.Config, l *zap.Logger) (*sql.DB, error) {
 	psqlconn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
 		c.SqlDBHost, c.SqlDBPort, c.SqlDBUsername, c.SqlDBPassword, c.SqlDBName)
 
@@ -19,11 +21,15 @@ func NewSQLConnection(c *config.Config) (*sql.DB, error) {
 	}
 
 	err = db.Ping()
-	fmt.Println("db ping..")
+	l.Debug("db ping..", zap.String("host", c.SqlDBHost), zap.Int64("port", c.SqlDBPort))
 	if err != nil {
 		return nil, err
 	}
-	fmt.Println("db ping success!")
+	l.Debug("db ping success!")
+
+	if err := metrics.RegisterDB(db, c.SqlDBName); err != nil {
+		return nil, err
+	}
 
 	return db, err
 }
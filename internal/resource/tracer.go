@@ -0,0 +1,58 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tesarwijaya/ouroboros/internal/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	otel_resource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+const serviceName = "ouroboros"
+
+// NewTracerProvider registers the global tracer provider and the W3C trace
+// context propagator. Spans are still created with the "none" exporter, so
+// trace IDs keep showing up in logs and event metadata.
+func NewTracerProvider(c *config.Config) (*sdktrace.TracerProvider, error) {
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(c.TracingSampleRatio))),
+		sdktrace.WithResource(otel_resource.NewSchemaless(semconv.ServiceName(serviceName))),
+	}
+
+	switch c.TracingExporter {
+	case "none":
+	case "stdout":
+		exporter, err := stdouttrace.New()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	case "otlp":
+		clientOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(c.TracingOTLPEndpoint)}
+		if c.TracingOTLPInsecure {
+			clientOpts = append(clientOpts, otlptracegrpc.WithInsecure())
+		}
+
+		// the exporter connects lazily, an unreachable collector doesn't fail the start
+		exporter, err := otlptracegrpc.New(context.Background(), clientOpts...)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", c.TracingExporter)
+	}
+
+	tp := sdktrace.NewTracerProvider(opts...)
+
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	return tp, nil
+}
@@ -0,0 +1,95 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/tesarwijaya/ouroboros/internal/logger"
+	"go.uber.org/dig"
+	"go.uber.org/zap"
+)
+
+// Worker runs Run every Interval in the background until the app stops, a
+// worker without a positive Interval is disabled.
+type Worker struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+type Runner interface {
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+}
+
+type RunnerImpl struct {
+	dig.In  `ignore-unexported:"true"`
+	Workers []Worker `group:"workers"`
+
+	cancel context.CancelFunc
+	wg     *sync.WaitGroup
+}
+
+func NewRunner(runner RunnerImpl) Runner {
+	runner.wg = &sync.WaitGroup{}
+
+	return &runner
+}
+
+func (r *RunnerImpl) Start(ctx context.Context) error {
+	runCtx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	for _, w := range r.Workers {
+		if w.Interval <= 0 {
+			logger.FromContext(ctx).Warn("worker disabled", zap.String("worker", w.Name), zap.Duration("interval", w.Interval))
+			continue
+		}
+
+		r.wg.Add(1)
+		go r.run(runCtx, w)
+	}
+
+	return nil
+}
+
+// Stop cancels the workers and waits for the running iterations until ctx
+// is done.
+func (r *RunnerImpl) Stop(ctx context.Context) error {
+	if r.cancel == nil {
+		return nil
+	}
+	r.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *RunnerImpl) run(ctx context.Context, w Worker) {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.Run(ctx); err != nil {
+				logger.FromContext(ctx).Error("worker failed", zap.String("worker", w.Name), zap.Error(err))
+			}
+		}
+	}
+}
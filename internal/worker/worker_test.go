@@ -0,0 +1,100 @@
+package worker_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tesarwijaya/ouroboros/internal/worker"
+)
+
+func Test_NewRunner(t *testing.T) {
+	runner := worker.NewRunner(worker.RunnerImpl{})
+
+	assert.Implements(t, (*worker.Runner)(nil), runner)
+}
+
+func Test_Runner(t *testing.T) {
+	var runs int32
+
+	runner := worker.NewRunner(worker.RunnerImpl{
+		Workers: []worker.Worker{{
+			Name:     "some-worker",
+			Interval: time.Millisecond,
+			Run: func(ctx context.Context) error {
+				atomic.AddInt32(&runs, 1)
+				return nil
+			},
+		}},
+	})
+
+	assert.Nil(t, runner.Start(context.Background()))
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&runs) >= 2
+	}, time.Second, time.Millisecond)
+
+	assert.Nil(t, runner.Stop(context.Background()))
+
+	stopped := atomic.LoadInt32(&runs)
+	time.Sleep(5 * time.Millisecond)
+	assert.Equal(t, stopped, atomic.LoadInt32(&runs))
+}
+
+func Test_Runner_Disabled(t *testing.T) {
+	var runs int32
+
+	runner := worker.NewRunner(worker.RunnerImpl{
+		Workers: []worker.Worker{{
+			Name: "some-worker",
+			Run: func(ctx context.Context) error {
+				atomic.AddInt32(&runs, 1)
+				return nil
+			},
+		}},
+	})
+
+	assert.Nil(t, runner.Start(context.Background()))
+	time.Sleep(5 * time.Millisecond)
+	assert.Nil(t, runner.Stop(context.Background()))
+
+	assert.Equal(t, int32(0), atomic.LoadInt32(&runs))
+}
+
+func Test_Stop(t *testing.T) {
+	t.Run("when_not_started", func(t *testing.T) {
+		runner := worker.NewRunner(worker.RunnerImpl{})
+
+		assert.Nil(t, runner.Stop(context.Background()))
+	})
+
+	t.Run("when_worker_outlives_deadline", func(t *testing.T) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		defer close(release)
+
+		runner := worker.NewRunner(worker.RunnerImpl{
+			Workers: []worker.Worker{{
+				Name:     "some-worker",
+				Interval: time.Millisecond,
+				Run: func(ctx context.Context) error {
+					select {
+					case started <- struct{}{}:
+					default:
+					}
+					<-release
+					return nil
+				},
+			}},
+		})
+
+		assert.Nil(t, runner.Start(context.Background()))
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		defer cancel()
+
+		assert.Equal(t, context.DeadlineExceeded, runner.Stop(ctx))
+	})
+}
@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+//go:embed sql/*.up.sql
+var files embed.FS
+
+// LatestVersion returns the version of the newest migration, which is the
+// schema version this build expects the database to be migrated to.
+func LatestVersion() (uint, error) {
+	names, err := fs.Glob(files, "sql/*.up.sql")
+	if err != nil {
+		return 0, err
+	}
+
+	var latest uint
+	for _, name := range names {
+		prefix := strings.SplitN(strings.TrimPrefix(name, "sql/"), "_", 2)[0]
+
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+
+		if uint(version) > latest {
+			latest = uint(version)
+		}
+	}
+
+	return latest, nil
+}
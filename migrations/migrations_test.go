@@ -0,0 +1,15 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tesarwijaya/ouroboros/migrations"
+)
+
+func Test_LatestVersion(t *testing.T) {
+	actual, err := migrations.LatestVersion()
+
+	assert.Nil(t, err)
+	assert.GreaterOrEqual(t, actual, uint(5))
+}
@@ -0,0 +1,7 @@
+CREATE TABLE public.snapshot (
+	stream_id varchar NOT NULL,
+	"version" int8 NOT NULL,
+	"data" jsonb NOT NULL,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	CONSTRAINT snapshot_pk PRIMARY KEY (stream_id, "version")
+);
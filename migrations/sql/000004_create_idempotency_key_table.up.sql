@@ -0,0 +1,10 @@
+CREATE TABLE public.idempotency_key (
+	"key" varchar NOT NULL,
+	fingerprint varchar NOT NULL,
+	status_code int4 NULL,
+	content_type varchar NULL,
+	body bytea NULL,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	expires_at timestamptz NOT NULL,
+	CONSTRAINT idempotency_key_pk PRIMARY KEY ("key")
+);
@@ -0,0 +1 @@
+ALTER TABLE public.idempotency_key DROP COLUMN "location";
@@ -0,0 +1 @@
+ALTER TABLE public.idempotency_key ADD "location" varchar NULL;
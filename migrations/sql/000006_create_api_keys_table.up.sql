@@ -0,0 +1,12 @@
+CREATE TABLE public.api_keys (
+	id bigserial NOT NULL,
+	"name" varchar NOT NULL,
+	key_hash varchar NOT NULL,
+	scopes text[] NOT NULL DEFAULT '{}',
+	created_at timestamptz NOT NULL DEFAULT now(),
+	expires_at timestamptz NULL,
+	last_used_at timestamptz NULL,
+	revoked_at timestamptz NULL,
+	CONSTRAINT api_keys_pk PRIMARY KEY (id),
+	CONSTRAINT api_keys_key_hash_un UNIQUE (key_hash)
+);
@@ -0,0 +1,7 @@
+CREATE TABLE public.rate_limit_bucket (
+	"key" varchar NOT NULL,
+	tokens float8 NOT NULL,
+	allowed bool NOT NULL,
+	updated_at timestamptz NOT NULL DEFAULT now(),
+	CONSTRAINT rate_limit_bucket_pk PRIMARY KEY ("key")
+);
@@ -0,0 +1 @@
+DROP TABLE public.transfer_offer;
@@ -0,0 +1,17 @@
+CREATE TABLE public.transfer_offer (
+	id bigserial NOT NULL,
+	parent_id int8 NULL,
+	player_id int8 NOT NULL,
+	from_team_id int8 NOT NULL,
+	to_team_id int8 NOT NULL,
+	proposed_by_team_id int8 NOT NULL,
+	fee int8 NOT NULL,
+	status varchar NOT NULL,
+	expires_at timestamptz NOT NULL,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	updated_at timestamptz NOT NULL DEFAULT now(),
+	CONSTRAINT transfer_offer_pk PRIMARY KEY (id)
+);
+
+CREATE INDEX transfer_offer_status_expires_at_idx ON public.transfer_offer (status, expires_at);
+CREATE INDEX transfer_offer_player_id_idx ON public.transfer_offer (player_id);
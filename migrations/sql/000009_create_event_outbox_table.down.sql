@@ -0,0 +1 @@
+DROP TABLE public.event_outbox;
@@ -0,0 +1,11 @@
+CREATE TABLE public.event_outbox (
+	id bigserial NOT NULL,
+	event_id uuid NOT NULL,
+	stream_id varchar NOT NULL,
+	"type" varchar NOT NULL,
+	content_type int2 NOT NULL,
+	"data" bytea NULL,
+	metadata bytea NULL,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	CONSTRAINT event_outbox_pk PRIMARY KEY (id)
+);
@@ -0,0 +1,3 @@
+DROP INDEX public.event_outbox_event_id_idx;
+
+ALTER TABLE public.event_outbox DROP COLUMN attempts;
@@ -0,0 +1,3 @@
+ALTER TABLE public.event_outbox ADD attempts int4 NOT NULL DEFAULT 0;
+
+CREATE INDEX event_outbox_event_id_idx ON public.event_outbox (event_id);
@@ -0,0 +1 @@
+DROP TABLE public.event_dead_letter;
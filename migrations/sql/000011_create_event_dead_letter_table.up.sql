@@ -0,0 +1,15 @@
+CREATE TABLE public.event_dead_letter (
+	id bigserial NOT NULL,
+	event_id uuid NOT NULL,
+	stream_id varchar NOT NULL,
+	"type" varchar NOT NULL,
+	content_type int2 NOT NULL,
+	"data" bytea NULL,
+	metadata bytea NULL,
+	created_at timestamptz NOT NULL,
+	attempts int4 NOT NULL,
+	handler varchar NOT NULL,
+	"error" text NOT NULL,
+	dead_lettered_at timestamptz NOT NULL DEFAULT now(),
+	CONSTRAINT event_dead_letter_pk PRIMARY KEY (id)
+);